@@ -1,13 +1,17 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"kaabe-app/internal/api/controller"
 	"kaabe-app/internal/api/gateway"
 	"kaabe-app/internal/api/routes"
 	"kaabe-app/internal/config"
+	"kaabe-app/internal/domain/repository"
 	"kaabe-app/internal/domain/service"
+	"kaabe-app/internal/job"
+	"time"
 
 	// utils "kaabe-app/pkg/config"
 
@@ -19,6 +23,7 @@ import (
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"github.com/redis/go-redis/v9"
 )
 
 func init() {
@@ -94,35 +99,102 @@ func main() {
 
 	userRepo := gateway.NewUserRepository(dbConn)
 	tokenRepo := gateway.NewTokenRepository(dbConn)
+	permRepo := gateway.NewPermissionRepository(dbConn)
 	courseRepo := gateway.NewCourseRepository(dbConn)
+	catalogRepo := gateway.NewCatalogRepository(dbConn)
+	revisionRepo := gateway.NewCourseRevisionRepository(dbConn)
 	lessonRepo := gateway.NewLessonRepository(dbConn)
 	ratingRepo := gateway.NewRatingRepository(dbConn)
 	SubscriptionRepo := gateway.NewSubscriptionImpl(dbConn)
 	WithdrawalRepo := gateway.NewWithdrawalRepositoryImpl(dbConn)
 	paymentRepo := gateway.NewPaymentRepository(dbConn)
+	auditRepo := gateway.NewAuditRepository(dbConn)
+	twoFactorRepo := gateway.NewTwoFactorRepository(dbConn)
+	identityRepo := gateway.NewIdentityRepository(dbConn)
+	phoneOTPRepo := gateway.NewPhoneOTPRepository(dbConn)
+	applicationRepo := gateway.NewInfluencerApplicationRepository(dbConn)
+	influencerRepo := gateway.NewInfluencerRepository(dbConn)
+	promotionRepo := gateway.NewPromotionRepository(dbConn)
+	bundleRepo := gateway.NewBundleRepository(dbConn)
+	attemptStore := newAttemptStore(appCfg)
 
+	// Email delivery: outbox writes .eml files for local testing, smtp sends for real
+	var mailer service.Mailer
+	switch appCfg.Mail.Driver {
+	case "smtp":
+		mailer = gateway.NewSMTPMailer(appCfg.Mail.SMTP.Host, appCfg.Mail.SMTP.Port, appCfg.Mail.SMTP.Username, appCfg.Mail.SMTP.Password, appCfg.Mail.From)
+	default:
+		mailer = gateway.NewOutboxMailer(appCfg.Mail.OutboxDir, appCfg.Mail.From)
+	}
+	notificationService := service.NewNotificationService(mailer, appCfg.App.Name, appCfg.App.FrontendURL)
+
+	// SMS delivery for phone login codes; both drivers are for development until a provider is added
+	var smsSender service.SMSSender
+	switch appCfg.SMS.Driver {
+	case "log":
+		smsSender = gateway.NewLogSMSSender()
+	default:
+		smsSender = gateway.NewOutboxSMSSender(appCfg.SMS.OutboxDir)
+	}
 
 	// Initialize Services
-	userService := service.NewUserService(userRepo, tokenRepo)
-	courseService := service.NewCourseService(courseRepo, tokenRepo)
-	lessonService := service.NewLessonService(lessonRepo, tokenRepo)
+	loginLimiter := service.NewLoginLimiter(attemptStore, auditRepo)
+	resetLimiter := service.NewPasswordResetLimiter(attemptStore, auditRepo)
+	otpLimiter := service.NewPhoneOTPLimiter(attemptStore, auditRepo)
+	verifyLimiter := service.NewVerificationResendLimiter(attemptStore, auditRepo)
+	userService := service.NewUserService(userRepo, tokenRepo, twoFactorRepo, identityRepo, newIdentityProviders(appCfg), notificationService, loginLimiter, resetLimiter, phoneOTPRepo, smsSender, otpLimiter, verifyLimiter, service.UserServiceOptions{
+		Issuer:               appCfg.App.Name,
+		VerificationTTL:      time.Duration(appCfg.Auth.EmailVerificationTTLHours) * time.Hour,
+		RequireVerifiedLogin: appCfg.Auth.RequireVerifiedEmailForLogin,
+		RequireTwoFactor:     appCfg.Auth.RequireTwoFactor,
+		DefaultCountryCode:   appCfg.SMS.DefaultCountryCode,
+	})
+	influencerService := service.NewInfluencerService(influencerRepo, courseRepo, notificationService)
+	bundleService := service.NewBundleService(bundleRepo, courseRepo)
+	courseService := service.NewCourseService(courseRepo, tokenRepo, applicationRepo, catalogRepo, revisionRepo, influencerService, bundleService)
+	lessonService := service.NewLessonService(lessonRepo, courseRepo, tokenRepo)
 	ratingService := service.NewRatingService(ratingRepo, tokenRepo)
 	subscriptionService := service.NewSubscriptionService(SubscriptionRepo, tokenRepo)
-	withdrawalService := service.NewWithdrawalService(WithdrawalRepo, tokenRepo)
-	paymentService := service.NewPaymentService(paymentRepo)
+	withdrawalService := service.NewWithdrawalService(WithdrawalRepo, tokenRepo, userRepo, notificationService)
+	paymentService := service.NewPaymentService(paymentRepo, userRepo, promotionRepo, SubscriptionRepo, notificationService, appCfg.Auth.RequireVerifiedEmailForPurchase)
+	applicationService := service.NewInfluencerApplicationService(applicationRepo, userRepo, notificationService, appCfg.SMS.DefaultCountryCode)
+	promotionService := service.NewPromotionService(promotionRepo, courseRepo, bundleRepo)
+	accountService := service.NewAccountService(userRepo, identityRepo, SubscriptionRepo, ratingRepo, paymentRepo, WithdrawalRepo, applicationRepo, influencerRepo, auditRepo, notificationService, time.Duration(appCfg.Auth.AccountDeletionGraceDays)*24*time.Hour)
+
+	// Start background jobs
+	processor := job.NewProcessor()
+	processor.Register(job.NewTokenPurgeTask(tokenRepo, time.Hour))
+	processor.Register(job.NewPhoneOTPPurgeTask(phoneOTPRepo, time.Hour))
+	processor.Register(job.NewAccountDeletionTask(accountService, time.Hour))
+	processor.Register(job.NewCourseReleaseTask(courseService, time.Minute))
+	processor.Start(context.Background())
+	defer processor.Stop()
 
+	// Ownership checks shared by the controllers
+	accessPolicy := service.NewAccessPolicy(courseRepo, lessonRepo, ratingRepo, SubscriptionRepo, WithdrawalRepo, paymentRepo)
 
 	// Initialize Controllers
 	userController := controller.NewUserController(userService)
-	courseController := controller.NewCourseController(courseService)
-	lessonController := controller.NewLessonController(lessonService)
-	ratingController := controller.NewRatingController(ratingService)
-	subscriptionController := controller.NewSubscriptionController(subscriptionService)
-	withdrawalController := controller.NewWithdrawalController(withdrawalService)
-	paymentController := controller.NewPaymentController(paymentService)
+	courseController := controller.NewCourseController(courseService, accessPolicy)
+	lessonController := controller.NewLessonController(lessonService, accessPolicy)
+	ratingController := controller.NewRatingController(ratingService, accessPolicy)
+	subscriptionController := controller.NewSubscriptionController(subscriptionService, accessPolicy)
+	withdrawalController := controller.NewWithdrawalController(withdrawalService, accessPolicy)
+	paymentController := controller.NewPaymentController(paymentService, accessPolicy, appCfg.Payments.WebhookSecret)
+	accountController := controller.NewAccountController(accountService)
+	applicationController := controller.NewInfluencerApplicationController(applicationService)
+	influencerController := controller.NewInfluencerController(influencerService)
+	catalogController := controller.NewCatalogController(courseService)
+	revisionController := controller.NewCourseRevisionController(courseService, accessPolicy)
+	promotionController := controller.NewPromotionController(promotionService, accessPolicy)
+	bundleController := controller.NewBundleController(bundleService)
 
 	// Setup Gin HTTP Server
 	r := gin.Default()
+	// Client IPs key the login and reset rate limits, so forwarded headers are only believed from known proxies
+	if err := r.SetTrustedProxies(appCfg.App.TrustedProxies); err != nil {
+		log.Fatalf("Invalid trusted proxies: %v", err)
+	}
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
@@ -132,15 +204,69 @@ func main() {
 	}))
 
 	// Register API Routes
-	routes.RegisterUserRoutes(r, userController, tokenRepo)
-	routes.RegisterCoursesRoutes(r, courseController, tokenRepo)
-	routes.RegisterLessonRoutes(r, lessonController, tokenRepo)
-	routes.RegisterRatingRoutes(r, ratingController, tokenRepo)
-	routes.RegisterSubscriptionRoutes(r, subscriptionController, tokenRepo)
-	routes.RegisterWithdrawalRoutes(r, withdrawalController, tokenRepo)
-	routes.RegisterPaymentRoutes(r, paymentController, tokenRepo)
+	routes.RegisterUserRoutes(r, userController, tokenRepo, permRepo)
+	routes.RegisterAccountRoutes(r, accountController, tokenRepo)
+	routes.RegisterInfluencerApplicationRoutes(r, applicationController, tokenRepo, permRepo)
+	routes.RegisterInfluencerRoutes(r, influencerController, tokenRepo, permRepo)
+	routes.RegisterCoursesRoutes(r, courseController, tokenRepo, permRepo)
+	routes.RegisterCatalogRoutes(r, catalogController, tokenRepo, permRepo)
+	routes.RegisterCourseRevisionRoutes(r, revisionController, tokenRepo, permRepo)
+	routes.RegisterLessonRoutes(r, lessonController, tokenRepo, permRepo)
+	routes.RegisterRatingRoutes(r, ratingController, tokenRepo, permRepo)
+	routes.RegisterSubscriptionRoutes(r, subscriptionController, tokenRepo, permRepo)
+	routes.RegisterWithdrawalRoutes(r, withdrawalController, tokenRepo, permRepo)
+	routes.RegisterPaymentRoutes(r, paymentController, tokenRepo, permRepo)
+	routes.RegisterPromotionRoutes(r, promotionController, tokenRepo, permRepo)
+	routes.RegisterBundleRoutes(r, bundleController, tokenRepo, permRepo)
 	// Start main API server
 	if err := r.Run(":" + appCfg.App.Port); err != nil {
 		log.Fatal("Failed to start API server:", err)
 	}
 }
+
+// newIdentityProviders builds the OIDC providers that have a client ID configured
+func newIdentityProviders(appCfg *config.AppConfig) []service.IdentityProvider {
+	var providers []service.IdentityProvider
+	for name, provider := range appCfg.OIDC {
+		if provider.ClientID == "" {
+			continue
+		}
+		providers = append(providers, gateway.NewOIDCProvider(gateway.OIDCConfig{
+			Name:         name,
+			Issuer:       provider.Issuer,
+			ClientID:     provider.ClientID,
+			ClientSecret: provider.ClientSecret,
+			RedirectURL:  provider.RedirectURL,
+			Scopes:       provider.Scopes,
+			ResponseMode: provider.ResponseMode,
+		}))
+		log.Printf("OIDC login enabled for %s", name)
+	}
+	return providers
+}
+
+// newAttemptStore uses Redis for brute-force counters when it is reachable so limits hold
+// across instances, and falls back to process memory otherwise
+func newAttemptStore(appCfg *config.AppConfig) repository.AttemptStore {
+	if appCfg.Redis.Address == "" {
+		log.Println("Redis not configured, using in-memory attempt store")
+		return gateway.NewMemoryAttemptStore()
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     appCfg.Redis.Address,
+		Password: appCfg.Redis.Password,
+		DB:       appCfg.Redis.DB,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Printf("Redis unavailable at %s (%v), using in-memory attempt store", appCfg.Redis.Address, err)
+		client.Close()
+		return gateway.NewMemoryAttemptStore()
+	}
+
+	log.Printf("Using Redis attempt store at %s", appCfg.Redis.Address)
+	return gateway.NewRedisAttemptStore(client)
+}
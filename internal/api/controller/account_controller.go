@@ -0,0 +1,145 @@
+package controller
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"kaabe-app/internal/domain/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AccountController struct {
+	accountService service.AccountService
+}
+
+func NewAccountController(accountService service.AccountService) *AccountController {
+	return &AccountController{accountService: accountService}
+}
+
+// ExportPersonalData returns everything stored about the current user, as JSON or ?format=zip
+func (ac *AccountController) ExportPersonalData(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID is required"})
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "zip" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or zip"})
+		return
+	}
+
+	export, err := ac.accountService.ExportPersonalData(userID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	response := newPersonalDataExportResponse(export)
+	filename := fmt.Sprintf("personal-data-%s", export.ExportedAt.Format("20060102"))
+
+	if format == "json" {
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.json"`, filename))
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	archive, err := zipExport(response)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.zip"`, filename))
+	c.Data(http.StatusOK, "application/zip", archive)
+}
+
+// RequestDeletion schedules the current user's account for deletion
+func (ac *AccountController) RequestDeletion(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID is required"})
+		return
+	}
+
+	// The body is optional for accounts without a password
+	var req RequestDeletionRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := ac.accountService.RequestDeletion(userID, req.CurrentPassword)
+	if err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, AccountDeletionResponse{
+		Message:             "Account scheduled for deletion",
+		DeletionScheduledAt: *user.DeletionScheduledAt,
+	})
+}
+
+// CancelDeletion keeps the current user's account if its deletion is still pending
+func (ac *AccountController) CancelDeletion(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID is required"})
+		return
+	}
+
+	if err := ac.accountService.CancelDeletion(userID); err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Account deletion cancelled"})
+}
+
+// respondAccountError maps AccountService errors to HTTP responses
+func respondAccountError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidCurrentPassword):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrNoDeletionPending):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case err.Error() == "user not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// zipExport writes each section of the export to its own JSON file in a ZIP archive
+func zipExport(export PersonalDataExportResponse) ([]byte, error) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+
+	for _, file := range export.files() {
+		w, err := archive.Create(file.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build export: %v", err)
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.Data); err != nil {
+			return nil, fmt.Errorf("failed to build export: %v", err)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("failed to build export: %v", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"kaabe-app/internal/domain/model"
+	"time"
+)
+
+// RequestDeletionRequest is the body of POST /users/me/deletion
+type RequestDeletionRequest struct {
+	CurrentPassword string `json:"current_password"` // not needed when the account has no password
+}
+
+// AccountDeletionResponse says when a requested deletion will happen
+type AccountDeletionResponse struct {
+	Message             string    `json:"message"`
+	DeletionScheduledAt time.Time `json:"deletion_scheduled_at"`
+}
+
+// PersonalDataExportResponse is the body of GET /users/me/export
+type PersonalDataExportResponse struct {
+	ExportedAt             time.Time                       `json:"exported_at"`
+	Profile                UserResponse                    `json:"profile"`
+	Identities             []IdentityResponse              `json:"identities"`
+	Subscriptions          []SubscriptionResponse          `json:"subscriptions"`
+	Ratings                []RatingResponse                `json:"ratings"`
+	Payments               []PaymentResponse               `json:"payments"`
+	Withdrawals            []WithdrawalResponse            `json:"withdrawals"`
+	InfluencerApplications []InfluencerApplicationResponse `json:"influencer_applications"`
+	InfluencerProfile      *InfluencerProfileResponse      `json:"influencer_profile,omitempty"`
+	Following              []InfluencerProfileResponse     `json:"following"`
+}
+
+func newPersonalDataExportResponse(export *model.PersonalDataExport) PersonalDataExportResponse {
+	response := PersonalDataExportResponse{
+		ExportedAt:             export.ExportedAt,
+		Profile:                newUserResponse(export.User),
+		Identities:             newIdentityResponses(export.Identities),
+		Subscriptions:          newSubscriptionResponses(export.Subscriptions),
+		Ratings:                newRatingResponses(export.Ratings),
+		Payments:               newPaymentResponses(export.Payments),
+		Withdrawals:            newWithdrawalResponses(export.Withdrawals),
+		InfluencerApplications: newInfluencerApplicationResponses(export.InfluencerApplications),
+		Following:              newInfluencerProfileResponses(export.Following),
+	}
+	if export.InfluencerProfile != nil {
+		profile := newInfluencerProfileResponse(export.InfluencerProfile)
+		response.InfluencerProfile = &profile
+	}
+	return response
+}
+
+// exportFile is one JSON document in the ZIP download
+type exportFile struct {
+	Name string
+	Data interface{}
+}
+
+// files splits the export into one JSON document per section for the ZIP download
+func (r PersonalDataExportResponse) files() []exportFile {
+	return []exportFile{
+		{Name: "profile.json", Data: r.Profile},
+		{Name: "identities.json", Data: r.Identities},
+		{Name: "subscriptions.json", Data: r.Subscriptions},
+		{Name: "ratings.json", Data: r.Ratings},
+		{Name: "payments.json", Data: r.Payments},
+		{Name: "withdrawals.json", Data: r.Withdrawals},
+		{Name: "influencer_applications.json", Data: r.InfluencerApplications},
+		{Name: "influencer_profile.json", Data: r.InfluencerProfile},
+		{Name: "following.json", Data: r.Following},
+	}
+}
@@ -0,0 +1,267 @@
+package controller
+
+import (
+	"errors"
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+// BundleController serves course bundles and influencer all-access passes, and the purchases
+// buyers made of them. Influencers manage their own bundles and pass; admins manage any.
+type BundleController struct {
+	bundleService service.BundleService
+}
+
+// NewBundleController creates a new BundleController instance
+func NewBundleController(bundleService service.BundleService) *BundleController {
+	return &BundleController{bundleService: bundleService}
+}
+
+// ListBundles lists the bundles on sale a page at a time.
+// Filters: ?influencer_id=, ?course_id=; sort: created_at (default, newest first), price, title
+func (bc *BundleController) ListBundles(c *gin.Context) {
+	active := true
+	bc.listBundles(c, model.BundleFilter{Active: &active})
+}
+
+// ListOwnBundles lists the caller's bundles, including inactive ones; admins see everyone's
+// and may filter by ?influencer_id=. Also takes ?course_id=.
+func (bc *BundleController) ListOwnBundles(c *gin.Context) {
+	var filter model.BundleFilter
+	actor, _ := currentActor(c)
+	if !actor.IsAdmin() {
+		filter.InfluencerID = &actor.UserID
+	}
+	bc.listBundles(c, filter)
+}
+
+func (bc *BundleController) listBundles(c *gin.Context, filter model.BundleFilter) {
+	page, err := bindPageRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	influencerID, influencerErr := queryUUID(c, "influencer_id")
+	courseID, courseErr := queryUUID(c, "course_id")
+	if err := firstError(influencerErr, courseErr); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if filter.InfluencerID == nil {
+		filter.InfluencerID = influencerID
+	}
+	filter.CourseID = courseID
+
+	bundles, err := bc.bundleService.ListBundles(filter, page)
+	if err != nil {
+		respondListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newPageResponse(bundles, newBundleResponses))
+}
+
+// GetBundle returns a bundle on sale
+func (bc *BundleController) GetBundle(c *gin.Context) {
+	bundleID, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid bundle ID"})
+		return
+	}
+
+	bundle, err := bc.bundleService.GetBundle(bundleID)
+	if err != nil {
+		respondBundleError(c, err)
+		return
+	}
+	// Inactive bundles are as good as missing to buyers
+	if !bundle.Active {
+		respondBundleError(c, service.ErrBundleNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, newBundleResponse(bundle))
+}
+
+// CreateBundle adds a bundle of the caller's published courses; admins may create one for an
+// influencer by giving influencer_id
+func (bc *BundleController) CreateBundle(c *gin.Context) {
+	var req BundleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	actor, _ := currentActor(c)
+	influencerID := actor.UserID
+	if actor.IsAdmin() && req.InfluencerID != uuid.Nil {
+		influencerID = req.InfluencerID
+	}
+
+	bundle := req.toModel(uuid.Nil, influencerID)
+	if err := bc.bundleService.CreateBundle(bundle); err != nil {
+		respondBundleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newBundleResponse(bundle))
+}
+
+// UpdateBundle replaces a bundle's details and courses; buyers keep what they paid for
+func (bc *BundleController) UpdateBundle(c *gin.Context) {
+	existing, ok := bc.authorizeBundle(c)
+	if !ok {
+		return
+	}
+
+	var req BundleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	bundle := req.toModel(existing.ID, existing.InfluencerID)
+	if err := bc.bundleService.UpdateBundle(bundle); err != nil {
+		respondBundleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newBundleResponse(bundle))
+}
+
+// DeleteBundle removes a bundle nobody has bought; bought bundles are deactivated instead
+func (bc *BundleController) DeleteBundle(c *gin.Context) {
+	bundle, ok := bc.authorizeBundle(c)
+	if !ok {
+		return
+	}
+
+	if err := bc.bundleService.DeleteBundle(bundle.ID); err != nil {
+		respondBundleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Bundle deleted successfully"})
+}
+
+// GetPass returns the influencer's all-access pass, if they sell one
+func (bc *BundleController) GetPass(c *gin.Context) {
+	influencerID, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid influencer ID"})
+		return
+	}
+
+	pass, err := bc.bundleService.GetPass(influencerID)
+	if err != nil {
+		respondBundleError(c, err)
+		return
+	}
+	if !pass.Active {
+		respondBundleError(c, service.ErrPassNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, newInfluencerPassResponse(pass))
+}
+
+// SetPass creates or replaces the influencer's all-access pass; influencers may only set their own
+func (bc *BundleController) SetPass(c *gin.Context) {
+	influencerID, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid influencer ID"})
+		return
+	}
+
+	actor, _ := currentActor(c)
+	if !actor.IsAdmin() && actor.UserID != influencerID {
+		respondAccessError(c, service.ErrForbidden)
+		return
+	}
+
+	var req InfluencerPassRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	pass := req.toModel(influencerID)
+	if err := bc.bundleService.SetPass(pass); err != nil {
+		respondBundleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newInfluencerPassResponse(pass))
+}
+
+// ListPurchases lists the caller's bundle and pass purchases a page at a time; admins may look at
+// another user's with ?user_id=. Sort: created_at (default, newest first), expires_at
+func (bc *BundleController) ListPurchases(c *gin.Context) {
+	page, err := bindPageRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	actor, _ := currentActor(c)
+	userID := actor.UserID
+	if actor.IsAdmin() {
+		requested, err := queryUUID(c, "user_id")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if requested != nil {
+			userID = *requested
+		}
+	}
+
+	purchases, err := bc.bundleService.ListPurchases(userID, page)
+	if err != nil {
+		respondListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newPageResponse(purchases, newPurchaseResponses))
+}
+
+// authorizeBundle loads the bundle in the URL if the caller may manage it
+func (bc *BundleController) authorizeBundle(c *gin.Context) (*model.Bundle, bool) {
+	bundleID, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid bundle ID"})
+		return nil, false
+	}
+
+	bundle, err := bc.bundleService.GetBundle(bundleID)
+	if err != nil {
+		respondBundleError(c, err)
+		return nil, false
+	}
+
+	actor, _ := currentActor(c)
+	if !actor.IsAdmin() && actor.UserID != bundle.InfluencerID {
+		respondAccessError(c, service.ErrForbidden)
+		return nil, false
+	}
+	return bundle, true
+}
+
+func respondBundleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrBundleNotFound), errors.Is(err, service.ErrPassNotFound),
+		errors.Is(err, service.ErrCourseNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidBundle), errors.Is(err, service.ErrInvalidPass), isMoneyError(err):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrBundlePurchased):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"kaabe-app/internal/domain/model"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// BundleRequest is the body of POST /bundles and PUT /bundles/:id
+type BundleRequest struct {
+	InfluencerID uuid.UUID    `json:"influencer_id"` // honoured for admins creating a bundle only
+	Title        string       `json:"title" binding:"required,max=255"`
+	Description  string       `json:"description"`
+	Price        MoneyRequest `json:"price"`
+	AccessDays   int          `json:"access_days" binding:"required"` // how long buyers keep the courses
+	Active       *bool        `json:"active"`                         // true if omitted
+	CourseIDs    []uuid.UUID  `json:"course_ids" binding:"required"`  // published courses of the influencer, in order
+}
+
+// InfluencerPassRequest is the body of PUT /influencers/:id/pass
+type InfluencerPassRequest struct {
+	Price      MoneyRequest `json:"price"`
+	AccessDays int          `json:"access_days" binding:"required"`
+	Active     *bool        `json:"active"` // true if omitted
+}
+
+// BundleResponse is a bundle as buyers and its influencer see it
+type BundleResponse struct {
+	ID           uuid.UUID     `json:"id"`
+	InfluencerID uuid.UUID     `json:"influencer_id"`
+	Title        string        `json:"title"`
+	Description  string        `json:"description"`
+	Price        MoneyResponse `json:"price"`
+	AccessDays   int           `json:"access_days"`
+	Active       bool          `json:"active"`
+	CourseIDs    []uuid.UUID   `json:"course_ids"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+}
+
+// InfluencerPassResponse is an influencer's all-access pass
+type InfluencerPassResponse struct {
+	InfluencerID uuid.UUID     `json:"influencer_id"`
+	Price        MoneyResponse `json:"price"`
+	AccessDays   int           `json:"access_days"`
+	Active       bool          `json:"active"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+}
+
+// PurchaseResponse is a bundle, pass or course the user bought, with the courses it subscribed them to
+type PurchaseResponse struct {
+	ID               uuid.UUID   `json:"id"`
+	PaymentID        uuid.UUID   `json:"payment_id"`
+	BundleID         *uuid.UUID  `json:"bundle_id,omitempty"`
+	PassInfluencerID *uuid.UUID  `json:"pass_influencer_id,omitempty"`
+	CourseID         *uuid.UUID  `json:"course_id,omitempty"`
+	CourseIDs        []uuid.UUID `json:"course_ids"`
+	StartedAt        time.Time   `json:"started_at"`
+	ExpiresAt        time.Time   `json:"expires_at"`
+}
+
+func (r BundleRequest) toModel(id, influencerID uuid.UUID) *model.Bundle {
+	return &model.Bundle{
+		ID:           id,
+		InfluencerID: influencerID,
+		Title:        r.Title,
+		Description:  r.Description,
+		Price:        r.Price.toModel(),
+		AccessDays:   r.AccessDays,
+		Active:       r.Active == nil || *r.Active,
+		CourseIDs:    r.CourseIDs,
+	}
+}
+
+func (r InfluencerPassRequest) toModel(influencerID uuid.UUID) *model.InfluencerPass {
+	return &model.InfluencerPass{
+		InfluencerID: influencerID,
+		Price:        r.Price.toModel(),
+		AccessDays:   r.AccessDays,
+		Active:       r.Active == nil || *r.Active,
+	}
+}
+
+func newBundleResponse(bundle *model.Bundle) BundleResponse {
+	return BundleResponse{
+		ID:           bundle.ID,
+		InfluencerID: bundle.InfluencerID,
+		Title:        bundle.Title,
+		Description:  bundle.Description,
+		Price:        newMoneyResponse(bundle.Price),
+		AccessDays:   bundle.AccessDays,
+		Active:       bundle.Active,
+		CourseIDs:    bundle.CourseIDs,
+		CreatedAt:    bundle.CreatedAt,
+		UpdatedAt:    bundle.UpdatedAt,
+	}
+}
+
+func newBundleResponses(bundles []*model.Bundle) []BundleResponse {
+	responses := make([]BundleResponse, 0, len(bundles))
+	for _, bundle := range bundles {
+		responses = append(responses, newBundleResponse(bundle))
+	}
+	return responses
+}
+
+func newInfluencerPassResponse(pass *model.InfluencerPass) InfluencerPassResponse {
+	return InfluencerPassResponse{
+		InfluencerID: pass.InfluencerID,
+		Price:        newMoneyResponse(pass.Price),
+		AccessDays:   pass.AccessDays,
+		Active:       pass.Active,
+		UpdatedAt:    pass.UpdatedAt,
+	}
+}
+
+func newPurchaseResponses(purchases []*model.Purchase) []PurchaseResponse {
+	responses := make([]PurchaseResponse, 0, len(purchases))
+	for _, purchase := range purchases {
+		responses = append(responses, PurchaseResponse{
+			ID:               purchase.ID,
+			PaymentID:        purchase.PaymentID,
+			BundleID:         purchase.BundleID,
+			PassInfluencerID: purchase.PassInfluencerID,
+			CourseID:         purchase.CourseID,
+			CourseIDs:        purchase.CourseIDs,
+			StartedAt:        purchase.StartedAt,
+			ExpiresAt:        purchase.ExpiresAt,
+		})
+	}
+	return responses
+}
@@ -0,0 +1,228 @@
+package controller
+
+import (
+	"errors"
+	"kaabe-app/internal/domain/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+// Default and largest number of tags returned by GET /tags
+const (
+	defaultTagLimit = 50
+	maxTagLimit     = 200
+)
+
+// CatalogController serves the course taxonomy: categories, tags and curated collections
+type CatalogController struct {
+	courseService service.CourseService
+}
+
+// NewCatalogController creates a new CatalogController instance
+func NewCatalogController(courseService service.CourseService) *CatalogController {
+	return &CatalogController{courseService: courseService}
+}
+
+// ListCategories returns the category tree; browse one with GET /courses?category=<slug>
+func (cc *CatalogController) ListCategories(c *gin.Context) {
+	categories, err := cc.courseService.ListCategoryTree()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newCategoryResponses(categories))
+}
+
+// CreateCategory adds a category, optionally under a parent
+func (cc *CatalogController) CreateCategory(c *gin.Context) {
+	var req CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	category := req.toModel(uuid.Nil)
+	if err := cc.courseService.CreateCategory(category); err != nil {
+		respondCatalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newCategoryResponse(category))
+}
+
+// UpdateCategory renames, re-slugs, reorders or moves a category
+func (cc *CatalogController) UpdateCategory(c *gin.Context) {
+	categoryID, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	var req CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	category := req.toModel(categoryID)
+	if err := cc.courseService.UpdateCategory(category); err != nil {
+		respondCatalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCategoryResponse(category))
+}
+
+// DeleteCategory removes a category without subcategories; its courses become uncategorized
+func (cc *CatalogController) DeleteCategory(c *gin.Context) {
+	categoryID, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	if err := cc.courseService.DeleteCategory(categoryID); err != nil {
+		respondCatalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Category deleted successfully"})
+}
+
+// ListTags returns the most used tags on published courses; browse one with GET /courses?tag=<name>
+func (cc *CatalogController) ListTags(c *gin.Context) {
+	limit := defaultTagLimit
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxTagLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and " + strconv.Itoa(maxTagLimit)})
+			return
+		}
+		limit = parsed
+	}
+
+	tags, err := cc.courseService.ListPopularTags(limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// ListCollections returns the curated collections in display order
+func (cc *CatalogController) ListCollections(c *gin.Context) {
+	collections, err := cc.courseService.ListCollections()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newCollectionResponses(collections))
+}
+
+// GetCollection returns a collection and its published courses
+func (cc *CatalogController) GetCollection(c *gin.Context) {
+	collection, courses, err := cc.courseService.GetCollection(c.Param("slug"))
+	if err != nil {
+		respondCatalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCollectionDetailResponse(collection, courses))
+}
+
+// CreateCollection adds an empty collection
+func (cc *CatalogController) CreateCollection(c *gin.Context) {
+	var req CollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	collection := req.toModel(uuid.Nil)
+	if err := cc.courseService.CreateCollection(collection); err != nil {
+		respondCatalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newCollectionResponse(collection))
+}
+
+// UpdateCollection changes a collection's name, slug, description or position
+func (cc *CatalogController) UpdateCollection(c *gin.Context) {
+	collectionID, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid collection ID"})
+		return
+	}
+
+	var req CollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	collection := req.toModel(collectionID)
+	if err := cc.courseService.UpdateCollection(collection); err != nil {
+		respondCatalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCollectionResponse(collection))
+}
+
+// DeleteCollection removes a collection; its courses are not affected
+func (cc *CatalogController) DeleteCollection(c *gin.Context) {
+	collectionID, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid collection ID"})
+		return
+	}
+
+	if err := cc.courseService.DeleteCollection(collectionID); err != nil {
+		respondCatalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Collection deleted successfully"})
+}
+
+// SetCollectionCourses replaces the courses of a collection with the given ordered list
+func (cc *CatalogController) SetCollectionCourses(c *gin.Context) {
+	collectionID, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid collection ID"})
+		return
+	}
+
+	var req SetCollectionCoursesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := cc.courseService.SetCollectionCourses(collectionID, req.CourseIDs); err != nil {
+		respondCatalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Collection courses updated successfully"})
+}
+
+func respondCatalogError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidSlug), errors.Is(err, service.ErrInvalidTags), errors.Is(err, service.ErrCategoryCycle):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrCategoryNotFound), errors.Is(err, service.ErrCollectionNotFound), errors.Is(err, service.ErrCourseNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrSlugTaken), errors.Is(err, service.ErrCategoryHasChildren):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
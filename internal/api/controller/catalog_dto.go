@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"kaabe-app/internal/domain/model"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// SetCourseTaxonomyRequest is the body of PUT /courses/:id/taxonomy; it replaces both the category and the tags
+type SetCourseTaxonomyRequest struct {
+	CategoryID *uuid.UUID `json:"category_id"` // null to uncategorize
+	Tags       []string   `json:"tags" binding:"max=10,dive,max=50"`
+}
+
+// CategoryRequest is the body of POST /categories and PUT /categories/:id
+type CategoryRequest struct {
+	ParentID *uuid.UUID `json:"parent_id"` // null for a top-level category
+	Name     string     `json:"name" binding:"required,max=100"`
+	Slug     string     `json:"slug" binding:"max=100"` // derived from the name when empty
+	Position int        `json:"position"`
+}
+
+// CollectionRequest is the body of POST /collections and PUT /collections/:id
+type CollectionRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Slug        string `json:"slug" binding:"max=100"` // derived from the name when empty
+	Description string `json:"description"`
+	Position    int    `json:"position"`
+}
+
+// SetCollectionCoursesRequest is the body of PUT /collections/:id/courses, in display order
+type SetCollectionCoursesRequest struct {
+	CourseIDs []uuid.UUID `json:"course_ids" binding:"max=100"`
+}
+
+// CategoryResponse is a category with its subcategories
+type CategoryResponse struct {
+	ID        uuid.UUID          `json:"id"`
+	ParentID  *uuid.UUID         `json:"parent_id,omitempty"`
+	Name      string             `json:"name"`
+	Slug      string             `json:"slug"`
+	Position  int                `json:"position"`
+	Children  []CategoryResponse `json:"children"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+// CollectionResponse is a curated collection
+type CollectionResponse struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
+	Description string    `json:"description"`
+	Position    int       `json:"position"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CollectionDetailResponse is a collection with its published courses in display order
+type CollectionDetailResponse struct {
+	CollectionResponse
+	Courses []CourseResponse `json:"courses"`
+}
+
+func (r CategoryRequest) toModel(id uuid.UUID) *model.Category {
+	return &model.Category{
+		ID:       id,
+		ParentID: r.ParentID,
+		Name:     r.Name,
+		Slug:     r.Slug,
+		Position: r.Position,
+	}
+}
+
+func (r CollectionRequest) toModel(id uuid.UUID) *model.Collection {
+	return &model.Collection{
+		ID:          id,
+		Name:        r.Name,
+		Slug:        r.Slug,
+		Description: r.Description,
+		Position:    r.Position,
+	}
+}
+
+func newCategoryResponse(category *model.Category) CategoryResponse {
+	return CategoryResponse{
+		ID:        category.ID,
+		ParentID:  category.ParentID,
+		Name:      category.Name,
+		Slug:      category.Slug,
+		Position:  category.Position,
+		Children:  newCategoryResponses(category.Children),
+		CreatedAt: category.CreatedAt,
+		UpdatedAt: category.UpdatedAt,
+	}
+}
+
+func newCategoryResponses(categories []*model.Category) []CategoryResponse {
+	responses := make([]CategoryResponse, 0, len(categories))
+	for _, category := range categories {
+		responses = append(responses, newCategoryResponse(category))
+	}
+	return responses
+}
+
+func newCollectionResponse(collection *model.Collection) CollectionResponse {
+	return CollectionResponse{
+		ID:          collection.ID,
+		Name:        collection.Name,
+		Slug:        collection.Slug,
+		Description: collection.Description,
+		Position:    collection.Position,
+		CreatedAt:   collection.CreatedAt,
+		UpdatedAt:   collection.UpdatedAt,
+	}
+}
+
+func newCollectionDetailResponse(collection *model.Collection, courses []*model.Course) CollectionDetailResponse {
+	return CollectionDetailResponse{
+		CollectionResponse: newCollectionResponse(collection),
+		Courses:            newCourseResponses(courses),
+	}
+}
+
+func newCollectionResponses(collections []*model.Collection) []CollectionResponse {
+	responses := make([]CollectionResponse, 0, len(collections))
+	for _, collection := range collections {
+		responses = append(responses, newCollectionResponse(collection))
+	}
+	return responses
+}
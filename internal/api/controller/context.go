@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"errors"
+	"kaabe-app/internal/domain/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+// currentUserID returns the authenticated user ID set by the AuthMiddleware
+func currentUserID(ctx *gin.Context) (uuid.UUID, bool) {
+	value, exists := ctx.Get("userID")
+	if !exists {
+		return uuid.Nil, false
+	}
+
+	userID, ok := value.(uuid.UUID)
+	return userID, ok
+}
+
+// currentActor returns the authenticated user and the role resolved by the authorization middleware
+func currentActor(ctx *gin.Context) (service.Actor, bool) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return service.Actor{}, false
+	}
+
+	return service.Actor{UserID: userID, Role: ctx.GetString("userRole")}, true
+}
+
+// respondAccessError maps an AccessPolicy error to an HTTP response
+func respondAccessError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrForbidden):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to access this resource"})
+	case errors.Is(err, service.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// respondTooManyAttempts writes a 429 with Retry-After if err is a TooManyAttemptsError
+func respondTooManyAttempts(ctx *gin.Context, err error) bool {
+	var tooMany *service.TooManyAttemptsError
+	if !errors.As(err, &tooMany) {
+		return false
+	}
+
+	seconds := int(tooMany.RetryAfter.Seconds()) + 1
+	ctx.Header("Retry-After", strconv.Itoa(seconds))
+	ctx.JSON(http.StatusTooManyRequests, gin.H{"error": tooMany.Error()})
+	return true
+}
@@ -1,9 +1,11 @@
 package controller
 
 import (
+	"errors"
 	"kaabe-app/internal/domain/model"
 	"kaabe-app/internal/domain/service"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gofrs/uuid"
@@ -12,51 +14,63 @@ import (
 // CourseController struct that defines the course controller with its service
 type CourseController struct {
 	courseService service.CourseService
+	policy        service.AccessPolicy
 }
 
 // NewCourseController creates a new CourseController instance
-func NewCourseController(courseService service.CourseService) *CourseController {
-	return &CourseController{courseService: courseService}
+func NewCourseController(courseService service.CourseService, policy service.AccessPolicy) *CourseController {
+	return &CourseController{courseService: courseService, policy: policy}
 }
 
 // CreateCourse handles the creation of a new course
 func (c *CourseController) CreateCourse(ctx *gin.Context) {
-	var course model.Course
+	var course CreateCourseRequest
 
-	// Bind JSON to the course struct, which now includes CoverImageURL as []string
 	if err := ctx.ShouldBindJSON(&course); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	// Get the user ID from the request  set by the AuthMiddleware
-	_, exists := ctx.Get("userID")
+	actor, exists := currentActor(ctx)
 	if !exists {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user ID is required"})
 		return
 	}
 
+	// Influencers always create courses for themselves; admins may create on behalf of one
+	if !actor.IsAdmin() || course.InfluencerID == uuid.Nil {
+		course.InfluencerID = actor.UserID
+	}
+
 	// Call the service with the bound struct's CoverImageURL slice and instructorID
 	createdCourse, err := c.courseService.CreateCourse(
 		course.InfluencerID,
 		course.Title,
 		course.Description,
-		course.Price,
+		course.Price.toModel(),
 		course.CoverImageURL,
-		course.Status,
 	)
 
 	if err != nil {
+		if isMoneyError(err) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrInfluencerNotApproved) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, createdCourse)
+	ctx.JSON(http.StatusOK, newCourseResponse(createdCourse))
 }
 
 // UpdateCourse handles the update of an existing course
 func (c *CourseController) UpdateCourse(ctx *gin.Context) {
-	var course model.Course
+	var req UpdateCourseRequest
 
 	// Parse and validate course ID from URL
 	courseIdParam := ctx.Param("id")
@@ -66,16 +80,29 @@ func (c *CourseController) UpdateCourse(ctx *gin.Context) {
 		return
 	}
 
-	// Bind JSON to the course struct, which now includes CoverImageURL as []string
-	if err := ctx.ShouldBindJSON(&course); err != nil {
+	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	course.ID = courseID
+	course := req.toModel(courseID)
+
+	actor, _ := currentActor(ctx)
+	if err := c.policy.AuthorizeCourse(actor, courseID); err != nil {
+		respondAccessError(ctx, err)
+		return
+	}
 
 	// Call the service with the bound struct's CoverImageURL slice and instructorID
-	if err := c.courseService.UpdateCourse(&course); err != nil {
+	if err := c.courseService.UpdateCourse(course); err != nil {
+		if isMoneyError(err) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrCourseInReview) || errors.Is(err, service.ErrCourseLive) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -94,6 +121,12 @@ func (c *CourseController) DeleteCourse(ctx *gin.Context) {
 		return
 	}
 
+	actor, _ := currentActor(ctx)
+	if err := c.policy.AuthorizeCourse(actor, courseID); err != nil {
+		respondAccessError(ctx, err)
+		return
+	}
+
 	// Call service to delete course
 	if err := c.courseService.DeleteCourse(courseID); err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -114,26 +147,260 @@ func (c *CourseController) GetCourseByID(ctx *gin.Context) {
 		return
 	}
 
-	// Call service to get course
-	course, err := c.courseService.GetCourseByID(courseID)
+	// Anonymous visitors get a zero actor and only see published courses
+	actor, _ := currentActor(ctx)
+	course, err := c.courseService.GetVisibleCourse(actor, courseID)
 	if err != nil {
+		if errors.Is(err, service.ErrCourseNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, course)
+	ctx.JSON(http.StatusOK, newCourseResponse(course))
 
 }
 
-// getall courses
+// GetAllCourses lists courses a page at a time.
+// Filters: ?status=, ?influencer_id=, ?currency= (courses sold in it), ?min_price= and ?max_price= (decimal, in ?currency=,
+// default USD), ?category= (a slug, subcategories included), ?tag=; sort: created_at (default, newest first), price, title
 func (c *CourseController) GetAllCourses(ctx *gin.Context) {
-	// Call service to get course
-	courses, err := c.courseService.GetAllCourses()
+	page, err := bindPageRequest(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var filter model.CourseFilter
+	var statusErr, influencerErr, currencyErr, minErr, maxErr error
+	filter.Status, statusErr = queryOneOf(ctx, "status", model.CourseStatusDraft, model.CourseStatusInReview, model.CourseStatusPublished, model.CourseStatusArchived)
+	filter.InfluencerID, influencerErr = queryUUID(ctx, "influencer_id")
+	filter.Currency, currencyErr = queryCurrency(ctx, "currency")
+	if filter.Currency == "" && (ctx.Query("min_price") != "" || ctx.Query("max_price") != "") {
+		filter.Currency = model.DefaultCurrency
+	}
+	filter.MinPrice, minErr = queryMoney(ctx, "min_price", filter.Currency)
+	filter.MaxPrice, maxErr = queryMoney(ctx, "max_price", filter.Currency)
+	filter.Category = ctx.Query("category")
+	filter.Tag = strings.ToLower(strings.TrimSpace(ctx.Query("tag")))
+	if err := firstError(statusErr, influencerErr, currencyErr, minErr, maxErr); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Anonymous visitors get a zero actor and only see published courses
+	actor, _ := currentActor(ctx)
+	courses, err := c.courseService.ListVisibleCourses(actor, filter, page)
+	if err != nil {
+		respondListError(ctx, err)
 		return
 	}
 	// respond success
-	ctx.JSON(http.StatusOK, courses)
+	ctx.JSON(http.StatusOK, newPageResponse(courses, newCourseResponses))
+
+}
+
+// SearchCourses runs a full-text search, most relevant first.
+// Query: ?q= (required); filters: ?price= (a price facet value), ?min_rating= (1-5); ?limit= and ?cursor= page as usual
+func (c *CourseController) SearchCourses(ctx *gin.Context) {
+	page, err := bindPageRequest(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	search := model.CourseSearch{Query: ctx.Query("q")}
+	var priceErr, ratingErr error
+	search.PriceBucket, priceErr = queryOneOf(ctx, "price",
+		model.PriceBucketFree, model.PriceBucketUnder20, model.PriceBucket20To50, model.PriceBucket50To100, model.PriceBucketOver100)
+	search.MinRating, ratingErr = queryFloat(ctx, "min_rating")
+	if ratingErr == nil && search.MinRating != nil && (*search.MinRating < 1 || *search.MinRating > 5) {
+		ratingErr = errors.New("min_rating must be between 1 and 5")
+	}
+	if err := firstError(priceErr, ratingErr); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
+	// Anonymous visitors get a zero actor and only find published courses
+	actor, _ := currentActor(ctx)
+	result, err := c.courseService.SearchCourses(actor, search, page)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidSearchQuery) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		respondListError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newCourseSearchResponse(result))
+}
+
+// SetCourseTaxonomy replaces a course's category and tags
+func (c *CourseController) SetCourseTaxonomy(ctx *gin.Context) {
+	courseID, err := uuid.FromString(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	var req SetCourseTaxonomyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	actor, _ := currentActor(ctx)
+	if err := c.policy.AuthorizeCourse(actor, courseID); err != nil {
+		respondAccessError(ctx, err)
+		return
+	}
+
+	course, err := c.courseService.SetCourseTaxonomy(courseID, req.CategoryID, req.Tags)
+	if err != nil {
+		respondCatalogError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newCourseResponse(course))
+}
+
+// SubmitCourse sends a draft to the review queue
+func (c *CourseController) SubmitCourse(ctx *gin.Context) {
+	c.changeCourseStatus(ctx, c.courseService.SubmitCourse)
+}
+
+// WithdrawCourse takes a course out of review and back to draft
+func (c *CourseController) WithdrawCourse(ctx *gin.Context) {
+	c.changeCourseStatus(ctx, c.courseService.WithdrawCourse)
+}
+
+// ArchiveCourse takes a published course off the catalog
+func (c *CourseController) ArchiveCourse(ctx *gin.Context) {
+	c.changeCourseStatus(ctx, c.courseService.ArchiveCourse)
+}
+
+// RestoreCourse brings an archived course back as a draft
+func (c *CourseController) RestoreCourse(ctx *gin.Context) {
+	c.changeCourseStatus(ctx, c.courseService.RestoreCourse)
+}
+
+// changeCourseStatus runs a workflow step on the course in the URL after checking the caller may manage it
+func (c *CourseController) changeCourseStatus(ctx *gin.Context, step func(courseID uuid.UUID) (*model.Course, error)) {
+	courseID, err := uuid.FromString(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	actor, _ := currentActor(ctx)
+	if err := c.policy.AuthorizeCourse(actor, courseID); err != nil {
+		respondAccessError(ctx, err)
+		return
+	}
+
+	course, err := step(courseID)
+	if err != nil {
+		respondPublishingError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newCourseResponse(course))
+}
+
+// ScheduleCourse sets when an approved course goes live and when a published one is archived
+func (c *CourseController) ScheduleCourse(ctx *gin.Context) {
+	courseID, err := uuid.FromString(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	var req ScheduleCourseRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	actor, _ := currentActor(ctx)
+	if err := c.policy.AuthorizeCourse(actor, courseID); err != nil {
+		respondAccessError(ctx, err)
+		return
+	}
+
+	course, err := c.courseService.ScheduleCourse(courseID, req.PublishAt, req.UnpublishAt)
+	if err != nil {
+		respondPublishingError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newCourseResponse(course))
+}
+
+// GetReviewQueue lists the courses awaiting review; sort: submitted_at (default, oldest first), created_at, price, title
+func (c *CourseController) GetReviewQueue(ctx *gin.Context) {
+	page, err := bindPageRequest(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	courses, err := c.courseService.ListReviewQueue(page)
+	if err != nil {
+		respondListError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newPageResponse(courses, newCourseResponses))
+}
+
+// ApproveCourse publishes a course in review, or queues it for its publish_at
+func (c *CourseController) ApproveCourse(ctx *gin.Context) {
+	c.reviewCourse(ctx, true, "")
+}
+
+// RejectCourse sends a course in review back to draft with a reason for the influencer
+func (c *CourseController) RejectCourse(ctx *gin.Context) {
+	var req RejectCourseRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.reviewCourse(ctx, false, req.Reason)
+}
+
+func (c *CourseController) reviewCourse(ctx *gin.Context, approve bool, reason string) {
+	courseID, err := uuid.FromString(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	actor, _ := currentActor(ctx)
+	course, err := c.courseService.ReviewCourse(actor, courseID, approve, reason)
+	if err != nil {
+		respondPublishingError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newCourseResponse(course))
+}
+
+func respondPublishingError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrCourseNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidSchedule), errors.Is(err, service.ErrRejectionReasonRequired):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrCourseHasNoLessons), errors.Is(err, service.ErrCourseHasNoCover):
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidCourseTransition), errors.Is(err, service.ErrCourseNotInReview):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
 }
@@ -0,0 +1,224 @@
+package controller
+
+import (
+	"html"
+	"kaabe-app/internal/domain/model"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// CreateCourseRequest is the body of POST /courses
+type CreateCourseRequest struct {
+	InfluencerID  uuid.UUID    `json:"influencer_id"` // honoured for admins only
+	Title         string       `json:"title" binding:"required"`
+	Description   string       `json:"description"`
+	Price         MoneyRequest `json:"price"` // the base price
+	CoverImageURL []string     `json:"cover_image_url"`
+}
+
+// UpdateCourseRequest is the body of PUT /courses/:id; the status changes through the publishing endpoints
+type UpdateCourseRequest struct {
+	Title         string       `json:"title" binding:"required"`
+	Description   string       `json:"description"`
+	Price         MoneyRequest `json:"price"` // the base price; prices in other currencies are kept
+	CoverImageURL []string     `json:"cover_image_url"`
+}
+
+// ScheduleCourseRequest is the body of PUT /courses/:id/schedule; null clears a time
+type ScheduleCourseRequest struct {
+	PublishAt   *time.Time `json:"publish_at"`
+	UnpublishAt *time.Time `json:"unpublish_at"`
+}
+
+// CoursePriceRequest is the body of PUT /courses/:id/prices/:currency; amount is in minor units
+type CoursePriceRequest struct {
+	Amount int64 `json:"amount" binding:"gte=0"`
+}
+
+// CoursePriceListResponse is the body of GET /courses/:id/prices
+type CoursePriceListResponse struct {
+	Base   MoneyResponse   `json:"base"`
+	Prices []MoneyResponse `json:"prices"`
+}
+
+// CoursePriceChangeResponse is one entry of a course's price history; amount is null from the
+// time the course stopped being sold in the currency
+type CoursePriceChangeResponse struct {
+	Currency  string    `json:"currency"`
+	Amount    *int64    `json:"amount"`
+	Display   string    `json:"display,omitempty"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+func newCoursePriceListResponse(course *model.Course) CoursePriceListResponse {
+	return CoursePriceListResponse{Base: newMoneyResponse(course.Price), Prices: newMoneyResponses(course.Prices)}
+}
+
+func newCoursePriceChangeResponses(changes []*model.CoursePriceChange) []CoursePriceChangeResponse {
+	responses := make([]CoursePriceChangeResponse, 0, len(changes))
+	for _, change := range changes {
+		response := CoursePriceChangeResponse{Currency: change.Currency, Amount: change.Amount, ChangedAt: change.ChangedAt}
+		if change.Amount != nil {
+			response.Display = model.Money{Amount: *change.Amount, Currency: change.Currency}.Decimal()
+		}
+		responses = append(responses, response)
+	}
+	return responses
+}
+
+// RejectCourseRequest is the body of POST /courses/:id/reject
+type RejectCourseRequest struct {
+	Reason string `json:"reason" binding:"required,max=2000"`
+}
+
+// CourseResponse is the public view of a course
+type CourseResponse struct {
+	ID              uuid.UUID       `json:"id"`
+	InfluencerID    uuid.UUID       `json:"influencer_id"`
+	Title           string          `json:"title"`
+	Description     string          `json:"description"`
+	Price           MoneyResponse   `json:"price"`  // base price
+	Prices          []MoneyResponse `json:"prices"` // prices in other currencies
+	CoverImageURL   []string        `json:"cover_image_url"`
+	Status          string          `json:"status"`
+	CategoryID      *uuid.UUID      `json:"category_id"`
+	Tags            []string        `json:"tags"`
+	Approved        bool            `json:"approved"` // in review, approved and waiting for publish_at
+	SubmittedAt     *time.Time      `json:"submitted_at,omitempty"`
+	ReviewedAt      *time.Time      `json:"reviewed_at,omitempty"`
+	RejectionReason string          `json:"rejection_reason,omitempty"`
+	PublishAt       *time.Time      `json:"publish_at,omitempty"`
+	UnpublishAt     *time.Time      `json:"unpublish_at,omitempty"`
+	PublishedAt     *time.Time      `json:"published_at,omitempty"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+}
+
+func (r UpdateCourseRequest) toModel(id uuid.UUID) *model.Course {
+	return &model.Course{
+		ID:            id,
+		Title:         r.Title,
+		Description:   r.Description,
+		Price:         r.Price.toModel(),
+		CoverImageURL: r.CoverImageURL,
+	}
+}
+
+func newCourseResponse(course *model.Course) CourseResponse {
+	tags := course.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return CourseResponse{
+		ID:              course.ID,
+		InfluencerID:    course.InfluencerID,
+		Title:           course.Title,
+		Description:     course.Description,
+		Price:           newMoneyResponse(course.Price),
+		Prices:          newMoneyResponses(course.Prices),
+		CoverImageURL:   course.CoverImageURL,
+		Status:          course.Status,
+		CategoryID:      course.CategoryID,
+		Tags:            tags,
+		Approved:        course.ApprovedAt != nil,
+		SubmittedAt:     course.SubmittedAt,
+		ReviewedAt:      course.ReviewedAt,
+		RejectionReason: course.RejectionReason,
+		PublishAt:       course.PublishAt,
+		UnpublishAt:     course.UnpublishAt,
+		PublishedAt:     course.PublishedAt,
+		CreatedAt:       course.CreatedAt,
+		UpdatedAt:       course.UpdatedAt,
+	}
+}
+
+func newCourseResponses(courses []*model.Course) []CourseResponse {
+	responses := make([]CourseResponse, 0, len(courses))
+	for _, course := range courses {
+		responses = append(responses, newCourseResponse(course))
+	}
+	return responses
+}
+
+// CourseSearchResponse is the body of GET /courses/search. Fuzzy is true when no course
+// contained the searched words and the hits are titles spelled similarly instead.
+type CourseSearchResponse struct {
+	Data       []CourseSearchHitResponse  `json:"data"`
+	NextCursor string                     `json:"next_cursor,omitempty"`
+	HasMore    bool                       `json:"has_more"`
+	Fuzzy      bool                       `json:"fuzzy"`
+	Facets     CourseSearchFacetsResponse `json:"facets"`
+}
+
+// CourseSearchHitResponse is one matched course. Highlights are HTML-escaped text with the
+// matched words wrapped in <mark>.
+type CourseSearchHitResponse struct {
+	Course        CourseResponse           `json:"course"`
+	Relevance     float64                  `json:"relevance"`
+	Highlights    SearchHighlightsResponse `json:"highlights"`
+	AverageRating float64                  `json:"average_rating"`
+	RatingCount   int                      `json:"rating_count"`
+}
+
+// SearchHighlightsResponse holds the highlighted title and description excerpt
+type SearchHighlightsResponse struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// CourseSearchFacetsResponse counts matches per facet value. A price value can be passed back as
+// ?price=; a rating value "N_up" counts courses rated N or better and maps to ?min_rating=N.
+type CourseSearchFacetsResponse struct {
+	Price  []FacetBucketResponse `json:"price"`
+	Rating []FacetBucketResponse `json:"rating"`
+}
+
+// FacetBucketResponse is one facet value and its match count
+type FacetBucketResponse struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+func newCourseSearchResponse(result *model.CourseSearchResult) CourseSearchResponse {
+	hits := make([]CourseSearchHitResponse, 0, len(result.Hits.Items))
+	for _, hit := range result.Hits.Items {
+		hits = append(hits, CourseSearchHitResponse{
+			Course:    newCourseResponse(hit.Course),
+			Relevance: hit.Relevance,
+			Highlights: SearchHighlightsResponse{
+				Title:       highlightHTML(hit.TitleHighlight),
+				Description: highlightHTML(hit.DescriptionHighlight),
+			},
+			AverageRating: hit.AverageRating,
+			RatingCount:   hit.RatingCount,
+		})
+	}
+
+	return CourseSearchResponse{
+		Data:       hits,
+		NextCursor: result.Hits.NextCursor,
+		HasMore:    result.Hits.HasMore(),
+		Fuzzy:      result.Fuzzy,
+		Facets: CourseSearchFacetsResponse{
+			Price:  newFacetBucketResponses(result.Facets[model.SearchFacetPrice]),
+			Rating: newFacetBucketResponses(result.Facets[model.SearchFacetRating]),
+		},
+	}
+}
+
+func newFacetBucketResponses(buckets []model.FacetBucket) []FacetBucketResponse {
+	responses := make([]FacetBucketResponse, 0, len(buckets))
+	for _, bucket := range buckets {
+		responses = append(responses, FacetBucketResponse{Value: bucket.Value, Count: bucket.Count})
+	}
+	return responses
+}
+
+// highlightHTML escapes course text and turns the search highlight markers into <mark> tags,
+// so markup typed into a title cannot reach the page
+func highlightHTML(text string) string {
+	return strings.NewReplacer(model.HighlightStart, "<mark>", model.HighlightEnd, "</mark>").
+		Replace(html.EscapeString(text))
+}
@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"errors"
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+// GetCoursePrices returns a course's base price and its prices in other currencies;
+// like the course itself, it is public once the course is published
+func (c *CourseController) GetCoursePrices(ctx *gin.Context) {
+	courseID, err := uuid.FromString(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	actor, _ := currentActor(ctx)
+	course, err := c.courseService.GetVisibleCourse(actor, courseID)
+	if err != nil {
+		respondPricingError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newCoursePriceListResponse(course))
+}
+
+// SetCoursePrice sets the course's price in the currency in the URL: its base price if that is the
+// base currency, otherwise an entry of its price list
+func (c *CourseController) SetCoursePrice(ctx *gin.Context) {
+	courseID, err := uuid.FromString(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	var req CoursePriceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	actor, _ := currentActor(ctx)
+	if err := c.policy.AuthorizeCourse(actor, courseID); err != nil {
+		respondAccessError(ctx, err)
+		return
+	}
+
+	course, err := c.courseService.SetCoursePrice(courseID, model.Money{Amount: req.Amount, Currency: ctx.Param("currency")})
+	if err != nil {
+		respondPricingError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newCoursePriceListResponse(course))
+}
+
+// DeleteCoursePrice stops selling the course in the currency in the URL; the base price cannot be removed
+func (c *CourseController) DeleteCoursePrice(ctx *gin.Context) {
+	courseID, err := uuid.FromString(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	actor, _ := currentActor(ctx)
+	if err := c.policy.AuthorizeCourse(actor, courseID); err != nil {
+		respondAccessError(ctx, err)
+		return
+	}
+
+	course, err := c.courseService.DeleteCoursePrice(courseID, ctx.Param("currency"))
+	if err != nil {
+		respondPricingError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newCoursePriceListResponse(course))
+}
+
+// GetCoursePriceHistory lists a course's price changes, newest first.
+// Filters: ?currency=; sort: changed_at (default, newest first)
+func (c *CourseController) GetCoursePriceHistory(ctx *gin.Context) {
+	courseID, err := uuid.FromString(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	page, err := bindPageRequest(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	currency, err := queryCurrency(ctx, "currency")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	actor, _ := currentActor(ctx)
+	if err := c.policy.AuthorizeCourse(actor, courseID); err != nil {
+		respondAccessError(ctx, err)
+		return
+	}
+
+	history, err := c.courseService.ListCoursePriceHistory(courseID, currency, page)
+	if err != nil {
+		if errors.Is(err, service.ErrCourseNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		respondListError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newPageResponse(history, newCoursePriceChangeResponses))
+}
+
+func respondPricingError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrCourseNotFound), errors.Is(err, service.ErrPriceNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case isMoneyError(err):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrBasePriceRequired):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,210 @@
+package controller
+
+import (
+	"errors"
+	"kaabe-app/internal/domain/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+// CourseRevisionController serves the draft revision and version history of published courses
+type CourseRevisionController struct {
+	courseService service.CourseService
+	policy        service.AccessPolicy
+}
+
+// NewCourseRevisionController creates a new CourseRevisionController instance
+func NewCourseRevisionController(courseService service.CourseService, policy service.AccessPolicy) *CourseRevisionController {
+	return &CourseRevisionController{courseService: courseService, policy: policy}
+}
+
+// StartDraft opens a draft copy of a published course, or returns the one already open
+func (rc *CourseRevisionController) StartDraft(c *gin.Context) {
+	courseID, actor, ok := rc.authorizeCourse(c)
+	if !ok {
+		return
+	}
+
+	draft, err := rc.courseService.StartCourseDraft(actor, courseID)
+	if err != nil {
+		respondRevisionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCourseRevisionResponse(draft))
+}
+
+// GetDraft returns the draft for preview
+func (rc *CourseRevisionController) GetDraft(c *gin.Context) {
+	courseID, _, ok := rc.authorizeCourse(c)
+	if !ok {
+		return
+	}
+
+	draft, err := rc.courseService.GetCourseDraft(courseID)
+	if err != nil {
+		respondRevisionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCourseRevisionResponse(draft))
+}
+
+// SaveDraft replaces the draft's content and lessons; nothing changes for subscribers until it is published
+func (rc *CourseRevisionController) SaveDraft(c *gin.Context) {
+	courseID, _, ok := rc.authorizeCourse(c)
+	if !ok {
+		return
+	}
+
+	var req CourseDraftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	draft, err := rc.courseService.SaveCourseDraft(req.toModel(courseID))
+	if err != nil {
+		respondRevisionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCourseRevisionResponse(draft))
+}
+
+// DiscardDraft throws the draft away
+func (rc *CourseRevisionController) DiscardDraft(c *gin.Context) {
+	courseID, _, ok := rc.authorizeCourse(c)
+	if !ok {
+		return
+	}
+
+	if err := rc.courseService.DiscardCourseDraft(courseID); err != nil {
+		respondRevisionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Draft discarded successfully"})
+}
+
+// PublishDraft puts the draft live in one step once a reviewer has checked it, and returns the
+// version it became
+func (rc *CourseRevisionController) PublishDraft(c *gin.Context) {
+	courseID, actor, ok := rc.authorizeCourse(c)
+	if !ok {
+		return
+	}
+
+	revision, err := rc.courseService.PublishCourseDraft(actor, courseID)
+	if err != nil {
+		respondRevisionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCourseRevisionResponse(revision))
+}
+
+// ListRevisions returns the course's published versions; sort: version (default, newest first)
+func (rc *CourseRevisionController) ListRevisions(c *gin.Context) {
+	courseID, _, ok := rc.authorizeCourse(c)
+	if !ok {
+		return
+	}
+
+	page, err := bindPageRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	revisions, err := rc.courseService.ListCourseRevisions(courseID, page)
+	if err != nil {
+		respondListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newPageResponse(revisions, newCourseRevisionResponses))
+}
+
+// GetRevision returns one published version
+func (rc *CourseRevisionController) GetRevision(c *gin.Context) {
+	courseID, _, ok := rc.authorizeCourse(c)
+	if !ok {
+		return
+	}
+	version, ok := versionParam(c)
+	if !ok {
+		return
+	}
+
+	revision, err := rc.courseService.GetCourseRevision(courseID, version)
+	if err != nil {
+		respondRevisionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCourseRevisionResponse(revision))
+}
+
+// RollbackRevision puts an earlier version back live; it is recorded as the newest version
+func (rc *CourseRevisionController) RollbackRevision(c *gin.Context) {
+	courseID, actor, ok := rc.authorizeCourse(c)
+	if !ok {
+		return
+	}
+	version, ok := versionParam(c)
+	if !ok {
+		return
+	}
+
+	revision, err := rc.courseService.RollbackCourse(actor, courseID, version)
+	if err != nil {
+		respondRevisionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCourseRevisionResponse(revision))
+}
+
+// authorizeCourse reads the course ID from the URL and checks the caller owns the course or is an admin
+func (rc *CourseRevisionController) authorizeCourse(c *gin.Context) (uuid.UUID, service.Actor, bool) {
+	courseID, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return uuid.Nil, service.Actor{}, false
+	}
+
+	actor, _ := currentActor(c)
+	if err := rc.policy.AuthorizeCourse(actor, courseID); err != nil {
+		respondAccessError(c, err)
+		return uuid.Nil, service.Actor{}, false
+	}
+	return courseID, actor, true
+}
+
+func versionParam(c *gin.Context) (int, bool) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid version"})
+		return 0, false
+	}
+	return version, true
+}
+
+func respondRevisionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidRevision):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrCourseNotFound), errors.Is(err, service.ErrNoCourseDraft), errors.Is(err, service.ErrRevisionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrCourseNotPublished):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrCourseHasNoLessons), errors.Is(err, service.ErrCourseHasNoCover):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"kaabe-app/internal/domain/model"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// CourseDraftRequest is the body of PUT /courses/:id/draft; it replaces the whole draft, lessons included.
+// Prices are not drafted; they change through PUT /courses/:id/prices/:currency.
+type CourseDraftRequest struct {
+	Title         string                  `json:"title" binding:"required"`
+	Description   string                  `json:"description"`
+	CoverImageURL []string                `json:"cover_image_url"`
+	Lessons       []RevisionLessonRequest `json:"lessons" binding:"max=200,dive"`
+}
+
+// RevisionLessonRequest is one lesson of a draft; id is the live lesson it changes, omitted for a new lesson
+type RevisionLessonRequest struct {
+	ID       *uuid.UUID `json:"id"`
+	Title    string     `json:"title" binding:"required,max=255"`
+	VideoURL []string   `json:"video_url"`
+	Order    int        `json:"order"`
+}
+
+// CourseRevisionResponse is a course's draft (version null) or one of its published versions
+type CourseRevisionResponse struct {
+	ID            uuid.UUID                `json:"id"`
+	CourseID      uuid.UUID                `json:"course_id"`
+	Version       *int                     `json:"version"`
+	Title         string                   `json:"title"`
+	Description   string                   `json:"description"`
+	CoverImageURL []string                 `json:"cover_image_url"`
+	Lessons       []RevisionLessonResponse `json:"lessons"`
+	RestoredFrom  *int                     `json:"restored_from,omitempty"`
+	CreatedBy     *uuid.UUID               `json:"created_by,omitempty"`
+	CreatedAt     time.Time                `json:"created_at"`
+	UpdatedAt     time.Time                `json:"updated_at"`
+	PublishedBy   *uuid.UUID               `json:"published_by,omitempty"`
+	PublishedAt   *time.Time               `json:"published_at,omitempty"`
+}
+
+// RevisionLessonResponse is a lesson as it is in a revision; id is null for a lesson not live yet
+type RevisionLessonResponse struct {
+	ID       *uuid.UUID `json:"id"`
+	Title    string     `json:"title"`
+	VideoURL []string   `json:"video_url"`
+	Order    int        `json:"order"`
+}
+
+func (r CourseDraftRequest) toModel(courseID uuid.UUID) *model.CourseRevision {
+	lessons := make([]model.RevisionLesson, 0, len(r.Lessons))
+	for _, lesson := range r.Lessons {
+		lessons = append(lessons, model.RevisionLesson{
+			ID:       lesson.ID,
+			Title:    lesson.Title,
+			VideoURL: lesson.VideoURL,
+			Order:    lesson.Order,
+		})
+	}
+	return &model.CourseRevision{
+		CourseID:      courseID,
+		Title:         r.Title,
+		Description:   r.Description,
+		CoverImageURL: r.CoverImageURL,
+		Lessons:       lessons,
+	}
+}
+
+func newCourseRevisionResponse(revision *model.CourseRevision) CourseRevisionResponse {
+	lessons := make([]RevisionLessonResponse, 0, len(revision.Lessons))
+	for _, lesson := range revision.Lessons {
+		lessons = append(lessons, RevisionLessonResponse{
+			ID:       lesson.ID,
+			Title:    lesson.Title,
+			VideoURL: lesson.VideoURL,
+			Order:    lesson.Order,
+		})
+	}
+	return CourseRevisionResponse{
+		ID:            revision.ID,
+		CourseID:      revision.CourseID,
+		Version:       revision.Version,
+		Title:         revision.Title,
+		Description:   revision.Description,
+		CoverImageURL: revision.CoverImageURL,
+		Lessons:       lessons,
+		RestoredFrom:  revision.RestoredFrom,
+		CreatedBy:     revision.CreatedBy,
+		CreatedAt:     revision.CreatedAt,
+		UpdatedAt:     revision.UpdatedAt,
+		PublishedBy:   revision.PublishedBy,
+		PublishedAt:   revision.PublishedAt,
+	}
+}
+
+func newCourseRevisionResponses(revisions []*model.CourseRevision) []CourseRevisionResponse {
+	responses := make([]CourseRevisionResponse, 0, len(revisions))
+	for _, revision := range revisions {
+		responses = append(responses, newCourseRevisionResponse(revision))
+	}
+	return responses
+}
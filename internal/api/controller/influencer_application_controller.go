@@ -0,0 +1,156 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/service"
+	utils "kaabe-app/pkg/config"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+type InfluencerApplicationController struct {
+	applicationService service.InfluencerApplicationService
+}
+
+func NewInfluencerApplicationController(applicationService service.InfluencerApplicationService) *InfluencerApplicationController {
+	return &InfluencerApplicationController{applicationService: applicationService}
+}
+
+// Apply submits the current user's application to become an influencer
+func (ic *InfluencerApplicationController) Apply(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID is required"})
+		return
+	}
+
+	var req ApplyInfluencerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	application, err := ic.applicationService.Apply(userID, req.Bio, req.PayoutWallet, req.IDDocumentURLs)
+	if err != nil {
+		respondApplicationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newInfluencerApplicationResponse(application))
+}
+
+// GetOwnApplication returns the current user's latest application and its review status
+func (ic *InfluencerApplicationController) GetOwnApplication(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID is required"})
+		return
+	}
+
+	application, err := ic.applicationService.GetOwnApplication(userID)
+	if err != nil {
+		respondApplicationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newInfluencerApplicationResponse(application))
+}
+
+// ListApplications returns the review queue; ?status=pending|approved|rejected, pending by default
+func (ic *InfluencerApplicationController) ListApplications(c *gin.Context) {
+	status := c.DefaultQuery("status", model.ApplicationPending)
+	switch status {
+	case model.ApplicationPending, model.ApplicationApproved, model.ApplicationRejected:
+	case "all":
+		status = ""
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be pending, approved, rejected or all"})
+		return
+	}
+
+	applications, err := ic.applicationService.ListApplications(status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newInfluencerApplicationResponses(applications))
+}
+
+// GetApplication returns one application for review
+func (ic *InfluencerApplicationController) GetApplication(c *gin.Context) {
+	applicationID, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid application ID"})
+		return
+	}
+
+	application, err := ic.applicationService.GetApplication(applicationID)
+	if err != nil {
+		respondApplicationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newInfluencerApplicationResponse(application))
+}
+
+// ApproveApplication grants the applicant the influencer role
+func (ic *InfluencerApplicationController) ApproveApplication(c *gin.Context) {
+	ic.review(c, ic.applicationService.Approve)
+}
+
+// RejectApplication turns the application down; a note explaining why is required
+func (ic *InfluencerApplicationController) RejectApplication(c *gin.Context) {
+	ic.review(c, ic.applicationService.Reject)
+}
+
+func (ic *InfluencerApplicationController) review(c *gin.Context, decide func(applicationID, reviewerID uuid.UUID, note string) (*model.InfluencerApplication, error)) {
+	reviewerID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID is required"})
+		return
+	}
+
+	applicationID, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid application ID"})
+		return
+	}
+
+	// The note is optional on approval, so an empty body is accepted
+	var req ReviewApplicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	application, err := decide(applicationID, reviewerID, req.Note)
+	if err != nil {
+		respondApplicationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newInfluencerApplicationResponse(application))
+}
+
+// respondApplicationError maps InfluencerApplicationService errors to HTTP responses
+func respondApplicationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, utils.ErrInvalidPhoneNumber), errors.Is(err, service.ErrReviewNoteRequired):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrEmailNotVerified):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrAlreadyInfluencer),
+		errors.Is(err, service.ErrApplicationPending),
+		errors.Is(err, service.ErrApplicationReviewed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrApplicationNotFound), err.Error() == "user not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
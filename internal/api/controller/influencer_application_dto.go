@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"kaabe-app/internal/domain/model"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// ApplyInfluencerRequest is the body of POST /influencer-applications
+type ApplyInfluencerRequest struct {
+	Bio            string   `json:"bio" binding:"required,max=2000"`
+	PayoutWallet   string   `json:"payout_wallet" binding:"required"` // mobile money number withdrawals are paid to
+	IDDocumentURLs []string `json:"id_document_urls" binding:"required,min=1,dive,url"`
+}
+
+// ReviewApplicationRequest is the body of the approve and reject endpoints
+type ReviewApplicationRequest struct {
+	Note string `json:"note" binding:"max=2000"`
+}
+
+// InfluencerApplicationResponse is the public view of an influencer application
+type InfluencerApplicationResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	UserID         uuid.UUID  `json:"user_id"`
+	Bio            string     `json:"bio"`
+	PayoutWallet   string     `json:"payout_wallet,omitempty"`
+	IDDocumentURLs []string   `json:"id_document_urls"`
+	Status         string     `json:"status"`
+	ReviewNote     string     `json:"review_note,omitempty"`
+	ReviewedBy     *uuid.UUID `json:"reviewed_by,omitempty"`
+	ReviewedAt     *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+func newInfluencerApplicationResponse(application *model.InfluencerApplication) InfluencerApplicationResponse {
+	documents := application.IDDocumentURLs
+	if documents == nil {
+		documents = []string{}
+	}
+	return InfluencerApplicationResponse{
+		ID:             application.ID,
+		UserID:         application.UserID,
+		Bio:            application.Bio,
+		PayoutWallet:   application.PayoutWallet,
+		IDDocumentURLs: documents,
+		Status:         application.Status,
+		ReviewNote:     application.ReviewNote,
+		ReviewedBy:     application.ReviewedBy,
+		ReviewedAt:     application.ReviewedAt,
+		CreatedAt:      application.CreatedAt,
+		UpdatedAt:      application.UpdatedAt,
+	}
+}
+
+func newInfluencerApplicationResponses(applications []*model.InfluencerApplication) []InfluencerApplicationResponse {
+	responses := make([]InfluencerApplicationResponse, 0, len(applications))
+	for _, application := range applications {
+		responses = append(responses, newInfluencerApplicationResponse(application))
+	}
+	return responses
+}
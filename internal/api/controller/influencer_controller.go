@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"errors"
+	"kaabe-app/internal/domain/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+type InfluencerController struct {
+	influencerService service.InfluencerService
+}
+
+func NewInfluencerController(influencerService service.InfluencerService) *InfluencerController {
+	return &InfluencerController{influencerService: influencerService}
+}
+
+// GetProfile returns an influencer's public profile page: profile, stats and published courses
+func (ic *InfluencerController) GetProfile(c *gin.Context) {
+	influencerID, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid influencer ID"})
+		return
+	}
+
+	profile, courses, err := ic.influencerService.GetPublicProfile(influencerID)
+	if err != nil {
+		respondInfluencerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newPublicInfluencerResponse(profile, courses))
+}
+
+// UpdateOwnProfile replaces the current influencer's public profile
+func (ic *InfluencerController) UpdateOwnProfile(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID is required"})
+		return
+	}
+
+	var req UpdateInfluencerProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	profile, err := ic.influencerService.UpdateProfile(userID, req.DisplayName, req.AvatarURL, req.Bio, req.SocialLinks)
+	if err != nil {
+		respondInfluencerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newInfluencerProfileResponse(profile))
+}
+
+// Follow makes the current user follow an influencer
+func (ic *InfluencerController) Follow(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID is required"})
+		return
+	}
+
+	influencerID, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid influencer ID"})
+		return
+	}
+
+	if err := ic.influencerService.Follow(userID, influencerID); err != nil {
+		respondInfluencerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Influencer followed"})
+}
+
+// Unfollow stops the current user following an influencer
+func (ic *InfluencerController) Unfollow(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID is required"})
+		return
+	}
+
+	influencerID, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid influencer ID"})
+		return
+	}
+
+	if err := ic.influencerService.Unfollow(userID, influencerID); err != nil {
+		respondInfluencerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Influencer unfollowed"})
+}
+
+// ListFollowing returns the influencers the current user follows
+func (ic *InfluencerController) ListFollowing(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID is required"})
+		return
+	}
+
+	profiles, err := ic.influencerService.ListFollowing(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newInfluencerProfileResponses(profiles))
+}
+
+// respondInfluencerError maps InfluencerService errors to HTTP responses
+func respondInfluencerError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrCannotFollowSelf), errors.Is(err, service.ErrInvalidProfileLink):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInfluencerNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
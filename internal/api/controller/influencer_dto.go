@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"kaabe-app/internal/domain/model"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// UpdateInfluencerProfileRequest is the body of PUT /influencers/me
+type UpdateInfluencerProfileRequest struct {
+	DisplayName string            `json:"display_name" binding:"required,max=100"`
+	AvatarURL   string            `json:"avatar_url" binding:"omitempty,url"`
+	Bio         string            `json:"bio" binding:"max=2000"`
+	SocialLinks map[string]string `json:"social_links" binding:"max=10,dive,keys,min=1,max=30,endkeys,url"` // e.g. {"instagram": "https://..."}
+}
+
+// InfluencerProfileResponse is the public view of an influencer
+type InfluencerProfileResponse struct {
+	ID          uuid.UUID                `json:"id"`
+	DisplayName string                   `json:"display_name"`
+	AvatarURL   string                   `json:"avatar_url,omitempty"`
+	Bio         string                   `json:"bio"`
+	SocialLinks map[string]string        `json:"social_links"`
+	Stats       *InfluencerStatsResponse `json:"stats,omitempty"`
+	Courses     []CourseResponse         `json:"courses,omitempty"`
+	UpdatedAt   time.Time                `json:"updated_at"`
+}
+
+// InfluencerStatsResponse aggregates an influencer's catalog and audience
+type InfluencerStatsResponse struct {
+	Courses       int     `json:"courses"`
+	Students      int     `json:"students"`
+	Ratings       int     `json:"ratings"`
+	AverageRating float64 `json:"average_rating"`
+	Followers     int     `json:"followers"`
+}
+
+func newInfluencerProfileResponse(profile *model.InfluencerProfile) InfluencerProfileResponse {
+	response := InfluencerProfileResponse{
+		ID:          profile.UserID,
+		DisplayName: profile.DisplayName,
+		AvatarURL:   profile.AvatarURL,
+		Bio:         profile.Bio,
+		SocialLinks: profile.SocialLinks,
+		UpdatedAt:   profile.UpdatedAt,
+	}
+	if response.SocialLinks == nil {
+		response.SocialLinks = map[string]string{}
+	}
+	if profile.Stats != nil {
+		response.Stats = &InfluencerStatsResponse{
+			Courses:       profile.Stats.CourseCount,
+			Students:      profile.Stats.StudentCount,
+			Ratings:       profile.Stats.RatingCount,
+			AverageRating: profile.Stats.AverageRating,
+			Followers:     profile.Stats.FollowerCount,
+		}
+	}
+	return response
+}
+
+// newPublicInfluencerResponse is the profile page: the profile, its stats and the published catalog
+func newPublicInfluencerResponse(profile *model.InfluencerProfile, courses []*model.Course) InfluencerProfileResponse {
+	response := newInfluencerProfileResponse(profile)
+	response.Courses = newCourseResponses(courses)
+	return response
+}
+
+func newInfluencerProfileResponses(profiles []*model.InfluencerProfile) []InfluencerProfileResponse {
+	responses := make([]InfluencerProfileResponse, 0, len(profiles))
+	for _, profile := range profiles {
+		responses = append(responses, newInfluencerProfileResponse(profile))
+	}
+	return responses
+}
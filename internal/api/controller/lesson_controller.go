@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"kaabe-app/internal/domain/model"
 	"kaabe-app/internal/domain/service"
 	"net/http"
@@ -13,23 +14,30 @@ import (
 // LessonController struct that defines the lesson controller with its service
 type LessonController struct {
 	LessonService service.LessonService
+	policy        service.AccessPolicy
 }
 
 // NewLessonController creates a new LessonController instance
-func NewLessonController(lessonService service.LessonService) *LessonController {
-	return &LessonController{LessonService: lessonService}
+func NewLessonController(lessonService service.LessonService, policy service.AccessPolicy) *LessonController {
+	return &LessonController{LessonService: lessonService, policy: policy}
 }
 
 // CreateLesson handles the creation of a new lesson
 func (l *LessonController) CreateLesson(ctx *gin.Context) {
-	var lesson model.Lesson
+	var lesson CreateLessonRequest
 
-	// Bind JSON to the lesson struct, which now includes VideoUrl as []string
 	if err := ctx.ShouldBindJSON(&lesson); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	// Only the course owner (or an admin) may add lessons to it
+	actor, _ := currentActor(ctx)
+	if err := l.policy.AuthorizeCourse(actor, lesson.CourseID); err != nil {
+		respondAccessError(ctx, err)
+		return
+	}
+
 	// Call the service with the bound struct's videoUrl slice and lessonID
 	createdLesson, err := l.LessonService.CreateLesson(
 		lesson.CourseID,
@@ -39,17 +47,17 @@ func (l *LessonController) CreateLesson(ctx *gin.Context) {
 	)
 
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondLessonWriteError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, createdLesson)
+	ctx.JSON(http.StatusOK, newLessonResponse(createdLesson))
 
 }
 
 // UpdateLesson handles the update of an existing lesson
 func (l *LessonController) UpdateLesson(ctx *gin.Context) {
-	var lesson model.Lesson
+	var req UpdateLessonRequest
 
 	lessonIdParam := ctx.Param("id")
 	lessonID, err := uuid.FromString(lessonIdParam)
@@ -58,17 +66,22 @@ func (l *LessonController) UpdateLesson(ctx *gin.Context) {
 		return
 	}
 
-	// Bind JSON to the lesson struct, which now includes videoUrl as []string
-	if err := ctx.ShouldBindJSON(&lesson); err != nil {
+	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	lesson.ID = lessonID
+	lesson := req.toModel(lessonID)
+
+	actor, _ := currentActor(ctx)
+	if err := l.policy.AuthorizeLesson(actor, lessonID); err != nil {
+		respondAccessError(ctx, err)
+		return
+	}
 
 	// Call the service with the bound struct's videoUrl slice and lessonID
-	if err := l.LessonService.UpdateLesson(&lesson); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := l.LessonService.UpdateLesson(lesson); err != nil {
+		respondLessonWriteError(ctx, err)
 		return
 	}
 
@@ -83,10 +96,17 @@ func (l *LessonController) DeleteLesson(ctx *gin.Context) {
 	lessonId, err := uuid.FromString(lessonIdParam)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid Lesson ID"})
+		return
+	}
+
+	actor, _ := currentActor(ctx)
+	if err := l.policy.AuthorizeLesson(actor, lessonId); err != nil {
+		respondAccessError(ctx, err)
+		return
 	}
 
 	if err := l.LessonService.DeleteLesson(lessonId); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondLessonWriteError(ctx, err)
 		return
 	}
 
@@ -105,9 +125,10 @@ func (l *LessonController) GetLessonByID(ctx *gin.Context) {
 		return
 	}
 
-	lesson, err := l.LessonService.GetLessonByID(lessonID)
+	actor, _ := currentActor(ctx)
+	lesson, err := l.LessonService.GetVisibleLesson(actor, lessonID)
 	if err != nil {
-		if err.Error() == "lesson not found" {
+		if errors.Is(err, service.ErrLessonNotFound) {
 			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		} else {
 			log.Printf("Unexpected error: %v", err)
@@ -116,18 +137,42 @@ func (l *LessonController) GetLessonByID(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, lesson)
+	ctx.JSON(http.StatusOK, newLessonResponse(lesson))
 }
 
 
-// get all lesson
+// GetAllLessons lists lessons a page at a time; only the owner and admins see lessons of unpublished courses.
+// Filters: ?course_id=; sort: order (default), created_at
 func (l *LessonController) GetAllLessons(ctx *gin.Context) {
+	page, err := bindPageRequest(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var filter model.LessonFilter
+	filter.CourseID, err = queryUUID(ctx, "course_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	// Call service to get lesson
-	lesson, err := l.LessonService.GetAllLessons()
+	actor, _ := currentActor(ctx)
+	lessons, err := l.LessonService.ListVisibleLessons(actor, filter, page)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondListError(ctx, err)
 		return
 	}
 	// respond success
-	ctx.JSON(http.StatusOK, lesson)
+	ctx.JSON(http.StatusOK, newPageResponse(lessons, newLessonResponses))
+}
+
+// respondLessonWriteError maps a failed lesson change to an HTTP response
+func respondLessonWriteError(ctx *gin.Context, err error) {
+	if errors.Is(err, service.ErrCourseLive) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 }
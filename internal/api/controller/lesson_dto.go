@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"kaabe-app/internal/domain/model"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// CreateLessonRequest is the body of POST /lessons
+type CreateLessonRequest struct {
+	CourseID uuid.UUID `json:"course_id" binding:"required"`
+	Title    string    `json:"title" binding:"required"`
+	VideoURL []string  `json:"video_url"`
+	Order    int       `json:"order" binding:"gte=0"`
+}
+
+// UpdateLessonRequest is the body of PUT /lessons/:id
+type UpdateLessonRequest struct {
+	Title    string   `json:"title" binding:"required"`
+	VideoURL []string `json:"video_url"`
+	Order    int      `json:"order" binding:"gte=0"`
+}
+
+// LessonResponse is the public view of a lesson
+type LessonResponse struct {
+	ID        uuid.UUID `json:"id"`
+	CourseID  uuid.UUID `json:"course_id"`
+	Title     string    `json:"title"`
+	VideoURL  []string  `json:"video_url"`
+	Order     int       `json:"order"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (r UpdateLessonRequest) toModel(id uuid.UUID) *model.Lesson {
+	return &model.Lesson{
+		ID:       id,
+		Title:    r.Title,
+		VideoURL: r.VideoURL,
+		Order:    r.Order,
+	}
+}
+
+func newLessonResponse(lesson *model.Lesson) LessonResponse {
+	return LessonResponse{
+		ID:        lesson.ID,
+		CourseID:  lesson.CourseID,
+		Title:     lesson.Title,
+		VideoURL:  lesson.VideoURL,
+		Order:     lesson.Order,
+		CreatedAt: lesson.CreatedAt,
+		UpdatedAt: lesson.UpdatedAt,
+	}
+}
+
+func newLessonResponses(lessons []*model.Lesson) []LessonResponse {
+	responses := make([]LessonResponse, 0, len(lessons))
+	for _, lesson := range lessons {
+		responses = append(responses, newLessonResponse(lesson))
+	}
+	return responses
+}
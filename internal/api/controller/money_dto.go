@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"errors"
+	"kaabe-app/internal/domain/model"
+)
+
+// MoneyRequest is an amount in the currency's minor units, e.g. {"amount": 1999, "currency": "USD"} for 19.99 USD
+type MoneyRequest struct {
+	Amount   int64  `json:"amount" binding:"gte=0"`
+	Currency string `json:"currency" binding:"required,len=3"`
+}
+
+// MoneyResponse is an amount in minor units with its currency; display is the amount in major units
+type MoneyResponse struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+	Display  string `json:"display"`
+}
+
+func (r MoneyRequest) toModel() model.Money {
+	return model.Money{Amount: r.Amount, Currency: r.Currency}
+}
+
+func newMoneyResponse(money model.Money) MoneyResponse {
+	return MoneyResponse{Amount: money.Amount, Currency: money.Currency, Display: money.Decimal()}
+}
+
+func newMoneyResponses(money []model.Money) []MoneyResponse {
+	responses := make([]MoneyResponse, 0, len(money))
+	for _, m := range money {
+		responses = append(responses, newMoneyResponse(m))
+	}
+	return responses
+}
+
+// isMoneyError reports whether err rejects a currency or amount, which is the client's mistake
+func isMoneyError(err error) bool {
+	return errors.Is(err, model.ErrUnsupportedCurrency) || errors.Is(err, model.ErrInvalidAmount)
+}
@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"kaabe-app/internal/domain/model"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+// PageResponse is the envelope of every list endpoint. Pass next_cursor back as ?cursor=
+// to get the following page, keeping the same sort and filters.
+type PageResponse[T any] struct {
+	Data       []T    `json:"data"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+func newPageResponse[S, T any](page *model.Page[S], convert func([]S) []T) PageResponse[T] {
+	return PageResponse[T]{
+		Data:       convert(page.Items),
+		NextCursor: page.NextCursor,
+		HasMore:    page.HasMore(),
+	}
+}
+
+// bindPageRequest reads ?limit=, ?cursor= and ?sort= (a field name, "-" prefixed for descending)
+func bindPageRequest(ctx *gin.Context) (model.PageRequest, error) {
+	page := model.PageRequest{
+		Cursor: ctx.Query("cursor"),
+		Sort:   ctx.Query("sort"),
+	}
+
+	if raw := ctx.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > model.MaxPageLimit {
+			return page, fmt.Errorf("limit must be between 1 and %d", model.MaxPageLimit)
+		}
+		page.Limit = limit
+	}
+
+	return page, nil
+}
+
+// queryUUID reads an optional UUID query parameter
+func queryUUID(ctx *gin.Context, name string) (*uuid.UUID, error) {
+	raw := ctx.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+	id, err := uuid.FromString(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be a UUID", name)
+	}
+	return &id, nil
+}
+
+// queryFloat reads an optional number query parameter
+func queryFloat(ctx *gin.Context, name string) (*float64, error) {
+	raw := ctx.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be a number", name)
+	}
+	return &value, nil
+}
+
+// queryCurrency reads an optional currency code query parameter
+func queryCurrency(ctx *gin.Context, name string) (string, error) {
+	raw := ctx.Query(name)
+	if raw == "" {
+		return "", nil
+	}
+	currency, err := model.NormalizeCurrency(raw)
+	if err != nil {
+		return "", fmt.Errorf("%s must be a supported currency code", name)
+	}
+	return currency, nil
+}
+
+// queryMoney reads an optional decimal amount query parameter, such as 19.99, in the currency's minor units
+func queryMoney(ctx *gin.Context, name, currency string) (*int64, error) {
+	raw := ctx.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+	money, err := model.ParseMoney(raw, currency)
+	if err != nil || money.Amount < 0 {
+		return nil, fmt.Errorf("%s must be an amount in %s", name, currency)
+	}
+	return &money.Amount, nil
+}
+
+// queryTime reads an optional RFC 3339 timestamp or YYYY-MM-DD date query parameter
+func queryTime(ctx *gin.Context, name string) (*time.Time, error) {
+	raw := ctx.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("%s must be an RFC 3339 timestamp or a YYYY-MM-DD date", name)
+}
+
+// queryOneOf reads an optional query parameter restricted to the allowed values
+func queryOneOf(ctx *gin.Context, name string, allowed ...string) (string, error) {
+	raw := ctx.Query(name)
+	if raw == "" {
+		return "", nil
+	}
+	for _, value := range allowed {
+		if raw == value {
+			return raw, nil
+		}
+	}
+	return "", fmt.Errorf("%s must be one of %v", name, allowed)
+}
+
+// firstError returns the first non-nil error, for validating several query parameters at once
+func firstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// respondListError maps a list failure to an HTTP response
+func respondListError(ctx *gin.Context, err error) {
+	if errors.Is(err, model.ErrInvalidCursor) || errors.Is(err, model.ErrInvalidSort) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
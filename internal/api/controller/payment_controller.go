@@ -4,56 +4,93 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
-	"fmt"
+	"errors"
 	"kaabe-app/internal/domain/model"
 	"kaabe-app/internal/domain/service"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gofrs/uuid"
 )
 
+// Payment statuses
+const (
+	paymentPending   = "pending"
+	paymentCompleted = "completed"
+	paymentFailed    = "failed"
+)
+
 type PaymentController struct {
 	paymentService service.PaymentService
+	policy         service.AccessPolicy
+	webhookSecret  string
 	//walletService  service.WalletService
 }
 
-// NewPaymentController creates a new PaymentController instance
-func NewPaymentController(paymentService service.PaymentService) *PaymentController {
-	return &PaymentController{paymentService: paymentService} //walletService:  walletService,
+// NewPaymentController creates a new PaymentController instance; webhookSecret signs the
+// payment provider's webhook, which is refused while it is empty
+func NewPaymentController(paymentService service.PaymentService, policy service.AccessPolicy, webhookSecret string) *PaymentController {
+	return &PaymentController{paymentService: paymentService, policy: policy, webhookSecret: webhookSecret} //walletService:  walletService,
 
 }
 
 // CreatePayment handles the creation of a new payment
 func (p *PaymentController) CreatePayment(ctx *gin.Context) {
-	var payment model.Payment
+	var payment CreatePaymentRequest
 
 	if err := ctx.ShouldBindJSON(&payment); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	// Users pay for themselves; admins may record a payment for someone else
+	actor, exists := currentActor(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user ID is required"})
+		return
+	}
+	if !actor.IsAdmin() || payment.UserID == uuid.Nil {
+		payment.UserID = actor.UserID
+	}
+	// Only the signed webhook and admins settle payments; a user's payment waits for the webhook.
+	// Its reference is generated so users cannot claim one the provider will report for someone else.
+	if !actor.IsAdmin() {
+		payment.Status = paymentPending
+		payment.ExternalRef = ""
+	}
+
 	createdPayment, err := p.paymentService.CreatePayment(
 		payment.ExternalRef,
 		payment.UserID,
 		payment.SubscriptionID,
-		payment.Amount,
+		payment.Amount.toModel(),
+		payment.QuoteID,
 		payment.Status,
 		payment.ProcessedAt,
 	)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if isMoneyError(err) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrEmailNotVerified) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "Please verify your email address before making a purchase"})
+			return
+		}
+		respondQuotePaymentError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, createdPayment)
+	ctx.JSON(http.StatusOK, newPaymentResponse(createdPayment))
 }
 
 // UpdatePayment handles updating an existing payment
 func (p *PaymentController) UpdatePayment(ctx *gin.Context) {
-	var payment model.Payment
+	var req UpdatePaymentRequest
 
 	paymentIDParam := ctx.Param("id")
 	paymentID, err := uuid.FromString(paymentIDParam)
@@ -62,15 +99,19 @@ func (p *PaymentController) UpdatePayment(ctx *gin.Context) {
 		return
 	}
 
-	if err := ctx.ShouldBindJSON(&payment); err != nil {
+	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	payment.ID = paymentID
+	payment := req.toModel(paymentID)
 	payment.UpdatedAt = time.Now()
 
-	if err := p.paymentService.UpdatePayment(&payment); err != nil {
+	if err := p.paymentService.UpdatePayment(payment); err != nil {
+		if isMoneyError(err) || errors.Is(err, service.ErrSubscriptionRequired) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -106,54 +147,106 @@ func (p *PaymentController) GetPaymentByID(ctx *gin.Context) {
 		return
 	}
 
+	actor, _ := currentActor(ctx)
+	if err := p.policy.AuthorizePayment(actor, paymentID); err != nil {
+		respondAccessError(ctx, err)
+		return
+	}
+
 	payment, err := p.paymentService.GetPaymentByID(paymentID)
 	if err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, payment)
+	ctx.JSON(http.StatusOK, newPaymentResponse(payment))
 }
 
-// GetAllPayments retrieves all non-deleted payments
+// GetAllPayments lists non-deleted payments a page at a time; non-admins only see their own.
+// Filters: ?user_id= (admins), ?status=, ?from=, ?to= (created at); sort: created_at (default, newest first), amount
 func (p *PaymentController) GetAllPayments(ctx *gin.Context) {
-	payments, err := p.paymentService.GetAllPayments()
+	page, err := bindPageRequest(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var filter model.PaymentFilter
+	var userErr, statusErr, fromErr, toErr error
+	filter.UserID, userErr = queryUUID(ctx, "user_id")
+	filter.Status, statusErr = queryOneOf(ctx, "status", "pending", "completed", "failed")
+	filter.From, fromErr = queryTime(ctx, "from")
+	filter.To, toErr = queryTime(ctx, "to")
+	if err := firstError(userErr, statusErr, fromErr, toErr); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	actor, _ := currentActor(ctx)
+	if !actor.IsAdmin() {
+		filter.UserID = &actor.UserID
+	}
+
+	payments, err := p.paymentService.GetAllPayments(filter, page)
+	if err != nil {
+		respondListError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, payments)
+	ctx.JSON(http.StatusOK, newPageResponse(payments, newPaymentResponses))
 }
 
 // WaafiPay Webhook
 func (p *PaymentController) HandleWaafiWebhook(c *gin.Context) {
 	var payload struct {
-		ExternalRef    string  `json:"external_ref"`
-		UserID         string  `json:"user_id"`
-		SubscriptionID string  `json:"subscription_id"`
-		Amount         float64 `json:"amount"`
-		Status         string  `json:"status"`
-		ProcessedAt    string  `json:"processed_at"`
-		Signature      string  `json:"signature"`
+		ExternalRef    string `json:"external_ref"`
+		UserID         string `json:"user_id"`
+		SubscriptionID string `json:"subscription_id"` // optional when paying a bundle or pass quote
+		QuoteID        string `json:"quote_id"`        // optional; the price quote the payment pays
+		Amount         int64  `json:"amount"`          // in minor units, e.g. 1999 for 19.99 USD
+		Currency       string `json:"currency"`        // USD if omitted
+		Status         string `json:"status"`
+		ProcessedAt    string `json:"processed_at"`
+		Signature      string `json:"signature"`
 	}
 	if err := c.ShouldBindJSON(&payload); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// ✅ Signature Verification Implementation (example HMAC)
-	expectedSecret := "your-waafi-secret"
-	computedSignature := ComputeHMAC(payload.ExternalRef+payload.UserID+payload.SubscriptionID+fmt.Sprintf("%f", payload.Amount)+payload.Status+payload.ProcessedAt, expectedSecret)
-	if payload.Signature != computedSignature {
+	if p.webhookSecret == "" {
+		log.Printf("Payment webhook refused: no webhook secret is configured")
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Payment webhook is not configured"})
+		return
+	}
+
+	// The signature covers every field, as sent, in this order, one per line
+	fields := []string{
+		payload.ExternalRef,
+		payload.UserID,
+		payload.SubscriptionID,
+		payload.QuoteID,
+		strconv.FormatInt(payload.Amount, 10),
+		payload.Currency,
+		payload.Status,
+		payload.ProcessedAt,
+	}
+	for _, field := range fields {
+		if strings.Contains(field, "\n") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Fields cannot contain line breaks"})
+			return
+		}
+	}
+	computedSignature := ComputeHMAC(strings.Join(fields, "\n"), p.webhookSecret)
+	if !hmac.Equal([]byte(payload.Signature), []byte(computedSignature)) {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid signature"})
 		return
 	}
 
-	// ✅ Duplicate Check
-	existing, err := p.paymentService.GetPaymentByExternalRef(payload.ExternalRef)
-	if err == nil && existing != nil {
-		c.JSON(http.StatusConflict, gin.H{"error": "Duplicate external_ref"})
+	switch payload.Status {
+	case paymentPending, paymentCompleted, paymentFailed:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
 		return
 	}
 
@@ -163,22 +256,78 @@ func (p *PaymentController) HandleWaafiWebhook(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user_id"})
 		return
 	}
-	subID, err := uuid.FromString(payload.SubscriptionID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid subscription_id"})
-		return
+	subID := uuid.Nil
+	if payload.SubscriptionID != "" {
+		subID, err = uuid.FromString(payload.SubscriptionID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid subscription_id"})
+			return
+		}
+	}
+	var quoteID *uuid.UUID
+	if payload.QuoteID != "" {
+		parsed, err := uuid.FromString(payload.QuoteID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid quote_id"})
+			return
+		}
+		quoteID = &parsed
 	}
 	processedAt, err := time.Parse(time.RFC3339, payload.ProcessedAt)
 	if err != nil {
 		processedAt = time.Now()
 	}
+	if payload.Currency == "" {
+		payload.Currency = model.DefaultCurrency
+	}
+	amount, err := model.NewMoney(payload.Amount, payload.Currency)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// A payment the user started is settled with the outcome; its details must match the report
+	existing, err := p.paymentService.GetPaymentByExternalRef(payload.ExternalRef)
+	if err == nil && existing != nil {
+		if existing.UserID != userID || existing.Amount != amount || (quoteID != nil && (existing.QuoteID == nil || *existing.QuoteID != *quoteID)) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Duplicate external_ref"})
+			return
+		}
+
+		existing.Status = payload.Status
+		existing.ProcessedAt = processedAt
+		if err := p.paymentService.UpdatePayment(existing); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, newPaymentResponse(existing))
+		return
+	}
 
-	payment, err := p.paymentService.CreatePayment(payload.ExternalRef, userID, subID, payload.Amount, payload.Status, processedAt)
+	payment, err := p.paymentService.CreatePayment(payload.ExternalRef, userID, subID, amount, quoteID, payload.Status, processedAt)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if isMoneyError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		respondQuotePaymentError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, payment)
+	c.JSON(http.StatusOK, newPaymentResponse(payment))
+}
+
+// respondQuotePaymentError answers a failed payment of a price quote; any other error is a 500
+func respondQuotePaymentError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrQuoteNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrQuoteMismatch), errors.Is(err, service.ErrSubscriptionRequired):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrQuoteExpired), errors.Is(err, service.ErrQuoteUnavailable):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
 }
 
 // ComputeHMAC generates an HMAC SHA256 hash
@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"kaabe-app/internal/domain/model"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// CreatePaymentRequest is the body of POST /payments
+type CreatePaymentRequest struct {
+	ExternalRef    string       `json:"external_ref"`    // honoured for admins only; generated when empty
+	UserID         uuid.UUID    `json:"user_id"`         // honoured for admins only
+	SubscriptionID uuid.UUID    `json:"subscription_id"` // not needed when paying a quote
+	Amount         MoneyRequest `json:"amount"`
+	QuoteID        *uuid.UUID   `json:"quote_id"`                  // from POST /quotes; the amount must be the quoted total
+	Status         string       `json:"status" binding:"required"` // honoured for admins only; other payments start pending unless a quote leaves nothing to pay
+	ProcessedAt    time.Time    `json:"processed_at"`
+}
+
+// UpdatePaymentRequest is the body of PUT /payments/:id
+type UpdatePaymentRequest struct {
+	ExternalRef    string       `json:"external_ref" binding:"required"`
+	UserID         uuid.UUID    `json:"user_id" binding:"required"`
+	SubscriptionID uuid.UUID    `json:"subscription_id"` // ignored for quote payments
+	Amount         MoneyRequest `json:"amount"`
+	Status         string       `json:"status" binding:"required"`
+	ProcessedAt    time.Time    `json:"processed_at"`
+}
+
+// PaymentResponse is the public view of a payment
+type PaymentResponse struct {
+	ID             uuid.UUID     `json:"id"`
+	ExternalRef    string        `json:"external_ref"`
+	UserID         uuid.UUID     `json:"user_id"`
+	SubscriptionID *uuid.UUID    `json:"subscription_id,omitempty"` // absent for payments that made a purchase
+	Amount         MoneyResponse `json:"amount"`
+	QuoteID        *uuid.UUID    `json:"quote_id,omitempty"`
+	Status         string        `json:"status"`
+	ProcessedAt    time.Time     `json:"processed_at"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+}
+
+func (r UpdatePaymentRequest) toModel(id uuid.UUID) *model.Payment {
+	return &model.Payment{
+		ID:             id,
+		ExternalRef:    r.ExternalRef,
+		UserID:         r.UserID,
+		SubscriptionID: r.SubscriptionID,
+		Amount:         r.Amount.toModel(),
+		Status:         r.Status,
+		ProcessedAt:    r.ProcessedAt,
+	}
+}
+
+func newPaymentResponse(payment *model.Payment) PaymentResponse {
+	response := PaymentResponse{
+		ID:          payment.ID,
+		ExternalRef: payment.ExternalRef,
+		UserID:      payment.UserID,
+		Amount:      newMoneyResponse(payment.Amount),
+		QuoteID:     payment.QuoteID,
+		Status:      payment.Status,
+		ProcessedAt: payment.ProcessedAt,
+		CreatedAt:   payment.CreatedAt,
+		UpdatedAt:   payment.UpdatedAt,
+	}
+	if payment.SubscriptionID != uuid.Nil {
+		response.SubscriptionID = &payment.SubscriptionID
+	}
+	return response
+}
+
+func newPaymentResponses(payments []*model.Payment) []PaymentResponse {
+	responses := make([]PaymentResponse, 0, len(payments))
+	for _, payment := range payments {
+		responses = append(responses, newPaymentResponse(payment))
+	}
+	return responses
+}
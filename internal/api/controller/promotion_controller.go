@@ -0,0 +1,300 @@
+package controller
+
+import (
+	"errors"
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+// PromotionController serves coupons, course sales and price quotes. Admins manage every coupon;
+// influencers manage coupons and sales for their own courses.
+type PromotionController struct {
+	promotionService service.PromotionService
+	policy           service.AccessPolicy
+}
+
+// NewPromotionController creates a new PromotionController instance
+func NewPromotionController(promotionService service.PromotionService, policy service.AccessPolicy) *PromotionController {
+	return &PromotionController{promotionService: promotionService, policy: policy}
+}
+
+// ListCoupons lists coupons a page at a time; non-admins only see those for their courses.
+// Filters: ?course_id=, ?active=true|false; sort: created_at (default, newest first), code
+func (pc *PromotionController) ListCoupons(c *gin.Context) {
+	page, err := bindPageRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var filter model.CouponFilter
+	var courseErr, activeErr error
+	var active string
+	filter.CourseID, courseErr = queryUUID(c, "course_id")
+	active, activeErr = queryOneOf(c, "active", "true", "false")
+	if err := firstError(courseErr, activeErr); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if active != "" {
+		isActive := active == "true"
+		filter.Active = &isActive
+	}
+
+	actor, _ := currentActor(c)
+	if !actor.IsAdmin() {
+		filter.OwnedBy = &actor.UserID
+	}
+
+	coupons, err := pc.promotionService.ListCoupons(filter, page)
+	if err != nil {
+		respondListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newPageResponse(coupons, newCouponResponses))
+}
+
+// GetCoupon returns a coupon with its redemption count
+func (pc *PromotionController) GetCoupon(c *gin.Context) {
+	coupon, _, ok := pc.authorizeCoupon(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, newCouponResponse(coupon))
+}
+
+// CreateCoupon adds a coupon; influencers may only create coupons for their own courses
+func (pc *PromotionController) CreateCoupon(c *gin.Context) {
+	var req CouponRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	actor, _ := currentActor(c)
+	if !pc.authorizeCouponCourse(c, actor, req.CourseID) {
+		return
+	}
+
+	coupon := req.toModel(uuid.Nil)
+	coupon.CreatedBy = &actor.UserID
+	if err := pc.promotionService.CreateCoupon(coupon); err != nil {
+		respondPromotionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newCouponResponse(coupon))
+}
+
+// UpdateCoupon replaces a coupon's settings; quotes already made keep their discount
+func (pc *PromotionController) UpdateCoupon(c *gin.Context) {
+	existing, actor, ok := pc.authorizeCoupon(c)
+	if !ok {
+		return
+	}
+
+	var req CouponRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !pc.authorizeCouponCourse(c, actor, req.CourseID) {
+		return
+	}
+
+	coupon := req.toModel(existing.ID)
+	if err := pc.promotionService.UpdateCoupon(coupon); err != nil {
+		respondPromotionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCouponResponse(coupon))
+}
+
+// DeleteCoupon removes a coupon nobody has paid with; redeemed coupons are deactivated instead
+func (pc *PromotionController) DeleteCoupon(c *gin.Context) {
+	coupon, _, ok := pc.authorizeCoupon(c)
+	if !ok {
+		return
+	}
+
+	if err := pc.promotionService.DeleteCoupon(coupon.ID); err != nil {
+		respondPromotionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Coupon deleted successfully"})
+}
+
+// ListSales returns the course's running and upcoming sales
+func (pc *PromotionController) ListSales(c *gin.Context) {
+	courseID, _, ok := pc.authorizeCourse(c)
+	if !ok {
+		return
+	}
+
+	sales, err := pc.promotionService.ListSales(courseID)
+	if err != nil {
+		respondPromotionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCourseSaleResponses(sales))
+}
+
+// CreateSale puts the course on sale in one currency between starts_at and ends_at
+func (pc *PromotionController) CreateSale(c *gin.Context) {
+	courseID, actor, ok := pc.authorizeCourse(c)
+	if !ok {
+		return
+	}
+
+	var req CourseSaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sale := req.toModel(courseID)
+	sale.CreatedBy = &actor.UserID
+	if err := pc.promotionService.CreateSale(sale); err != nil {
+		respondPromotionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newCourseSaleResponse(sale))
+}
+
+// DeleteSale cancels a sale, or ends a running one
+func (pc *PromotionController) DeleteSale(c *gin.Context) {
+	courseID, _, ok := pc.authorizeCourse(c)
+	if !ok {
+		return
+	}
+	saleID, err := uuid.FromString(c.Param("sale_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sale ID"})
+		return
+	}
+
+	if err := pc.promotionService.DeleteSale(courseID, saleID); err != nil {
+		respondPromotionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Sale deleted successfully"})
+}
+
+// CreateQuote works out the price the caller will be charged for a course, with any running sale
+// and the given coupon applied, or for a bundle or an influencer's all-access pass. The quote is
+// what POST /payments charges and redeems.
+func (pc *PromotionController) CreateQuote(c *gin.Context) {
+	var req PriceQuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	actor, exists := currentActor(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID is required"})
+		return
+	}
+
+	var quote *model.PriceQuote
+	var err error
+	switch {
+	case req.CourseID != nil && req.BundleID == nil && req.InfluencerID == nil:
+		quote, err = pc.promotionService.QuotePrice(actor.UserID, *req.CourseID, req.Currency, req.CouponCode)
+	case req.BundleID != nil && req.CourseID == nil && req.InfluencerID == nil:
+		quote, err = pc.promotionService.QuoteBundle(actor.UserID, *req.BundleID, req.Currency, req.CouponCode)
+	case req.InfluencerID != nil && req.CourseID == nil && req.BundleID == nil:
+		quote, err = pc.promotionService.QuotePass(actor.UserID, *req.InfluencerID, req.Currency, req.CouponCode)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "give exactly one of course_id, bundle_id and influencer_id"})
+		return
+	}
+	if err != nil {
+		respondPromotionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newPriceQuoteResponse(quote))
+}
+
+func (pc *PromotionController) authorizeCourse(c *gin.Context) (uuid.UUID, service.Actor, bool) {
+	courseID, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return uuid.Nil, service.Actor{}, false
+	}
+
+	actor, _ := currentActor(c)
+	if err := pc.policy.AuthorizeCourse(actor, courseID); err != nil {
+		respondAccessError(c, err)
+		return uuid.Nil, service.Actor{}, false
+	}
+	return courseID, actor, true
+}
+
+// authorizeCoupon loads the coupon in the URL if the caller may manage it
+func (pc *PromotionController) authorizeCoupon(c *gin.Context) (*model.Coupon, service.Actor, bool) {
+	couponID, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid coupon ID"})
+		return nil, service.Actor{}, false
+	}
+
+	coupon, err := pc.promotionService.GetCoupon(couponID)
+	if err != nil {
+		respondPromotionError(c, err)
+		return nil, service.Actor{}, false
+	}
+
+	actor, _ := currentActor(c)
+	if !pc.authorizeCouponCourse(c, actor, coupon.CourseID) {
+		return nil, service.Actor{}, false
+	}
+	return coupon, actor, true
+}
+
+// authorizeCouponCourse checks the caller may manage coupons for the course; site-wide coupons
+// (a nil course) are for admins only
+func (pc *PromotionController) authorizeCouponCourse(c *gin.Context, actor service.Actor, courseID *uuid.UUID) bool {
+	if actor.IsAdmin() {
+		return true
+	}
+	if courseID == nil {
+		respondAccessError(c, service.ErrForbidden)
+		return false
+	}
+	if err := pc.policy.AuthorizeCourse(actor, *courseID); err != nil {
+		respondAccessError(c, err)
+		return false
+	}
+	return true
+}
+
+func respondPromotionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrCouponNotFound), errors.Is(err, service.ErrSaleNotFound),
+		errors.Is(err, service.ErrCourseNotFound), errors.Is(err, service.ErrPriceNotFound),
+		errors.Is(err, service.ErrBundleNotFound), errors.Is(err, service.ErrPassNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidCoupon), errors.Is(err, service.ErrInvalidSale), isMoneyError(err):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrCouponNotApplicable), errors.Is(err, service.ErrCouponExhausted):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrCouponCodeTaken), errors.Is(err, service.ErrCouponRedeemed),
+		errors.Is(err, service.ErrSaleOverlaps):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
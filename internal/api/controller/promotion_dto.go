@@ -0,0 +1,187 @@
+package controller
+
+import (
+	"kaabe-app/internal/domain/model"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// CouponRequest is the body of POST /coupons and PUT /coupons/:id
+type CouponRequest struct {
+	Code           string        `json:"code" binding:"required,max=40"` // stored upper-case
+	DiscountType   string        `json:"discount_type" binding:"required,oneof=percentage fixed"`
+	PercentOff     int           `json:"percent_off"` // percentage coupons, 1-100
+	AmountOff      *MoneyRequest `json:"amount_off"`  // fixed coupons; applies to prices in its currency only
+	CourseID       *uuid.UUID    `json:"course_id"`   // null for a site-wide coupon (admins only)
+	MaxRedemptions *int          `json:"max_redemptions"`
+	MaxPerUser     *int          `json:"max_per_user"`
+	StartsAt       *time.Time    `json:"starts_at"`
+	ExpiresAt      *time.Time    `json:"expires_at"`
+	Active         *bool         `json:"active"` // true if omitted
+}
+
+// CourseSaleRequest is the body of POST /courses/:id/sales
+type CourseSaleRequest struct {
+	Price    MoneyRequest `json:"price"`
+	StartsAt *time.Time   `json:"starts_at"` // now if omitted
+	EndsAt   time.Time    `json:"ends_at" binding:"required"`
+}
+
+// PriceQuoteRequest is the body of POST /quotes; exactly one of course_id, bundle_id and
+// influencer_id (for the influencer's all-access pass) is given
+type PriceQuoteRequest struct {
+	CourseID     *uuid.UUID `json:"course_id"`
+	BundleID     *uuid.UUID `json:"bundle_id"`
+	InfluencerID *uuid.UUID `json:"influencer_id"`
+	Currency     string     `json:"currency"` // the item's base currency if omitted
+	CouponCode   string     `json:"coupon_code" binding:"max=40"`
+}
+
+// CouponResponse is a coupon as its managers see it
+type CouponResponse struct {
+	ID             uuid.UUID      `json:"id"`
+	Code           string         `json:"code"`
+	DiscountType   string         `json:"discount_type"`
+	PercentOff     int            `json:"percent_off,omitempty"`
+	AmountOff      *MoneyResponse `json:"amount_off,omitempty"`
+	CourseID       *uuid.UUID     `json:"course_id"`
+	MaxRedemptions *int           `json:"max_redemptions"`
+	MaxPerUser     *int           `json:"max_per_user"`
+	StartsAt       *time.Time     `json:"starts_at"`
+	ExpiresAt      *time.Time     `json:"expires_at"`
+	Active         bool           `json:"active"`
+	Redemptions    int            `json:"redemptions"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// CourseSaleResponse is a current or upcoming sale; running is true while it applies
+type CourseSaleResponse struct {
+	ID        uuid.UUID     `json:"id"`
+	CourseID  uuid.UUID     `json:"course_id"`
+	Price     MoneyResponse `json:"price"`
+	StartsAt  time.Time     `json:"starts_at"`
+	EndsAt    time.Time     `json:"ends_at"`
+	Running   bool          `json:"running"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// PriceQuoteResponse is the price the user will be charged; pay it with POST /payments,
+// passing quote_id and total as the amount, before expires_at
+type PriceQuoteResponse struct {
+	ID               uuid.UUID      `json:"id"`
+	CourseID         *uuid.UUID     `json:"course_id,omitempty"`
+	BundleID         *uuid.UUID     `json:"bundle_id,omitempty"`
+	PassInfluencerID *uuid.UUID     `json:"pass_influencer_id,omitempty"`
+	ListPrice        MoneyResponse  `json:"list_price"`
+	SalePrice        *MoneyResponse `json:"sale_price,omitempty"`
+	CouponCode       string         `json:"coupon_code,omitempty"`
+	Discount         MoneyResponse  `json:"discount"`
+	Total            MoneyResponse  `json:"total"`
+	ExpiresAt        time.Time      `json:"expires_at"`
+}
+
+func (r CouponRequest) toModel(id uuid.UUID) *model.Coupon {
+	coupon := &model.Coupon{
+		ID:             id,
+		Code:           r.Code,
+		DiscountType:   r.DiscountType,
+		PercentOff:     r.PercentOff,
+		CourseID:       r.CourseID,
+		MaxRedemptions: r.MaxRedemptions,
+		MaxPerUser:     r.MaxPerUser,
+		StartsAt:       r.StartsAt,
+		ExpiresAt:      r.ExpiresAt,
+		Active:         r.Active == nil || *r.Active,
+	}
+	if r.AmountOff != nil {
+		amountOff := r.AmountOff.toModel()
+		coupon.AmountOff = &amountOff
+	}
+	return coupon
+}
+
+func (r CourseSaleRequest) toModel(courseID uuid.UUID) *model.CourseSale {
+	startsAt := time.Now()
+	if r.StartsAt != nil {
+		startsAt = *r.StartsAt
+	}
+	return &model.CourseSale{
+		CourseID: courseID,
+		Price:    r.Price.toModel(),
+		StartsAt: startsAt,
+		EndsAt:   r.EndsAt,
+	}
+}
+
+func newCouponResponse(coupon *model.Coupon) CouponResponse {
+	response := CouponResponse{
+		ID:             coupon.ID,
+		Code:           coupon.Code,
+		DiscountType:   coupon.DiscountType,
+		PercentOff:     coupon.PercentOff,
+		CourseID:       coupon.CourseID,
+		MaxRedemptions: coupon.MaxRedemptions,
+		MaxPerUser:     coupon.MaxPerUser,
+		StartsAt:       coupon.StartsAt,
+		ExpiresAt:      coupon.ExpiresAt,
+		Active:         coupon.Active,
+		Redemptions:    coupon.Redemptions,
+		CreatedAt:      coupon.CreatedAt,
+		UpdatedAt:      coupon.UpdatedAt,
+	}
+	if coupon.AmountOff != nil {
+		amountOff := newMoneyResponse(*coupon.AmountOff)
+		response.AmountOff = &amountOff
+	}
+	return response
+}
+
+func newCouponResponses(coupons []*model.Coupon) []CouponResponse {
+	responses := make([]CouponResponse, 0, len(coupons))
+	for _, coupon := range coupons {
+		responses = append(responses, newCouponResponse(coupon))
+	}
+	return responses
+}
+
+func newCourseSaleResponse(sale *model.CourseSale) CourseSaleResponse {
+	now := time.Now()
+	return CourseSaleResponse{
+		ID:        sale.ID,
+		CourseID:  sale.CourseID,
+		Price:     newMoneyResponse(sale.Price),
+		StartsAt:  sale.StartsAt,
+		EndsAt:    sale.EndsAt,
+		Running:   !now.Before(sale.StartsAt) && now.Before(sale.EndsAt),
+		CreatedAt: sale.CreatedAt,
+	}
+}
+
+func newCourseSaleResponses(sales []*model.CourseSale) []CourseSaleResponse {
+	responses := make([]CourseSaleResponse, 0, len(sales))
+	for _, sale := range sales {
+		responses = append(responses, newCourseSaleResponse(sale))
+	}
+	return responses
+}
+
+func newPriceQuoteResponse(quote *model.PriceQuote) PriceQuoteResponse {
+	response := PriceQuoteResponse{
+		ID:               quote.ID,
+		CourseID:         quote.CourseID,
+		BundleID:         quote.BundleID,
+		PassInfluencerID: quote.PassInfluencerID,
+		ListPrice:        newMoneyResponse(quote.ListPrice),
+		CouponCode:       quote.CouponCode,
+		Discount:         newMoneyResponse(quote.Discount),
+		Total:            newMoneyResponse(quote.Total),
+		ExpiresAt:        quote.ExpiresAt,
+	}
+	if quote.SalePrice != nil {
+		salePrice := newMoneyResponse(*quote.SalePrice)
+		response.SalePrice = &salePrice
+	}
+	return response
+}
@@ -5,6 +5,7 @@ import (
 	"kaabe-app/internal/domain/service"
 	"log"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gofrs/uuid"
@@ -13,24 +14,32 @@ import (
 // ratingController struct that defines the rating controller with its service
 type RatingController struct {
 	RatingService service.RatingService
+	policy        service.AccessPolicy
 }
 
 // NewRatingController creates a new ratingController instance
-func NewRatingController(ratingService service.RatingService) *RatingController {
-	return &RatingController{RatingService: ratingService}
+func NewRatingController(ratingService service.RatingService, policy service.AccessPolicy) *RatingController {
+	return &RatingController{RatingService: ratingService, policy: policy}
 }
 
 // CreateRating handles the creation of a new rating
 func (r *RatingController) CreateRating(ctx *gin.Context) {
-	var rating model.Rating
+	var rating CreateRatingRequest
 
 	if err := ctx.ShouldBindJSON(&rating); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	// Ratings are always authored by the caller
+	actor, exists := currentActor(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user ID is required"})
+		return
+	}
+
 	createdRating, err := r.RatingService.CreateRating(
-		rating.UserID,
+		actor.UserID,
 		rating.CourseID,
 		rating.Score,
 		rating.Comment,
@@ -41,11 +50,11 @@ func (r *RatingController) CreateRating(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, createdRating)
+	ctx.JSON(http.StatusOK, newRatingResponse(createdRating))
 }
 
 func (r *RatingController) UpdateRating(ctx *gin.Context) {
-	var rating model.Rating
+	var req UpdateRatingRequest
 
 	ratingIdParam := ctx.Param("id")
 	ratingID, err := uuid.FromString(ratingIdParam)
@@ -54,15 +63,20 @@ func (r *RatingController) UpdateRating(ctx *gin.Context) {
 		return
 	}
 
-	//
-	if err := ctx.ShouldBindJSON(&rating); err != nil {
+	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	rating.ID = ratingID
+	rating := req.toModel(ratingID)
+
+	actor, _ := currentActor(ctx)
+	if err := r.policy.AuthorizeRating(actor, ratingID); err != nil {
+		respondAccessError(ctx, err)
+		return
+	}
 
-	if err := r.RatingService.UpdateRating(&rating); err != nil {
+	if err := r.RatingService.UpdateRating(rating); err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -75,6 +89,13 @@ func (r *RatingController) DeleteRating(ctx *gin.Context) {
 	ratingId, err := uuid.FromString(ratingIdParam)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid rating ID"})
+		return
+	}
+
+	actor, _ := currentActor(ctx)
+	if err := r.policy.AuthorizeRating(actor, ratingId); err != nil {
+		respondAccessError(ctx, err)
+		return
 	}
 
 	if err := r.RatingService.DeleteRating(ratingId); err != nil {
@@ -86,15 +107,40 @@ func (r *RatingController) DeleteRating(ctx *gin.Context) {
 
 }
 
+// GetAllRating lists ratings a page at a time.
+// Filters: ?course_id=, ?user_id=, ?min_score=; sort: created_at (default, newest first), score
 func (r *RatingController) GetAllRating(ctx *gin.Context) {
+	page, err := bindPageRequest(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var filter model.RatingFilter
+	var courseErr, userErr error
+	filter.CourseID, courseErr = queryUUID(ctx, "course_id")
+	filter.UserID, userErr = queryUUID(ctx, "user_id")
+	if err := firstError(courseErr, userErr); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if raw := ctx.Query("min_score"); raw != "" {
+		score, err := strconv.Atoi(raw)
+		if err != nil || score < 1 || score > 5 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "min_score must be between 1 and 5"})
+			return
+		}
+		filter.MinScore = score
+	}
+
 	// Call service to get rating
-	rating, err := r.RatingService.GetAllRatings()
+	ratings, err := r.RatingService.GetAllRatings(filter, page)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondListError(ctx, err)
 		return
 	}
 	// respond success
-	ctx.JSON(http.StatusOK, rating)
+	ctx.JSON(http.StatusOK, newPageResponse(ratings, newRatingResponses))
 }
 
 // GetRatingByID godoc
@@ -119,5 +165,5 @@ func (r *RatingController) GetRatingByID(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, rating)
+	c.JSON(http.StatusOK, newRatingResponse(rating))
 }
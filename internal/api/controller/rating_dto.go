@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"kaabe-app/internal/domain/model"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// CreateRatingRequest is the body of POST /ratings
+type CreateRatingRequest struct {
+	CourseID uuid.UUID `json:"course_id" binding:"required"`
+	Score    int       `json:"score" binding:"required,min=1,max=5"`
+	Comment  string    `json:"comment"`
+}
+
+// UpdateRatingRequest is the body of PUT /ratings/:id
+type UpdateRatingRequest struct {
+	Score   int    `json:"score" binding:"required,min=1,max=5"`
+	Comment string `json:"comment"`
+}
+
+// RatingResponse is the public view of a rating
+type RatingResponse struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	CourseID  uuid.UUID `json:"course_id"`
+	Score     int       `json:"score"`
+	Comment   string    `json:"comment"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (r UpdateRatingRequest) toModel(id uuid.UUID) *model.Rating {
+	return &model.Rating{
+		ID:      id,
+		Score:   r.Score,
+		Comment: r.Comment,
+	}
+}
+
+func newRatingResponse(rating *model.Rating) RatingResponse {
+	return RatingResponse{
+		ID:        rating.ID,
+		UserID:    rating.UserID,
+		CourseID:  rating.CourseID,
+		Score:     rating.Score,
+		Comment:   rating.Comment,
+		CreatedAt: rating.CreatedAt,
+		UpdatedAt: rating.UpdatedAt,
+	}
+}
+
+func newRatingResponses(ratings []*model.Rating) []RatingResponse {
+	responses := make([]RatingResponse, 0, len(ratings))
+	for _, rating := range ratings {
+		responses = append(responses, newRatingResponse(rating))
+	}
+	return responses
+}
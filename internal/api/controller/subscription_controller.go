@@ -12,21 +12,31 @@ import (
 
 type SubscriptionController struct {
 	SubscriptionService service.SubscriptionService
+	policy              service.AccessPolicy
 }
 
-func NewSubscriptionController(SubscriptionService service.SubscriptionService) *SubscriptionController {
-	return &SubscriptionController{SubscriptionService: SubscriptionService}
+func NewSubscriptionController(SubscriptionService service.SubscriptionService, policy service.AccessPolicy) *SubscriptionController {
+	return &SubscriptionController{SubscriptionService: SubscriptionService, policy: policy}
 }
 
 func (sc *SubscriptionController) CreateSubscription(ctx *gin.Context) {
-	var Subscription model.Subscription
+	var Subscription CreateSubscriptionRequest
 
-	// Bind JSON to the  struct,
 	if err := ctx.ShouldBindJSON(&Subscription); err != nil {
 		ctx.JSON(400, gin.H{"error": err.Error()})
 		return
 	}
 
+	// Users subscribe themselves; admins may subscribe someone else
+	actor, exists := currentActor(ctx)
+	if !exists {
+		ctx.JSON(401, gin.H{"error": "user ID is required"})
+		return
+	}
+	if !actor.IsAdmin() || Subscription.UserID == uuid.Nil {
+		Subscription.UserID = actor.UserID
+	}
+
 	// Call the service with the
 	createdSubscription, err := sc.SubscriptionService.CreateSubscription(
 		Subscription.UserID,
@@ -41,11 +51,11 @@ func (sc *SubscriptionController) CreateSubscription(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(201, createdSubscription)
+	ctx.JSON(201, newSubscriptionResponse(createdSubscription))
 }
 
 func (sc *SubscriptionController) UpdateSubscription(ctx *gin.Context) {
-	var Subscription model.Subscription
+	var req UpdateSubscriptionRequest
 
 	SubscriptionIdParam := ctx.Param("id")
 	SubscriptionID, err := uuid.FromString(SubscriptionIdParam)
@@ -54,16 +64,21 @@ func (sc *SubscriptionController) UpdateSubscription(ctx *gin.Context) {
 		return
 	}
 
-	// Bind JSON to the  struct, which now includes
-	if err := ctx.ShouldBindJSON(&Subscription); err != nil {
+	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(400, gin.H{"error": err.Error()})
 		return
 	}
 
-	Subscription.ID = SubscriptionID
+	Subscription := req.toModel(SubscriptionID)
+
+	actor, _ := currentActor(ctx)
+	if err := sc.policy.AuthorizeSubscription(actor, SubscriptionID); err != nil {
+		respondAccessError(ctx, err)
+		return
+	}
 
 	// Call the service with the bound struct's
-	if err := sc.SubscriptionService.UpdateSubscription(&Subscription); err != nil {
+	if err := sc.SubscriptionService.UpdateSubscription(Subscription); err != nil {
 		ctx.JSON(500, gin.H{"error": err.Error()})
 		return
 	}
@@ -76,6 +91,13 @@ func (sc *SubscriptionController) DeleteSubscription(ctx *gin.Context) {
 	SubscriptionId, err := uuid.FromString(SubscriptionParam)
 	if err != nil {
 		ctx.JSON(400, gin.H{"error": "invalid Subscription ID"})
+		return
+	}
+
+	actor, _ := currentActor(ctx)
+	if err := sc.policy.AuthorizeSubscription(actor, SubscriptionId); err != nil {
+		respondAccessError(ctx, err)
+		return
 	}
 
 	if err := sc.SubscriptionService.DeleteSubscription(SubscriptionId); err != nil {
@@ -95,6 +117,12 @@ func (sc *SubscriptionController) GetSubscriptionByID(ctx *gin.Context) {
 		return
 	}
 
+	actor, _ := currentActor(ctx)
+	if err := sc.policy.AuthorizeSubscription(actor, subscriptionID); err != nil {
+		respondAccessError(ctx, err)
+		return
+	}
+
 	Subscription, err := sc.SubscriptionService.GetSubscriptionByID(subscriptionID)
 	if err != nil {
 		if err.Error() == "Subscription not found" {
@@ -106,16 +134,39 @@ func (sc *SubscriptionController) GetSubscriptionByID(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(200, Subscription)
+	ctx.JSON(200, newSubscriptionResponse(Subscription))
 }
 
+// GetAllSubscription lists subscriptions a page at a time; non-admins only see their own.
+// Filters: ?user_id= (admins), ?course_id=, ?status=; sort: created_at (default, newest first), expires_at
 func (sc *SubscriptionController) GetAllSubscription(ctx *gin.Context) {
+	page, err := bindPageRequest(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var filter model.SubscriptionFilter
+	var userErr, courseErr, statusErr error
+	filter.UserID, userErr = queryUUID(ctx, "user_id")
+	filter.CourseID, courseErr = queryUUID(ctx, "course_id")
+	filter.Status, statusErr = queryOneOf(ctx, "status", "active", "expired", "cancelled", "pending")
+	if err := firstError(userErr, courseErr, statusErr); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	actor, _ := currentActor(ctx)
+	if !actor.IsAdmin() {
+		filter.UserID = &actor.UserID
+	}
+
 	// Call service to get Subscription
-	Subscription, err := sc.SubscriptionService.GetAllSubscription()
+	subscriptions, err := sc.SubscriptionService.GetAllSubscription(filter, page)
 	if err != nil {
-		ctx.JSON(500, gin.H{"error": err.Error()})
+		respondListError(ctx, err)
 		return
 	}
 	// respond success
-	ctx.JSON(http.StatusOK, Subscription)
+	ctx.JSON(http.StatusOK, newPageResponse(subscriptions, newSubscriptionResponses))
 }
@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"kaabe-app/internal/domain/model"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// CreateSubscriptionRequest is the body of POST /subscriptions
+type CreateSubscriptionRequest struct {
+	UserID    uuid.UUID `json:"user_id"` // honoured for admins only
+	CourseID  uuid.UUID `json:"course_id" binding:"required"`
+	StartedAt time.Time `json:"started_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Status    string    `json:"status"`
+}
+
+// UpdateSubscriptionRequest is the body of PUT /subscriptions/:id
+type UpdateSubscriptionRequest struct {
+	StartedAt time.Time `json:"started_at" binding:"required"`
+	ExpiresAt time.Time `json:"expires_at" binding:"required"`
+	Status    string    `json:"status" binding:"required"`
+}
+
+// SubscriptionResponse is the public view of a subscription
+type SubscriptionResponse struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	CourseID  uuid.UUID `json:"course_id"`
+	StartedAt time.Time `json:"started_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (r UpdateSubscriptionRequest) toModel(id uuid.UUID) *model.Subscription {
+	return &model.Subscription{
+		ID:        id,
+		StartedAt: r.StartedAt,
+		ExpiresAt: r.ExpiresAt,
+		Status:    r.Status,
+	}
+}
+
+func newSubscriptionResponse(subscription *model.Subscription) SubscriptionResponse {
+	return SubscriptionResponse{
+		ID:        subscription.ID,
+		UserID:    subscription.UserID,
+		CourseID:  subscription.CourseID,
+		StartedAt: subscription.StartedAt,
+		ExpiresAt: subscription.ExpiresAt,
+		Status:    subscription.Status,
+		CreatedAt: subscription.CreatedAt,
+		UpdatedAt: subscription.UpdatedAt,
+	}
+}
+
+func newSubscriptionResponses(subscriptions []*model.Subscription) []SubscriptionResponse {
+	responses := make([]SubscriptionResponse, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		responses = append(responses, newSubscriptionResponse(subscription))
+	}
+	return responses
+}
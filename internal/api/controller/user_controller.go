@@ -1,10 +1,12 @@
 package controller
 
 import (
+	"errors"
 	"kaabe-app/internal/domain/model"
 	"kaabe-app/internal/domain/service"
 	"log"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gofrs/uuid"
@@ -20,14 +22,14 @@ func NewUserController(userService service.UserService) *UserController {
 
 // RegisterUser handles user registration
 func (us *UserController) RegisterUser(c *gin.Context) {
-	var user model.User
+	var user RegisterUserRequest
 
 	if err := c.ShouldBindJSON(&user); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	log.Printf("bound user: %+v", user)
+	log.Printf("registering user: %s", user.Email)
 
 	createdUser, err := us.userService.RegisterUser(
 		user.Email,
@@ -35,32 +37,126 @@ func (us *UserController) RegisterUser(c *gin.Context) {
 		user.FirstName,
 		user.LastName,
 		user.Role,
-		*user.WalletID,
+		user.WalletID,
 	)
 	if err != nil {
+		if errors.Is(err, service.ErrInfluencerApplicationRequired) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, createdUser)
+	c.JSON(http.StatusCreated, newUserResponse(createdUser))
 }
 
 // AuthenticateUser handles login
 func (us *UserController) AuthenticateUser(c *gin.Context) {
-	var user model.User
+	var user LoginRequest
 
 	if err := c.ShouldBindJSON(&user); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
 		return
 	}
 
-	authenticatedUser, err := us.userService.AuthenticateUser(user.Email, user.Password)
+	result, err := us.userService.AuthenticateUser(user.Email, user.Password, c.ClientIP())
 	if err != nil {
+		if respondTooManyAttempts(c, err) {
+			return
+		}
+		if errors.Is(err, service.ErrEmailNotVerified) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Please verify your email address before logging in"})
+			return
+		}
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication failed: " + err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, authenticatedUser)
+	respondLogin(c, result)
+}
+
+// respondLogin writes the tokens of a finished login, or the challenge for its second step
+func respondLogin(c *gin.Context, result *model.LoginResult) {
+	// First step accepted, but the client must complete the second step with mfa_token
+	if result.Tokens == nil {
+		c.JSON(http.StatusOK, MFAChallengeResponse{
+			MFARequired:           result.MFARequired,
+			MFAEnrollmentRequired: result.MFAEnrollmentRequired,
+			MFAToken:              result.MFAToken,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, newLoginResponse(result.User, result.Tokens))
+}
+
+// RefreshToken exchanges a refresh token for a new token pair
+func (us *UserController) RefreshToken(c *gin.Context) {
+	var req struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	tokens, err := us.userService.RefreshTokens(req.RefreshToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newTokenResponse(tokens))
+}
+
+// Logout revokes the current session
+func (us *UserController) Logout(c *gin.Context) {
+	accessToken := c.GetString("accessToken")
+
+	if err := us.userService.Logout(accessToken); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "logged out successfully"})
+}
+
+// LogoutAll revokes every session of the current user
+func (us *UserController) LogoutAll(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID is required"})
+		return
+	}
+
+	if err := us.userService.LogoutAll(userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "logged out from all devices"})
+}
+
+// RevokeUserSessions lets an admin revoke all sessions of a user
+func (us *UserController) RevokeUserSessions(c *gin.Context) {
+	userID, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := us.userService.RevokeUserSessions(userID); err != nil {
+		if err.Error() == "user not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "user sessions revoked"})
 }
 
 // GetUserByID returns user by UUID
@@ -78,23 +174,38 @@ func (us *UserController) GetUserByID(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, user)
+	c.JSON(http.StatusOK, newUserResponse(user))
 }
 
-// ListUsers returns all users
+// ListUsers lists users a page at a time.
+// Filters: ?role=, ?q= (email or name); sort: created_at (default, newest first), email, last_name
 func (us *UserController) ListUsers(c *gin.Context) {
-	users, err := us.userService.ListUsers()
+	page, err := bindPageRequest(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, users)
+	var filter model.UserFilter
+	filter.Search = strings.TrimSpace(c.Query("q"))
+	filter.Role, err = queryOneOf(c, "role", model.RoleAdmin, model.RoleInfluencer, model.RoleUser)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	users, err := us.userService.ListUsers(filter, page)
+	if err != nil {
+		respondListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newPageResponse(users, newUserResponses))
 }
 
 // UpdateUser updates user by ID
 func (us *UserController) UpdateUser(c *gin.Context) {
-	var user model.User
+	var req UpdateUserRequest
 
 	userParam := c.Param("id")
 	userID, err := uuid.FromString(userParam)
@@ -103,19 +214,19 @@ func (us *UserController) UpdateUser(c *gin.Context) {
 		return
 	}
 
-	if err := c.ShouldBindJSON(&user); err != nil {
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	user.ID = userID
+	user := req.toModel(userID)
 
-	if err := us.userService.UpdateUser(&user); err != nil {
+	if err := us.userService.UpdateUser(user); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, user)
+	c.JSON(http.StatusOK, newUserResponse(user))
 }
 
 // DeleteUser removes a user
@@ -151,8 +262,11 @@ func (us *UserController) ForgotPassword(c *gin.Context) {
 		return
 	}
 
-	err := us.userService.ForgotPassword(req.Email)
+	err := us.userService.ForgotPassword(req.Email, c.ClientIP())
 	if err != nil {
+		if respondTooManyAttempts(c, err) {
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -186,3 +300,48 @@ func (us *UserController) ResetPassword(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Password reset successfully"})
 }
+
+// VerifyEmail confirms the email address in a verification token
+func (us *UserController) VerifyEmail(c *gin.Context) {
+	var req struct {
+		Token string `json:"token" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	if err := us.userService.VerifyEmail(req.Token); err != nil {
+		if errors.Is(err, service.ErrEmailAlreadyVerified) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Email verified successfully"})
+}
+
+// ResendVerificationEmail sends a new verification link
+func (us *UserController) ResendVerificationEmail(c *gin.Context) {
+	var req struct {
+		Email string `json:"email" binding:"required,email"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid email format"})
+		return
+	}
+
+	if err := us.userService.ResendVerificationEmail(req.Email, c.ClientIP()); err != nil {
+		if respondTooManyAttempts(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Verification email sent if the account exists and is not yet verified"})
+}
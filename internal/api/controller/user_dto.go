@@ -0,0 +1,229 @@
+package controller
+
+import (
+	"kaabe-app/internal/domain/model"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// RegisterUserRequest is the body of POST /users
+type RegisterUserRequest struct {
+	Email     string `json:"email" binding:"required,email"`
+	Password  string `json:"password" binding:"required,min=6"`
+	FirstName string `json:"first_name" binding:"required"`
+	LastName  string `json:"last_name" binding:"required"`
+	Role      string `json:"role"` // only "user"; influencers apply through /influencer-applications
+	WalletID  string `json:"wallet_id"`
+}
+
+// LoginRequest is the body of POST /users/authenticate
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+// UpdateUserRequest is the body of PUT /users/:id (admin only)
+type UpdateUserRequest struct {
+	Email     string  `json:"email" binding:"omitempty,email"` // phone-only accounts have none
+	FirstName string  `json:"first_name" binding:"required"`
+	LastName  string  `json:"last_name" binding:"required"`
+	Role      string  `json:"role" binding:"required,oneof=admin user influencer"`
+	WalletID  *string `json:"wallet_id"`
+}
+
+// UpdateProfileRequest is the body of PATCH /users/me; omitted fields are left unchanged
+type UpdateProfileRequest struct {
+	FirstName *string `json:"first_name" binding:"omitempty,min=1"`
+	LastName  *string `json:"last_name" binding:"omitempty,min=1"`
+	WalletID  *string `json:"wallet_id"`
+}
+
+// ChangePasswordRequest is the body of POST /users/me/password
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"` // not needed when the account has no password yet
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
+}
+
+// ChangeEmailRequest is the body of POST /users/me/email
+type ChangeEmailRequest struct {
+	NewEmail        string `json:"new_email" binding:"required,email"`
+	CurrentPassword string `json:"current_password"` // not needed when the account has no password yet
+}
+
+// PhoneCodeRequest is the body of POST /users/phone/otp and POST /users/me/phone
+type PhoneCodeRequest struct {
+	PhoneNumber string `json:"phone_number" binding:"required"`
+}
+
+// PhoneLoginRequest is the body of POST /users/phone/verify; names are used for new accounts
+type PhoneLoginRequest struct {
+	PhoneNumber string `json:"phone_number" binding:"required"`
+	Code        string `json:"code" binding:"required,len=6,numeric"`
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name"`
+}
+
+// VerifyPhoneRequest is the body of POST /users/me/phone/verify
+type VerifyPhoneRequest struct {
+	PhoneNumber string `json:"phone_number" binding:"required"`
+	Code        string `json:"code" binding:"required,len=6,numeric"`
+	UseAsWallet bool   `json:"use_as_wallet"` // also make the number the default payment wallet
+}
+
+// OIDCCallbackRequest is the body of the OIDC login and link callbacks. The names are only
+// used for new accounts when the ID token has none, as Apple sends them to the client instead.
+type OIDCCallbackRequest struct {
+	Code      string `json:"code" binding:"required"`
+	State     string `json:"state" binding:"required"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+// OIDCAuthorizationResponse tells the client where to send the user. The client should keep
+// state and check that the provider redirects back with the same value.
+type OIDCAuthorizationResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+	State            string `json:"state"`
+}
+
+// IdentityResponse is an external account linked to the user
+type IdentityResponse struct {
+	Provider    string     `json:"provider"`
+	Email       string     `json:"email,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+}
+
+// UserResponse is the public view of a user; it never carries the password hash or reset token
+type UserResponse struct {
+	ID                  uuid.UUID  `json:"id"`
+	Email               string     `json:"email,omitempty"`
+	FirstName           string     `json:"first_name"`
+	LastName            string     `json:"last_name"`
+	Role                string     `json:"role"`
+	WalletID            *string    `json:"wallet_id,omitempty"`
+	EmailVerified       bool       `json:"email_verified"`
+	EmailVerifiedAt     *time.Time `json:"email_verified_at,omitempty"`
+	TwoFactorEnabled    bool       `json:"two_factor_enabled"`
+	PhoneNumber         string     `json:"phone_number,omitempty"`
+	PhoneVerified       bool       `json:"phone_verified"`
+	WalletPhone         string     `json:"wallet_phone,omitempty"`
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty"` // set while a deletion request can still be cancelled
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// TokenResponse is a signed access/refresh token pair
+type TokenResponse struct {
+	AccessToken      string    `json:"access_token"`
+	RefreshToken     string    `json:"refresh_token"`
+	TokenType        string    `json:"token_type"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// LoginResponse is returned whenever a login completes
+type LoginResponse struct {
+	User UserResponse `json:"user"`
+	TokenResponse
+	RecoveryCodes []string `json:"recovery_codes,omitempty"` // only right after mandatory 2FA enrollment
+}
+
+// MFAChallengeResponse is returned when the password was accepted but a second step is needed
+type MFAChallengeResponse struct {
+	MFARequired           bool   `json:"mfa_required"`
+	MFAEnrollmentRequired bool   `json:"mfa_enrollment_required"`
+	MFAToken              string `json:"mfa_token"`
+}
+
+// TOTPEnrollmentResponse carries the secret to add to an authenticator app
+type TOTPEnrollmentResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// RecoveryCodesResponse lists freshly issued recovery codes; they are shown only once
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+func (r UpdateUserRequest) toModel(id uuid.UUID) *model.User {
+	return &model.User{
+		ID:        id,
+		Email:     r.Email,
+		FirstName: r.FirstName,
+		LastName:  r.LastName,
+		Role:      r.Role,
+		WalletID:  r.WalletID,
+	}
+}
+
+func newUserResponse(user *model.User) UserResponse {
+	return UserResponse{
+		ID:                  user.ID,
+		Email:               user.Email,
+		FirstName:           user.FirstName,
+		LastName:            user.LastName,
+		Role:                user.Role,
+		WalletID:            user.WalletID,
+		EmailVerified:       user.EmailVerifiedAt != nil,
+		EmailVerifiedAt:     user.EmailVerifiedAt,
+		TwoFactorEnabled:    user.TOTPEnabledAt != nil,
+		PhoneNumber:         user.PhoneNumber,
+		PhoneVerified:       user.PhoneVerifiedAt != nil,
+		WalletPhone:         user.WalletPhone,
+		DeletionScheduledAt: user.DeletionScheduledAt,
+		CreatedAt:           user.CreatedAt,
+		UpdatedAt:           user.UpdatedAt,
+	}
+}
+
+func newUserResponses(users []*model.User) []UserResponse {
+	responses := make([]UserResponse, 0, len(users))
+	for _, user := range users {
+		responses = append(responses, newUserResponse(user))
+	}
+	return responses
+}
+
+func newTokenResponse(tokens *model.AuthTokens) TokenResponse {
+	return TokenResponse{
+		AccessToken:      tokens.AccessToken,
+		RefreshToken:     tokens.RefreshToken,
+		TokenType:        tokens.TokenType,
+		ExpiresAt:        tokens.ExpiresAt,
+		RefreshExpiresAt: tokens.RefreshExpiresAt,
+	}
+}
+
+func newLoginResponse(user *model.User, tokens *model.AuthTokens) LoginResponse {
+	return LoginResponse{
+		User:          newUserResponse(user),
+		TokenResponse: newTokenResponse(tokens),
+	}
+}
+
+func newTOTPEnrollmentResponse(enrollment *model.TOTPEnrollment) TOTPEnrollmentResponse {
+	return TOTPEnrollmentResponse{
+		Secret:          enrollment.Secret,
+		ProvisioningURI: enrollment.ProvisioningURI,
+	}
+}
+
+func newIdentityResponse(identity *model.UserIdentity) IdentityResponse {
+	return IdentityResponse{
+		Provider:    identity.Provider,
+		Email:       identity.Email,
+		CreatedAt:   identity.CreatedAt,
+		LastLoginAt: identity.LastLoginAt,
+	}
+}
+
+func newIdentityResponses(identities []*model.UserIdentity) []IdentityResponse {
+	responses := make([]IdentityResponse, 0, len(identities))
+	for _, identity := range identities {
+		responses = append(responses, newIdentityResponse(identity))
+	}
+	return responses
+}
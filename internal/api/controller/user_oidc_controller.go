@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"errors"
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListIdentityProviders returns the social login providers that are enabled
+func (us *UserController) ListIdentityProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"providers": us.userService.IdentityProviders()})
+}
+
+// BeginOIDCLogin returns the provider URL that starts a social login
+func (us *UserController) BeginOIDCLogin(c *gin.Context) {
+	authorization, err := us.userService.BeginOIDCLogin(c.Param("provider"))
+	if err != nil {
+		respondIdentityError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newOIDCAuthorizationResponse(authorization))
+}
+
+// CompleteOIDCLogin finishes a social login with the code from the provider redirect
+func (us *UserController) CompleteOIDCLogin(c *gin.Context) {
+	var req OIDCCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	result, err := us.userService.CompleteOIDCLogin(c.Param("provider"), req.Code, req.State, req.FirstName, req.LastName)
+	if err != nil {
+		if errors.Is(err, service.ErrEmailNotVerified) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Please verify your email address before logging in"})
+			return
+		}
+		respondIdentityError(c, err)
+		return
+	}
+
+	respondLogin(c, result)
+}
+
+// ListIdentities returns the external accounts linked to the current user
+func (us *UserController) ListIdentities(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID is required"})
+		return
+	}
+
+	identities, err := us.userService.ListIdentities(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newIdentityResponses(identities))
+}
+
+// BeginIdentityLink returns the provider URL that links an external account to the current user
+func (us *UserController) BeginIdentityLink(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID is required"})
+		return
+	}
+
+	authorization, err := us.userService.BeginIdentityLink(userID, c.Param("provider"))
+	if err != nil {
+		respondIdentityError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newOIDCAuthorizationResponse(authorization))
+}
+
+// CompleteIdentityLink links the external account from the provider redirect
+func (us *UserController) CompleteIdentityLink(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID is required"})
+		return
+	}
+
+	var req OIDCCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	identity, err := us.userService.CompleteIdentityLink(userID, c.Param("provider"), req.Code, req.State)
+	if err != nil {
+		respondIdentityError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newIdentityResponse(identity))
+}
+
+// UnlinkIdentity removes a linked external account from the current user
+func (us *UserController) UnlinkIdentity(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID is required"})
+		return
+	}
+
+	if err := us.userService.UnlinkIdentity(userID, c.Param("provider")); err != nil {
+		respondIdentityError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "identity unlinked"})
+}
+
+func newOIDCAuthorizationResponse(authorization *model.OIDCAuthorization) OIDCAuthorizationResponse {
+	return OIDCAuthorizationResponse{AuthorizationURL: authorization.URL, State: authorization.State}
+}
+
+// respondIdentityError maps social login and linking errors to status codes
+func respondIdentityError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrUnknownIdentityProvider), errors.Is(err, service.ErrIdentityNotLinked):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidOIDCState):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrIdentityEmailInUse),
+		errors.Is(err, service.ErrIdentityLinkedElsewhere),
+		errors.Is(err, service.ErrIdentityAlreadyLinked),
+		errors.Is(err, service.ErrLastLoginMethod):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case err.Error() == "user not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		// Provider failures are logged by the service; the message names only the provider
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"errors"
+	"kaabe-app/internal/domain/service"
+	utils "kaabe-app/pkg/config"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestPhoneLoginCode texts a one-time login code to a phone number
+func (us *UserController) RequestPhoneLoginCode(c *gin.Context) {
+	var req PhoneCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	if err := us.userService.RequestPhoneLoginCode(req.PhoneNumber, c.ClientIP()); err != nil {
+		respondPhoneError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Code sent"})
+}
+
+// CompletePhoneLogin logs in, or registers, with the code texted to the phone number
+func (us *UserController) CompletePhoneLogin(c *gin.Context) {
+	var req PhoneLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	result, err := us.userService.CompletePhoneLogin(req.PhoneNumber, req.Code, req.FirstName, req.LastName, c.ClientIP())
+	if err != nil {
+		respondPhoneError(c, err)
+		return
+	}
+
+	respondLogin(c, result)
+}
+
+// RequestPhoneVerification texts a code that adds a phone number to the current user
+func (us *UserController) RequestPhoneVerification(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID is required"})
+		return
+	}
+
+	var req PhoneCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	if err := us.userService.RequestPhoneVerification(userID, req.PhoneNumber, c.ClientIP()); err != nil {
+		respondPhoneError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Code sent"})
+}
+
+// VerifyPhone stores the phone number on the current user once the code is confirmed
+func (us *UserController) VerifyPhone(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID is required"})
+		return
+	}
+
+	var req VerifyPhoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	user, err := us.userService.VerifyPhone(userID, req.PhoneNumber, req.Code, req.UseAsWallet)
+	if err != nil {
+		respondPhoneError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newUserResponse(user))
+}
+
+// UsePhoneAsWallet makes the current user's verified phone number their payment wallet
+func (us *UserController) UsePhoneAsWallet(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID is required"})
+		return
+	}
+
+	user, err := us.userService.UsePhoneAsWallet(userID)
+	if err != nil {
+		respondPhoneError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newUserResponse(user))
+}
+
+// respondPhoneError maps phone login errors to status codes
+func respondPhoneError(c *gin.Context, err error) {
+	if respondTooManyAttempts(c, err) {
+		return
+	}
+
+	switch {
+	case errors.Is(err, utils.ErrInvalidPhoneNumber):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidPhoneCode):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrPhoneInUse):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrPhoneNotVerified):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrEmailNotVerified):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case err.Error() == "user not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
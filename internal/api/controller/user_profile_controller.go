@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"errors"
+	"kaabe-app/internal/domain/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetMe returns the profile of the current user
+func (us *UserController) GetMe(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID is required"})
+		return
+	}
+
+	user, err := us.userService.GetUserByID(userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, newUserResponse(user))
+}
+
+// UpdateMe updates the name and wallet of the current user; email, role and password have their own flows
+func (us *UserController) UpdateMe(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID is required"})
+		return
+	}
+
+	var req UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := us.userService.UpdateProfile(userID, req.FirstName, req.LastName, req.WalletID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, newUserResponse(user))
+}
+
+// ChangePassword changes the current user's password and signs out their other sessions
+func (us *UserController) ChangePassword(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID is required"})
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	err := us.userService.ChangePassword(userID, c.GetString("accessToken"), req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		respondProfileError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password changed; other sessions were signed out"})
+}
+
+// RequestEmailChange sends a confirmation link to the new email address
+func (us *UserController) RequestEmailChange(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID is required"})
+		return
+	}
+
+	var req ChangeEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	if err := us.userService.RequestEmailChange(userID, req.CurrentPassword, req.NewEmail); err != nil {
+		respondProfileError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Confirmation link sent to the new email address"})
+}
+
+// ConfirmEmailChange applies an email change from the link sent to the new address
+func (us *UserController) ConfirmEmailChange(c *gin.Context) {
+	var req struct {
+		Token string `json:"token" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	if err := us.userService.ConfirmEmailChange(req.Token); err != nil {
+		respondProfileError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Email changed successfully"})
+}
+
+// respondProfileError maps self-service profile errors to status codes
+func respondProfileError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidCurrentPassword):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrEmailInUse):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrSameEmail):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case err.Error() == "user not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err.Error() == "invalid or expired confirmation token":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
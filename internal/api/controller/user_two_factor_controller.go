@@ -0,0 +1,181 @@
+package controller
+
+import (
+	"errors"
+	"kaabe-app/internal/domain/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// twoFactorCodeRequest carries a TOTP code (or, where allowed, a recovery code)
+type twoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// pendingLoginRequest carries the mfa_token returned by AuthenticateUser
+type pendingLoginRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code"`
+}
+
+// CompleteTwoFactorLogin finishes a login with a TOTP or recovery code
+func (us *UserController) CompleteTwoFactorLogin(c *gin.Context) {
+	var req pendingLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "mfa_token and code are required"})
+		return
+	}
+
+	user, tokens, err := us.userService.CompleteTwoFactorLogin(req.MFAToken, req.Code, c.ClientIP())
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newLoginResponse(user, tokens))
+}
+
+// BeginRequiredEnrollment returns a TOTP secret for a user who must set up 2FA to log in
+func (us *UserController) BeginRequiredEnrollment(c *gin.Context) {
+	var req pendingLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "mfa_token is required"})
+		return
+	}
+
+	enrollment, err := us.userService.BeginRequiredEnrollment(req.MFAToken)
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newTOTPEnrollmentResponse(enrollment))
+}
+
+// CompleteRequiredEnrollment activates 2FA and finishes the login
+func (us *UserController) CompleteRequiredEnrollment(c *gin.Context) {
+	var req pendingLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "mfa_token and code are required"})
+		return
+	}
+
+	user, tokens, recoveryCodes, err := us.userService.CompleteRequiredEnrollment(req.MFAToken, req.Code, c.ClientIP())
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	response := newLoginResponse(user, tokens)
+	response.RecoveryCodes = recoveryCodes
+	c.JSON(http.StatusOK, response)
+}
+
+// BeginTwoFactorEnrollment returns a new TOTP secret and provisioning URI for the current user
+func (us *UserController) BeginTwoFactorEnrollment(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID is required"})
+		return
+	}
+
+	enrollment, err := us.userService.BeginTwoFactorEnrollment(userID)
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newTOTPEnrollmentResponse(enrollment))
+}
+
+// ActivateTwoFactor confirms enrollment with a code and returns recovery codes
+func (us *UserController) ActivateTwoFactor(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID is required"})
+		return
+	}
+
+	var req twoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "code is required"})
+		return
+	}
+
+	recoveryCodes, err := us.userService.ActivateTwoFactor(userID, req.Code)
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, RecoveryCodesResponse{RecoveryCodes: recoveryCodes})
+}
+
+// DisableTwoFactor turns 2FA off for the current user
+func (us *UserController) DisableTwoFactor(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID is required"})
+		return
+	}
+
+	var req twoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "code is required"})
+		return
+	}
+
+	if err := us.userService.DisableTwoFactor(userID, req.Code); err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "two-factor authentication disabled"})
+}
+
+// RegenerateRecoveryCodes replaces the current user's recovery codes
+func (us *UserController) RegenerateRecoveryCodes(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID is required"})
+		return
+	}
+
+	var req twoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "code is required"})
+		return
+	}
+
+	recoveryCodes, err := us.userService.RegenerateRecoveryCodes(userID, req.Code)
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, RecoveryCodesResponse{RecoveryCodes: recoveryCodes})
+}
+
+// respondTwoFactorError maps two-factor errors to HTTP responses
+func respondTwoFactorError(c *gin.Context, err error) {
+	if respondTooManyAttempts(c, err) {
+		return
+	}
+
+	switch {
+	case errors.Is(err, service.ErrInvalidTwoFactorCode):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrTwoFactorAlreadyEnabled),
+		errors.Is(err, service.ErrTwoFactorNotEnabled):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrTwoFactorRequired):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case err.Error() == "invalid or expired mfa token":
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case err.Error() == "user not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
@@ -1,8 +1,10 @@
 package controller
 
 import (
+	"errors"
 	"kaabe-app/internal/domain/model"
 	"kaabe-app/internal/domain/service"
+	"net/http"
  
 	"github.com/gin-gonic/gin"
 	"github.com/gofrs/uuid"
@@ -12,35 +14,52 @@ import (
 // withdrawalController struct that defines the withdrawal controller with its service
 type WithdrawalController struct {
 	WithdrawalService service.WithdrawalService
+	policy            service.AccessPolicy
 }
 
-func NewWithdrawalController(withdrawalService service.WithdrawalService) *WithdrawalController {
-	return &WithdrawalController{WithdrawalService: withdrawalService}
+func NewWithdrawalController(withdrawalService service.WithdrawalService, policy service.AccessPolicy) *WithdrawalController {
+	return &WithdrawalController{WithdrawalService: withdrawalService, policy: policy}
 }
 
 func (wc *WithdrawalController) CreateWithdrawal(ctx * gin.Context) {
-	 var Withdrawal model.Withdrawal
+	 var Withdrawal CreateWithdrawalRequest
 
-	// Bind JSON to the createdWithdrawal struct, which now includes VideoUrl as []string
 	if err := ctx.ShouldBindJSON(&Withdrawal); err != nil {
 		ctx.JSON(400, gin.H{"error": err.Error()})
 		return
 	}
 
+	// Influencers request payouts for themselves; only admins set a status other than pending
+	actor, exists := currentActor(ctx)
+	if !exists {
+		ctx.JSON(401, gin.H{"error": "user ID is required"})
+		return
+	}
+	if !actor.IsAdmin() {
+		Withdrawal.InfluencerID = actor.UserID
+		Withdrawal.Status = "pending"
+	} else if Withdrawal.InfluencerID == uuid.Nil {
+		Withdrawal.InfluencerID = actor.UserID
+	}
+
 	// Call the service with the bound struct's videoUrl slice and WithdrawalD
-	createdWithdrawal, err := wc.WithdrawalService.CreateWithdrawal(Withdrawal.InfluencerID, Withdrawal.Amount, Withdrawal.Status, Withdrawal.RequestedAt, Withdrawal.ProcessedAt )
+	createdWithdrawal, err := wc.WithdrawalService.CreateWithdrawal(Withdrawal.InfluencerID, Withdrawal.Amount.toModel(), Withdrawal.Status, Withdrawal.RequestedAt, Withdrawal.ProcessedAt )
 
 	if err != nil {
+		if isMoneyError(err) {
+			ctx.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(500, gin.H{"error": err.Error()})
 		return
 	}
 
-	ctx.JSON(201, createdWithdrawal)
+	ctx.JSON(201, newWithdrawalResponse(createdWithdrawal))
 }
 
 func (wc *WithdrawalController) UpdateWithdrawal(ctx * gin.Context) {
 	
-	var Withdrawal model.Withdrawal
+	var req UpdateWithdrawalRequest
 
 	WithdrawalIdParam := ctx.Param("id")
 	WithdrawalID, err := uuid.FromString(WithdrawalIdParam)
@@ -49,16 +68,47 @@ func (wc *WithdrawalController) UpdateWithdrawal(ctx * gin.Context) {
 		return
 	}
 
-	// Bind JSON to the Withdrawal struct, which now
-	if err := ctx.ShouldBindJSON(&Withdrawal); err != nil {
+	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(400, gin.H{"error": err.Error()})
 		return
 	}
 
-	Withdrawal.ID = WithdrawalID
+	Withdrawal := req.toModel(WithdrawalID)
+
+	actor, _ := currentActor(ctx)
+	if err := wc.policy.AuthorizeWithdrawal(actor, WithdrawalID); err != nil {
+		respondAccessError(ctx, err)
+		return
+	}
+
+	// Approving or rejecting a payout is reserved to admins, and influencers may only change
+	// requests that are still pending
+	if !actor.IsAdmin() {
+		existing, err := wc.WithdrawalService.GetWithdrawalByID(WithdrawalID)
+		if err != nil {
+			respondAccessError(ctx, err)
+			return
+		}
+		if Withdrawal.Status != existing.Status {
+			ctx.JSON(403, gin.H{"error": "only admins can change a withdrawal status"})
+			return
+		}
+		if existing.Status != "pending" {
+			ctx.JSON(http.StatusConflict, gin.H{"error": service.ErrWithdrawalNotPending.Error()})
+			return
+		}
+	}
 
 	// Call the service with the bound and Withdrawal
-	if err := wc.WithdrawalService.UpdateWithdrawal(&Withdrawal); err != nil {
+	if err := wc.WithdrawalService.UpdateWithdrawal(Withdrawal); err != nil {
+		if isMoneyError(err) {
+			ctx.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrWithdrawalAmountLocked) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(500, gin.H{"error": err.Error()})
 		return
 	}
@@ -71,6 +121,26 @@ func (wc *WithdrawalController) DeleteWithdrawal(ctx * gin.Context) {
 	 WithdrawalId, err := uuid.FromString(WithdrawalParam)
 	 if err != nil {
 	 	ctx.JSON(400, gin.H{"error": "invalid Withdrawal ID"})
+		return
+	}
+
+	actor, _ := currentActor(ctx)
+	if err := wc.policy.AuthorizeWithdrawal(actor, WithdrawalId); err != nil {
+		respondAccessError(ctx, err)
+		return
+	}
+
+	// Influencers can only cancel a request before it is processed
+	if !actor.IsAdmin() {
+		existing, err := wc.WithdrawalService.GetWithdrawalByID(WithdrawalId)
+		if err != nil {
+			respondAccessError(ctx, err)
+			return
+		}
+		if existing.Status != "pending" {
+			ctx.JSON(http.StatusConflict, gin.H{"error": service.ErrWithdrawalNotPending.Error()})
+			return
+		}
 	}
 
 	if err := wc.WithdrawalService.DeleteWithdrawal(WithdrawalId); err != nil {
@@ -90,6 +160,12 @@ func (wc *WithdrawalController) GetWithdrawalByID(ctx * gin.Context) {
 		return
 	}
 
+	actor, _ := currentActor(ctx)
+	if err := wc.policy.AuthorizeWithdrawal(actor, WithdrawalID); err != nil {
+		respondAccessError(ctx, err)
+		return
+	}
+
 	Withdrawal, err := wc.WithdrawalService.GetWithdrawalByID(WithdrawalID)
 	if err != nil {
 		if err.Error() == "Withdrawal not found" {
@@ -101,16 +177,40 @@ func (wc *WithdrawalController) GetWithdrawalByID(ctx * gin.Context) {
 		return
 	}
 
-	ctx.JSON(200, Withdrawal)
+	ctx.JSON(200, newWithdrawalResponse(Withdrawal))
 }
  
-func (wc *WithdrawalController) GetAllWithdrawal(ctx * gin.Context) {
+// GetAllWithdrawal lists withdrawals a page at a time; non-admins only see their own.
+// Filters: ?influencer_id= (admins), ?status=, ?from=, ?to= (requested at); sort: created_at (default, newest first), amount
+func (wc *WithdrawalController) GetAllWithdrawal(ctx *gin.Context) {
+	page, err := bindPageRequest(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var filter model.WithdrawalFilter
+	var influencerErr, statusErr, fromErr, toErr error
+	filter.InfluencerID, influencerErr = queryUUID(ctx, "influencer_id")
+	filter.Status, statusErr = queryOneOf(ctx, "status", "pending", "approved", "rejected")
+	filter.From, fromErr = queryTime(ctx, "from")
+	filter.To, toErr = queryTime(ctx, "to")
+	if err := firstError(influencerErr, statusErr, fromErr, toErr); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	actor, _ := currentActor(ctx)
+	if !actor.IsAdmin() {
+		filter.InfluencerID = &actor.UserID
+	}
+
 	// Call service to get Withdrawal
-	Withdrawal, err := wc.WithdrawalService.GetAllWithdrawal()
+	withdrawals, err := wc.WithdrawalService.GetAllWithdrawal(filter, page)
 	if err != nil {
-		ctx.JSON(500, gin.H{"error": err.Error()})
+		respondListError(ctx, err)
 		return
 	}
 	// respond success
-	ctx.JSON(200, Withdrawal)
+	ctx.JSON(http.StatusOK, newPageResponse(withdrawals, newWithdrawalResponses))
 }
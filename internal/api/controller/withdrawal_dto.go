@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"kaabe-app/internal/domain/model"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// CreateWithdrawalRequest is the body of POST /Withdrawal
+type CreateWithdrawalRequest struct {
+	InfluencerID uuid.UUID    `json:"influencer_id"` // honoured for admins only
+	Amount       MoneyRequest `json:"amount"`
+	Status       string       `json:"status"` // forced to "pending" for non-admins
+	RequestedAt  time.Time    `json:"requested_at"`
+	ProcessedAt  time.Time    `json:"processed_at"`
+}
+
+// UpdateWithdrawalRequest is the body of PUT /Withdrawal/:id
+type UpdateWithdrawalRequest struct {
+	Amount      MoneyRequest `json:"amount"`
+	Status      string       `json:"status" binding:"required"`
+	ProcessedAt time.Time    `json:"processed_at"`
+}
+
+// WithdrawalResponse is the public view of a withdrawal
+type WithdrawalResponse struct {
+	ID           uuid.UUID     `json:"id"`
+	InfluencerID uuid.UUID     `json:"influencer_id"`
+	Amount       MoneyResponse `json:"amount"`
+	Status       string        `json:"status"`
+	RequestedAt  time.Time     `json:"requested_at"`
+	ProcessedAt  *time.Time    `json:"processed_at,omitempty"` // nil until processed
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+}
+
+func (r UpdateWithdrawalRequest) toModel(id uuid.UUID) *model.Withdrawal {
+	return &model.Withdrawal{
+		ID:          id,
+		Amount:      r.Amount.toModel(),
+		Status:      r.Status,
+		ProcessedAt: r.ProcessedAt,
+	}
+}
+
+func newWithdrawalResponse(withdrawal *model.Withdrawal) WithdrawalResponse {
+	response := WithdrawalResponse{
+		ID:           withdrawal.ID,
+		InfluencerID: withdrawal.InfluencerID,
+		Amount:       newMoneyResponse(withdrawal.Amount),
+		Status:       withdrawal.Status,
+		RequestedAt:  withdrawal.RequestedAt,
+		CreatedAt:    withdrawal.CreatedAt,
+		UpdatedAt:    withdrawal.UpdatedAt,
+	}
+	if !withdrawal.ProcessedAt.IsZero() {
+		processedAt := withdrawal.ProcessedAt
+		response.ProcessedAt = &processedAt
+	}
+	return response
+}
+
+func newWithdrawalResponses(withdrawals []*model.Withdrawal) []WithdrawalResponse {
+	responses := make([]WithdrawalResponse, 0, len(withdrawals))
+	for _, withdrawal := range withdrawals {
+		responses = append(responses, newWithdrawalResponse(withdrawal))
+	}
+	return responses
+}
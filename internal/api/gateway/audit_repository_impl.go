@@ -0,0 +1,58 @@
+package gateway
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/repository"
+	"log"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+type auditRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewAuditRepository returns a new AuditRepository instance
+func NewAuditRepository(db *sql.DB) repository.AuditRepository {
+	return &auditRepositoryImpl{db: db}
+}
+
+// Create stores an audit entry
+func (a *auditRepositoryImpl) Create(entry *model.AuditLog) error {
+	if entry.ID == uuid.Nil {
+		id, err := uuid.NewV4()
+		if err != nil {
+			return fmt.Errorf("failed to generate UUID: %v", err)
+		}
+		entry.ID = id
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	metadata, err := json.Marshal(entry.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit metadata: %v", err)
+	}
+	if entry.Metadata == nil {
+		metadata = []byte("{}")
+	}
+
+	_, err = a.db.Exec(`CALL create_audit_log($1, $2, $3, $4, $5, $6)`,
+		entry.ID,
+		entry.UserID,
+		entry.Action,
+		entry.Subject,
+		entry.IPAddress,
+		string(metadata),
+	)
+	if err != nil {
+		log.Printf("Error calling create_audit_log: %v", err)
+		return err
+	}
+	return nil
+}
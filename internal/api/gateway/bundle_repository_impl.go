@@ -0,0 +1,276 @@
+package gateway
+
+import (
+	"database/sql"
+	"fmt"
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/repository"
+	"log"
+
+	"github.com/gofrs/uuid"
+	"github.com/lib/pq"
+)
+
+type bundleRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewBundleRepository returns a new BundleRepository instance
+func NewBundleRepository(db *sql.DB) repository.BundleRepository {
+	return &bundleRepositoryImpl{db: db}
+}
+
+const bundleColumns = `id, influencer_id, title, description, price_amount, price_currency::text, access_days, active,
+	bundle_course_ids(id)::text[], created_at, updated_at`
+
+const influencerPassColumns = `influencer_id, price_amount, price_currency::text, access_days, active, created_at, updated_at`
+
+const purchaseColumns = `id, user_id, payment_id, bundle_id, pass_influencer_id, course_id,
+	ARRAY(SELECT course_id::text FROM subscriptions WHERE purchase_id = purchases.id ORDER BY created_at),
+	started_at, expires_at, created_at`
+
+func (r *bundleRepositoryImpl) CreateBundle(bundle *model.Bundle) error {
+	row := r.db.QueryRow(
+		`SELECT `+bundleColumns+` FROM create_bundle($1, $2, $3, $4, $5, $6, $7, $8::uuid[])`,
+		bundle.InfluencerID, bundle.Title, bundle.Description, bundle.Price.Amount, bundle.Price.Currency,
+		bundle.AccessDays, bundle.Active, pq.Array(uuidStrings(bundle.CourseIDs)),
+	)
+
+	created, err := scanBundle(row)
+	if err != nil {
+		log.Printf("Error calling create_bundle: %v", err)
+		return fmt.Errorf("failed to create bundle: %w", err)
+	}
+
+	*bundle = *created
+	return nil
+}
+
+func (r *bundleRepositoryImpl) UpdateBundle(bundle *model.Bundle) error {
+	row := r.db.QueryRow(
+		`SELECT `+bundleColumns+` FROM update_bundle($1, $2, $3, $4, $5, $6, $7, $8::uuid[])`,
+		bundle.ID, bundle.Title, bundle.Description, bundle.Price.Amount, bundle.Price.Currency,
+		bundle.AccessDays, bundle.Active, pq.Array(uuidStrings(bundle.CourseIDs)),
+	)
+
+	updated, err := scanBundle(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("bundle %w", model.ErrNotFound)
+		}
+		log.Printf("Error calling update_bundle: %v", err)
+		return fmt.Errorf("failed to update bundle: %w", err)
+	}
+
+	*bundle = *updated
+	return nil
+}
+
+func (r *bundleRepositoryImpl) DeleteBundle(bundleID uuid.UUID) (bool, error) {
+	var deleted int
+	if err := r.db.QueryRow(`SELECT delete_bundle($1)`, bundleID).Scan(&deleted); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			return false, model.ErrBundlePurchased
+		}
+		log.Printf("Error calling delete_bundle: %v", err)
+		return false, err
+	}
+	return deleted > 0, nil
+}
+
+func (r *bundleRepositoryImpl) GetBundle(bundleID uuid.UUID) (*model.Bundle, error) {
+	bundle, err := scanBundle(r.db.QueryRow(`SELECT `+bundleColumns+` FROM bundles WHERE id = $1`, bundleID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("bundle %w", model.ErrNotFound)
+		}
+		log.Printf("Error getting bundle: %v", err)
+		return nil, err
+	}
+	return bundle, nil
+}
+
+func (r *bundleRepositoryImpl) ListBundles(filter model.BundleFilter, page model.PageRequest) (*model.Page[*model.Bundle], error) {
+	q := &listQuery[*model.Bundle]{
+		name:    "bundles",
+		columns: bundleColumns,
+		from:    "bundles",
+		sorts: map[string]sortColumn[*model.Bundle]{
+			"created_at": {expr: "created_at", cast: "timestamp", value: func(b *model.Bundle) string { return timeCursor(b.CreatedAt) }},
+			"price":      {expr: "price_amount", cast: "bigint", value: func(b *model.Bundle) string { return intCursor(b.Price.Amount) }},
+			"title":      {expr: "title", cast: "text", value: func(b *model.Bundle) string { return b.Title }},
+		},
+		defaultSort: "-created_at",
+		id:          func(b *model.Bundle) uuid.UUID { return b.ID },
+		scan:        scanBundle,
+	}
+
+	if filter.InfluencerID != nil {
+		q.where("influencer_id = ?", *filter.InfluencerID)
+	}
+	if filter.CourseID != nil {
+		q.where("id IN (SELECT bundle_id FROM bundle_courses WHERE course_id = ?)", *filter.CourseID)
+	}
+	if filter.Active != nil {
+		q.where("active = ?", *filter.Active)
+	}
+
+	return q.run(r.db, page)
+}
+
+func (r *bundleRepositoryImpl) SetPass(pass *model.InfluencerPass) error {
+	row := r.db.QueryRow(
+		`SELECT `+influencerPassColumns+` FROM set_influencer_pass($1, $2, $3, $4, $5)`,
+		pass.InfluencerID, pass.Price.Amount, pass.Price.Currency, pass.AccessDays, pass.Active,
+	)
+
+	saved, err := scanInfluencerPass(row)
+	if err != nil {
+		log.Printf("Error calling set_influencer_pass: %v", err)
+		return fmt.Errorf("failed to save pass: %w", err)
+	}
+
+	*pass = *saved
+	return nil
+}
+
+func (r *bundleRepositoryImpl) GetPass(influencerID uuid.UUID) (*model.InfluencerPass, error) {
+	pass, err := scanInfluencerPass(r.db.QueryRow(`SELECT `+influencerPassColumns+` FROM influencer_passes WHERE influencer_id = $1`, influencerID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("pass %w", model.ErrNotFound)
+		}
+		log.Printf("Error getting influencer pass: %v", err)
+		return nil, err
+	}
+	return pass, nil
+}
+
+func (r *bundleRepositoryImpl) ListPurchases(userID uuid.UUID, page model.PageRequest) (*model.Page[*model.Purchase], error) {
+	q := &listQuery[*model.Purchase]{
+		name:    "purchases",
+		columns: purchaseColumns,
+		from:    "purchases",
+		sorts: map[string]sortColumn[*model.Purchase]{
+			"created_at": {expr: "created_at", cast: "timestamptz", value: func(p *model.Purchase) string { return timeCursor(p.CreatedAt) }},
+			"expires_at": {expr: "expires_at", cast: "timestamptz", value: func(p *model.Purchase) string { return timeCursor(p.ExpiresAt) }},
+		},
+		defaultSort: "-created_at",
+		id:          func(p *model.Purchase) uuid.UUID { return p.ID },
+		scan:        scanPurchase,
+	}
+
+	// Purchases whose payment failed or was deleted are revoked
+	q.where("user_id = ?", userID)
+	q.where("revoked_at IS NULL")
+	return q.run(r.db, page)
+}
+
+func (r *bundleRepositoryImpl) GrantPassCourse(courseID uuid.UUID) (int, error) {
+	var granted int
+	if err := r.db.QueryRow(`SELECT grant_pass_course($1)`, courseID).Scan(&granted); err != nil {
+		log.Printf("Error calling grant_pass_course: %v", err)
+		return 0, err
+	}
+	return granted, nil
+}
+
+func scanBundle(row rowScanner) (*model.Bundle, error) {
+	var bundle model.Bundle
+	var courseIDs []string
+	err := row.Scan(
+		&bundle.ID,
+		&bundle.InfluencerID,
+		&bundle.Title,
+		&bundle.Description,
+		&bundle.Price.Amount,
+		&bundle.Price.Currency,
+		&bundle.AccessDays,
+		&bundle.Active,
+		pq.Array(&courseIDs),
+		&bundle.CreatedAt,
+		&bundle.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if bundle.CourseIDs, err = parseUUIDs(courseIDs); err != nil {
+		return nil, err
+	}
+	return &bundle, nil
+}
+
+func scanInfluencerPass(row rowScanner) (*model.InfluencerPass, error) {
+	var pass model.InfluencerPass
+	err := row.Scan(
+		&pass.InfluencerID,
+		&pass.Price.Amount,
+		&pass.Price.Currency,
+		&pass.AccessDays,
+		&pass.Active,
+		&pass.CreatedAt,
+		&pass.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &pass, nil
+}
+
+func scanPurchase(row rowScanner) (*model.Purchase, error) {
+	var purchase model.Purchase
+	var bundleID, passInfluencerID, courseID uuid.NullUUID
+	var courseIDs []string
+	err := row.Scan(
+		&purchase.ID,
+		&purchase.UserID,
+		&purchase.PaymentID,
+		&bundleID,
+		&passInfluencerID,
+		&courseID,
+		pq.Array(&courseIDs),
+		&purchase.StartedAt,
+		&purchase.ExpiresAt,
+		&purchase.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if bundleID.Valid {
+		purchase.BundleID = &bundleID.UUID
+	}
+	if passInfluencerID.Valid {
+		purchase.PassInfluencerID = &passInfluencerID.UUID
+	}
+	if courseID.Valid {
+		purchase.CourseID = &courseID.UUID
+	}
+	if purchase.CourseIDs, err = parseUUIDs(courseIDs); err != nil {
+		return nil, err
+	}
+	return &purchase, nil
+}
+
+// uuidStrings passes a list of IDs as a Postgres uuid[] parameter
+func uuidStrings(ids []uuid.UUID) []string {
+	strs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		strs = append(strs, id.String())
+	}
+	return strs
+}
+
+// parseUUIDs reads a uuid[] column selected as text[]
+func parseUUIDs(strs []string) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(strs))
+	for _, s := range strs {
+		id, err := uuid.FromString(s)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
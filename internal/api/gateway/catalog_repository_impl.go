@@ -0,0 +1,291 @@
+package gateway
+
+import (
+	"database/sql"
+	"fmt"
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/repository"
+	"log"
+
+	"github.com/gofrs/uuid"
+	"github.com/lib/pq"
+)
+
+type catalogRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewCatalogRepository returns a new CatalogRepository instance
+func NewCatalogRepository(db *sql.DB) repository.CatalogRepository {
+	return &catalogRepositoryImpl{db: db}
+}
+
+const categoryColumns = `id, parent_id, name, slug, position, created_at, updated_at`
+
+const collectionColumns = `id, name, slug, description, position, created_at, updated_at`
+
+func (c *catalogRepositoryImpl) CreateCategory(category *model.Category) error {
+	row := c.db.QueryRow(
+		`SELECT `+categoryColumns+` FROM create_category($1, $2, $3, $4)`,
+		nullUUID(category.ParentID), category.Name, category.Slug, category.Position,
+	)
+
+	created, err := scanCategory(row)
+	if err != nil {
+		if err := categoryWriteError(err); err != nil {
+			return err
+		}
+		log.Printf("Error calling create_category: %v", err)
+		return fmt.Errorf("failed to create category: %w", err)
+	}
+
+	*category = *created
+	return nil
+}
+
+func (c *catalogRepositoryImpl) UpdateCategory(category *model.Category) error {
+	row := c.db.QueryRow(
+		`SELECT `+categoryColumns+` FROM update_category($1, $2, $3, $4, $5)`,
+		category.ID, nullUUID(category.ParentID), category.Name, category.Slug, category.Position,
+	)
+
+	updated, err := scanCategory(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("category %w", model.ErrNotFound)
+		}
+		if err := categoryWriteError(err); err != nil {
+			return err
+		}
+		log.Printf("Error calling update_category: %v", err)
+		return fmt.Errorf("failed to update category: %w", err)
+	}
+
+	*category = *updated
+	return nil
+}
+
+func (c *catalogRepositoryImpl) DeleteCategory(categoryID uuid.UUID) (bool, error) {
+	var deleted int
+	if err := c.db.QueryRow(`SELECT delete_category($1)`, categoryID).Scan(&deleted); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			return false, fmt.Errorf("category has subcategories")
+		}
+		log.Printf("Error calling delete_category: %v", err)
+		return false, err
+	}
+	return deleted > 0, nil
+}
+
+func (c *catalogRepositoryImpl) GetCategory(categoryID uuid.UUID) (*model.Category, error) {
+	category, err := scanCategory(c.db.QueryRow(`SELECT `+categoryColumns+` FROM get_category($1)`, categoryID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("category %w", model.ErrNotFound)
+		}
+		log.Printf("Error calling get_category: %v", err)
+		return nil, err
+	}
+	return category, nil
+}
+
+func (c *catalogRepositoryImpl) ListCategories() ([]*model.Category, error) {
+	rows, err := c.db.Query(`SELECT ` + categoryColumns + ` FROM get_categories()`)
+	if err != nil {
+		log.Printf("Error calling get_categories: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []*model.Category
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+	return categories, rows.Err()
+}
+
+func (c *catalogRepositoryImpl) ListPopularTags(limit int) ([]*model.TagCount, error) {
+	rows, err := c.db.Query(`SELECT name, course_count FROM get_popular_tags($1)`, limit)
+	if err != nil {
+		log.Printf("Error calling get_popular_tags: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []*model.TagCount
+	for rows.Next() {
+		var tag model.TagCount
+		if err := rows.Scan(&tag.Name, &tag.CourseCount); err != nil {
+			return nil, err
+		}
+		tags = append(tags, &tag)
+	}
+	return tags, rows.Err()
+}
+
+func (c *catalogRepositoryImpl) CreateCollection(collection *model.Collection) error {
+	row := c.db.QueryRow(
+		`SELECT `+collectionColumns+` FROM create_collection($1, $2, $3, $4)`,
+		collection.Name, collection.Slug, collection.Description, collection.Position,
+	)
+
+	created, err := scanCollection(row)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return fmt.Errorf("slug %w", model.ErrAlreadyInUse)
+		}
+		log.Printf("Error calling create_collection: %v", err)
+		return fmt.Errorf("failed to create collection: %w", err)
+	}
+
+	*collection = *created
+	return nil
+}
+
+func (c *catalogRepositoryImpl) UpdateCollection(collection *model.Collection) error {
+	row := c.db.QueryRow(
+		`SELECT `+collectionColumns+` FROM update_collection($1, $2, $3, $4, $5)`,
+		collection.ID, collection.Name, collection.Slug, collection.Description, collection.Position,
+	)
+
+	updated, err := scanCollection(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("collection %w", model.ErrNotFound)
+		}
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return fmt.Errorf("slug %w", model.ErrAlreadyInUse)
+		}
+		log.Printf("Error calling update_collection: %v", err)
+		return fmt.Errorf("failed to update collection: %w", err)
+	}
+
+	*collection = *updated
+	return nil
+}
+
+func (c *catalogRepositoryImpl) DeleteCollection(collectionID uuid.UUID) (bool, error) {
+	var deleted int
+	if err := c.db.QueryRow(`SELECT delete_collection($1)`, collectionID).Scan(&deleted); err != nil {
+		log.Printf("Error calling delete_collection: %v", err)
+		return false, err
+	}
+	return deleted > 0, nil
+}
+
+func (c *catalogRepositoryImpl) GetCollection(collectionID uuid.UUID) (*model.Collection, error) {
+	return c.getCollection(`get_collection`, collectionID)
+}
+
+func (c *catalogRepositoryImpl) GetCollectionBySlug(slug string) (*model.Collection, error) {
+	return c.getCollection(`get_collection_by_slug`, slug)
+}
+
+func (c *catalogRepositoryImpl) getCollection(function string, key interface{}) (*model.Collection, error) {
+	collection, err := scanCollection(c.db.QueryRow(`SELECT `+collectionColumns+` FROM `+function+`($1)`, key))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("collection %w", model.ErrNotFound)
+		}
+		log.Printf("Error calling %s: %v", function, err)
+		return nil, err
+	}
+	return collection, nil
+}
+
+func (c *catalogRepositoryImpl) ListCollections() ([]*model.Collection, error) {
+	rows, err := c.db.Query(`SELECT ` + collectionColumns + ` FROM get_collections()`)
+	if err != nil {
+		log.Printf("Error calling get_collections: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var collections []*model.Collection
+	for rows.Next() {
+		collection, err := scanCollection(rows)
+		if err != nil {
+			return nil, err
+		}
+		collections = append(collections, collection)
+	}
+	return collections, rows.Err()
+}
+
+func (c *catalogRepositoryImpl) SetCollectionCourses(collectionID uuid.UUID, courseIDs []uuid.UUID) error {
+	if _, err := c.db.Exec(`SELECT set_collection_courses($1, $2::uuid[])`, collectionID, pq.Array(uuidStrings(courseIDs))); err != nil {
+		log.Printf("Error calling set_collection_courses: %v", err)
+		return fmt.Errorf("failed to set collection courses: %w", err)
+	}
+	return nil
+}
+
+func (c *catalogRepositoryImpl) ListCollectionCourses(collectionID uuid.UUID) ([]*model.Course, error) {
+	rows, err := c.db.Query(`SELECT `+courseColumns+` FROM collection_courses
+		JOIN courses ON courses.id = collection_courses.course_id
+		WHERE collection_courses.collection_id = $1 AND courses.status = 'published' AND courses.deleted_at IS NULL
+		ORDER BY collection_courses.position`, collectionID)
+	if err != nil {
+		log.Printf("Error listing collection courses: %v", err)
+		return nil, err
+	}
+	return scanCourseRows(rows)
+}
+
+// categoryWriteError maps constraint violations on categories to repository errors; nil for any other error
+func categoryWriteError(err error) error {
+	if pqErr, ok := err.(*pq.Error); ok {
+		switch pqErr.Code {
+		case "23505":
+			return fmt.Errorf("slug %w", model.ErrAlreadyInUse)
+		case "23503":
+			return fmt.Errorf("parent category %w", model.ErrNotFound)
+		}
+	}
+	return nil
+}
+
+func scanCategory(row rowScanner) (*model.Category, error) {
+	var category model.Category
+	var parentID uuid.NullUUID
+
+	err := row.Scan(
+		&category.ID,
+		&parentID,
+		&category.Name,
+		&category.Slug,
+		&category.Position,
+		&category.CreatedAt,
+		&category.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if parentID.Valid {
+		category.ParentID = &parentID.UUID
+	}
+	return &category, nil
+}
+
+func scanCollection(row rowScanner) (*model.Collection, error) {
+	var collection model.Collection
+
+	err := row.Scan(
+		&collection.ID,
+		&collection.Name,
+		&collection.Slug,
+		&collection.Description,
+		&collection.Position,
+		&collection.CreatedAt,
+		&collection.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &collection, nil
+}
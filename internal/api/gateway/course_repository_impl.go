@@ -6,6 +6,8 @@ import (
 	"kaabe-app/internal/domain/model"
 	"kaabe-app/internal/domain/repository"
 	"log"
+	"strconv"
+	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/lib/pq"
@@ -21,12 +23,13 @@ func NewCourseRepository(db *sql.DB) repository.CourseRepository {
 
 // Create inserts a new course using the stored procedure
 func (r *CourseRepositoryImpl) Create(course *model.Course) error {
-	_, err := r.db.Exec(`CALL create_course($1, $2, $3, $4, $5, $6, $7)`,
+	_, err := r.db.Exec(`CALL create_course($1, $2, $3, $4, $5, $6, $7, $8)`,
 		course.ID, // Include course.ID here
 		course.InfluencerID,
 		course.Title,
 		course.Description,
-		course.Price,
+		course.Price.Amount,
+		course.Price.Currency,
 		pq.Array(course.CoverImageURL),
 		course.Status,
 	)
@@ -42,8 +45,8 @@ func (r *CourseRepositoryImpl) Create(course *model.Course) error {
 
 // Update modifies an existing course using the stored procedure
 func (r *CourseRepositoryImpl) Update(course *model.Course) error {
-	_, err := r.db.Exec(`CALL update_course($1, $2, $3, $4, $5, $6)`,
-		course.ID, course.Title, course.Description, course.Price, pq.Array(course.CoverImageURL), course.Status)
+	_, err := r.db.Exec(`CALL update_course($1, $2, $3, $4, $5, $6, $7)`,
+		course.ID, course.Title, course.Description, course.Price.Amount, course.Price.Currency, pq.Array(course.CoverImageURL), course.Status)
 	if err != nil {
 		log.Printf("Error calling update_course: %v", err)
 		return err
@@ -65,72 +68,422 @@ func (r *CourseRepositoryImpl) Delete(courseID uuid.UUID) error {
 	return nil
 }
 
-// GetByID retrieves a single course by its ID using the get_course_by_id() function
+// GetByID retrieves a single live course by its ID
 func (r *CourseRepositoryImpl) GetByID(courseID uuid.UUID) (*model.Course, error) {
-	var course model.Course
-
-	query := `SELECT * FROM get_course_by_id($1)`
-	row := r.db.QueryRow(query, courseID)
-
-	err := row.Scan(
-		&course.ID,
-		&course.InfluencerID,
-		&course.Title,
-		&course.Description,
-		&course.Price,
-		pq.Array(&course.CoverImageURL),
-		&course.Status,
-		&course.CreatedAt,
-		&course.UpdatedAt,
-	)
+	query := `SELECT ` + courseColumns + ` FROM courses WHERE id = $1 AND deleted_at IS NULL`
+	course, err := scanCourse(r.db.QueryRow(query, courseID))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Printf("course not found")
-			return nil, fmt.Errorf("course not found")
+			return nil, fmt.Errorf("course %w", model.ErrNotFound)
 		}
 		log.Printf("DB error: %v", err)
 		return nil, err
 	}
 
-	return &course, nil
+	return course, nil
+}
+
+// List retrieves one page of courses matching the filter
+func (r *CourseRepositoryImpl) List(filter model.CourseFilter, page model.PageRequest) (*model.Page[*model.Course], error) {
+	q := &listQuery[*model.Course]{
+		name:    "courses",
+		columns: courseColumns,
+		from:    "courses",
+		sorts: map[string]sortColumn[*model.Course]{
+			"created_at": {expr: "created_at", cast: "timestamp", value: func(c *model.Course) string { return timeCursor(c.CreatedAt) }},
+			"price":      {expr: "money_major(price_amount, price_currency)", cast: "numeric", value: func(c *model.Course) string { return c.Price.Decimal() }},
+			"title":      {expr: "title", cast: "text", value: func(c *model.Course) string { return c.Title }},
+			"submitted_at": {expr: "COALESCE(submitted_at, created_at)", cast: "timestamp", value: func(c *model.Course) string {
+				if c.SubmittedAt != nil {
+					return timeCursor(*c.SubmittedAt)
+				}
+				return timeCursor(c.CreatedAt)
+			}},
+		},
+		defaultSort: "-created_at",
+		id:          func(c *model.Course) uuid.UUID { return c.ID },
+		scan:        scanCourse,
+	}
+
+	q.where("deleted_at IS NULL")
+	if filter.VisibleTo != nil {
+		q.where("(status = 'published' OR influencer_id = ?)", *filter.VisibleTo)
+	}
+	if filter.Status != "" {
+		q.where("status::text = ?", filter.Status)
+	}
+	if filter.AwaitingReview {
+		q.where("status = 'in_review' AND approved_at IS NULL")
+	}
+	if filter.InfluencerID != nil {
+		q.where("influencer_id = ?", *filter.InfluencerID)
+	}
+	if filter.Currency != "" {
+		q.where("course_price_in(id, ?) IS NOT NULL", filter.Currency)
+		if filter.MinPrice != nil {
+			q.where("course_price_in(id, ?) >= ?", filter.Currency, *filter.MinPrice)
+		}
+		if filter.MaxPrice != nil {
+			q.where("course_price_in(id, ?) <= ?", filter.Currency, *filter.MaxPrice)
+		}
+	}
+	if filter.Category != "" {
+		q.where("category_id IN (SELECT category_subtree(id) FROM categories WHERE slug = ?)", filter.Category)
+	}
+	if filter.Tag != "" {
+		q.where(`EXISTS (SELECT 1 FROM course_tags JOIN tags ON tags.id = course_tags.tag_id
+			WHERE course_tags.course_id = courses.id AND tags.name = ?)`, filter.Tag)
+	}
+
+	return q.run(r.db, page)
+}
+
+// ListPublishedByInfluencer retrieves an influencer's published courses, newest first
+func (r *CourseRepositoryImpl) ListPublishedByInfluencer(influencerID uuid.UUID) ([]*model.Course, error) {
+	rows, err := r.db.Query(`SELECT `+courseColumns+` FROM courses
+		WHERE influencer_id = $1 AND status = 'published' AND deleted_at IS NULL
+		ORDER BY created_at DESC`, influencerID)
+	if err != nil {
+		log.Printf("Error listing influencer courses: %v", err)
+		return nil, err
+	}
+	return scanCourseRows(rows)
+}
+
+// SetTaxonomy sets the course's category and tags using the set_course_taxonomy() function
+func (r *CourseRepositoryImpl) SetTaxonomy(courseID uuid.UUID, categoryID *uuid.UUID, tags []string) (bool, error) {
+	var updated int
+	err := r.db.QueryRow(`SELECT set_course_taxonomy($1, $2, $3)`, courseID, nullUUID(categoryID), pq.Array(tags)).Scan(&updated)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			return false, fmt.Errorf("category %w", model.ErrNotFound)
+		}
+		log.Printf("Error calling set_course_taxonomy: %v", err)
+		return false, err
+	}
+	return updated > 0, nil
+}
+
+// CountLessons counts the course's live lessons using the count_course_lessons() function
+func (r *CourseRepositoryImpl) CountLessons(courseID uuid.UUID) (int, error) {
+	var count int
+	if err := r.db.QueryRow(`SELECT count_course_lessons($1)`, courseID).Scan(&count); err != nil {
+		log.Printf("Error calling count_course_lessons: %v", err)
+		return 0, err
+	}
+	return count, nil
+}
+
+// Transition changes the course status using the transition_course() function
+func (r *CourseRepositoryImpl) Transition(courseID uuid.UUID, from, to string) (bool, error) {
+	var updated int
+	if err := r.db.QueryRow(`SELECT transition_course($1, $2, $3)`, courseID, from, to).Scan(&updated); err != nil {
+		log.Printf("Error calling transition_course: %v", err)
+		return false, err
+	}
+
+	log.Printf("Course %s moved from %s to %s: %t", courseID, from, to, updated > 0)
+	return updated > 0, nil
+}
+
+// Review records a review decision using the review_course() function
+func (r *CourseRepositoryImpl) Review(courseID, reviewerID uuid.UUID, approve bool, reason string) (string, error) {
+	var status sql.NullString
+	err := r.db.QueryRow(`SELECT review_course($1, $2, $3, $4)`, courseID, reviewerID, approve, nullString(reason)).Scan(&status)
+	if err != nil {
+		log.Printf("Error calling review_course: %v", err)
+		return "", err
+	}
+	return status.String, nil
+}
+
+// Schedule sets the release times using the schedule_course() function
+func (r *CourseRepositoryImpl) Schedule(courseID uuid.UUID, publishAt, unpublishAt *time.Time) (bool, error) {
+	var updated int
+	err := r.db.QueryRow(`SELECT schedule_course($1, $2, $3)`, courseID, publishAt, unpublishAt).Scan(&updated)
+	if err != nil {
+		log.Printf("Error calling schedule_course: %v", err)
+		return false, err
+	}
+	return updated > 0, nil
+}
+
+// ListDueReleases retrieves the courses the get_due_course_releases() function reports as due
+func (r *CourseRepositoryImpl) ListDueReleases(limit int) ([]*model.Course, error) {
+	rows, err := r.db.Query(`SELECT `+courseColumns+` FROM courses
+		WHERE id IN (SELECT id FROM get_due_course_releases($1))`, limit)
+	if err != nil {
+		log.Printf("Error calling get_due_course_releases: %v", err)
+		return nil, err
+	}
+	return scanCourseRows(rows)
+}
+
+// SetPrice sets a price using the set_course_price() function
+func (r *CourseRepositoryImpl) SetPrice(courseID uuid.UUID, price model.Money) (bool, error) {
+	var updated int
+	err := r.db.QueryRow(`SELECT set_course_price($1, $2, $3)`, courseID, price.Currency, price.Amount).Scan(&updated)
+	if err != nil {
+		log.Printf("Error calling set_course_price: %v", err)
+		return false, err
+	}
+
+	log.Printf("Course %s priced at %s", courseID, price)
+	return updated > 0, nil
+}
+
+// DeletePrice removes a price list entry using the delete_course_price() function
+func (r *CourseRepositoryImpl) DeletePrice(courseID uuid.UUID, currency string) (bool, error) {
+	var deleted int
+	if err := r.db.QueryRow(`SELECT delete_course_price($1, $2)`, courseID, currency).Scan(&deleted); err != nil {
+		log.Printf("Error calling delete_course_price: %v", err)
+		return false, err
+	}
+	return deleted > 0, nil
+}
+
+// ListPriceHistory retrieves one page of the course's price changes
+func (r *CourseRepositoryImpl) ListPriceHistory(courseID uuid.UUID, currency string, page model.PageRequest) (*model.Page[*model.CoursePriceChange], error) {
+	q := &listQuery[*model.CoursePriceChange]{
+		name:    "course price history",
+		columns: `id, course_id, currency::text, amount, changed_at`,
+		from:    "course_price_history",
+		sorts: map[string]sortColumn[*model.CoursePriceChange]{
+			"changed_at": {expr: "changed_at", cast: "timestamp", value: func(c *model.CoursePriceChange) string { return timeCursor(c.ChangedAt) }},
+		},
+		defaultSort: "-changed_at",
+		id:          func(c *model.CoursePriceChange) uuid.UUID { return c.ID },
+		scan:        scanCoursePriceChange,
+	}
+
+	q.where("course_id = ?", courseID)
+	if currency != "" {
+		q.where("currency = ?", currency)
+	}
+
+	return q.run(r.db, page)
+}
+
+func scanCoursePriceChange(row rowScanner) (*model.CoursePriceChange, error) {
+	var change model.CoursePriceChange
+	var amount sql.NullInt64
+	if err := row.Scan(&change.ID, &change.CourseID, &change.Currency, &amount, &change.ChangedAt); err != nil {
+		return nil, err
+	}
+	if amount.Valid {
+		change.Amount = &amount.Int64
+	}
+	return &change, nil
 }
 
-// GetAll retrieves all non-deleted courses using the get_all_courses() function
-func (r *CourseRepositoryImpl) GetAll() ([]*model.Course, error) {
-	rows, err := r.db.Query(`SELECT * FROM get_all_courses()`)
+// relevanceSort is the only order of search results
+const relevanceSort = "relevance"
+
+// Search retrieves one page of matches using the search_courses() and search_course_facets() functions.
+// Relevance is not a stable keyset, so the cursor carries the offset of the next page.
+func (r *CourseRepositoryImpl) Search(search model.CourseSearch, page model.PageRequest) (*model.CourseSearchResult, error) {
+	if page.Sort != "" && page.Sort != relevanceSort {
+		return nil, model.ErrInvalidSort
+	}
+
+	limit := pageLimit(page)
+
+	offset := 0
+	if page.Cursor != "" {
+		cursor, err := decodeCursor(page.Cursor)
+		if err != nil || cursor.Sort != relevanceSort {
+			return nil, model.ErrInvalidCursor
+		}
+		offset, err = strconv.Atoi(cursor.Value)
+		if err != nil || offset < 0 {
+			return nil, model.ErrInvalidCursor
+		}
+	}
+
+	viewerID := nullUUID(search.VisibleTo)
+	var minRating sql.NullFloat64
+	if search.MinRating != nil {
+		minRating = sql.NullFloat64{Float64: *search.MinRating, Valid: true}
+	}
+	all := search.VisibleTo == nil
+
+	rows, err := r.db.Query(`SELECT `+courseColumns+`, s.relevance, s.fuzzy, s.title_highlight,
+		s.description_highlight, s.average_rating, s.rating_count
+		FROM search_courses($1, $2, $3, $4, $5, $6, $7) s
+		JOIN courses ON courses.id = s.course_id
+		ORDER BY s.relevance DESC, s.course_id`,
+		search.Query, viewerID, all, nullString(search.PriceBucket), minRating, limit+1, offset)
 	if err != nil {
-		log.Printf("Error querying get_all_courses: %v", err)
+		log.Printf("Error querying search_courses: %v", err)
 		return nil, err
 	}
 	defer rows.Close()
 
+	result := &model.CourseSearchResult{Hits: &model.Page[*model.CourseSearchHit]{}}
+	hits := make([]*model.CourseSearchHit, 0, limit)
+	for rows.Next() {
+		var hit model.CourseSearchHit
+		var fuzzy bool
+
+		course, err := scanCourseWith(rows,
+			&hit.Relevance,
+			&fuzzy,
+			&hit.TitleHighlight,
+			&hit.DescriptionHighlight,
+			&hit.AverageRating,
+			&hit.RatingCount,
+		)
+		if err != nil {
+			log.Printf("Error scanning search result row: %v", err)
+			return nil, err
+		}
+
+		hit.Course = course
+		result.Fuzzy = fuzzy
+		hits = append(hits, &hit)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Row iteration error: %v", err)
+		return nil, err
+	}
+
+	result.Hits.Items = hits
+	if len(hits) > limit {
+		result.Hits.Items = hits[:limit]
+		result.Hits.NextCursor = encodeCursor(listCursor{Sort: relevanceSort, Value: strconv.Itoa(offset + limit)})
+	}
+
+	result.Facets, err = r.searchFacets(search, viewerID, all)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *CourseRepositoryImpl) searchFacets(search model.CourseSearch, viewerID uuid.NullUUID, all bool) (map[string][]model.FacetBucket, error) {
+	rows, err := r.db.Query(`SELECT * FROM search_course_facets($1, $2, $3)`, search.Query, viewerID, all)
+	if err != nil {
+		log.Printf("Error querying search_course_facets: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	facets := make(map[string][]model.FacetBucket)
+	for rows.Next() {
+		var facet string
+		var bucket model.FacetBucket
+		if err := rows.Scan(&facet, &bucket.Value, &bucket.Count); err != nil {
+			log.Printf("Error scanning search facet row: %v", err)
+			return nil, err
+		}
+		facets[facet] = append(facets[facet], bucket)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Row iteration error: %v", err)
+		return nil, err
+	}
+
+	return facets, nil
+}
+
+// scanCourseRows reads rows selected with courseColumns
+func scanCourseRows(rows *sql.Rows) ([]*model.Course, error) {
+	defer rows.Close()
+
 	var courses []*model.Course
 
 	for rows.Next() {
-		var course model.Course
-		err := rows.Scan(
-			&course.ID,
-			&course.InfluencerID,
-			&course.Title,
-			&course.Description,
-			&course.Price,
-			pq.Array(&course.CoverImageURL),
-			&course.Status,
-			&course.CreatedAt,
-			&course.UpdatedAt,
-		)
+		course, err := scanCourse(rows)
 		if err != nil {
 			log.Printf("Error scanning course row: %v", err)
 			return nil, err
 		}
-		courses = append(courses, &course)
+		courses = append(courses, course)
 	}
 
-	if err = rows.Err(); err != nil {
+	if err := rows.Err(); err != nil {
 		log.Printf("Row iteration error: %v", err)
 		return nil, err
 	}
 
-	log.Printf("Courses retrieved: %d", len(courses))
 	return courses, nil
 }
+
+// courseColumns is the column list of a course read from the courses table, in scanCourse order.
+// It may be joined with other tables as long as they share no column names with courses.
+const courseColumns = `id, influencer_id, title, description, price_amount, price_currency::text,
+	ARRAY(SELECT currency::text FROM course_prices WHERE course_prices.course_id = courses.id ORDER BY currency),
+	ARRAY(SELECT amount FROM course_prices WHERE course_prices.course_id = courses.id ORDER BY currency),
+	cover_image_url, status::text, created_at, updated_at,
+	category_id,
+	ARRAY(SELECT tags.name FROM course_tags JOIN tags ON tags.id = course_tags.tag_id
+		WHERE course_tags.course_id = courses.id ORDER BY tags.name),
+	submitted_at, approved_at, reviewed_by, reviewed_at, rejection_reason, publish_at, unpublish_at, published_at`
+
+// scanCourse reads one row selected with courseColumns
+func scanCourse(row rowScanner) (*model.Course, error) {
+	return scanCourseWith(row)
+}
+
+// scanCourseWith reads a row selected with courseColumns followed by the extra columns
+func scanCourseWith(row rowScanner, extra ...interface{}) (*model.Course, error) {
+	var course model.Course
+	var description sql.NullString
+	var priceCurrencies []string
+	var priceAmounts []int64
+	var status sql.NullString
+	var categoryID uuid.NullUUID
+	var reviewedBy uuid.NullUUID
+	var rejectionReason sql.NullString
+	var submittedAt, approvedAt, reviewedAt, publishAt, unpublishAt, publishedAt sql.NullTime
+
+	dest := []interface{}{
+		&course.ID,
+		&course.InfluencerID,
+		&course.Title,
+		&description,
+		&course.Price.Amount,
+		&course.Price.Currency,
+		pq.Array(&priceCurrencies),
+		pq.Array(&priceAmounts),
+		pq.Array(&course.CoverImageURL),
+		&status,
+		&course.CreatedAt,
+		&course.UpdatedAt,
+		&categoryID,
+		pq.Array(&course.Tags),
+		&submittedAt,
+		&approvedAt,
+		&reviewedBy,
+		&reviewedAt,
+		&rejectionReason,
+		&publishAt,
+		&unpublishAt,
+		&publishedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	course.Description = description.String
+	course.Prices = make([]model.Money, 0, len(priceCurrencies))
+	for i, currency := range priceCurrencies {
+		course.Prices = append(course.Prices, model.Money{Amount: priceAmounts[i], Currency: currency})
+	}
+	course.Status = status.String
+	if categoryID.Valid {
+		course.CategoryID = &categoryID.UUID
+	}
+	if reviewedBy.Valid {
+		course.ReviewedBy = &reviewedBy.UUID
+	}
+	course.RejectionReason = rejectionReason.String
+	course.SubmittedAt = nullTimePtr(submittedAt)
+	course.ApprovedAt = nullTimePtr(approvedAt)
+	course.ReviewedAt = nullTimePtr(reviewedAt)
+	course.PublishAt = nullTimePtr(publishAt)
+	course.UnpublishAt = nullTimePtr(unpublishAt)
+	course.PublishedAt = nullTimePtr(publishedAt)
+	return &course, nil
+}
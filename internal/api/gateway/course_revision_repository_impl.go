@@ -0,0 +1,177 @@
+package gateway
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/repository"
+	"log"
+	"strconv"
+
+	"github.com/gofrs/uuid"
+	"github.com/lib/pq"
+)
+
+type courseRevisionRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewCourseRevisionRepository returns a new CourseRevisionRepository instance
+func NewCourseRevisionRepository(db *sql.DB) repository.CourseRevisionRepository {
+	return &courseRevisionRepositoryImpl{db: db}
+}
+
+const courseRevisionColumns = `id, course_id, version, title, description, cover_image_url, lessons,
+	restored_from, created_by, created_at, updated_at, published_by, published_at`
+
+func (r *courseRevisionRepositoryImpl) CreateDraft(courseID, userID uuid.UUID) (*model.CourseRevision, error) {
+	var draftID uuid.NullUUID
+	if err := r.db.QueryRow(`SELECT create_course_draft($1, $2)`, courseID, userID).Scan(&draftID); err != nil {
+		log.Printf("Error calling create_course_draft: %v", err)
+		return nil, err
+	}
+	if !draftID.Valid {
+		return nil, fmt.Errorf("course %w", model.ErrNotFound)
+	}
+	return r.GetDraft(courseID)
+}
+
+func (r *courseRevisionRepositoryImpl) GetDraft(courseID uuid.UUID) (*model.CourseRevision, error) {
+	return r.getRevision(`course_id = $1 AND version IS NULL`, courseID)
+}
+
+func (r *courseRevisionRepositoryImpl) GetVersion(courseID uuid.UUID, version int) (*model.CourseRevision, error) {
+	return r.getRevision(`course_id = $1 AND version = $2`, courseID, version)
+}
+
+func (r *courseRevisionRepositoryImpl) getRevision(condition string, args ...interface{}) (*model.CourseRevision, error) {
+	revision, err := scanCourseRevision(r.db.QueryRow(`SELECT `+courseRevisionColumns+` FROM course_revisions WHERE `+condition, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("revision %w", model.ErrNotFound)
+		}
+		log.Printf("Error reading course revision: %v", err)
+		return nil, err
+	}
+	return revision, nil
+}
+
+func (r *courseRevisionRepositoryImpl) SaveDraft(draft *model.CourseRevision) (bool, error) {
+	lessons, err := json.Marshal(draft.Lessons)
+	if err != nil {
+		return false, err
+	}
+
+	var updated int
+	err = r.db.QueryRow(`SELECT save_course_draft($1, $2, $3, $4, $5)`,
+		draft.CourseID, draft.Title, draft.Description, pq.Array(draft.CoverImageURL), string(lessons),
+	).Scan(&updated)
+	if err != nil {
+		log.Printf("Error calling save_course_draft: %v", err)
+		return false, err
+	}
+	return updated > 0, nil
+}
+
+func (r *courseRevisionRepositoryImpl) DeleteDraft(courseID uuid.UUID) (bool, error) {
+	var deleted int
+	if err := r.db.QueryRow(`SELECT delete_course_draft($1)`, courseID).Scan(&deleted); err != nil {
+		log.Printf("Error calling delete_course_draft: %v", err)
+		return false, err
+	}
+	return deleted > 0, nil
+}
+
+func (r *courseRevisionRepositoryImpl) PublishDraft(courseID, userID uuid.UUID) (int, error) {
+	var version sql.NullInt64
+	if err := r.db.QueryRow(`SELECT publish_course_draft($1, $2)`, courseID, userID).Scan(&version); err != nil {
+		log.Printf("Error calling publish_course_draft: %v", err)
+		return 0, err
+	}
+
+	log.Printf("Course %s draft published as version %d", courseID, version.Int64)
+	return int(version.Int64), nil
+}
+
+func (r *courseRevisionRepositoryImpl) Rollback(courseID uuid.UUID, version int, userID uuid.UUID) (int, error) {
+	var newVersion sql.NullInt64
+	if err := r.db.QueryRow(`SELECT rollback_course($1, $2, $3)`, courseID, version, userID).Scan(&newVersion); err != nil {
+		log.Printf("Error calling rollback_course: %v", err)
+		return 0, err
+	}
+
+	log.Printf("Course %s rolled back to version %d as version %d", courseID, version, newVersion.Int64)
+	return int(newVersion.Int64), nil
+}
+
+func (r *courseRevisionRepositoryImpl) ListVersions(courseID uuid.UUID, page model.PageRequest) (*model.Page[*model.CourseRevision], error) {
+	q := &listQuery[*model.CourseRevision]{
+		name:    "course revisions",
+		columns: courseRevisionColumns,
+		from:    "course_revisions",
+		sorts: map[string]sortColumn[*model.CourseRevision]{
+			"version": {expr: "version", cast: "integer", value: func(r *model.CourseRevision) string { return strconv.Itoa(*r.Version) }},
+		},
+		defaultSort: "-version",
+		id:          func(r *model.CourseRevision) uuid.UUID { return r.ID },
+		scan:        scanCourseRevision,
+	}
+
+	q.where("course_id = ?", courseID)
+	q.where("version IS NOT NULL")
+
+	return q.run(r.db, page)
+}
+
+func scanCourseRevision(row rowScanner) (*model.CourseRevision, error) {
+	var revision model.CourseRevision
+	var version, restoredFrom sql.NullInt64
+	var description sql.NullString
+	var lessons []byte
+	var createdBy, publishedBy uuid.NullUUID
+	var publishedAt sql.NullTime
+
+	err := row.Scan(
+		&revision.ID,
+		&revision.CourseID,
+		&version,
+		&revision.Title,
+		&description,
+		pq.Array(&revision.CoverImageURL),
+		&lessons,
+		&restoredFrom,
+		&createdBy,
+		&revision.CreatedAt,
+		&revision.UpdatedAt,
+		&publishedBy,
+		&publishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(lessons, &revision.Lessons); err != nil {
+		return nil, fmt.Errorf("invalid lessons snapshot: %w", err)
+	}
+	revision.Version = nullIntPtr(version)
+	revision.RestoredFrom = nullIntPtr(restoredFrom)
+	revision.Description = description.String
+	if createdBy.Valid {
+		revision.CreatedBy = &createdBy.UUID
+	}
+	if publishedBy.Valid {
+		revision.PublishedBy = &publishedBy.UUID
+	}
+	revision.PublishedAt = nullTimePtr(publishedAt)
+	return &revision, nil
+}
+
+// nullIntPtr reads a nullable integer column
+func nullIntPtr(n sql.NullInt64) *int {
+	if !n.Valid {
+		return nil
+	}
+	value := int(n.Int64)
+	return &value
+}
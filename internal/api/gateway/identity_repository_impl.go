@@ -0,0 +1,130 @@
+package gateway
+
+import (
+	"database/sql"
+	"fmt"
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/repository"
+	"log"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/lib/pq"
+)
+
+type identityRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewIdentityRepository returns a new IdentityRepository instance
+func NewIdentityRepository(db *sql.DB) repository.IdentityRepository {
+	return &identityRepositoryImpl{db: db}
+}
+
+const identityColumns = `id, user_id, provider, subject, email, created_at, last_login_at`
+
+func (i *identityRepositoryImpl) Create(identity *model.UserIdentity) error {
+	err := i.db.QueryRow(
+		`SELECT id, created_at FROM create_user_identity($1, $2, $3, $4)`,
+		identity.UserID, identity.Provider, identity.Subject, nullString(identity.Email),
+	).Scan(&identity.ID, &identity.CreatedAt)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return model.ErrIdentityLinked
+		}
+		log.Printf("Error calling create_user_identity: %v", err)
+		return fmt.Errorf("failed to link identity: %w", err)
+	}
+	return nil
+}
+
+func (i *identityRepositoryImpl) FindBySubject(provider, subject string) (*model.UserIdentity, error) {
+	row := i.db.QueryRow(`SELECT `+identityColumns+` FROM get_user_identity($1, $2)`, provider, subject)
+
+	identity, err := scanIdentity(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		log.Printf("Error calling get_user_identity: %v", err)
+		return nil, err
+	}
+	return identity, nil
+}
+
+func (i *identityRepositoryImpl) ListByUser(userID uuid.UUID) ([]*model.UserIdentity, error) {
+	rows, err := i.db.Query(`SELECT `+identityColumns+` FROM get_user_identities($1)`, userID)
+	if err != nil {
+		log.Printf("Error calling get_user_identities: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var identities []*model.UserIdentity
+	for rows.Next() {
+		identity, err := scanIdentity(rows)
+		if err != nil {
+			return nil, err
+		}
+		identities = append(identities, identity)
+	}
+	return identities, rows.Err()
+}
+
+func (i *identityRepositoryImpl) TouchLogin(identityID uuid.UUID) error {
+	var updated int
+	if err := i.db.QueryRow(`SELECT touch_user_identity($1)`, identityID).Scan(&updated); err != nil {
+		log.Printf("Error calling touch_user_identity: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (i *identityRepositoryImpl) Delete(userID uuid.UUID, provider string) (bool, error) {
+	var deleted int
+	if err := i.db.QueryRow(`SELECT delete_user_identity($1, $2)`, userID, provider).Scan(&deleted); err != nil {
+		log.Printf("Error calling delete_user_identity: %v", err)
+		return false, fmt.Errorf("failed to unlink identity: %w", err)
+	}
+	return deleted > 0, nil
+}
+
+func scanIdentity(row rowScanner) (*model.UserIdentity, error) {
+	var identity model.UserIdentity
+	var email sql.NullString
+	err := row.Scan(
+		&identity.ID,
+		&identity.UserID,
+		&identity.Provider,
+		&identity.Subject,
+		&email,
+		&identity.CreatedAt,
+		&identity.LastLoginAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	identity.Email = email.String
+	return &identity, nil
+}
+
+// nullString stores empty strings as NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// nullUUID stores a nil pointer as NULL
+func nullUUID(id *uuid.UUID) uuid.NullUUID {
+	if id == nil {
+		return uuid.NullUUID{}
+	}
+	return uuid.NullUUID{UUID: *id, Valid: true}
+}
+
+// nullTimePtr reads a nullable timestamp column
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
@@ -0,0 +1,166 @@
+package gateway
+
+import (
+	"database/sql"
+	"fmt"
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/repository"
+	"log"
+
+	"github.com/gofrs/uuid"
+	"github.com/lib/pq"
+)
+
+type influencerApplicationRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewInfluencerApplicationRepository returns a new InfluencerApplicationRepository instance
+func NewInfluencerApplicationRepository(db *sql.DB) repository.InfluencerApplicationRepository {
+	return &influencerApplicationRepositoryImpl{db: db}
+}
+
+const applicationColumns = `id, user_id, bio, payout_wallet, id_document_urls, status, review_note, reviewed_by, reviewed_at, created_at, updated_at`
+
+func (a *influencerApplicationRepositoryImpl) Create(application *model.InfluencerApplication) error {
+	row := a.db.QueryRow(
+		`SELECT `+applicationColumns+` FROM create_influencer_application($1, $2, $3, $4)`,
+		application.UserID,
+		application.Bio,
+		nullString(application.PayoutWallet),
+		pq.Array(application.IDDocumentURLs),
+	)
+
+	created, err := scanApplication(row)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return model.ErrApplicationOpen
+		}
+		log.Printf("Error calling create_influencer_application: %v", err)
+		return fmt.Errorf("failed to create application: %w", err)
+	}
+
+	*application = *created
+	return nil
+}
+
+func (a *influencerApplicationRepositoryImpl) Get(applicationID uuid.UUID) (*model.InfluencerApplication, error) {
+	row := a.db.QueryRow(`SELECT `+applicationColumns+` FROM get_influencer_application($1)`, applicationID)
+
+	application, err := scanApplication(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("application %w", model.ErrNotFound)
+		}
+		log.Printf("Error calling get_influencer_application: %v", err)
+		return nil, err
+	}
+	return application, nil
+}
+
+func (a *influencerApplicationRepositoryImpl) FindLatestByUser(userID uuid.UUID) (*model.InfluencerApplication, error) {
+	row := a.db.QueryRow(`SELECT `+applicationColumns+` FROM get_latest_influencer_application($1)`, userID)
+
+	application, err := scanApplication(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		log.Printf("Error calling get_latest_influencer_application: %v", err)
+		return nil, err
+	}
+	return application, nil
+}
+
+func (a *influencerApplicationRepositoryImpl) ListByUser(userID uuid.UUID) ([]*model.InfluencerApplication, error) {
+	rows, err := a.db.Query(`SELECT `+applicationColumns+` FROM get_user_influencer_applications($1)`, userID)
+	if err != nil {
+		log.Printf("Error calling get_user_influencer_applications: %v", err)
+		return nil, err
+	}
+	return scanApplications(rows)
+}
+
+func (a *influencerApplicationRepositoryImpl) List(status string) ([]*model.InfluencerApplication, error) {
+	rows, err := a.db.Query(`SELECT `+applicationColumns+` FROM get_influencer_applications($1)`, nullString(status))
+	if err != nil {
+		log.Printf("Error calling get_influencer_applications: %v", err)
+		return nil, err
+	}
+	return scanApplications(rows)
+}
+
+func (a *influencerApplicationRepositoryImpl) Review(applicationID uuid.UUID, approve bool, reviewerID uuid.UUID, note string) (*model.InfluencerApplication, error) {
+	row := a.db.QueryRow(
+		`SELECT `+applicationColumns+` FROM review_influencer_application($1, $2, $3, $4)`,
+		applicationID, approve, reviewerID, nullString(note),
+	)
+
+	application, err := scanApplication(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		log.Printf("Error calling review_influencer_application: %v", err)
+		return nil, fmt.Errorf("failed to review application: %w", err)
+	}
+	return application, nil
+}
+
+func (a *influencerApplicationRepositoryImpl) IsApprovedCreator(userID uuid.UUID) (bool, error) {
+	var approved bool
+	if err := a.db.QueryRow(`SELECT is_approved_creator($1)`, userID).Scan(&approved); err != nil {
+		log.Printf("Error calling is_approved_creator: %v", err)
+		return false, err
+	}
+	return approved, nil
+}
+
+func scanApplications(rows *sql.Rows) ([]*model.InfluencerApplication, error) {
+	defer rows.Close()
+
+	var applications []*model.InfluencerApplication
+	for rows.Next() {
+		application, err := scanApplication(rows)
+		if err != nil {
+			return nil, err
+		}
+		applications = append(applications, application)
+	}
+	return applications, rows.Err()
+}
+
+func scanApplication(row rowScanner) (*model.InfluencerApplication, error) {
+	var application model.InfluencerApplication
+	var payoutWallet sql.NullString
+	var reviewNote sql.NullString
+	var reviewedBy uuid.NullUUID
+	var reviewedAt sql.NullTime
+
+	err := row.Scan(
+		&application.ID,
+		&application.UserID,
+		&application.Bio,
+		&payoutWallet,
+		pq.Array(&application.IDDocumentURLs),
+		&application.Status,
+		&reviewNote,
+		&reviewedBy,
+		&reviewedAt,
+		&application.CreatedAt,
+		&application.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	application.PayoutWallet = payoutWallet.String
+	application.ReviewNote = reviewNote.String
+	if reviewedBy.Valid {
+		application.ReviewedBy = &reviewedBy.UUID
+	}
+	if reviewedAt.Valid {
+		application.ReviewedAt = &reviewedAt.Time
+	}
+	return &application, nil
+}
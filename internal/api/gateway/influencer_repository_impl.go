@@ -0,0 +1,167 @@
+package gateway
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/repository"
+	"log"
+
+	"github.com/gofrs/uuid"
+)
+
+type influencerRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewInfluencerRepository returns a new InfluencerRepository instance
+func NewInfluencerRepository(db *sql.DB) repository.InfluencerRepository {
+	return &influencerRepositoryImpl{db: db}
+}
+
+const profileColumns = `user_id, display_name, avatar_url, bio, social_links, created_at, updated_at`
+
+func (i *influencerRepositoryImpl) UpsertProfile(profile *model.InfluencerProfile) error {
+	links := profile.SocialLinks
+	if links == nil {
+		links = map[string]string{}
+	}
+	socialLinks, err := json.Marshal(links)
+	if err != nil {
+		return fmt.Errorf("failed to encode social links: %v", err)
+	}
+
+	row := i.db.QueryRow(
+		`SELECT `+profileColumns+` FROM upsert_influencer_profile($1, $2, $3, $4, $5)`,
+		profile.UserID,
+		profile.DisplayName,
+		nullString(profile.AvatarURL),
+		profile.Bio,
+		string(socialLinks),
+	)
+
+	saved, err := scanProfile(row)
+	if err != nil {
+		log.Printf("Error calling upsert_influencer_profile: %v", err)
+		return fmt.Errorf("failed to save profile: %w", err)
+	}
+
+	*profile = *saved
+	return nil
+}
+
+func (i *influencerRepositoryImpl) GetProfile(influencerID uuid.UUID) (*model.InfluencerProfile, error) {
+	row := i.db.QueryRow(`SELECT `+profileColumns+` FROM get_influencer_profile($1)`, influencerID)
+
+	profile, err := scanProfile(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("influencer %w", model.ErrNotFound)
+		}
+		log.Printf("Error calling get_influencer_profile: %v", err)
+		return nil, err
+	}
+	return profile, nil
+}
+
+func (i *influencerRepositoryImpl) GetStats(influencerID uuid.UUID) (*model.InfluencerStats, error) {
+	var stats model.InfluencerStats
+
+	err := i.db.QueryRow(
+		`SELECT course_count, student_count, rating_count, average_rating, follower_count FROM get_influencer_stats($1)`,
+		influencerID,
+	).Scan(
+		&stats.CourseCount,
+		&stats.StudentCount,
+		&stats.RatingCount,
+		&stats.AverageRating,
+		&stats.FollowerCount,
+	)
+	if err != nil {
+		log.Printf("Error calling get_influencer_stats: %v", err)
+		return nil, err
+	}
+	return &stats, nil
+}
+
+func (i *influencerRepositoryImpl) Follow(followerID, influencerID uuid.UUID) (bool, error) {
+	var inserted int
+	if err := i.db.QueryRow(`SELECT follow_influencer($1, $2)`, followerID, influencerID).Scan(&inserted); err != nil {
+		log.Printf("Error calling follow_influencer: %v", err)
+		return false, err
+	}
+	return inserted > 0, nil
+}
+
+func (i *influencerRepositoryImpl) Unfollow(followerID, influencerID uuid.UUID) (bool, error) {
+	var deleted int
+	if err := i.db.QueryRow(`SELECT unfollow_influencer($1, $2)`, followerID, influencerID).Scan(&deleted); err != nil {
+		log.Printf("Error calling unfollow_influencer: %v", err)
+		return false, err
+	}
+	return deleted > 0, nil
+}
+
+func (i *influencerRepositoryImpl) ListFollowers(influencerID uuid.UUID) ([]*model.User, error) {
+	rows, err := i.db.Query(`SELECT `+userColumns+` FROM get_influencer_followers($1)`, influencerID)
+	if err != nil {
+		log.Printf("Error calling get_influencer_followers: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var followers []*model.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		followers = append(followers, user)
+	}
+	return followers, rows.Err()
+}
+
+func (i *influencerRepositoryImpl) ListFollowing(userID uuid.UUID) ([]*model.InfluencerProfile, error) {
+	rows, err := i.db.Query(`SELECT `+profileColumns+` FROM get_followed_influencers($1)`, userID)
+	if err != nil {
+		log.Printf("Error calling get_followed_influencers: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var profiles []*model.InfluencerProfile
+	for rows.Next() {
+		profile, err := scanProfile(rows)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles, rows.Err()
+}
+
+func scanProfile(row rowScanner) (*model.InfluencerProfile, error) {
+	var profile model.InfluencerProfile
+	var avatarURL sql.NullString
+	var socialLinks []byte
+
+	err := row.Scan(
+		&profile.UserID,
+		&profile.DisplayName,
+		&avatarURL,
+		&profile.Bio,
+		&socialLinks,
+		&profile.CreatedAt,
+		&profile.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	profile.AvatarURL = avatarURL.String
+	if err := json.Unmarshal(socialLinks, &profile.SocialLinks); err != nil {
+		return nil, fmt.Errorf("failed to decode social links: %v", err)
+	}
+	return &profile, nil
+}
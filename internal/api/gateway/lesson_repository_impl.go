@@ -6,6 +6,7 @@ import (
 	"kaabe-app/internal/domain/repository"
 	"log"
 	"fmt"
+	"strconv"
 
 	"github.com/gofrs/uuid"
 	"github.com/lib/pq"
@@ -44,45 +45,32 @@ func (l *LessonRepositoryImpl) Delete(lessonID uuid.UUID) error {
 }
 
 
-// GetAllByCourseID retrieves all lessons for a specific course using the get_lessons_by_course_id() function
-func (l *LessonRepositoryImpl) GetAll() ([]*model.Lesson, error) {
-	rows, err := l.db.Query(`SELECT * FROM get_all_lessons()`)
-	if err != nil {
-		log.Printf("Error querying get_all_lessons: %v", err)
-		return nil, err
-	}
-	defer rows.Close()
-
-	var lessons []*model.Lesson
-
-	for rows.Next() {
-		var lesson model.Lesson
-		err := rows.Scan(
-			&lesson.ID,
-			&lesson.CourseID,
-			&lesson.Title,
-			pq.Array(&lesson.VideoURL),
-			&lesson.Order,
-			&lesson.CreatedAt,
-			&lesson.UpdatedAt,
-		)
-		if err != nil {
-			log.Printf("Error scanning lesson row: %v", err)
-			return nil, err
-		}
-		lessons = append(lessons, &lesson)
+// List retrieves one page of lessons matching the filter
+func (l *LessonRepositoryImpl) List(filter model.LessonFilter, page model.PageRequest) (*model.Page[*model.Lesson], error) {
+	q := &listQuery[*model.Lesson]{
+		name:    "lessons",
+		columns: lessonColumns,
+		from:    "lessons",
+		sorts: map[string]sortColumn[*model.Lesson]{
+			"created_at": {expr: "created_at", cast: "timestamptz", value: func(l *model.Lesson) string { return timeCursor(l.CreatedAt) }},
+			"order":      {expr: "lesson_order", cast: "integer", value: func(l *model.Lesson) string { return strconv.Itoa(l.Order) }},
+		},
+		defaultSort: "order",
+		id:          func(l *model.Lesson) uuid.UUID { return l.ID },
+		scan:        scanLesson,
 	}
 
-	if err = rows.Err(); err != nil {
-		log.Printf("Row iteration error: %v", err)
-		return nil, err
+	q.where("deleted_at IS NULL")
+	if filter.CourseID != nil {
+		q.where("course_id = ?", *filter.CourseID)
+	}
+	if filter.VisibleTo != nil {
+		q.where("course_id IN (SELECT id FROM courses WHERE deleted_at IS NULL AND (status = 'published' OR influencer_id = ?))", *filter.VisibleTo)
 	}
 
-	log.Printf("Lessons retrieved: %d", len(lessons))
-	return lessons, nil
+	return q.run(l.db, page)
 }
 
-
 // GetByID implements repository.LessonRepository.
 func (l *LessonRepositoryImpl) GetByID(lessonID uuid.UUID) (*model.Lesson, error) {
 	var lesson model.Lesson
@@ -102,7 +90,7 @@ func (l *LessonRepositoryImpl) GetByID(lessonID uuid.UUID) (*model.Lesson, error
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Printf("Lesson not found with ID: %v", lessonID)
-			return nil, fmt.Errorf("lesson not found")
+			return nil, fmt.Errorf("lesson %w", model.ErrNotFound)
 		}
 		log.Printf("Error scanning lesson by ID: %v", err)
 		return nil, err
@@ -129,3 +117,23 @@ func (l *LessonRepositoryImpl) Update(lesson *model.Lesson) error {
 func NewLessonRepository(db *sql.DB) repository.LessonRepository {
 	return &LessonRepositoryImpl{db: db}
 }
+
+// lessonColumns is the column list of a lesson read straight from the table, in scanLesson order
+const lessonColumns = `id, course_id, title, video_url, lesson_order, created_at, updated_at`
+
+func scanLesson(row rowScanner) (*model.Lesson, error) {
+	var lesson model.Lesson
+	err := row.Scan(
+		&lesson.ID,
+		&lesson.CourseID,
+		&lesson.Title,
+		pq.Array(&lesson.VideoURL),
+		&lesson.Order,
+		&lesson.CreatedAt,
+		&lesson.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &lesson, nil
+}
@@ -0,0 +1,159 @@
+package gateway
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"kaabe-app/internal/domain/model"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// sortColumn is a field a list may be ordered by. Rows are always ordered by the column
+// and then by id, so (column, id) identifies a position for keyset pagination.
+type sortColumn[T any] struct {
+	expr  string         // SQL expression; must not be NULL
+	cast  string         // SQL type the cursor value is cast back to
+	value func(T) string // the item's value, stored in the cursor
+}
+
+// listQuery builds one keyset-paginated SELECT. Filters are added with where; the sort
+// field is checked against the list's whitelist, so request input never reaches the SQL text.
+type listQuery[T any] struct {
+	name        string // for logs
+	columns     string
+	from        string
+	sorts       map[string]sortColumn[T]
+	defaultSort string
+	id          func(T) uuid.UUID
+	scan        func(rowScanner) (T, error)
+
+	conditions []string
+	args       []interface{}
+}
+
+// where adds a condition; each "?" in it is bound to the next arg
+func (q *listQuery[T]) where(condition string, args ...interface{}) {
+	for _, arg := range args {
+		q.args = append(q.args, arg)
+		condition = strings.Replace(condition, "?", "$"+strconv.Itoa(len(q.args)), 1)
+	}
+	q.conditions = append(q.conditions, condition)
+}
+
+// listCursor is the position after the last item of a page
+type listCursor struct {
+	Sort  string    `json:"s"`
+	Value string    `json:"v"`
+	ID    uuid.UUID `json:"id"`
+}
+
+// run fetches the page; it reads one row more than the limit to tell whether another page follows
+func (q *listQuery[T]) run(db *sql.DB, page model.PageRequest) (*model.Page[T], error) {
+	sortName := page.Sort
+	if sortName == "" {
+		sortName = q.defaultSort
+	}
+	descending := strings.HasPrefix(sortName, "-")
+	column, ok := q.sorts[strings.TrimPrefix(sortName, "-")]
+	if !ok {
+		return nil, model.ErrInvalidSort
+	}
+
+	limit := pageLimit(page)
+
+	direction, comparison := "ASC", ">"
+	if descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	if page.Cursor != "" {
+		cursor, err := decodeCursor(page.Cursor)
+		if err != nil || cursor.Sort != sortName {
+			return nil, model.ErrInvalidCursor
+		}
+		q.where(fmt.Sprintf("(%s, id) %s (?::%s, ?)", column.expr, comparison, column.cast), cursor.Value, cursor.ID)
+	}
+
+	query := `SELECT ` + q.columns + ` FROM ` + q.from
+	if len(q.conditions) > 0 {
+		query += ` WHERE ` + strings.Join(q.conditions, ` AND `)
+	}
+	query += fmt.Sprintf(` ORDER BY %s %s, id %s LIMIT %d`, column.expr, direction, direction, limit+1)
+
+	rows, err := db.Query(query, q.args...)
+	if err != nil {
+		log.Printf("Error listing %s: %v", q.name, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]T, 0, limit)
+	for rows.Next() {
+		item, err := q.scan(rows)
+		if err != nil {
+			log.Printf("Error scanning %s row: %v", q.name, err)
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error iterating %s rows: %v", q.name, err)
+		return nil, err
+	}
+
+	result := &model.Page[T]{Items: items}
+	if len(items) > limit {
+		result.Items = items[:limit]
+		last := result.Items[limit-1]
+		result.NextCursor = encodeCursor(listCursor{Sort: sortName, Value: column.value(last), ID: q.id(last)})
+	}
+	return result, nil
+}
+
+// pageLimit is the requested page size, defaulted and capped
+func pageLimit(page model.PageRequest) int {
+	switch {
+	case page.Limit <= 0:
+		return model.DefaultPageLimit
+	case page.Limit > model.MaxPageLimit:
+		return model.MaxPageLimit
+	}
+	return page.Limit
+}
+
+func encodeCursor(cursor listCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(encoded string) (listCursor, error) {
+	var cursor listCursor
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return cursor, err
+	}
+	err = json.Unmarshal(data, &cursor)
+	return cursor, err
+}
+
+// Cursor value encoders; they must round-trip through the sort column's cast
+
+func timeCursor(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
+
+func intCursor(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
+
+// likePattern matches s anywhere in a column, with LIKE wildcards in s taken literally
+func likePattern(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+	return "%" + s + "%"
+}
@@ -0,0 +1,21 @@
+package gateway
+
+import (
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/service"
+	"log"
+)
+
+// logSMSSender prints messages to the server log instead of sending them
+type logSMSSender struct{}
+
+// NewLogSMSSender returns an SMSSender for local development
+func NewLogSMSSender() service.SMSSender {
+	return &logSMSSender{}
+}
+
+// Send implements service.SMSSender
+func (s *logSMSSender) Send(message *model.SMSMessage) error {
+	log.Printf("SMS to %s: %s", message.To, message.Body)
+	return nil
+}
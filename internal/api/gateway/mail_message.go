@@ -0,0 +1,66 @@
+package gateway
+
+import (
+	"bytes"
+	"fmt"
+	"kaabe-app/internal/domain/model"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// buildMIMEMessage encodes a message as multipart/alternative with text and HTML parts
+func buildMIMEMessage(from string, message *model.EmailMessage) ([]byte, error) {
+	boundary, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", message.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", boundary.String())
+
+	for _, part := range []struct {
+		contentType string
+		body        string
+	}{
+		{"text/plain", message.TextBody},
+		{"text/html", message.HTMLBody},
+	} {
+		if part.body == "" {
+			continue
+		}
+
+		fmt.Fprintf(&buf, "--%s\r\n", boundary.String())
+		fmt.Fprintf(&buf, "Content-Type: %s; charset=utf-8\r\n", part.contentType)
+		fmt.Fprintf(&buf, "Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+		qp := quotedprintable.NewWriter(&buf)
+		if _, err := qp.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+		buf.WriteString("\r\n")
+	}
+
+	fmt.Fprintf(&buf, "--%s--\r\n", boundary.String())
+	return buf.Bytes(), nil
+}
+
+// envelopeAddress extracts the bare address from a "Name <addr>" string
+func envelopeAddress(address string) (string, error) {
+	parsed, err := mail.ParseAddress(address)
+	if err != nil {
+		return "", fmt.Errorf("invalid email address %q: %v", address, err)
+	}
+	return parsed.Address, nil
+}
@@ -0,0 +1,85 @@
+package gateway
+
+import (
+	"kaabe-app/internal/domain/repository"
+	"sync"
+	"time"
+)
+
+// memoryAttemptStore keeps counters in process memory. It is the fallback when Redis
+// is not available and only protects a single instance.
+type memoryAttemptStore struct {
+	mu       sync.Mutex
+	counters map[string]*attemptCounter
+	locks    map[string]time.Time
+}
+
+type attemptCounter struct {
+	count     int
+	expiresAt time.Time
+}
+
+// NewMemoryAttemptStore returns an in-memory AttemptStore
+func NewMemoryAttemptStore() repository.AttemptStore {
+	return &memoryAttemptStore{
+		counters: make(map[string]*attemptCounter),
+		locks:    make(map[string]time.Time),
+	}
+}
+
+func (m *memoryAttemptStore) RegisterFailure(key string, window time.Duration) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.evictExpired(now)
+
+	counter, ok := m.counters[key]
+	if !ok {
+		counter = &attemptCounter{expiresAt: now.Add(window)}
+		m.counters[key] = counter
+	}
+	counter.count++
+	return counter.count, nil
+}
+
+func (m *memoryAttemptStore) Reset(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.counters, key)
+	return nil
+}
+
+func (m *memoryAttemptStore) Lock(key string, duration time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.locks[key] = time.Now().Add(duration)
+	return nil
+}
+
+func (m *memoryAttemptStore) LockedUntil(key string) (time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	until, ok := m.locks[key]
+	if !ok || !until.After(time.Now()) {
+		return time.Time{}, nil
+	}
+	return until, nil
+}
+
+// evictExpired drops closed windows and lapsed locks so the maps do not grow without bound
+func (m *memoryAttemptStore) evictExpired(now time.Time) {
+	for key, counter := range m.counters {
+		if !counter.expiresAt.After(now) {
+			delete(m.counters, key)
+		}
+	}
+	for key, until := range m.locks {
+		if !until.After(now) {
+			delete(m.locks, key)
+		}
+	}
+}
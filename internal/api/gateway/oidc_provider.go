@@ -0,0 +1,306 @@
+package gateway
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/service"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// OIDCConfig describes one OpenID Connect provider
+type OIDCConfig struct {
+	Name         string
+	Issuer       string // discovery is read from Issuer + /.well-known/openid-configuration
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	ResponseMode string // "form_post" for Apple when requesting name or email
+}
+
+// oidcDiscovery is the subset of the discovery document we use
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// jwksRefreshInterval limits how often an unknown key ID triggers a JWKS download
+const jwksRefreshInterval = 5 * time.Minute
+
+type oidcProvider struct {
+	cfg    OIDCConfig
+	client *http.Client
+
+	mu          sync.Mutex
+	discovery   *oidcDiscovery
+	keys        map[string]*rsa.PublicKey
+	keysFetched time.Time
+}
+
+// NewOIDCProvider returns an IdentityProvider for a standard OIDC authorization code flow.
+// Discovery happens on first use so a provider outage does not stop the server from starting.
+func NewOIDCProvider(cfg OIDCConfig) service.IdentityProvider {
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "email", "profile"}
+	}
+	return &oidcProvider{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (p *oidcProvider) Name() string {
+	return p.cfg.Name
+}
+
+func (p *oidcProvider) AuthCodeURL(state, nonce string) (string, error) {
+	discovery, err := p.discover(context.Background())
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", p.cfg.ClientID)
+	query.Set("redirect_uri", p.cfg.RedirectURL)
+	query.Set("scope", strings.Join(p.cfg.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	if p.cfg.ResponseMode != "" {
+		query.Set("response_mode", p.cfg.ResponseMode)
+	}
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return discovery.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+func (p *oidcProvider) Exchange(ctx context.Context, code, nonce string) (*model.ExternalIdentity, error) {
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.cfg.RedirectURL)
+	form.Set("client_id", p.cfg.ClientID)
+	form.Set("client_secret", p.cfg.ClientSecret)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	var tokenResponse struct {
+		IDToken string `json:"id_token"`
+		Error   string `json:"error"`
+	}
+	if err := p.doJSON(req, &tokenResponse); err != nil {
+		return nil, fmt.Errorf("%s token exchange failed: %v", p.cfg.Name, err)
+	}
+	if tokenResponse.IDToken == "" {
+		return nil, fmt.Errorf("%s token exchange returned no id_token", p.cfg.Name)
+	}
+
+	return p.verifyIDToken(ctx, discovery, tokenResponse.IDToken, nonce)
+}
+
+// idTokenClaims are the standard claims we read from an ID token
+type idTokenClaims struct {
+	Nonce         string       `json:"nonce"`
+	Email         string       `json:"email"`
+	EmailVerified flexibleBool `json:"email_verified"`
+	GivenName     string       `json:"given_name"`
+	FamilyName    string       `json:"family_name"`
+	jwt.RegisteredClaims
+}
+
+// flexibleBool accepts both true and "true"; Apple sends booleans as strings
+type flexibleBool bool
+
+func (b *flexibleBool) UnmarshalJSON(data []byte) error {
+	*b = flexibleBool(strings.Trim(string(data), `"`) == "true")
+	return nil
+}
+
+func (p *oidcProvider) verifyIDToken(ctx context.Context, discovery *oidcDiscovery, rawToken, nonce string) (*model.ExternalIdentity, error) {
+	var claims idTokenClaims
+	_, err := jwt.ParseWithClaims(rawToken, &claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.publicKey(ctx, discovery, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256"}),
+		jwt.WithIssuer(discovery.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s id_token: %v", p.cfg.Name, err)
+	}
+
+	if claims.Nonce != nonce {
+		return nil, fmt.Errorf("invalid %s id_token: nonce mismatch", p.cfg.Name)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("invalid %s id_token: missing subject", p.cfg.Name)
+	}
+
+	return &model.ExternalIdentity{
+		Provider:      p.cfg.Name,
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified),
+		FirstName:     claims.GivenName,
+		LastName:      claims.FamilyName,
+	}, nil
+}
+
+// discover loads and caches the provider's discovery document
+func (p *oidcProvider) discover(ctx context.Context) (*oidcDiscovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	wellKnown := strings.TrimSuffix(p.cfg.Issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, wellKnown, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var discovery oidcDiscovery
+	if err := p.doJSON(req, &discovery); err != nil {
+		return nil, fmt.Errorf("%s discovery failed: %v", p.cfg.Name, err)
+	}
+	if discovery.Issuer != strings.TrimSuffix(p.cfg.Issuer, "/") && discovery.Issuer != p.cfg.Issuer {
+		return nil, fmt.Errorf("%s discovery issuer %q does not match %q", p.cfg.Name, discovery.Issuer, p.cfg.Issuer)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, fmt.Errorf("%s discovery document is incomplete", p.cfg.Name)
+	}
+
+	p.discovery = &discovery
+	return p.discovery, nil
+}
+
+// publicKey returns the signing key with the given ID, downloading the JWKS again when
+// the key is unknown so provider key rotation is picked up
+func (p *oidcProvider) publicKey(ctx context.Context, discovery *oidcDiscovery, kid string) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	if time.Since(p.keysFetched) < jwksRefreshInterval && p.keys != nil {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	keys, err := p.fetchKeys(ctx, discovery.JWKSURI)
+	if err != nil {
+		return nil, err
+	}
+	p.keys = keys
+	p.keysFetched = time.Now()
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// lookupKey finds a key by ID; a token without kid is accepted only if the set has one key
+func (p *oidcProvider) lookupKey(kid string) (*rsa.PublicKey, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+func (p *oidcProvider) fetchKeys(ctx context.Context, jwksURI string) (map[string]*rsa.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURI, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var jwks struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			Kty string `json:"kty"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := p.doJSON(req, &jwks); err != nil {
+		return nil, fmt.Errorf("%s jwks download failed: %v", p.cfg.Name, err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, jwk := range jwks.Keys {
+		if jwk.Kty != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("jwks contains no usable RSA signing keys")
+	}
+	return keys, nil
+}
+
+// doJSON sends the request and decodes a JSON response, treating non-2xx statuses as errors
+func (p *oidcProvider) doJSON(req *http.Request, out interface{}) error {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s returned %d: %s", req.URL.Host, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.Unmarshal(body, out)
+}
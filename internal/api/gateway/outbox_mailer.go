@@ -0,0 +1,51 @@
+package gateway
+
+import (
+	"fmt"
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/service"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// outboxMailer writes every message as an .eml file instead of sending it,
+// so emails can be inspected during local development and testing
+type outboxMailer struct {
+	dir  string
+	from string
+}
+
+// NewOutboxMailer returns a Mailer that stores messages in dir
+func NewOutboxMailer(dir, from string) service.Mailer {
+	return &outboxMailer{dir: dir, from: from}
+}
+
+// Send implements service.Mailer
+func (m *outboxMailer) Send(message *model.EmailMessage) error {
+	body, err := buildMIMEMessage(m.from, message)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create outbox directory: %v", err)
+	}
+
+	id, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405"), id)
+	path := filepath.Join(m.dir, name)
+	if err := os.WriteFile(path, body, 0o644); err != nil {
+		return fmt.Errorf("failed to write outbox message: %v", err)
+	}
+
+	log.Printf("Email to %s written to %s", message.To, path)
+	return nil
+}
@@ -0,0 +1,48 @@
+package gateway
+
+import (
+	"fmt"
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/service"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// outboxSMSSender writes every message as a .txt file instead of sending it,
+// so codes can be read during local development and testing
+type outboxSMSSender struct {
+	dir string
+}
+
+// NewOutboxSMSSender returns an SMSSender that stores messages in dir
+func NewOutboxSMSSender(dir string) service.SMSSender {
+	return &outboxSMSSender{dir: dir}
+}
+
+// Send implements service.SMSSender
+func (s *outboxSMSSender) Send(message *model.SMSMessage) error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create sms outbox directory: %v", err)
+	}
+
+	id, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+
+	recipient := strings.TrimPrefix(message.To, "+")
+	name := fmt.Sprintf("%s-%s-%s.txt", time.Now().UTC().Format("20060102T150405"), recipient, id)
+	path := filepath.Join(s.dir, name)
+	body := fmt.Sprintf("To: %s\n\n%s\n", message.To, message.Body)
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		return fmt.Errorf("failed to write sms outbox message: %v", err)
+	}
+
+	log.Printf("SMS to %s written to %s", message.To, path)
+	return nil
+}
@@ -8,19 +8,28 @@ import (
 	"log"
 
 	"github.com/gofrs/uuid"
+	"github.com/lib/pq"
 )
 
+// quoteNotRedeemableCode is the SQLSTATE create_payment raises for a quote it cannot redeem
+const quoteNotRedeemableCode = "KB001"
+
 type PaymentRepositoryImpl struct {
 	db *sql.DB
 }
 
 // Create implements repository.PaymentRepository.
 func (p *PaymentRepositoryImpl) Create(payment *model.Payment) error {
-	_, err := p.db.Exec(`call create_payment($1, $2, $3, $4, $5, $6, $7)`,
-		payment.ID, payment.ExternalRef, payment.UserID, payment.SubscriptionID, payment.Amount, payment.Status, payment.ProcessedAt)
+	_, err := p.db.Exec(`call create_payment($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		payment.ID, payment.ExternalRef, payment.UserID, paymentSubscription(payment), payment.Amount.Amount, payment.Amount.Currency, payment.Status, payment.ProcessedAt, nullUUID(payment.QuoteID))
 
 	//
 	if err != nil {
+		// create_payment raises when the quote cannot be redeemed; the unique index catches a quote paid concurrently
+		if pqErr, ok := err.(*pq.Error); ok && payment.QuoteID != nil &&
+			(pqErr.Code == quoteNotRedeemableCode || (pqErr.Code == "23505" && pqErr.Constraint == "idx_payments_quote")) {
+			return model.ErrQuoteNotRedeemable
+		}
 		log.Printf("call create_payment error: %v", err)
 		return err
 	}
@@ -41,12 +50,45 @@ func (p *PaymentRepositoryImpl) Delete(paymentID uuid.UUID) error {
 	return nil
 }
 
-// GetAll implements repository.PaymentRepository.
-func (p *PaymentRepositoryImpl) GetAll() ([]*model.Payment, error) {
-	//
-	rows, err := p.db.Query(`select * from get_all_payments()`)
+// List implements repository.PaymentRepository.
+func (p *PaymentRepositoryImpl) List(filter model.PaymentFilter, page model.PageRequest) (*model.Page[*model.Payment], error) {
+	q := &listQuery[*model.Payment]{
+		name:    "payments",
+		columns: paymentColumns,
+		from:    "payments",
+		sorts: map[string]sortColumn[*model.Payment]{
+			"created_at": {expr: "created_at", cast: "timestamptz", value: func(p *model.Payment) string { return timeCursor(p.CreatedAt) }},
+			"amount":     {expr: "amount", cast: "bigint", value: func(p *model.Payment) string { return intCursor(p.Amount.Amount) }},
+		},
+		defaultSort: "-created_at",
+		id:          func(p *model.Payment) uuid.UUID { return p.ID },
+		scan:        scanPayment,
+	}
+
+	q.where("deleted_at IS NULL")
+	if filter.UserID != nil {
+		q.where("user_id = ?", *filter.UserID)
+	}
+	if filter.Status != "" {
+		q.where("status = ?", filter.Status)
+	}
+	if filter.From != nil {
+		q.where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		q.where("created_at < ?", *filter.To)
+	}
+
+	return q.run(p.db, page)
+}
+
+// GetByUserID implements repository.PaymentRepository.
+func (p *PaymentRepositoryImpl) GetByUserID(userID uuid.UUID) ([]*model.Payment, error) {
+	rows, err := p.db.Query(`SELECT `+paymentColumns+` FROM payments
+		WHERE user_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at DESC`, userID)
 	if err != nil {
-		log.Printf("call get_all_payment error: %v", err)
+		log.Printf("Error listing user payments: %v", err)
 		return nil, err
 	}
 
@@ -54,68 +96,42 @@ func (p *PaymentRepositoryImpl) GetAll() ([]*model.Payment, error) {
 	var payments []*model.Payment
 
 	for rows.Next() {
-		var payment model.Payment
-		err = rows.Scan(
-			&payment.ID,
-			&payment.ExternalRef,
-			&payment.UserID,
-			&payment.SubscriptionID,
-			&payment.Amount,
-			&payment.Status,
-			&payment.ProcessedAt,
-			&payment.UpdatedAt,
-			&payment.UpdatedAt,
-		)
+		payment, err := scanPayment(rows)
 		if err != nil {
 			log.Printf("scan error payment row: %v", err)
 			return nil, err
 		}
-		payments = append(payments, &payment)
+		payments = append(payments, payment)
 	}
 	if err := rows.Err(); err != nil {
 		log.Printf(" Row iteration error: %v", err)
 		return nil, err
 	}
-	log.Printf("get all payment : %v", payments)
 	return payments, nil
 }
 
 // GetByID implements repository.PaymentRepository.
 func (p *PaymentRepositoryImpl) GetByID(paymentID uuid.UUID) (*model.Payment, error) {
-	//
-	var payment model.Payment
-	row := p.db.QueryRow(`select * from get_payment_by_id($1)`, paymentID)
-
-	err := row.Scan(
-		&payment.ID,
-		&payment.ExternalRef,
-		&payment.UserID,
-		&payment.SubscriptionID,
-		&payment.Amount,
-		&payment.Status,
-		&payment.ProcessedAt,
-		&payment.CreatedAt,
-		&payment.UpdatedAt,
-	)
+	payment, err := scanPayment(p.db.QueryRow(`SELECT `+paymentColumns+` FROM payments WHERE id = $1 AND deleted_at IS NULL`, paymentID))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Printf("paymnet not found with ID: %v", paymentID)
-			return nil, fmt.Errorf("payment not found")
+			return nil, fmt.Errorf("payment %w", model.ErrNotFound)
 		}
 		log.Printf("Error scanning rating by ID: %v", err)
 		return nil, err
 	}
 
 	log.Printf("payment retrieved by ID: %+v", payment)
-	return &payment, nil
+	return payment, nil
 
 }
 
 // Update implements repository.PaymentRepository.
 func (p *PaymentRepositoryImpl) Update(payment *model.Payment) error {
 	//
-	_, err := p.db.Exec(`CALL update_payment($1, $2, $3, $4, $5, $6, $7)`,
-		payment.ID, payment.ExternalRef, payment.UserID, payment.SubscriptionID, payment.Amount, payment.Status, payment.ProcessedAt)
+	_, err := p.db.Exec(`CALL update_payment($1, $2, $3, $4, $5, $6, $7, $8)`,
+		payment.ID, payment.ExternalRef, payment.UserID, paymentSubscription(payment), payment.Amount.Amount, payment.Amount.Currency, payment.Status, payment.ProcessedAt)
 	if err != nil {
 		log.Printf("Error calling update_payment: %v", err)
 		return err
@@ -128,16 +144,46 @@ func (p *PaymentRepositoryImpl) Update(payment *model.Payment) error {
 
 
 func (p *PaymentRepositoryImpl) GetByExternalRef(ref string) (*model.Payment, error) {
+	return scanPayment(p.db.QueryRow(`SELECT `+paymentColumns+` FROM payments WHERE external_ref = $1 AND deleted_at IS NULL`, ref))
+}
+
+// NewPaymentRepositoryImpl returns a new instance of PaymentRepositoryImpl
+func NewPaymentRepository(db *sql.DB) repository.PaymentRepository {
+	return &PaymentRepositoryImpl{db: db}
+}
+
+// paymentColumns is the column list of a payment read straight from the table, in scanPayment order
+const paymentColumns = `id, external_ref, user_id, subscription_id, amount, currency::text, quote_id, status, processed_at, created_at, updated_at`
+
+func scanPayment(row rowScanner) (*model.Payment, error) {
 	var payment model.Payment
-	row := p.db.QueryRow(`SELECT * FROM payments WHERE external_ref = $1 AND deleted_at IS NULL`, ref)
-	err := row.Scan(&payment.ID, &payment.ExternalRef, &payment.UserID, &payment.SubscriptionID, &payment.Amount, &payment.Status, &payment.ProcessedAt, &payment.CreatedAt, &payment.UpdatedAt)
+	var processedAt sql.NullTime
+	var subscriptionID, quoteID uuid.NullUUID
+	err := row.Scan(
+		&payment.ID,
+		&payment.ExternalRef,
+		&payment.UserID,
+		&subscriptionID,
+		&payment.Amount.Amount,
+		&payment.Amount.Currency,
+		&quoteID,
+		&payment.Status,
+		&processedAt,
+		&payment.CreatedAt,
+		&payment.UpdatedAt,
+	)
 	if err != nil {
 		return nil, err
 	}
+	payment.ProcessedAt = processedAt.Time
+	payment.SubscriptionID = subscriptionID.UUID
+	if quoteID.Valid {
+		payment.QuoteID = &quoteID.UUID
+	}
 	return &payment, nil
 }
 
-// NewPaymentRepositoryImpl returns a new instance of PaymentRepositoryImpl
-func NewPaymentRepository(db *sql.DB) repository.PaymentRepository {
-	return &PaymentRepositoryImpl{db: db}
+// paymentSubscription stores the missing subscription of a bundle or pass payment as NULL
+func paymentSubscription(payment *model.Payment) uuid.NullUUID {
+	return uuid.NullUUID{UUID: payment.SubscriptionID, Valid: payment.SubscriptionID != uuid.Nil}
 }
@@ -0,0 +1,48 @@
+package gateway
+
+import (
+	"database/sql"
+	"fmt"
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/repository"
+	"log"
+
+	"github.com/gofrs/uuid"
+)
+
+type permissionRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewPermissionRepository returns a new PermissionRepository instance
+func NewPermissionRepository(db *sql.DB) repository.PermissionRepository {
+	return &permissionRepositoryImpl{db: db}
+}
+
+// GetUserRole returns the role stored on the users row
+func (p *permissionRepositoryImpl) GetUserRole(userID uuid.UUID) (string, error) {
+	var role sql.NullString
+
+	err := p.db.QueryRow(`SELECT get_user_role($1)`, userID).Scan(&role)
+	if err != nil {
+		log.Printf("Error calling get_user_role: %v", err)
+		return "", err
+	}
+
+	if !role.Valid {
+		return "", fmt.Errorf("user %w", model.ErrNotFound)
+	}
+	return role.String, nil
+}
+
+// HasPermission checks the user's role, extra roles and direct grants
+func (p *permissionRepositoryImpl) HasPermission(userID uuid.UUID, permission string) (bool, error) {
+	var allowed bool
+
+	err := p.db.QueryRow(`SELECT user_has_permission($1, $2)`, userID, permission).Scan(&allowed)
+	if err != nil {
+		log.Printf("Error calling user_has_permission: %v", err)
+		return false, err
+	}
+	return allowed, nil
+}
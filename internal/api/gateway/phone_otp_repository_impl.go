@@ -0,0 +1,97 @@
+package gateway
+
+import (
+	"database/sql"
+	"fmt"
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/repository"
+	"log"
+
+	"github.com/gofrs/uuid"
+)
+
+type phoneOTPRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewPhoneOTPRepository returns a new PhoneOTPRepository instance
+func NewPhoneOTPRepository(db *sql.DB) repository.PhoneOTPRepository {
+	return &phoneOTPRepositoryImpl{db: db}
+}
+
+func (p *phoneOTPRepositoryImpl) Create(otp *model.PhoneOTP) error {
+	var userID uuid.NullUUID
+	if otp.UserID != nil {
+		userID = uuid.NullUUID{UUID: *otp.UserID, Valid: true}
+	}
+
+	err := p.db.QueryRow(
+		`SELECT create_phone_otp($1, $2, $3, $4, $5)`,
+		otp.PhoneNumber, otp.Purpose, userID, otp.CodeHash, otp.ExpiresAt,
+	).Scan(&otp.ID)
+	if err != nil {
+		log.Printf("Error calling create_phone_otp: %v", err)
+		return fmt.Errorf("failed to store code: %w", err)
+	}
+	return nil
+}
+
+func (p *phoneOTPRepositoryImpl) FindActive(phoneNumber, purpose string) (*model.PhoneOTP, error) {
+	var otp model.PhoneOTP
+	var userID uuid.NullUUID
+
+	err := p.db.QueryRow(
+		`SELECT id, phone_number, purpose, user_id, code_hash, attempts, expires_at, consumed_at, created_at
+		FROM get_active_phone_otp($1, $2)`,
+		phoneNumber, purpose,
+	).Scan(
+		&otp.ID,
+		&otp.PhoneNumber,
+		&otp.Purpose,
+		&userID,
+		&otp.CodeHash,
+		&otp.Attempts,
+		&otp.ExpiresAt,
+		&otp.ConsumedAt,
+		&otp.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		log.Printf("Error calling get_active_phone_otp: %v", err)
+		return nil, err
+	}
+
+	if userID.Valid {
+		otp.UserID = &userID.UUID
+	}
+	return &otp, nil
+}
+
+func (p *phoneOTPRepositoryImpl) RegisterAttempt(otpID uuid.UUID) (int, error) {
+	var attempts int
+	if err := p.db.QueryRow(`SELECT register_phone_otp_attempt($1)`, otpID).Scan(&attempts); err != nil {
+		log.Printf("Error calling register_phone_otp_attempt: %v", err)
+		return 0, err
+	}
+	return attempts, nil
+}
+
+func (p *phoneOTPRepositoryImpl) Consume(otpID uuid.UUID) (bool, error) {
+	var updated int
+	if err := p.db.QueryRow(`SELECT consume_phone_otp($1)`, otpID).Scan(&updated); err != nil {
+		log.Printf("Error calling consume_phone_otp: %v", err)
+		return false, err
+	}
+	return updated > 0, nil
+}
+
+func (p *phoneOTPRepositoryImpl) PurgeExpired() (int, error) {
+	var deleted int
+	if err := p.db.QueryRow(`SELECT purge_phone_otps()`).Scan(&deleted); err != nil {
+		log.Printf("Error calling purge_phone_otps: %v", err)
+		return 0, err
+	}
+	return deleted, nil
+}
@@ -0,0 +1,379 @@
+package gateway
+
+import (
+	"database/sql"
+	"fmt"
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/repository"
+	"log"
+
+	"github.com/gofrs/uuid"
+	"github.com/lib/pq"
+)
+
+type promotionRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewPromotionRepository returns a new PromotionRepository instance
+func NewPromotionRepository(db *sql.DB) repository.PromotionRepository {
+	return &promotionRepositoryImpl{db: db}
+}
+
+const couponColumns = `id, code, discount_type, percent_off, amount_off, amount_currency::text, course_id,
+	max_redemptions, max_per_user, starts_at, expires_at, active, coupon_redemptions(id, NULL),
+	created_by, created_at, updated_at`
+
+const courseSaleColumns = `id, course_id, amount, currency::text, starts_at, ends_at, created_by, created_at`
+
+const priceQuoteColumns = `id, user_id, course_id, bundle_id, pass_influencer_id, currency::text, list_amount, sale_id, sale_amount,
+	coupon_id, coupon_code, discount_amount, total_amount, expires_at, created_at`
+
+func (r *promotionRepositoryImpl) CreateCoupon(coupon *model.Coupon) error {
+	amountOff, currency := couponAmountOff(coupon)
+	row := r.db.QueryRow(
+		`SELECT `+couponColumns+` FROM create_coupon($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		coupon.Code, coupon.DiscountType, couponPercentOff(coupon), amountOff, currency, nullUUID(coupon.CourseID),
+		coupon.MaxRedemptions, coupon.MaxPerUser, coupon.StartsAt, coupon.ExpiresAt, coupon.Active, nullUUID(coupon.CreatedBy),
+	)
+
+	created, err := scanCoupon(row)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return fmt.Errorf("coupon code %w", model.ErrAlreadyInUse)
+		}
+		log.Printf("Error calling create_coupon: %v", err)
+		return fmt.Errorf("failed to create coupon: %w", err)
+	}
+
+	*coupon = *created
+	return nil
+}
+
+func (r *promotionRepositoryImpl) UpdateCoupon(coupon *model.Coupon) error {
+	amountOff, currency := couponAmountOff(coupon)
+	row := r.db.QueryRow(
+		`SELECT `+couponColumns+` FROM update_coupon($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		coupon.ID, coupon.Code, coupon.DiscountType, couponPercentOff(coupon), amountOff, currency, nullUUID(coupon.CourseID),
+		coupon.MaxRedemptions, coupon.MaxPerUser, coupon.StartsAt, coupon.ExpiresAt, coupon.Active,
+	)
+
+	updated, err := scanCoupon(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("coupon %w", model.ErrNotFound)
+		}
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return fmt.Errorf("coupon code %w", model.ErrAlreadyInUse)
+		}
+		log.Printf("Error calling update_coupon: %v", err)
+		return fmt.Errorf("failed to update coupon: %w", err)
+	}
+
+	*coupon = *updated
+	return nil
+}
+
+func (r *promotionRepositoryImpl) DeleteCoupon(couponID uuid.UUID) (bool, error) {
+	var deleted int
+	if err := r.db.QueryRow(`SELECT delete_coupon($1)`, couponID).Scan(&deleted); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			return false, model.ErrCouponRedeemed
+		}
+		log.Printf("Error calling delete_coupon: %v", err)
+		return false, err
+	}
+	return deleted > 0, nil
+}
+
+func (r *promotionRepositoryImpl) GetCoupon(couponID uuid.UUID) (*model.Coupon, error) {
+	return r.getCoupon(`id = $1`, couponID)
+}
+
+func (r *promotionRepositoryImpl) GetCouponByCode(code string) (*model.Coupon, error) {
+	return r.getCoupon(`code = $1`, code)
+}
+
+func (r *promotionRepositoryImpl) getCoupon(condition string, key interface{}) (*model.Coupon, error) {
+	coupon, err := scanCoupon(r.db.QueryRow(`SELECT `+couponColumns+` FROM coupons WHERE `+condition, key))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("coupon %w", model.ErrNotFound)
+		}
+		log.Printf("Error getting coupon: %v", err)
+		return nil, err
+	}
+	return coupon, nil
+}
+
+func (r *promotionRepositoryImpl) ListCoupons(filter model.CouponFilter, page model.PageRequest) (*model.Page[*model.Coupon], error) {
+	q := &listQuery[*model.Coupon]{
+		name:    "coupons",
+		columns: couponColumns,
+		from:    "coupons",
+		sorts: map[string]sortColumn[*model.Coupon]{
+			"created_at": {expr: "created_at", cast: "timestamp", value: func(c *model.Coupon) string { return timeCursor(c.CreatedAt) }},
+			"code":       {expr: "code", cast: "text", value: func(c *model.Coupon) string { return c.Code }},
+		},
+		defaultSort: "-created_at",
+		id:          func(c *model.Coupon) uuid.UUID { return c.ID },
+		scan:        scanCoupon,
+	}
+
+	if filter.CourseID != nil {
+		q.where("course_id = ?", *filter.CourseID)
+	}
+	if filter.Active != nil {
+		q.where("active = ?", *filter.Active)
+	}
+	if filter.OwnedBy != nil {
+		q.where("course_id IN (SELECT id FROM courses WHERE influencer_id = ?)", *filter.OwnedBy)
+	}
+
+	return q.run(r.db, page)
+}
+
+func (r *promotionRepositoryImpl) CountRedemptions(couponID uuid.UUID, userID *uuid.UUID) (int, error) {
+	var count int
+	if err := r.db.QueryRow(`SELECT coupon_redemptions($1, $2)`, couponID, nullUUID(userID)).Scan(&count); err != nil {
+		log.Printf("Error calling coupon_redemptions: %v", err)
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *promotionRepositoryImpl) CreateSale(sale *model.CourseSale) error {
+	row := r.db.QueryRow(
+		`SELECT `+courseSaleColumns+` FROM create_course_sale($1, $2, $3, $4, $5, $6)`,
+		sale.CourseID, sale.Price.Currency, sale.Price.Amount, sale.StartsAt, sale.EndsAt, nullUUID(sale.CreatedBy),
+	)
+
+	created, err := scanCourseSale(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.ErrSaleOverlaps
+		}
+		log.Printf("Error calling create_course_sale: %v", err)
+		return fmt.Errorf("failed to create sale: %w", err)
+	}
+
+	*sale = *created
+	return nil
+}
+
+func (r *promotionRepositoryImpl) DeleteSale(courseID, saleID uuid.UUID) (bool, error) {
+	var deleted int
+	if err := r.db.QueryRow(`SELECT delete_course_sale($1, $2)`, courseID, saleID).Scan(&deleted); err != nil {
+		log.Printf("Error calling delete_course_sale: %v", err)
+		return false, err
+	}
+	return deleted > 0, nil
+}
+
+func (r *promotionRepositoryImpl) GetActiveSale(courseID uuid.UUID, currency string) (*model.CourseSale, error) {
+	sale, err := scanCourseSale(r.db.QueryRow(`SELECT `+courseSaleColumns+` FROM active_course_sale($1, $2)`, courseID, currency))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		log.Printf("Error calling active_course_sale: %v", err)
+		return nil, err
+	}
+	return sale, nil
+}
+
+func (r *promotionRepositoryImpl) ListSales(courseID uuid.UUID) ([]*model.CourseSale, error) {
+	rows, err := r.db.Query(`SELECT `+courseSaleColumns+` FROM course_sales
+		WHERE course_id = $1 AND ends_at > CURRENT_TIMESTAMP
+		ORDER BY starts_at, currency`, courseID)
+	if err != nil {
+		log.Printf("Error listing course sales: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sales []*model.CourseSale
+	for rows.Next() {
+		sale, err := scanCourseSale(rows)
+		if err != nil {
+			return nil, err
+		}
+		sales = append(sales, sale)
+	}
+	return sales, rows.Err()
+}
+
+func (r *promotionRepositoryImpl) CreateQuote(quote *model.PriceQuote) error {
+	var saleAmount *int64
+	if quote.SalePrice != nil {
+		saleAmount = &quote.SalePrice.Amount
+	}
+
+	row := r.db.QueryRow(
+		`SELECT `+priceQuoteColumns+` FROM create_price_quote($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`,
+		quote.UserID, nullUUID(quote.CourseID), nullUUID(quote.BundleID), nullUUID(quote.PassInfluencerID),
+		quote.ListPrice.Currency, quote.ListPrice.Amount,
+		nullUUID(quote.SaleID), saleAmount, nullUUID(quote.CouponID), nullString(quote.CouponCode),
+		quote.Discount.Amount, quote.Total.Amount, quote.ExpiresAt,
+	)
+
+	created, err := scanPriceQuote(row)
+	if err != nil {
+		log.Printf("Error calling create_price_quote: %v", err)
+		return fmt.Errorf("failed to create price quote: %w", err)
+	}
+
+	*quote = *created
+	return nil
+}
+
+func (r *promotionRepositoryImpl) GetQuote(quoteID uuid.UUID) (*model.PriceQuote, error) {
+	quote, err := scanPriceQuote(r.db.QueryRow(`SELECT `+priceQuoteColumns+` FROM price_quotes WHERE id = $1`, quoteID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("price quote %w", model.ErrNotFound)
+		}
+		log.Printf("Error getting price quote: %v", err)
+		return nil, err
+	}
+	return quote, nil
+}
+
+// couponPercentOff stores the percentage of percentage coupons only
+func couponPercentOff(coupon *model.Coupon) *int {
+	if coupon.DiscountType != model.DiscountPercentage {
+		return nil
+	}
+	return &coupon.PercentOff
+}
+
+// couponAmountOff stores the amount of fixed coupons only
+func couponAmountOff(coupon *model.Coupon) (*int64, *string) {
+	if coupon.DiscountType != model.DiscountFixed || coupon.AmountOff == nil {
+		return nil, nil
+	}
+	return &coupon.AmountOff.Amount, &coupon.AmountOff.Currency
+}
+
+func scanCoupon(row rowScanner) (*model.Coupon, error) {
+	var coupon model.Coupon
+	var percentOff, amountOff, maxRedemptions, maxPerUser sql.NullInt64
+	var currency sql.NullString
+	var courseID, createdBy uuid.NullUUID
+	var startsAt, expiresAt sql.NullTime
+
+	err := row.Scan(
+		&coupon.ID,
+		&coupon.Code,
+		&coupon.DiscountType,
+		&percentOff,
+		&amountOff,
+		&currency,
+		&courseID,
+		&maxRedemptions,
+		&maxPerUser,
+		&startsAt,
+		&expiresAt,
+		&coupon.Active,
+		&coupon.Redemptions,
+		&createdBy,
+		&coupon.CreatedAt,
+		&coupon.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	coupon.PercentOff = int(percentOff.Int64)
+	if amountOff.Valid {
+		coupon.AmountOff = &model.Money{Amount: amountOff.Int64, Currency: currency.String}
+	}
+	if courseID.Valid {
+		coupon.CourseID = &courseID.UUID
+	}
+	if createdBy.Valid {
+		coupon.CreatedBy = &createdBy.UUID
+	}
+	coupon.MaxRedemptions = nullIntPtr(maxRedemptions)
+	coupon.MaxPerUser = nullIntPtr(maxPerUser)
+	coupon.StartsAt = nullTimePtr(startsAt)
+	coupon.ExpiresAt = nullTimePtr(expiresAt)
+	return &coupon, nil
+}
+
+func scanCourseSale(row rowScanner) (*model.CourseSale, error) {
+	var sale model.CourseSale
+	var createdBy uuid.NullUUID
+
+	err := row.Scan(
+		&sale.ID,
+		&sale.CourseID,
+		&sale.Price.Amount,
+		&sale.Price.Currency,
+		&sale.StartsAt,
+		&sale.EndsAt,
+		&createdBy,
+		&sale.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if createdBy.Valid {
+		sale.CreatedBy = &createdBy.UUID
+	}
+	return &sale, nil
+}
+
+func scanPriceQuote(row rowScanner) (*model.PriceQuote, error) {
+	var quote model.PriceQuote
+	var currency string
+	var courseID, bundleID, passInfluencerID, saleID, couponID uuid.NullUUID
+	var saleAmount sql.NullInt64
+	var couponCode sql.NullString
+
+	err := row.Scan(
+		&quote.ID,
+		&quote.UserID,
+		&courseID,
+		&bundleID,
+		&passInfluencerID,
+		&currency,
+		&quote.ListPrice.Amount,
+		&saleID,
+		&saleAmount,
+		&couponID,
+		&couponCode,
+		&quote.Discount.Amount,
+		&quote.Total.Amount,
+		&quote.ExpiresAt,
+		&quote.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	quote.ListPrice.Currency = currency
+	quote.Discount.Currency = currency
+	quote.Total.Currency = currency
+	if courseID.Valid {
+		quote.CourseID = &courseID.UUID
+	}
+	if bundleID.Valid {
+		quote.BundleID = &bundleID.UUID
+	}
+	if passInfluencerID.Valid {
+		quote.PassInfluencerID = &passInfluencerID.UUID
+	}
+	if saleID.Valid {
+		quote.SaleID = &saleID.UUID
+	}
+	if saleAmount.Valid {
+		quote.SalePrice = &model.Money{Amount: saleAmount.Int64, Currency: currency}
+	}
+	if couponID.Valid {
+		quote.CouponID = &couponID.UUID
+	}
+	quote.CouponCode = couponCode.String
+	return &quote, nil
+}
@@ -6,6 +6,7 @@ import (
 	"kaabe-app/internal/domain/model"
 	"kaabe-app/internal/domain/repository"
 	"log"
+	"strconv"
 
 	"github.com/gofrs/uuid"
 )
@@ -58,7 +59,7 @@ func (r *RatingRepositoryImpl) GetByID(ratingID uuid.UUID) (*model.Rating, error
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Printf("rating not found with ID: %v", ratingID)
-			return nil, fmt.Errorf("rating not found")
+			return nil, fmt.Errorf("rating %w", model.ErrNotFound)
 		}
 		log.Printf("Error scanning rating by ID: %v", err)
 		return nil, err
@@ -68,35 +69,64 @@ func (r *RatingRepositoryImpl) GetByID(ratingID uuid.UUID) (*model.Rating, error
 	return &rating, nil
 }
 
-// Getall implements repository.RatingRepository.
-func (r *RatingRepositoryImpl) Getall() ([]*model.Rating, error) {
+// List implements repository.RatingRepository.
+func (r *RatingRepositoryImpl) List(filter model.RatingFilter, page model.PageRequest) (*model.Page[*model.Rating], error) {
+	q := &listQuery[*model.Rating]{
+		name:    "ratings",
+		columns: ratingColumns,
+		from:    "ratings",
+		sorts: map[string]sortColumn[*model.Rating]{
+			"created_at": {expr: "created_at", cast: "timestamptz", value: func(r *model.Rating) string { return timeCursor(r.CreatedAt) }},
+			"score":      {expr: "score", cast: "integer", value: func(r *model.Rating) string { return strconv.Itoa(r.Score) }},
+		},
+		defaultSort: "-created_at",
+		id:          func(r *model.Rating) uuid.UUID { return r.ID },
+		scan:        scanRating,
+	}
+
+	q.where("deleted_at IS NULL")
+	if filter.CourseID != nil {
+		q.where("course_id = ?", *filter.CourseID)
+	}
+	if filter.UserID != nil {
+		q.where("user_id = ?", *filter.UserID)
+	}
+	if filter.MinScore > 0 {
+		q.where("score >= ?", filter.MinScore)
+	}
+
+	return q.run(r.db, page)
+}
 
-	rows, err := r.db.Query(`SELECT * FROM get_all_ratings()`)
+// GetByUserID implements repository.RatingRepository.
+func (r *RatingRepositoryImpl) GetByUserID(userID uuid.UUID) ([]*model.Rating, error) {
+	rows, err := r.db.Query(`SELECT * FROM get_user_ratings($1)`, userID)
 	if err != nil {
-		log.Printf("Error querying get_all_ratings: %v", err)
+		log.Printf("Error querying get_user_ratings: %v", err)
 		return nil, err
 	}
 	defer rows.Close()
 
-	var Ratings []*model.Rating
+	var ratings []*model.Rating
 
 	for rows.Next() {
-		var Rating model.Rating
+		var rating model.Rating
+		var comment sql.NullString
 		err := rows.Scan(
-			&Rating.ID,
-			&Rating.UserID,
-			&Rating.CourseID,
-			&Rating.Score,
-			&Rating.Comment,
-			&Rating.CreatedAt,
-			&Rating.UpdatedAt,
+			&rating.ID,
+			&rating.UserID,
+			&rating.CourseID,
+			&rating.Score,
+			&comment,
+			&rating.CreatedAt,
+			&rating.UpdatedAt,
 		)
-
 		if err != nil {
-			log.Printf("Error scanning ratig row: %v", err)
+			log.Printf("Error scanning rating row: %v", err)
 			return nil, err
 		}
-		Ratings = append(Ratings, &Rating)
+		rating.Comment = comment.String
+		ratings = append(ratings, &rating)
 	}
 
 	if err = rows.Err(); err != nil {
@@ -104,8 +134,7 @@ func (r *RatingRepositoryImpl) Getall() ([]*model.Rating, error) {
 		return nil, err
 	}
 
-	log.Printf("Lessons retrieved: %d", len(Ratings))
-	return Ratings, nil
+	return ratings, nil
 }
 
 // Update implements repository.RatingRepository.
@@ -124,3 +153,25 @@ func (r *RatingRepositoryImpl) Update(rating *model.Rating) error {
 func NewRatingRepository(db *sql.DB) repository.RatingRepository {
 	return &RatingRepositoryImpl{db: db}
 }
+
+// ratingColumns is the column list of a rating read straight from the table, in scanRating order
+const ratingColumns = `id, user_id, course_id, score, comment, created_at, updated_at`
+
+func scanRating(row rowScanner) (*model.Rating, error) {
+	var rating model.Rating
+	var comment sql.NullString
+	err := row.Scan(
+		&rating.ID,
+		&rating.UserID,
+		&rating.CourseID,
+		&rating.Score,
+		&comment,
+		&rating.CreatedAt,
+		&rating.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	rating.Comment = comment.String
+	return &rating, nil
+}
@@ -0,0 +1,78 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"kaabe-app/internal/domain/repository"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisAttemptTimeout bounds every Redis call so a slow Redis cannot stall logins
+const redisAttemptTimeout = 2 * time.Second
+
+// redisAttemptStore shares counters and locks between all API instances
+type redisAttemptStore struct {
+	client *redis.Client
+}
+
+// NewRedisAttemptStore returns a Redis-backed AttemptStore
+func NewRedisAttemptStore(client *redis.Client) repository.AttemptStore {
+	return &redisAttemptStore{client: client}
+}
+
+func (r *redisAttemptStore) RegisterFailure(key string, window time.Duration) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisAttemptTimeout)
+	defer cancel()
+
+	counterKey := "attempts:" + key
+	count, err := r.client.Incr(ctx, counterKey).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count attempt: %v", err)
+	}
+
+	// The first failure opens the window
+	if count == 1 {
+		if err := r.client.Expire(ctx, counterKey, window).Err(); err != nil {
+			return 0, fmt.Errorf("failed to set attempt window: %v", err)
+		}
+	}
+	return int(count), nil
+}
+
+func (r *redisAttemptStore) Reset(key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), redisAttemptTimeout)
+	defer cancel()
+
+	if err := r.client.Del(ctx, "attempts:"+key).Err(); err != nil {
+		return fmt.Errorf("failed to reset attempts: %v", err)
+	}
+	return nil
+}
+
+func (r *redisAttemptStore) Lock(key string, duration time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), redisAttemptTimeout)
+	defer cancel()
+
+	if err := r.client.Set(ctx, "lock:"+key, 1, duration).Err(); err != nil {
+		return fmt.Errorf("failed to set lock: %v", err)
+	}
+	return nil
+}
+
+func (r *redisAttemptStore) LockedUntil(key string) (time.Time, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisAttemptTimeout)
+	defer cancel()
+
+	ttl, err := r.client.PTTL(ctx, "lock:"+key).Result()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read lock: %v", err)
+	}
+
+	// PTTL is negative when the key does not exist or has no expiry
+	if ttl <= 0 {
+		return time.Time{}, nil
+	}
+	return time.Now().Add(ttl), nil
+}
@@ -0,0 +1,58 @@
+package gateway
+
+import (
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/service"
+	"log"
+	"net"
+	"net/smtp"
+)
+
+// smtpMailer delivers email through an SMTP relay
+type smtpMailer struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     string
+}
+
+// NewSMTPMailer returns a Mailer that sends through the given SMTP server.
+// Authentication is skipped when username is empty.
+func NewSMTPMailer(host, port, username, password, from string) service.Mailer {
+	return &smtpMailer{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     from,
+	}
+}
+
+// Send implements service.Mailer
+func (m *smtpMailer) Send(message *model.EmailMessage) error {
+	body, err := buildMIMEMessage(m.from, message)
+	if err != nil {
+		return err
+	}
+
+	sender, err := envelopeAddress(m.from)
+	if err != nil {
+		return err
+	}
+	recipient, err := envelopeAddress(message.To)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.username != "" {
+		auth = smtp.PlainAuth("", m.username, m.password, m.host)
+	}
+
+	if err := smtp.SendMail(net.JoinHostPort(m.host, m.port), auth, sender, []string{recipient}, body); err != nil {
+		log.Printf("Error sending email via SMTP: %v", err)
+		return err
+	}
+	return nil
+}
@@ -59,7 +59,7 @@ func (r *SubscriptionImpl) Get(SubscriptionID uuid.UUID) (*model.Subscription, e
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Printf("Subscription not found with ID: %v", SubscriptionID)
-			return nil, fmt.Errorf("Subscription not found")
+			return nil, fmt.Errorf("Subscription %w", model.ErrNotFound)
 		}
 		log.Printf("Error scanning Subscription by ID: %v", err)
 		return nil, err
@@ -70,33 +70,62 @@ func (r *SubscriptionImpl) Get(SubscriptionID uuid.UUID) (*model.Subscription, e
 }
 
 // List implements repository.SubscriptionRepository.
-func (r *SubscriptionImpl) List() ([]*model.Subscription, error) {
-	rows, err := r.db.Query(`SELECT * FROM get_all_subscriptions()`)
+func (r *SubscriptionImpl) List(filter model.SubscriptionFilter, page model.PageRequest) (*model.Page[*model.Subscription], error) {
+	q := &listQuery[*model.Subscription]{
+		name:    "subscriptions",
+		columns: subscriptionColumns,
+		from:    "subscriptions",
+		sorts: map[string]sortColumn[*model.Subscription]{
+			"created_at": {expr: "created_at", cast: "timestamptz", value: func(s *model.Subscription) string { return timeCursor(s.CreatedAt) }},
+			"expires_at": {expr: "expires_at", cast: "timestamptz", value: func(s *model.Subscription) string { return timeCursor(s.ExpiresAt) }},
+		},
+		defaultSort: "-created_at",
+		id:          func(s *model.Subscription) uuid.UUID { return s.ID },
+		scan:        scanSubscription,
+	}
+
+	q.where("deleted_at IS NULL")
+	if filter.UserID != nil {
+		q.where("user_id = ?", *filter.UserID)
+	}
+	if filter.CourseID != nil {
+		q.where("course_id = ?", *filter.CourseID)
+	}
+	if filter.Status != "" {
+		q.where("status::text = ?", filter.Status)
+	}
+
+	return q.run(r.db, page)
+}
+
+// ListByUser implements repository.SubscriptionRepository.
+func (r *SubscriptionImpl) ListByUser(userID uuid.UUID) ([]*model.Subscription, error) {
+	rows, err := r.db.Query(`SELECT * FROM get_user_subscriptions($1)`, userID)
 	if err != nil {
-		log.Printf("Error querying get_all_Subscription: %v", err)
+		log.Printf("Error querying get_user_subscriptions: %v", err)
 		return nil, err
 	}
 	defer rows.Close()
 
-	var Subscriptions []*model.Subscription
+	var subscriptions []*model.Subscription
 
 	for rows.Next() {
-		var Subscription model.Subscription
+		var subscription model.Subscription
 		err := rows.Scan(
-			&Subscription.ID,
-			&Subscription.UserID,
-			&Subscription.CourseID,
-			&Subscription.StartedAt,
-			&Subscription.ExpiresAt,
-			&Subscription.Status,
-			&Subscription.CreatedAt,
-			&Subscription.UpdatedAt,
+			&subscription.ID,
+			&subscription.UserID,
+			&subscription.CourseID,
+			&subscription.StartedAt,
+			&subscription.ExpiresAt,
+			&subscription.Status,
+			&subscription.CreatedAt,
+			&subscription.UpdatedAt,
 		)
 		if err != nil {
 			log.Printf("Error scanning Subscription row: %v", err)
 			return nil, err
 		}
-		Subscriptions = append(Subscriptions, &Subscription)
+		subscriptions = append(subscriptions, &subscription)
 	}
 
 	if err = rows.Err(); err != nil {
@@ -104,8 +133,7 @@ func (r *SubscriptionImpl) List() ([]*model.Subscription, error) {
 		return nil, err
 	}
 
-	log.Printf("Subscription retrieved: %d", len(Subscriptions))
-	return Subscriptions, nil
+	return subscriptions, nil
 }
 
 // Update implements repository.SubscriptionRepository.
@@ -129,3 +157,24 @@ func (r *SubscriptionImpl) Update(Subscription *model.Subscription) error {
 func NewSubscriptionImpl(db *sql.DB) repository.SubscriptionRepository {
 	return &SubscriptionImpl{db: db}
 }
+
+// subscriptionColumns is the column list of a subscription read straight from the table, in scanSubscription order
+const subscriptionColumns = `id, user_id, course_id, started_at, expires_at, status::text, created_at, updated_at`
+
+func scanSubscription(row rowScanner) (*model.Subscription, error) {
+	var subscription model.Subscription
+	err := row.Scan(
+		&subscription.ID,
+		&subscription.UserID,
+		&subscription.CourseID,
+		&subscription.StartedAt,
+		&subscription.ExpiresAt,
+		&subscription.Status,
+		&subscription.CreatedAt,
+		&subscription.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
@@ -7,6 +7,8 @@ import (
 	"kaabe-app/internal/domain/repository"
 	"log"
 	"time"
+
+	"github.com/gofrs/uuid"
 )
 
 // tokenRepositoryImpl is the PostgreSQL-based implementation of TokenRepository
@@ -25,12 +27,18 @@ func (t *tokenRepositoryImpl) Create(token *model.Token) error {
 	token.CreatedAt = now
 	token.UpdatedAt = now
 
+	if token.Type == "" {
+		token.Type = model.TokenTypeAccess
+	}
+
 	_, err := t.db.Exec(`
-		CALL create_token($1, $2, $3, $4, $5, $6)
+		CALL create_token($1, $2, $3, $4, $5, $6, $7, $8)
 	`,
 		token.ID,
 		token.UserID,
 		token.Token,
+		token.Type,
+		token.FamilyID,
 		token.ExpiresAt,
 		token.CreatedAt,
 		token.UpdatedAt,
@@ -44,31 +52,130 @@ func (t *tokenRepositoryImpl) Create(token *model.Token) error {
 	return nil
 }
 
-// FindByToken retrieves a token by its value using a SQL function
+// FindByToken retrieves an active token by its value using a SQL function
 func (t *tokenRepositoryImpl) FindByToken(tokenStr string) (*model.Token, error) {
-	query := `SELECT * FROM get_token_by_token($1);`
+	row := t.db.QueryRow(`SELECT * FROM get_token_by_token($1);`, tokenStr)
+
+	token, err := scanToken(row)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		log.Printf("Token not found")
+		return nil, nil
+	case err != nil:
+		log.Printf("Error scanning token row: %v", err)
+		return nil, err
+	default:
+		return token, nil
+	}
+}
+
+// FindRefreshToken retrieves a refresh token by its value, including used and revoked ones
+func (t *tokenRepositoryImpl) FindRefreshToken(tokenStr string) (*model.Token, error) {
+	row := t.db.QueryRow(`SELECT * FROM get_refresh_token($1);`, tokenStr)
+
+	token, err := scanToken(row)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		log.Printf("Refresh token not found")
+		return nil, nil
+	case err != nil:
+		log.Printf("Error scanning refresh token row: %v", err)
+		return nil, err
+	default:
+		return token, nil
+	}
+}
+
+// MarkUsed flags a refresh token as rotated; returns false if it had already been used
+func (t *tokenRepositoryImpl) MarkUsed(tokenID uuid.UUID) (bool, error) {
+	var updated int
+	if err := t.db.QueryRow(`SELECT mark_token_used($1)`, tokenID).Scan(&updated); err != nil {
+		log.Printf("Error calling mark_token_used: %v", err)
+		return false, err
+	}
+	return updated > 0, nil
+}
+
+// RevokeFamily revokes every token issued from the same login
+func (t *tokenRepositoryImpl) RevokeFamily(familyID uuid.UUID) error {
+	var revoked int
+	if err := t.db.QueryRow(`SELECT revoke_token_family($1)`, familyID).Scan(&revoked); err != nil {
+		log.Printf("Error calling revoke_token_family: %v", err)
+		return err
+	}
 
-	row := t.db.QueryRow(query, tokenStr)
+	log.Printf("Revoked %d tokens in family %v", revoked, familyID)
+	return nil
+}
 
+// Revoke marks a single token as revoked
+func (t *tokenRepositoryImpl) Revoke(tokenStr string) error {
+	var revoked int
+	if err := t.db.QueryRow(`SELECT revoke_token($1)`, tokenStr).Scan(&revoked); err != nil {
+		log.Printf("Error calling revoke_token: %v", err)
+		return err
+	}
+	return nil
+}
+
+// RevokeAllForUser revokes every active token belonging to a user
+func (t *tokenRepositoryImpl) RevokeAllForUser(userID uuid.UUID) error {
+	var revoked int
+	if err := t.db.QueryRow(`SELECT revoke_user_tokens($1)`, userID).Scan(&revoked); err != nil {
+		log.Printf("Error calling revoke_user_tokens: %v", err)
+		return err
+	}
+
+	log.Printf("Revoked %d tokens for user %v", revoked, userID)
+	return nil
+}
+
+// RevokeAllForUserExcept revokes every active token of a user outside the given session
+func (t *tokenRepositoryImpl) RevokeAllForUserExcept(userID, familyID uuid.UUID) error {
+	var revoked int
+	if err := t.db.QueryRow(`SELECT revoke_user_tokens_except($1, $2)`, userID, familyID).Scan(&revoked); err != nil {
+		log.Printf("Error calling revoke_user_tokens_except: %v", err)
+		return err
+	}
+
+	log.Printf("Revoked %d tokens for user %v, kept session %v", revoked, userID, familyID)
+	return nil
+}
+
+// PurgeExpired deletes expired token rows and returns how many were removed
+func (t *tokenRepositoryImpl) PurgeExpired() (int, error) {
+	var purged int
+	if err := t.db.QueryRow(`SELECT purge_expired_tokens()`).Scan(&purged); err != nil {
+		log.Printf("Error calling purge_expired_tokens: %v", err)
+		return 0, err
+	}
+	return purged, nil
+}
+
+// scanToken maps a token row returned by the token SQL functions
+func scanToken(row *sql.Row) (*model.Token, error) {
 	var token model.Token
+	var familyID uuid.NullUUID
+
 	err := row.Scan(
 		&token.ID,
 		&token.UserID,
 		&token.Token,
+		&token.Type,
+		&familyID,
 		&token.ExpiresAt,
+		&token.UsedAt,
 		&token.CreatedAt,
 		&token.UpdatedAt,
 		&token.DeletedAt,
 	)
-
-	switch {
-	case errors.Is(err, sql.ErrNoRows):
-		log.Printf("Token not found: %s", tokenStr)
-		return nil, nil
-	case err != nil:
-		log.Printf("Error scanning token row: %v", err)
+	if err != nil {
 		return nil, err
-	default:
-		return &token, nil
 	}
+
+	if familyID.Valid {
+		token.FamilyID = &familyID.UUID
+	}
+
+	return &token, nil
 }
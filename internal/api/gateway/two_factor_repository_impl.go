@@ -0,0 +1,71 @@
+package gateway
+
+import (
+	"database/sql"
+	"fmt"
+	"kaabe-app/internal/domain/repository"
+	"log"
+
+	"github.com/gofrs/uuid"
+	"github.com/lib/pq"
+)
+
+type twoFactorRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewTwoFactorRepository returns a new TwoFactorRepository instance
+func NewTwoFactorRepository(db *sql.DB) repository.TwoFactorRepository {
+	return &twoFactorRepositoryImpl{db: db}
+}
+
+func (t *twoFactorRepositoryImpl) SetSecret(userID uuid.UUID, secret string) (bool, error) {
+	return t.updated(`SELECT set_totp_secret($1, $2)`, userID, secret)
+}
+
+func (t *twoFactorRepositoryImpl) Enable(userID uuid.UUID) (bool, error) {
+	return t.updated(`SELECT enable_totp($1)`, userID)
+}
+
+func (t *twoFactorRepositoryImpl) Disable(userID uuid.UUID) error {
+	if _, err := t.db.Exec(`CALL disable_totp($1)`, userID); err != nil {
+		log.Printf("Error calling disable_totp: %v", err)
+		return fmt.Errorf("failed to disable two-factor authentication: %w", err)
+	}
+	return nil
+}
+
+func (t *twoFactorRepositoryImpl) UseStep(userID uuid.UUID, step int64) (bool, error) {
+	return t.updated(`SELECT use_totp_step($1, $2)`, userID, step)
+}
+
+func (t *twoFactorRepositoryImpl) ReplaceRecoveryCodes(userID uuid.UUID, codeHashes []string) error {
+	if _, err := t.db.Exec(`CALL replace_recovery_codes($1, $2)`, userID, pq.Array(codeHashes)); err != nil {
+		log.Printf("Error calling replace_recovery_codes: %v", err)
+		return fmt.Errorf("failed to store recovery codes: %w", err)
+	}
+	return nil
+}
+
+func (t *twoFactorRepositoryImpl) ConsumeRecoveryCode(userID uuid.UUID, codeHash string) (bool, error) {
+	return t.updated(`SELECT consume_recovery_code($1, $2)`, userID, codeHash)
+}
+
+func (t *twoFactorRepositoryImpl) CountRecoveryCodes(userID uuid.UUID) (int, error) {
+	var count int
+	if err := t.db.QueryRow(`SELECT count_recovery_codes($1)`, userID).Scan(&count); err != nil {
+		log.Printf("Error calling count_recovery_codes: %v", err)
+		return 0, err
+	}
+	return count, nil
+}
+
+// updated runs a function that returns an affected row count and reports whether it was positive
+func (t *twoFactorRepositoryImpl) updated(query string, args ...interface{}) (bool, error) {
+	var count int
+	if err := t.db.QueryRow(query, args...).Scan(&count); err != nil {
+		log.Printf("Error running %q: %v", query, err)
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/gofrs/uuid"
+	"github.com/lib/pq"
 )
 
 type userRepositoryImpl struct {
@@ -26,7 +27,7 @@ func (r *userRepositoryImpl) Create(user *model.User) error {
 
 	err := r.db.QueryRow(
 		query,
-		user.Email,
+		nullString(user.Email),
 		user.Password,
 		user.FirstName,
 		user.LastName,
@@ -51,7 +52,7 @@ func (r *userRepositoryImpl) Update(user *model.User) error {
 	err := r.db.QueryRow(
 		query,
 		user.ID,
-		user.Email,
+		nullString(user.Email),
 		user.Password,
 		user.FirstName,
 		user.LastName,
@@ -82,7 +83,7 @@ func (r *userRepositoryImpl) Delete(userID uuid.UUID) error {
 
 	if rowsDeleted == 0 {
 		log.Printf("User not found")
-		return fmt.Errorf("user not found")
+		return fmt.Errorf("user %w", model.ErrNotFound)
 	}
 
 	log.Printf("User deleted")
@@ -91,71 +92,234 @@ func (r *userRepositoryImpl) Delete(userID uuid.UUID) error {
 
 // Get user by ID
 func (r *userRepositoryImpl) Get(userID uuid.UUID) (*model.User, error) {
-	var user model.User
-
-	query := `SELECT * FROM get_user_by_id($1)`
-	err := r.db.QueryRow(query, userID).Scan(
-		&user.ID,
-		&user.Email,
-		&user.Password,
-		&user.FirstName,
-		&user.LastName,
-		&user.Role,
-		&user.WalletID,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	query := `SELECT ` + userColumns + ` FROM get_user_by_id($1)`
+	user, err := scanUser(r.db.QueryRow(query, userID))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Printf("User not found")
-			return nil, fmt.Errorf("user not found")
+			return nil, fmt.Errorf("user %w", model.ErrNotFound)
 		}
 		log.Printf("DB error: %v", err)
 		return nil, err
 	}
 
-	return &user, nil
+	return user, nil
 }
 
 // Find user by email
 func (r *userRepositoryImpl) FindByEmail(email string) (*model.User, error) {
-	var user model.User
-	var walletID uuid.NullUUID
+	query := `SELECT ` + userColumns + ` FROM get_user_by_email($1)`
+	user, err := scanUser(r.db.QueryRow(query, email))
+	if err != nil {
+		log.Printf("DB error: %v", err)
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
 
-	query := `SELECT * FROM get_user_by_email($1)`
-	err := r.db.QueryRow(query, email).Scan(
-		&user.ID,
-		&user.Email,
-		&user.Password,
-		&user.FirstName,
-		&user.LastName,
-		&user.Role,
-		&walletID,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	return user, nil
+}
+
+// List returns one page of users matching the filter; anonymized accounts are never listed
+func (r *userRepositoryImpl) List(filter model.UserFilter, page model.PageRequest) (*model.Page[*model.User], error) {
+	q := &listQuery[*model.User]{
+		name:    "users",
+		columns: userColumns,
+		from:    "users",
+		sorts: map[string]sortColumn[*model.User]{
+			"created_at": {expr: "created_at", cast: "timestamp", value: func(u *model.User) string { return timeCursor(u.CreatedAt) }},
+			"email":      {expr: "COALESCE(email, '')", cast: "text", value: func(u *model.User) string { return u.Email }},
+			"last_name":  {expr: "last_name", cast: "text", value: func(u *model.User) string { return u.LastName }},
+		},
+		defaultSort: "-created_at",
+		id:          func(u *model.User) uuid.UUID { return u.ID },
+		scan:        scanUser,
+	}
 
+	q.where("deleted_at IS NULL")
+	if filter.Role != "" {
+		q.where("role::text = ?", filter.Role)
+	}
+	if filter.Search != "" {
+		q.where("(email ILIKE ? OR first_name ILIKE ? OR last_name ILIKE ?)",
+			likePattern(filter.Search), likePattern(filter.Search), likePattern(filter.Search))
+	}
+
+	return q.run(r.db, page)
+}
+
+// SetResetToken saves reset token and expiry
+func (r *userRepositoryImpl) SetResetToken(email string, token uuid.UUID, expiry string) error {
+	query := `SELECT set_reset_token($1, $2, $3)`
+	_, err := r.db.Exec(query, email, token, expiry)
 	if err != nil {
+		log.Printf("Error setting reset token: %v", err)
+		return fmt.Errorf("failed to set reset token: %w", err)
+	}
+	return nil
+}
+
+// FindByResetToken finds user by reset token
+func (r *userRepositoryImpl) FindByResetToken(token uuid.UUID) (*model.User, error) {
+	query := `SELECT ` + userColumns + ` FROM get_user_by_reset_token($1)`
+	user, err := scanUser(r.db.QueryRow(query, token))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("Reset token invalid or expired")
+			return nil, fmt.Errorf("reset token invalid or expired")
+		}
+		log.Printf("DB error: %v", err)
+		return nil, fmt.Errorf("failed to find user by reset token: %w", err)
+	}
+
+	return user, nil
+}
+
+// UpdatePassword updates the user's hashed password
+func (r *userRepositoryImpl) UpdatePassword(userID uuid.UUID, hashedPassword string) error {
+	query := `SELECT update_user_password($1, $2)`
+	_, err := r.db.Exec(query, userID, hashedPassword)
+	if err != nil {
+		log.Printf("Error updating password: %v", err)
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+	return nil
+}
+
+// ClearResetToken clears the reset token fields
+func (r *userRepositoryImpl) ClearResetToken(userID uuid.UUID) error {
+	query := `SELECT clear_reset_token($1)`
+	_, err := r.db.Exec(query, userID)
+	if err != nil {
+		log.Printf("Error clearing reset token: %v", err)
+		return fmt.Errorf("failed to clear reset token: %w", err)
+	}
+	return nil
+}
+
+// MarkEmailVerified stamps email_verified_at; it reports false if the email was already verified
+func (r *userRepositoryImpl) MarkEmailVerified(userID uuid.UUID) (bool, error) {
+	var updated int
+	err := r.db.QueryRow(`SELECT mark_email_verified($1)`, userID).Scan(&updated)
+	if err != nil {
+		log.Printf("Error marking email verified: %v", err)
+		return false, fmt.Errorf("failed to mark email verified: %w", err)
+	}
+	return updated > 0, nil
+}
+
+// ChangeEmail replaces the user's email with a confirmed address
+func (r *userRepositoryImpl) ChangeEmail(userID uuid.UUID, email string) error {
+	var updated int
+	err := r.db.QueryRow(`SELECT change_user_email($1, $2)`, userID, email).Scan(&updated)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return fmt.Errorf("email %w", model.ErrAlreadyInUse)
+		}
+		log.Printf("Error changing email: %v", err)
+		return fmt.Errorf("failed to change email: %w", err)
+	}
+
+	if updated == 0 {
+		return fmt.Errorf("user %w", model.ErrNotFound)
+	}
+	return nil
+}
+
+// FindByPhone finds the user a verified phone number belongs to
+func (r *userRepositoryImpl) FindByPhone(phoneNumber string) (*model.User, error) {
+	query := `SELECT ` + userColumns + ` FROM get_user_by_phone($1)`
+	user, err := scanUser(r.db.QueryRow(query, phoneNumber))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user %w", model.ErrNotFound)
+		}
 		log.Printf("DB error: %v", err)
 		return nil, fmt.Errorf("failed to find user: %w", err)
 	}
 
-	if walletID.Valid {
-		wid := walletID.UUID.String()
-		user.WalletID = &wid
-	} else {
-		user.WalletID = nil
+	return user, nil
+}
+
+// CreatePhoneUser creates an account identified only by a verified phone number
+func (r *userRepositoryImpl) CreatePhoneUser(user *model.User) error {
+	err := r.db.QueryRow(
+		`SELECT create_phone_user($1, $2, $3)`,
+		user.PhoneNumber,
+		user.FirstName,
+		user.LastName,
+	).Scan(&user.ID)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return fmt.Errorf("phone number %w", model.ErrAlreadyInUse)
+		}
+		log.Printf("Error calling create_phone_user: %v", err)
+		return err
 	}
 
-	return &user, nil
+	log.Printf("User created with ID: %s", user.ID)
+	return nil
 }
 
+// SetPhone stores a phone number the user has just verified
+func (r *userRepositoryImpl) SetPhone(userID uuid.UUID, phoneNumber string) error {
+	var updated int
+	err := r.db.QueryRow(`SELECT set_user_phone($1, $2)`, userID, phoneNumber).Scan(&updated)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return fmt.Errorf("phone number %w", model.ErrAlreadyInUse)
+		}
+		log.Printf("Error setting phone number: %v", err)
+		return fmt.Errorf("failed to set phone number: %w", err)
+	}
 
-// List all users
-func (r *userRepositoryImpl) List() ([]*model.User, error) {
-	rows, err := r.db.Query("SELECT * FROM get_all_users()")
+	if updated == 0 {
+		return fmt.Errorf("user %w", model.ErrNotFound)
+	}
+	return nil
+}
+
+// SetWalletPhone makes the user's verified number their payment wallet; it reports false
+// if the number is not the user's verified number
+func (r *userRepositoryImpl) SetWalletPhone(userID uuid.UUID, phoneNumber string) (bool, error) {
+	var updated int
+	err := r.db.QueryRow(`SELECT set_wallet_phone($1, $2)`, userID, phoneNumber).Scan(&updated)
+	if err != nil {
+		log.Printf("Error setting wallet phone: %v", err)
+		return false, fmt.Errorf("failed to set wallet phone: %w", err)
+	}
+	return updated > 0, nil
+}
+
+// RequestDeletion schedules the account to be anonymized at scheduledAt
+func (r *userRepositoryImpl) RequestDeletion(userID uuid.UUID, scheduledAt time.Time) error {
+	var updated int
+	err := r.db.QueryRow(`SELECT request_user_deletion($1, $2)`, userID, scheduledAt).Scan(&updated)
+	if err != nil {
+		log.Printf("Error requesting user deletion: %v", err)
+		return fmt.Errorf("failed to request deletion: %w", err)
+	}
+
+	if updated == 0 {
+		return fmt.Errorf("user %w", model.ErrNotFound)
+	}
+	return nil
+}
+
+// CancelDeletion clears a pending deletion request; it reports false if none was pending
+func (r *userRepositoryImpl) CancelDeletion(userID uuid.UUID) (bool, error) {
+	var updated int
+	err := r.db.QueryRow(`SELECT cancel_user_deletion($1)`, userID).Scan(&updated)
+	if err != nil {
+		log.Printf("Error cancelling user deletion: %v", err)
+		return false, fmt.Errorf("failed to cancel deletion: %w", err)
+	}
+	return updated > 0, nil
+}
+
+// ListDueForDeletion returns up to limit accounts whose grace period has ended
+func (r *userRepositoryImpl) ListDueForDeletion(limit int) ([]*model.User, error) {
+	rows, err := r.db.Query(`SELECT `+userColumns+` FROM get_users_due_for_deletion($1)`, limit)
 	if err != nil {
-		log.Printf("Error getting users: %v", err)
+		log.Printf("Error getting users due for deletion: %v", err)
 		return nil, err
 	}
 	defer rows.Close()
@@ -163,23 +327,12 @@ func (r *userRepositoryImpl) List() ([]*model.User, error) {
 	var users []*model.User
 
 	for rows.Next() {
-		var user model.User
-		err := rows.Scan(
-			&user.ID,
-			&user.Email,
-			&user.Password,
-			&user.FirstName,
-			&user.LastName,
-			&user.Role,
-			&user.WalletID,
-			&user.CreatedAt,
-			&user.UpdatedAt,
-		)
+		user, err := scanUser(rows)
 		if err != nil {
 			log.Printf("Error scanning user: %v", err)
 			return nil, err
 		}
-		users = append(users, &user)
+		users = append(users, user)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -190,74 +343,94 @@ func (r *userRepositoryImpl) List() ([]*model.User, error) {
 	return users, nil
 }
 
-// SetResetToken saves reset token and expiry
-func (r *userRepositoryImpl) SetResetToken(email string, token uuid.UUID, expiry string) error {
-	query := `SELECT set_reset_token($1, $2, $3)`
-	_, err := r.db.Exec(query, email, token, expiry)
+// Anonymize scrubs the user's personal data and login methods but keeps the row,
+// so their payments and withdrawals stay intact
+func (r *userRepositoryImpl) Anonymize(userID uuid.UUID) error {
+	var updated int
+	err := r.db.QueryRow(`SELECT anonymize_user($1)`, userID).Scan(&updated)
 	if err != nil {
-		log.Printf("Error setting reset token: %v", err)
-		return fmt.Errorf("failed to set reset token: %w", err)
+		log.Printf("Error anonymizing user: %v", err)
+		return fmt.Errorf("failed to anonymize user: %w", err)
+	}
+
+	if updated == 0 {
+		return fmt.Errorf("user %w", model.ErrNotFound)
 	}
+
+	log.Printf("User %s anonymized", userID)
 	return nil
 }
 
-// FindByResetToken finds user by reset token
-func (r *userRepositoryImpl) FindByResetToken(token uuid.UUID) (*model.User, error) {
+// userColumns is the column list every user query selects, in scanUser order
+const userColumns = `id, email, password, first_name, last_name, role::text, wallet_id, email_verified_at, totp_secret, totp_enabled_at, phone_number, phone_verified_at, wallet_phone, deletion_scheduled_at, deleted_at, created_at, updated_at`
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanUser reads one row selected with userColumns
+func scanUser(row rowScanner) (*model.User, error) {
 	var user model.User
+	var email sql.NullString
 	var walletID uuid.NullUUID
-
-	query := `SELECT * FROM get_user_by_reset_token($1)`
-	err := r.db.QueryRow(query, token).Scan(
+	var emailVerifiedAt sql.NullTime
+	var totpSecret sql.NullString
+	var totpEnabledAt sql.NullTime
+	var phoneNumber sql.NullString
+	var phoneVerifiedAt sql.NullTime
+	var walletPhone sql.NullString
+	var deletionScheduledAt sql.NullTime
+	var deletedAt sql.NullTime
+
+	err := row.Scan(
 		&user.ID,
-		&user.Email,
+		&email,
 		&user.Password,
 		&user.FirstName,
 		&user.LastName,
 		&user.Role,
 		&walletID,
+		&emailVerifiedAt,
+		&totpSecret,
+		&totpEnabledAt,
+		&phoneNumber,
+		&phoneVerifiedAt,
+		&walletPhone,
+		&deletionScheduledAt,
+		&deletedAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
-
 	if err != nil {
-		if err == sql.ErrNoRows {
-			log.Printf("Reset token invalid or expired")
-			return nil, fmt.Errorf("reset token invalid or expired")
-		}
-		log.Printf("DB error: %v", err)
-		return nil, fmt.Errorf("failed to find user by reset token: %w", err)
+		return nil, err
 	}
 
+	user.Email = email.String
 	if walletID.Valid {
 		wid := walletID.UUID.String()
 		user.WalletID = &wid
-	} else {
-		user.WalletID = nil
 	}
-
-	return &user, nil
-}
-
-// UpdatePassword updates the user's hashed password
-func (r *userRepositoryImpl) UpdatePassword(userID uuid.UUID, hashedPassword string) error {
-	query := `SELECT update_user_password($1, $2)`
-	_, err := r.db.Exec(query, userID, hashedPassword)
-	if err != nil {
-		log.Printf("Error updating password: %v", err)
-		return fmt.Errorf("failed to update password: %w", err)
+	if emailVerifiedAt.Valid {
+		user.EmailVerifiedAt = &emailVerifiedAt.Time
 	}
-	return nil
-}
-
-// ClearResetToken clears the reset token fields
-func (r *userRepositoryImpl) ClearResetToken(userID uuid.UUID) error {
-	query := `SELECT clear_reset_token($1)`
-	_, err := r.db.Exec(query, userID)
-	if err != nil {
-		log.Printf("Error clearing reset token: %v", err)
-		return fmt.Errorf("failed to clear reset token: %w", err)
+	user.TOTPSecret = totpSecret.String
+	if totpEnabledAt.Valid {
+		user.TOTPEnabledAt = &totpEnabledAt.Time
 	}
-	return nil
+	user.PhoneNumber = phoneNumber.String
+	if phoneVerifiedAt.Valid {
+		user.PhoneVerifiedAt = &phoneVerifiedAt.Time
+	}
+	user.WalletPhone = walletPhone.String
+	if deletionScheduledAt.Valid {
+		user.DeletionScheduledAt = &deletionScheduledAt.Time
+	}
+	if deletedAt.Valid {
+		user.DeletedAt = &deletedAt.Time
+	}
+
+	return &user, nil
 }
 
 // Factory
@@ -15,8 +15,8 @@ type WithdrawalRepositoryImpl struct {
 
 // Create implements repository.WithdrawalRepository.
 func (r *WithdrawalRepositoryImpl) Create(Withdrawal *model.Withdrawal) error {
-	_, err := r.db.Exec(`CALL create_withdrawal($1, $2, $3, $4, $5, $6)`,
-		Withdrawal.ID, Withdrawal.InfluencerID, Withdrawal.Amount, Withdrawal.Status, Withdrawal.RequestedAt, Withdrawal.ProcessedAt)
+	_, err := r.db.Exec(`CALL create_withdrawal($1, $2, $3, $4, $5, $6, $7)`,
+		Withdrawal.ID, Withdrawal.InfluencerID, Withdrawal.Amount.Amount, Withdrawal.Amount.Currency, Withdrawal.Status, Withdrawal.RequestedAt, Withdrawal.ProcessedAt)
 	if err != nil {
 		log.Printf("Error calling create_withdrawal: %v", err)
 		return err
@@ -40,62 +40,72 @@ func (r *WithdrawalRepositoryImpl) Delete(WithdrawalID uuid.UUID) error {
 
 // Get implements repository.WithdrawalRepository.
 func (r *WithdrawalRepositoryImpl) Get(WithdrawalID uuid.UUID) (*model.Withdrawal, error) {
-	var Withdrawal model.Withdrawal
-
-	row := r.db.QueryRow(`SELECT * FROM get_withdrawal_by_id($1)`, WithdrawalID)
-
-	err := row.Scan(
-		&Withdrawal.ID,
-		&Withdrawal.InfluencerID,
-		&Withdrawal.Amount,
-		&Withdrawal.Status,
-		&Withdrawal.RequestedAt,
-		&Withdrawal.ProcessedAt,
-		&Withdrawal.CreatedAt,
-		&Withdrawal.UpdatedAt,
-	)
-
+	Withdrawal, err := scanWithdrawal(r.db.QueryRow(`SELECT `+withdrawalColumns+` FROM withdrawals WHERE id = $1 AND deleted_at IS NULL`, WithdrawalID))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Printf("Withdrawal not found with ID: %v", WithdrawalID)
-			return nil, fmt.Errorf("Withdrawal not found")
+			return nil, fmt.Errorf("Withdrawal %w", model.ErrNotFound)
 		}
 		log.Printf("Error scanning Withdrawal by ID: %v", err)
 		return nil, err
 	}
 
 	log.Printf("Withdrawal retrieved by ID: %+v", Withdrawal)
-	return &Withdrawal, nil
+	return Withdrawal, nil
 }
 
 // List implements repository.WithdrawalRepository.
-func (r *WithdrawalRepositoryImpl) List() ([]*model.Withdrawal, error) {
-	rows, err := r.db.Query(`SELECT * FROM get_all_withdrawals()`)
+func (r *WithdrawalRepositoryImpl) List(filter model.WithdrawalFilter, page model.PageRequest) (*model.Page[*model.Withdrawal], error) {
+	q := &listQuery[*model.Withdrawal]{
+		name:    "withdrawals",
+		columns: withdrawalColumns,
+		from:    "withdrawals",
+		sorts: map[string]sortColumn[*model.Withdrawal]{
+			"created_at": {expr: "created_at", cast: "timestamptz", value: func(w *model.Withdrawal) string { return timeCursor(w.CreatedAt) }},
+			"amount":     {expr: "amount", cast: "bigint", value: func(w *model.Withdrawal) string { return intCursor(w.Amount.Amount) }},
+		},
+		defaultSort: "-created_at",
+		id:          func(w *model.Withdrawal) uuid.UUID { return w.ID },
+		scan:        scanWithdrawal,
+	}
+
+	q.where("deleted_at IS NULL")
+	if filter.InfluencerID != nil {
+		q.where("influencer_id = ?", *filter.InfluencerID)
+	}
+	if filter.Status != "" {
+		q.where("status::text = ?", filter.Status)
+	}
+	if filter.From != nil {
+		q.where("requested_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		q.where("requested_at < ?", *filter.To)
+	}
+
+	return q.run(r.db, page)
+}
+
+// ListByUser implements repository.WithdrawalRepository.
+func (r *WithdrawalRepositoryImpl) ListByUser(influencerID uuid.UUID) ([]*model.Withdrawal, error) {
+	rows, err := r.db.Query(`SELECT `+withdrawalColumns+` FROM withdrawals
+		WHERE influencer_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at DESC`, influencerID)
 	if err != nil {
-		log.Printf("Error querying get_all_withdrawals(): %v", err)
+		log.Printf("Error listing user withdrawals: %v", err)
 		return nil, err
 	}
 	defer rows.Close()
 
-	var Withdrawals []*model.Withdrawal
+	var withdrawals []*model.Withdrawal
 
 	for rows.Next() {
-		var Withdrawal model.Withdrawal
-		err := rows.Scan(
-			&Withdrawal.ID,
-			&Withdrawal.InfluencerID,
-			&Withdrawal.Amount,
-			&Withdrawal.Status,
-			&Withdrawal.RequestedAt,
-			&Withdrawal.ProcessedAt,
-			&Withdrawal.CreatedAt,
-			&Withdrawal.UpdatedAt,
-		)
+		withdrawal, err := scanWithdrawal(rows)
 		if err != nil {
 			log.Printf("Error scanning Withdrawal row: %v", err)
 			return nil, err
 		}
-		Withdrawals = append(Withdrawals, &Withdrawal)
+		withdrawals = append(withdrawals, withdrawal)
 	}
 
 	if err = rows.Err(); err != nil {
@@ -103,14 +113,13 @@ func (r *WithdrawalRepositoryImpl) List() ([]*model.Withdrawal, error) {
 		return nil, err
 	}
 
-	log.Printf("Withdrawal retrieved: %d", len(Withdrawals))
-	return Withdrawals, nil
+	return withdrawals, nil
 }
 
 // Update implements repository.WithdrawalRepository.
 func (r *WithdrawalRepositoryImpl) Update(Withdrawal *model.Withdrawal) error {
-	_, err := r.db.Exec(`CALL update_withdrawal($1, $2, $3, $4)`,
-		Withdrawal.ID,  Withdrawal.Amount, Withdrawal.Status, Withdrawal.ProcessedAt )
+	_, err := r.db.Exec(`CALL update_withdrawal($1, $2, $3, $4, $5)`,
+		Withdrawal.ID,  Withdrawal.Amount.Amount, Withdrawal.Amount.Currency, Withdrawal.Status, Withdrawal.ProcessedAt )
 	if err != nil {
 		log.Printf("Error calling update_withdrawal: %v", err)
 		return err
@@ -124,3 +133,27 @@ func NewWithdrawalRepositoryImpl(db *sql.DB) repository.WithdrawalRepository {
 	return &WithdrawalRepositoryImpl{db: db}
 
 }
+
+// withdrawalColumns is the column list of a withdrawal read straight from the table, in scanWithdrawal order
+const withdrawalColumns = `id, influencer_id, amount, currency::text, status::text, requested_at, processed_at, created_at, updated_at`
+
+func scanWithdrawal(row rowScanner) (*model.Withdrawal, error) {
+	var withdrawal model.Withdrawal
+	var processedAt sql.NullTime
+	err := row.Scan(
+		&withdrawal.ID,
+		&withdrawal.InfluencerID,
+		&withdrawal.Amount.Amount,
+		&withdrawal.Amount.Currency,
+		&withdrawal.Status,
+		&withdrawal.RequestedAt,
+		&processedAt,
+		&withdrawal.CreatedAt,
+		&withdrawal.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	withdrawal.ProcessedAt = processedAt.Time
+	return &withdrawal, nil
+}
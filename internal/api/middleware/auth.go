@@ -1,16 +1,18 @@
 package middleware
 
 import (
+	"kaabe-app/internal/domain/model"
 	"kaabe-app/internal/domain/repository"
+	utils "kaabe-app/pkg/config"
 	"log"
 	"net/http"
 	"strings"
-	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
 )
 
-// AuthMiddleware verifies the token and protects the routes by checking the token in the database.
+// AuthMiddleware verifies the JWT and protects the routes, checking the tokens table for revocation.
 func AuthMiddleware(tokenRepo repository.TokenRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get the token from the Authorization header
@@ -22,48 +24,95 @@ func AuthMiddleware(tokenRepo repository.TokenRepository) gin.HandlerFunc {
 			return
 		}
 
-		// Expecting header format: "Bearer <token>"
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			log.Println("Invalid Authorization format")
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization format must be Bearer <token>"})
-			c.Abort()
+		if !authenticate(c, tokenRepo, authHeader) {
 			return
 		}
 
-		tokenString := parts[1]
+		// Proceed to the next handler
+		c.Next()
+	}
+}
 
-		// Fetch the token from the database
-		token, err := tokenRepo.FindByToken(tokenString)
-		if err != nil {
-			log.Printf("Token lookup failed: %v", err)
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
-			c.Abort()
+// OptionalAuth lets anonymous requests through but authenticates the caller when an
+// Authorization header is sent, so public endpoints can show signed-in users more.
+// A header that is sent but invalid is still rejected, so clients notice expired tokens.
+func OptionalAuth(tokenRepo repository.TokenRepository, permRepo repository.PermissionRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			c.Next()
 			return
 		}
 
-		// ✅ Added check to prevent nil pointer dereference
-		if token == nil {
-			log.Println("Token not found")
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
-			c.Abort()
+		if !authenticate(c, tokenRepo, authHeader) {
 			return
 		}
-
-		// Check if the token has expired
-		if token.ExpiresAt.Before(time.Now()) {
-			log.Printf("Token expired at: %v", token.ExpiresAt)
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token expired"})
-			c.Abort()
+		userID, _ := contextUserID(c)
+		if _, ok := loadRole(c, permRepo, userID); !ok {
 			return
 		}
 
-		// Token is valid — set user ID into request context
-		c.Set("userID", token.UserID)
-
-		// Proceed to the next handler
 		c.Next()
 	}
 }
 
+// authenticate validates a "Bearer <token>" header and stores the user in the context;
+// it aborts the request and returns false if the token is not valid
+func authenticate(c *gin.Context, tokenRepo repository.TokenRepository, authHeader string) bool {
+	// Expecting header format: "Bearer <token>"
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		log.Println("Invalid Authorization format")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization format must be Bearer <token>"})
+		c.Abort()
+		return false
+	}
+
+	tokenString := parts[1]
+
+	// Verify signature and expiry statelessly first
+	claims, err := utils.ValidateToken(tokenString, false)
+	if err != nil {
+		log.Printf("Token validation failed: %v", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+		c.Abort()
+		return false
+	}
 
+	userID, err := uuid.FromString(claims.UserID)
+	if err != nil {
+		log.Printf("Invalid user ID in token claims: %v", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+		c.Abort()
+		return false
+	}
+
+	// Fall back to the tokens table to make sure the token has not been revoked
+	token, err := tokenRepo.FindByToken(tokenString)
+	if err != nil {
+		log.Printf("Token lookup failed: %v", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+		c.Abort()
+		return false
+	}
+
+	if token == nil || token.UserID != userID {
+		log.Println("Token not found or revoked")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token has been revoked"})
+		c.Abort()
+		return false
+	}
+
+	// Only access tokens authenticate requests; refresh tokens are for the refresh endpoint
+	if token.Type != model.TokenTypeAccess {
+		log.Printf("Rejected %s token used as an access token", token.Type)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+		c.Abort()
+		return false
+	}
+
+	// Token is valid — set user ID into request context
+	c.Set("userID", userID)
+	c.Set("accessToken", tokenString)
+	return true
+}
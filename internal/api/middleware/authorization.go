@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"kaabe-app/internal/domain/repository"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+// RequireRole allows the request only if the authenticated user has one of the given roles.
+// It must run after AuthMiddleware.
+func RequireRole(permRepo repository.PermissionRepository, roles ...string) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		allowed[role] = true
+	}
+
+	return func(c *gin.Context) {
+		userID, ok := contextUserID(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization token required"})
+			c.Abort()
+			return
+		}
+
+		role, ok := loadRole(c, permRepo, userID)
+		if !ok {
+			return
+		}
+
+		if !allowed[role] {
+			log.Printf("User %s with role %s denied, requires one of %v", userID, role, roles)
+			c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to perform this action"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequirePermission allows the request only if the authenticated user holds the permission.
+// It must run after AuthMiddleware.
+func RequirePermission(permRepo repository.PermissionRepository, permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := contextUserID(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization token required"})
+			c.Abort()
+			return
+		}
+
+		if _, ok := loadRole(c, permRepo, userID); !ok {
+			return
+		}
+
+		if !checkPermission(c, permRepo, userID, permission) {
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireSelfOrPermission allows the request if the :id route param is the authenticated
+// user, otherwise the user must hold the permission. It must run after AuthMiddleware.
+func RequireSelfOrPermission(permRepo repository.PermissionRepository, permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := contextUserID(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization token required"})
+			c.Abort()
+			return
+		}
+
+		if _, ok := loadRole(c, permRepo, userID); !ok {
+			return
+		}
+
+		if c.Param("id") == userID.String() {
+			c.Next()
+			return
+		}
+
+		if !checkPermission(c, permRepo, userID, permission) {
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// contextUserID reads the user ID set by AuthMiddleware
+func contextUserID(c *gin.Context) (uuid.UUID, bool) {
+	value, exists := c.Get("userID")
+	if !exists {
+		return uuid.Nil, false
+	}
+
+	userID, ok := value.(uuid.UUID)
+	return userID, ok
+}
+
+// loadRole resolves the user's role once per request and stores it as "userRole"
+func loadRole(c *gin.Context, permRepo repository.PermissionRepository, userID uuid.UUID) (string, bool) {
+	if role := c.GetString("userRole"); role != "" {
+		return role, true
+	}
+
+	role, err := permRepo.GetUserRole(userID)
+	if err != nil {
+		log.Printf("Role lookup failed for user %s: %v", userID, err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+		c.Abort()
+		return "", false
+	}
+
+	c.Set("userRole", role)
+	return role, true
+}
+
+// checkPermission aborts with 403 unless the user holds the permission
+func checkPermission(c *gin.Context, permRepo repository.PermissionRepository, userID uuid.UUID, permission string) bool {
+	allowed, err := permRepo.HasPermission(userID, permission)
+	if err != nil {
+		log.Printf("Permission lookup failed for user %s: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to check permissions"})
+		c.Abort()
+		return false
+	}
+
+	if !allowed {
+		log.Printf("User %s denied, missing permission %s", userID, permission)
+		c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to perform this action"})
+		c.Abort()
+		return false
+	}
+
+	return true
+}
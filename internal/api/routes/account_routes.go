@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"kaabe-app/internal/api/controller"
+	"kaabe-app/internal/api/middleware"
+	"kaabe-app/internal/domain/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterAccountRoutes registers the personal data export and account deletion routes
+func RegisterAccountRoutes(router *gin.Engine, accountController *controller.AccountController, tokenRepo repository.TokenRepository) {
+	authMiddleware := middleware.AuthMiddleware(tokenRepo)
+
+	accountGroup := router.Group("/users/me")
+	accountGroup.Use(authMiddleware)
+	{
+		accountGroup.GET("/export", accountController.ExportPersonalData)
+		accountGroup.POST("/deletion", accountController.RequestDeletion)
+		accountGroup.DELETE("/deletion", accountController.CancelDeletion)
+	}
+}
@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"kaabe-app/internal/api/controller"
+	"kaabe-app/internal/api/middleware"
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterBundleRoutes registers course bundles and influencer passes, managed by influencers for
+// their own courses and by admins, and the purchases buyers made of them. Both are bought by paying
+// a quote from POST /quotes.
+func RegisterBundleRoutes(router *gin.Engine, bundleController *controller.BundleController, tokenRepo repository.TokenRepository, permRepo repository.PermissionRepository) {
+	authMiddleware := middleware.AuthMiddleware(tokenRepo)
+	manage := middleware.RequirePermission(permRepo, model.PermCoursesUpdate)
+
+	bundleGroup := router.Group("/bundles")
+	{
+		// Bundles on sale are public
+		bundleGroup.GET("", bundleController.ListBundles)
+		bundleGroup.GET("/:id", bundleController.GetBundle)
+
+		bundleGroup.GET("/mine", authMiddleware, manage, bundleController.ListOwnBundles)
+		bundleGroup.POST("", authMiddleware, manage, bundleController.CreateBundle)
+		bundleGroup.PUT("/:id", authMiddleware, manage, bundleController.UpdateBundle)
+		bundleGroup.DELETE("/:id", authMiddleware, manage, bundleController.DeleteBundle)
+	}
+
+	router.GET("/influencers/:id/pass", bundleController.GetPass)
+	router.PUT("/influencers/:id/pass", authMiddleware, manage, bundleController.SetPass)
+
+	router.GET("/purchases", authMiddleware, bundleController.ListPurchases)
+}
@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"kaabe-app/internal/api/controller"
+	"kaabe-app/internal/api/middleware"
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterCatalogRoutes registers the public course taxonomy and its admin management
+func RegisterCatalogRoutes(router *gin.Engine, catalogController *controller.CatalogController, tokenRepo repository.TokenRepository, permRepo repository.PermissionRepository) {
+	authMiddleware := middleware.AuthMiddleware(tokenRepo)
+	manage := middleware.RequirePermission(permRepo, model.PermCatalogManage)
+
+	categoryGroup := router.Group("/categories")
+	{
+		categoryGroup.GET("", catalogController.ListCategories)
+
+		categoryGroup.POST("", authMiddleware, manage, catalogController.CreateCategory)
+		categoryGroup.PUT("/:id", authMiddleware, manage, catalogController.UpdateCategory)
+		categoryGroup.DELETE("/:id", authMiddleware, manage, catalogController.DeleteCategory)
+	}
+
+	router.GET("/tags", catalogController.ListTags)
+
+	collectionGroup := router.Group("/collections")
+	{
+		collectionGroup.GET("", catalogController.ListCollections)
+		collectionGroup.GET("/:slug", catalogController.GetCollection)
+
+		collectionGroup.POST("", authMiddleware, manage, catalogController.CreateCollection)
+		collectionGroup.PUT("/:id", authMiddleware, manage, catalogController.UpdateCollection)
+		collectionGroup.DELETE("/:id", authMiddleware, manage, catalogController.DeleteCollection)
+		collectionGroup.PUT("/:id/courses", authMiddleware, manage, catalogController.SetCollectionCourses)
+	}
+}
@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"kaabe-app/internal/api/controller"
+	"kaabe-app/internal/api/middleware"
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterCourseRevisionRoutes registers the draft and version history of published courses;
+// all of them are for the course's influencer and admins. Publishing a draft is a review decision,
+// like approving a course, so it is reserved to holders of courses:review.
+func RegisterCourseRevisionRoutes(router *gin.Engine, revisionController *controller.CourseRevisionController, tokenRepo repository.TokenRepository, permRepo repository.PermissionRepository) {
+	courseGroup := router.Group("/courses/:id", middleware.AuthMiddleware(tokenRepo), middleware.RequirePermission(permRepo, model.PermCoursesUpdate))
+	{
+		courseGroup.POST("/draft", revisionController.StartDraft)
+		courseGroup.GET("/draft", revisionController.GetDraft)
+		courseGroup.PUT("/draft", revisionController.SaveDraft)
+		courseGroup.DELETE("/draft", revisionController.DiscardDraft)
+		courseGroup.POST("/draft/publish", middleware.RequirePermission(permRepo, model.PermCoursesReview), revisionController.PublishDraft)
+
+		courseGroup.GET("/revisions", revisionController.ListRevisions)
+		courseGroup.GET("/revisions/:version", revisionController.GetRevision)
+		courseGroup.POST("/revisions/:version/rollback", revisionController.RollbackRevision)
+	}
+}
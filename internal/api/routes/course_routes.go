@@ -3,25 +3,46 @@ package routes
 import (
 	"kaabe-app/internal/api/controller"
 	"kaabe-app/internal/api/middleware"
+	"kaabe-app/internal/domain/model"
 	"kaabe-app/internal/domain/repository"
 
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterCoursesRoutes(routes *gin.Engine, courseController *controller.CourseController, tokenRepo repository.TokenRepository) {
+func RegisterCoursesRoutes(routes *gin.Engine, courseController *controller.CourseController, tokenRepo repository.TokenRepository, permRepo repository.PermissionRepository) {
 	authMiddleware := middleware.AuthMiddleware(tokenRepo)
 
 	courseGroup := routes.Group("/courses")
 	{
+		// Public catalog; signed-in owners and admins also see unpublished courses
+		optionalAuth := middleware.OptionalAuth(tokenRepo, permRepo)
+		courseGroup.GET("/search", optionalAuth, courseController.SearchCourses)
+		courseGroup.GET("/:id", optionalAuth, courseController.GetCourseByID)
+		courseGroup.GET("/:id/prices", optionalAuth, courseController.GetCoursePrices)
+		courseGroup.GET("", optionalAuth, courseController.GetAllCourses)
 
 		// Protected routes (require valid authentication)
 		courseGroup.Use(authMiddleware)
 		{
-			courseGroup.POST("", courseController.CreateCourse)
-			courseGroup.PUT("/:id", courseController.UpdateCourse)
-			courseGroup.DELETE("/:id", courseController.DeleteCourse)
-			courseGroup.GET("/:id", courseController.GetCourseByID)
-			courseGroup.GET("", courseController.GetAllCourses)
+			courseGroup.POST("", middleware.RequirePermission(permRepo, model.PermCoursesCreate), courseController.CreateCourse)
+			courseGroup.PUT("/:id", middleware.RequirePermission(permRepo, model.PermCoursesUpdate), courseController.UpdateCourse)
+			courseGroup.PUT("/:id/taxonomy", middleware.RequirePermission(permRepo, model.PermCoursesUpdate), courseController.SetCourseTaxonomy)
+			courseGroup.DELETE("/:id", middleware.RequirePermission(permRepo, model.PermCoursesDelete), courseController.DeleteCourse)
+
+			// Pricing: prices change directly, even on a published course, and every change is kept
+			courseGroup.PUT("/:id/prices/:currency", middleware.RequirePermission(permRepo, model.PermCoursesUpdate), courseController.SetCoursePrice)
+			courseGroup.DELETE("/:id/prices/:currency", middleware.RequirePermission(permRepo, model.PermCoursesUpdate), courseController.DeleteCoursePrice)
+			courseGroup.GET("/:id/price-history", middleware.RequirePermission(permRepo, model.PermCoursesUpdate), courseController.GetCoursePriceHistory)
+
+			// Publishing workflow: owners move their courses through it, admins review
+			courseGroup.POST("/:id/submit", middleware.RequirePermission(permRepo, model.PermCoursesUpdate), courseController.SubmitCourse)
+			courseGroup.POST("/:id/withdraw", middleware.RequirePermission(permRepo, model.PermCoursesUpdate), courseController.WithdrawCourse)
+			courseGroup.POST("/:id/archive", middleware.RequirePermission(permRepo, model.PermCoursesUpdate), courseController.ArchiveCourse)
+			courseGroup.POST("/:id/restore", middleware.RequirePermission(permRepo, model.PermCoursesUpdate), courseController.RestoreCourse)
+			courseGroup.PUT("/:id/schedule", middleware.RequirePermission(permRepo, model.PermCoursesUpdate), courseController.ScheduleCourse)
+			courseGroup.GET("/review-queue", middleware.RequirePermission(permRepo, model.PermCoursesReview), courseController.GetReviewQueue)
+			courseGroup.POST("/:id/approve", middleware.RequirePermission(permRepo, model.PermCoursesReview), courseController.ApproveCourse)
+			courseGroup.POST("/:id/reject", middleware.RequirePermission(permRepo, model.PermCoursesReview), courseController.RejectCourse)
 		}
 
 	}
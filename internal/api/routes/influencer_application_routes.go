@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"kaabe-app/internal/api/controller"
+	"kaabe-app/internal/api/middleware"
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterInfluencerApplicationRoutes registers influencer onboarding and the admin review queue
+func RegisterInfluencerApplicationRoutes(router *gin.Engine, applicationController *controller.InfluencerApplicationController, tokenRepo repository.TokenRepository, permRepo repository.PermissionRepository) {
+	authMiddleware := middleware.AuthMiddleware(tokenRepo)
+
+	applicationGroup := router.Group("/influencer-applications")
+	applicationGroup.Use(authMiddleware)
+	{
+		// Any signed-in user may apply and follow their own application
+		applicationGroup.POST("", applicationController.Apply)
+		applicationGroup.GET("/me", applicationController.GetOwnApplication)
+
+		// Review queue
+		review := middleware.RequirePermission(permRepo, model.PermInfluencersReview)
+		applicationGroup.GET("", review, applicationController.ListApplications)
+		applicationGroup.GET("/:id", review, applicationController.GetApplication)
+		applicationGroup.POST("/:id/approve", review, applicationController.ApproveApplication)
+		applicationGroup.POST("/:id/reject", review, applicationController.RejectApplication)
+	}
+}
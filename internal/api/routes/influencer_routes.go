@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"kaabe-app/internal/api/controller"
+	"kaabe-app/internal/api/middleware"
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterInfluencerRoutes registers public influencer profiles and following
+func RegisterInfluencerRoutes(router *gin.Engine, influencerController *controller.InfluencerController, tokenRepo repository.TokenRepository, permRepo repository.PermissionRepository) {
+	authMiddleware := middleware.AuthMiddleware(tokenRepo)
+
+	influencerGroup := router.Group("/influencers")
+	{
+		// Profile pages are public
+		influencerGroup.GET("/:id", influencerController.GetProfile)
+
+		influencerGroup.GET("/following", authMiddleware, influencerController.ListFollowing)
+		influencerGroup.PUT("/me", authMiddleware, middleware.RequireRole(permRepo, model.RoleInfluencer), influencerController.UpdateOwnProfile)
+		influencerGroup.POST("/:id/follow", authMiddleware, influencerController.Follow)
+		influencerGroup.DELETE("/:id/follow", authMiddleware, influencerController.Unfollow)
+	}
+}
@@ -3,12 +3,13 @@ package routes
 import (
 	"kaabe-app/internal/api/controller"
 	"kaabe-app/internal/api/middleware"
+	"kaabe-app/internal/domain/model"
 	"kaabe-app/internal/domain/repository"
 
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterLessonRoutes(routes *gin.Engine, lessonController *controller.LessonController, tokenRepo repository.TokenRepository) {
+func RegisterLessonRoutes(routes *gin.Engine, lessonController *controller.LessonController, tokenRepo repository.TokenRepository, permRepo repository.PermissionRepository) {
 	authMiddleWare := middleware.AuthMiddleware(tokenRepo)
 
 	courGroup := routes.Group("/lessons")
@@ -16,11 +17,11 @@ func RegisterLessonRoutes(routes *gin.Engine, lessonController *controller.Lesso
 		// Protected routes (require valid authentication)
 		courGroup.Use(authMiddleWare)
 		{
-			courGroup.POST("", lessonController.CreateLesson)
-			courGroup.PUT("/:id", lessonController.UpdateLesson)
-			courGroup.DELETE("/:id", lessonController.DeleteLesson)
-			courGroup.GET("/:id", lessonController.GetLessonByID)
-			courGroup.GET("", lessonController.GetAllLessons)
+			courGroup.POST("", middleware.RequirePermission(permRepo, model.PermLessonsCreate), lessonController.CreateLesson)
+			courGroup.PUT("/:id", middleware.RequirePermission(permRepo, model.PermLessonsUpdate), lessonController.UpdateLesson)
+			courGroup.DELETE("/:id", middleware.RequirePermission(permRepo, model.PermLessonsDelete), lessonController.DeleteLesson)
+			courGroup.GET("/:id", middleware.RequirePermission(permRepo, model.PermLessonsRead), lessonController.GetLessonByID)
+			courGroup.GET("", middleware.RequirePermission(permRepo, model.PermLessonsRead), lessonController.GetAllLessons)
 		}
 	}
 
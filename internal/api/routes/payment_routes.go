@@ -3,12 +3,13 @@ package routes
 import (
 	"kaabe-app/internal/api/controller"
 	"kaabe-app/internal/api/middleware"
+	"kaabe-app/internal/domain/model"
 	"kaabe-app/internal/domain/repository"
 
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterPaymentRoutes(routes *gin.Engine, PaymentController *controller.PaymentController, tokenRepo repository.TokenRepository) {
+func RegisterPaymentRoutes(routes *gin.Engine, PaymentController *controller.PaymentController, tokenRepo repository.TokenRepository, permRepo repository.PermissionRepository) {
 	authMiddleWare := middleware.AuthMiddleware(tokenRepo)
 
 	paymentGroup := routes.Group("/payments")
@@ -16,11 +17,11 @@ func RegisterPaymentRoutes(routes *gin.Engine, PaymentController *controller.Pay
 		// Protected routes (require valid authentication)
 		paymentGroup.Use(authMiddleWare)
 		{
-			paymentGroup.POST("", PaymentController.CreatePayment)
-			paymentGroup.PUT("/:id", PaymentController.UpdatePayment)
-			paymentGroup.DELETE("/:id", PaymentController.DeletePayment)
-			paymentGroup.GET("/:id", PaymentController.GetPaymentByID)
-			paymentGroup.GET("", PaymentController.GetAllPayments)
+			paymentGroup.POST("", middleware.RequirePermission(permRepo, model.PermPaymentsCreate), PaymentController.CreatePayment)
+			paymentGroup.PUT("/:id", middleware.RequirePermission(permRepo, model.PermPaymentsUpdate), PaymentController.UpdatePayment)
+			paymentGroup.DELETE("/:id", middleware.RequirePermission(permRepo, model.PermPaymentsDelete), PaymentController.DeletePayment)
+			paymentGroup.GET("/:id", middleware.RequirePermission(permRepo, model.PermPaymentsRead), PaymentController.GetPaymentByID)
+			paymentGroup.GET("", middleware.RequirePermission(permRepo, model.PermPaymentsRead), PaymentController.GetAllPayments)
 		}
 
 	}
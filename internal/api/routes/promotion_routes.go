@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"kaabe-app/internal/api/controller"
+	"kaabe-app/internal/api/middleware"
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterPromotionRoutes registers coupon and course sale management, for admins and for
+// influencers on their own courses, and the price quotes buyers pay
+func RegisterPromotionRoutes(router *gin.Engine, promotionController *controller.PromotionController, tokenRepo repository.TokenRepository, permRepo repository.PermissionRepository) {
+	authMiddleware := middleware.AuthMiddleware(tokenRepo)
+	manage := middleware.RequirePermission(permRepo, model.PermPromotionsManage)
+
+	couponGroup := router.Group("/coupons", authMiddleware, manage)
+	{
+		couponGroup.GET("", promotionController.ListCoupons)
+		couponGroup.POST("", promotionController.CreateCoupon)
+		couponGroup.GET("/:id", promotionController.GetCoupon)
+		couponGroup.PUT("/:id", promotionController.UpdateCoupon)
+		couponGroup.DELETE("/:id", promotionController.DeleteCoupon)
+	}
+
+	saleGroup := router.Group("/courses/:id/sales", authMiddleware, manage)
+	{
+		saleGroup.GET("", promotionController.ListSales)
+		saleGroup.POST("", promotionController.CreateSale)
+		saleGroup.DELETE("/:sale_id", promotionController.DeleteSale)
+	}
+
+	router.POST("/quotes", authMiddleware, middleware.RequirePermission(permRepo, model.PermPaymentsCreate), promotionController.CreateQuote)
+}
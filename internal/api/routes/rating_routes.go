@@ -3,12 +3,13 @@ package routes
 import (
 	"kaabe-app/internal/api/controller"
 	"kaabe-app/internal/api/middleware"
+	"kaabe-app/internal/domain/model"
 	"kaabe-app/internal/domain/repository"
 
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterRatingRoutes(routes *gin.Engine, ratingcontroller *controller.RatingController, tokenRepo repository.TokenRepository) {
+func RegisterRatingRoutes(routes *gin.Engine, ratingcontroller *controller.RatingController, tokenRepo repository.TokenRepository, permRepo repository.PermissionRepository) {
 	authMiddleware := middleware.AuthMiddleware(tokenRepo)
 
 	ratingGroup := routes.Group("/rating")
@@ -17,11 +18,11 @@ func RegisterRatingRoutes(routes *gin.Engine, ratingcontroller *controller.Ratin
 		// Protected routes (require valid authentication)
 		ratingGroup.Use(authMiddleware)
 		{
-			ratingGroup.POST("/", ratingcontroller.CreateRating)
-			ratingGroup.PUT("/:id", ratingcontroller.UpdateRating)
-			ratingGroup.DELETE("/:id", ratingcontroller.DeleteRating)
-			ratingGroup.GET("/:id", ratingcontroller.GetRatingByID)
-			ratingGroup.GET("", ratingcontroller.GetAllRating)
+			ratingGroup.POST("/", middleware.RequirePermission(permRepo, model.PermRatingsCreate), ratingcontroller.CreateRating)
+			ratingGroup.PUT("/:id", middleware.RequirePermission(permRepo, model.PermRatingsUpdate), ratingcontroller.UpdateRating)
+			ratingGroup.DELETE("/:id", middleware.RequirePermission(permRepo, model.PermRatingsDelete), ratingcontroller.DeleteRating)
+			ratingGroup.GET("/:id", middleware.RequirePermission(permRepo, model.PermRatingsRead), ratingcontroller.GetRatingByID)
+			ratingGroup.GET("", middleware.RequirePermission(permRepo, model.PermRatingsRead), ratingcontroller.GetAllRating)
 		}
 	}
 }
@@ -3,12 +3,13 @@ package routes
 import (
 	"kaabe-app/internal/api/controller"
 	"kaabe-app/internal/api/middleware"
+	"kaabe-app/internal/domain/model"
 	"kaabe-app/internal/domain/repository"
 
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterSubscriptionRoutes(routes *gin.Engine, SubscriptionController *controller.SubscriptionController, tokenRepo repository.TokenRepository) {
+func RegisterSubscriptionRoutes(routes *gin.Engine, SubscriptionController *controller.SubscriptionController, tokenRepo repository.TokenRepository, permRepo repository.PermissionRepository) {
 	authMiddleWare := middleware.AuthMiddleware(tokenRepo)
 
 	courGroup := routes.Group("/Subscription")
@@ -16,11 +17,11 @@ func RegisterSubscriptionRoutes(routes *gin.Engine, SubscriptionController *cont
 		// Protected routes (require valid authentication)
 		courGroup.Use(authMiddleWare)
 		{
-			courGroup.POST("", SubscriptionController.CreateSubscription)
-			courGroup.PUT("/:id", SubscriptionController.UpdateSubscription)
-			courGroup.DELETE("/:id", SubscriptionController.DeleteSubscription)
-			courGroup.GET("/:id", SubscriptionController.GetSubscriptionByID)
-			courGroup.GET("", SubscriptionController.GetAllSubscription)
+			courGroup.POST("", middleware.RequirePermission(permRepo, model.PermSubscriptionsCreate), SubscriptionController.CreateSubscription)
+			courGroup.PUT("/:id", middleware.RequirePermission(permRepo, model.PermSubscriptionsUpdate), SubscriptionController.UpdateSubscription)
+			courGroup.DELETE("/:id", middleware.RequirePermission(permRepo, model.PermSubscriptionsDelete), SubscriptionController.DeleteSubscription)
+			courGroup.GET("/:id", middleware.RequirePermission(permRepo, model.PermSubscriptionsRead), SubscriptionController.GetSubscriptionByID)
+			courGroup.GET("", middleware.RequirePermission(permRepo, model.PermSubscriptionsRead), SubscriptionController.GetAllSubscription)
 		}
 	}
 
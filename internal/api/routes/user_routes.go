@@ -3,13 +3,14 @@ package routes
 import (
 	"kaabe-app/internal/api/controller"
 	"kaabe-app/internal/api/middleware"
+	"kaabe-app/internal/domain/model"
 	"kaabe-app/internal/domain/repository"
 
 	"github.com/gin-gonic/gin"
 )
 
 // RegisterUserRoutes registers user-related routes
-func RegisterUserRoutes(router *gin.Engine, userController *controller.UserController, tokenRepo repository.TokenRepository) {
+func RegisterUserRoutes(router *gin.Engine, userController *controller.UserController, tokenRepo repository.TokenRepository, permRepo repository.PermissionRepository) {
 	// Auth middleware
 	authMiddleware := middleware.AuthMiddleware(tokenRepo)
 
@@ -19,16 +20,54 @@ func RegisterUserRoutes(router *gin.Engine, userController *controller.UserContr
 		// 🚪 Public Routes
 		userGroup.POST("", userController.RegisterUser)
 		userGroup.POST("/authenticate", userController.AuthenticateUser)
+		userGroup.POST("/authenticate/2fa", userController.CompleteTwoFactorLogin)
+		userGroup.POST("/authenticate/2fa/setup", userController.BeginRequiredEnrollment)
+		userGroup.POST("/authenticate/2fa/activate", userController.CompleteRequiredEnrollment)
+		userGroup.POST("/refresh", userController.RefreshToken)
 		userGroup.POST("/forgot-password", userController.ForgotPassword)
 		userGroup.POST("/reset-password", userController.ResetPassword)
+		userGroup.POST("/verify-email", userController.VerifyEmail)
+		userGroup.POST("/verify-email/resend", userController.ResendVerificationEmail)
+		userGroup.POST("/email-change/confirm", userController.ConfirmEmailChange)
+		userGroup.GET("/oidc/providers", userController.ListIdentityProviders)
+		userGroup.GET("/oidc/:provider/authorize", userController.BeginOIDCLogin)
+		userGroup.POST("/oidc/:provider/callback", userController.CompleteOIDCLogin)
+		userGroup.POST("/phone/otp", userController.RequestPhoneLoginCode)
+		userGroup.POST("/phone/verify", userController.CompletePhoneLogin)
 
 		// 🔒 Protected Routes (Require Auth)
 		userGroup.Use(authMiddleware)
 		{
-			userGroup.GET("", userController.ListUsers)
-			userGroup.GET("/:id", userController.GetUserByID)
-			userGroup.PUT("/:id", userController.UpdateUser)
-			userGroup.DELETE("/:id", userController.DeleteUser)
+			userGroup.POST("/logout", userController.Logout)
+			userGroup.POST("/logout-all", userController.LogoutAll)
+
+			// Self-service profile; role changes go through the admin-only PUT /users/:id
+			userGroup.GET("/me", userController.GetMe)
+			userGroup.PATCH("/me", userController.UpdateMe)
+			userGroup.POST("/me/password", userController.ChangePassword)
+			userGroup.POST("/me/email", userController.RequestEmailChange)
+
+			userGroup.POST("/me/phone", userController.RequestPhoneVerification)
+			userGroup.POST("/me/phone/verify", userController.VerifyPhone)
+			userGroup.POST("/me/phone/wallet", userController.UsePhoneAsWallet)
+
+			// Linked social login accounts
+			userGroup.GET("/me/identities", userController.ListIdentities)
+			userGroup.POST("/me/identities/:provider/authorize", userController.BeginIdentityLink)
+			userGroup.POST("/me/identities/:provider", userController.CompleteIdentityLink)
+			userGroup.DELETE("/me/identities/:provider", userController.UnlinkIdentity)
+
+			userGroup.POST("/2fa/setup", userController.BeginTwoFactorEnrollment)
+			userGroup.POST("/2fa/activate", userController.ActivateTwoFactor)
+			userGroup.POST("/2fa/disable", userController.DisableTwoFactor)
+			userGroup.POST("/2fa/recovery-codes", userController.RegenerateRecoveryCodes)
+
+			userGroup.POST("/:id/revoke-sessions", middleware.RequirePermission(permRepo, model.PermSessionsRevoke), userController.RevokeUserSessions)
+
+			userGroup.GET("", middleware.RequirePermission(permRepo, model.PermUsersRead), userController.ListUsers)
+			userGroup.GET("/:id", middleware.RequireSelfOrPermission(permRepo, model.PermUsersRead), userController.GetUserByID)
+			userGroup.PUT("/:id", middleware.RequirePermission(permRepo, model.PermUsersUpdate), userController.UpdateUser)
+			userGroup.DELETE("/:id", middleware.RequirePermission(permRepo, model.PermUsersDelete), userController.DeleteUser)
 		}
 	}
 }
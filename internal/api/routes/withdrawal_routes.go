@@ -3,6 +3,7 @@ package routes
 import (
 	"kaabe-app/internal/api/controller"
 	"kaabe-app/internal/api/middleware"
+	"kaabe-app/internal/domain/model"
 	"kaabe-app/internal/domain/repository"
 
 	"github.com/gin-gonic/gin"
@@ -10,7 +11,7 @@ import (
 
 
 
-func RegisterWithdrawalRoutes(routes *gin.Engine, WithdrawalController *controller.WithdrawalController, tokenRepo repository.TokenRepository) {
+func RegisterWithdrawalRoutes(routes *gin.Engine, WithdrawalController *controller.WithdrawalController, tokenRepo repository.TokenRepository, permRepo repository.PermissionRepository) {
 	authMiddleWare := middleware.AuthMiddleware(tokenRepo)
 
 	courGroup := routes.Group("/Withdrawal")
@@ -18,11 +19,11 @@ func RegisterWithdrawalRoutes(routes *gin.Engine, WithdrawalController *controll
 		// Protected routes (require valid authentication)
 		courGroup.Use(authMiddleWare)
 		{
-			courGroup.POST("", WithdrawalController.CreateWithdrawal)
-			courGroup.PUT("/:id", WithdrawalController.UpdateWithdrawal)
-			courGroup.DELETE("/:id", WithdrawalController.DeleteWithdrawal)
-			courGroup.GET("/:id", WithdrawalController.GetWithdrawalByID)
-			courGroup.GET("", WithdrawalController.GetAllWithdrawal)
+			courGroup.POST("", middleware.RequirePermission(permRepo, model.PermWithdrawalsCreate), WithdrawalController.CreateWithdrawal)
+			courGroup.PUT("/:id", middleware.RequirePermission(permRepo, model.PermWithdrawalsUpdate), WithdrawalController.UpdateWithdrawal)
+			courGroup.DELETE("/:id", middleware.RequirePermission(permRepo, model.PermWithdrawalsDelete), WithdrawalController.DeleteWithdrawal)
+			courGroup.GET("/:id", middleware.RequirePermission(permRepo, model.PermWithdrawalsRead), WithdrawalController.GetWithdrawalByID)
+			courGroup.GET("", middleware.RequirePermission(permRepo, model.PermWithdrawalsRead), WithdrawalController.GetAllWithdrawal)
 		}
 	}
 
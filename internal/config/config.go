@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 	"gopkg.in/yaml.v3"
@@ -15,18 +17,65 @@ import (
 // App-wide structured config
 type AppConfig struct {
 	App struct {
-		Name string `yaml:"name"`
-		Env  string `yaml:"env"`
-		Port string
+		Name        string `yaml:"name"`
+		Env         string `yaml:"env"`
+		Port        string
+		FrontendURL string `yaml:"frontend_url"` // used to build links in emails
+		// Proxies whose X-Forwarded-For is believed for the client IP; none by default
+		TrustedProxies []string `yaml:"trusted_proxies"`
 	} `yaml:"app"`
 
 	DatabaseURL string
 	JWTSecret   string
 
 	Redis struct {
-		Address string `yaml:"address"`
-		DB      int    `yaml:"db"`
+		Address  string `yaml:"address"`
+		DB       int    `yaml:"db"`
+		Password string
 	} `yaml:"redis"`
+
+	Mail struct {
+		Driver    string `yaml:"driver"` // "smtp" or "outbox"
+		From      string `yaml:"from"`
+		OutboxDir string `yaml:"outbox_dir"`
+		SMTP      struct {
+			Host     string `yaml:"host"`
+			Port     string `yaml:"port"`
+			Username string `yaml:"username"`
+			Password string
+		} `yaml:"smtp"`
+	} `yaml:"mail"`
+
+	SMS struct {
+		Driver             string `yaml:"driver"` // "log" or "outbox"
+		OutboxDir          string `yaml:"outbox_dir"`
+		DefaultCountryCode string `yaml:"default_country_code"` // for numbers typed without one
+	} `yaml:"sms"`
+
+	Auth struct {
+		EmailVerificationTTLHours       int  `yaml:"email_verification_ttl_hours"`
+		RequireVerifiedEmailForLogin    bool `yaml:"require_verified_email_for_login"`
+		RequireVerifiedEmailForPurchase bool `yaml:"require_verified_email_for_purchase"`
+		RequireTwoFactor                bool `yaml:"require_two_factor"`          // for admin and influencer roles
+		AccountDeletionGraceDays        int  `yaml:"account_deletion_grace_days"` // time to cancel before an account is anonymized
+	} `yaml:"auth"`
+
+	Payments struct {
+		WebhookSecret string // env only; signs the payment provider's webhook, which is refused while unset
+	} `yaml:"payments"`
+
+	// OIDC social login providers by name; a provider without a client ID is disabled
+	OIDC map[string]OIDCProviderConfig `yaml:"oidc"`
+}
+
+// OIDCProviderConfig configures one OpenID Connect provider
+type OIDCProviderConfig struct {
+	Issuer       string   `yaml:"issuer"`
+	ClientID     string   `yaml:"client_id"`
+	ClientSecret string   // env only; for Apple this is the signed client secret JWT
+	RedirectURL  string   `yaml:"redirect_url"`
+	Scopes       []string `yaml:"scopes"`
+	ResponseMode string   `yaml:"response_mode"`
 }
 
 // Env + DB + JWT secrets config
@@ -62,6 +111,20 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
+// getEnvBool gets a boolean env var or fallback
+func getEnvBool(key string, fallback bool) bool {
+	val, ok := os.LookupEnv(key)
+	if !ok || val == "" {
+		return fallback
+	}
+	parsed, err := strconv.ParseBool(val)
+	if err != nil {
+		log.Printf("Ignoring invalid boolean %s=%q", key, val)
+		return fallback
+	}
+	return parsed
+}
+
 // LoadAppConfig loads YAML + overrides from env
 func LoadAppConfig() (*AppConfig, error) {
 	file, err := os.ReadFile("config/config.yaml")
@@ -78,6 +141,49 @@ func LoadAppConfig() (*AppConfig, error) {
 	cfg.App.Port = getEnv("PORT", cfg.App.Port)
 	cfg.DatabaseURL = getEnv("DATABASE_URL", cfg.DatabaseURL)
 	cfg.JWTSecret = getEnv("JWT_SECRET", cfg.JWTSecret)
+	cfg.App.FrontendURL = getEnv("FRONTEND_URL", cfg.App.FrontendURL)
+	if proxies := getEnv("TRUSTED_PROXIES", ""); proxies != "" {
+		cfg.App.TrustedProxies = strings.Split(proxies, ",")
+		for i := range cfg.App.TrustedProxies {
+			cfg.App.TrustedProxies[i] = strings.TrimSpace(cfg.App.TrustedProxies[i])
+		}
+	}
+	cfg.Redis.Address = getEnv("REDIS_ADDRESS", cfg.Redis.Address)
+	cfg.Redis.Password = getEnv("REDIS_PASSWORD", cfg.Redis.Password)
+
+	cfg.Mail.Driver = getEnv("MAIL_DRIVER", cfg.Mail.Driver)
+	cfg.Mail.From = getEnv("MAIL_FROM", cfg.Mail.From)
+	cfg.Mail.OutboxDir = getEnv("MAIL_OUTBOX_DIR", cfg.Mail.OutboxDir)
+	cfg.Mail.SMTP.Host = getEnv("SMTP_HOST", cfg.Mail.SMTP.Host)
+	cfg.Mail.SMTP.Port = getEnv("SMTP_PORT", cfg.Mail.SMTP.Port)
+	cfg.Mail.SMTP.Username = getEnv("SMTP_USERNAME", cfg.Mail.SMTP.Username)
+	cfg.Mail.SMTP.Password = getEnv("SMTP_PASSWORD", cfg.Mail.SMTP.Password)
+
+	cfg.SMS.Driver = getEnv("SMS_DRIVER", cfg.SMS.Driver)
+	cfg.SMS.OutboxDir = getEnv("SMS_OUTBOX_DIR", cfg.SMS.OutboxDir)
+	cfg.SMS.DefaultCountryCode = getEnv("SMS_DEFAULT_COUNTRY_CODE", cfg.SMS.DefaultCountryCode)
+
+	cfg.Payments.WebhookSecret = getEnv("WAAFI_WEBHOOK_SECRET", cfg.Payments.WebhookSecret)
+
+	cfg.Auth.RequireVerifiedEmailForLogin = getEnvBool("REQUIRE_VERIFIED_EMAIL_FOR_LOGIN", cfg.Auth.RequireVerifiedEmailForLogin)
+	cfg.Auth.RequireVerifiedEmailForPurchase = getEnvBool("REQUIRE_VERIFIED_EMAIL_FOR_PURCHASE", cfg.Auth.RequireVerifiedEmailForPurchase)
+	cfg.Auth.RequireTwoFactor = getEnvBool("REQUIRE_TWO_FACTOR", cfg.Auth.RequireTwoFactor)
+	// OIDC_<NAME>_ISSUER, OIDC_<NAME>_CLIENT_ID and OIDC_<NAME>_CLIENT_SECRET override each provider
+	for name, provider := range cfg.OIDC {
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		provider.Issuer = getEnv(prefix+"ISSUER", provider.Issuer)
+		provider.ClientID = getEnv(prefix+"CLIENT_ID", provider.ClientID)
+		provider.ClientSecret = getEnv(prefix+"CLIENT_SECRET", provider.ClientSecret)
+		provider.RedirectURL = getEnv(prefix+"REDIRECT_URL", provider.RedirectURL)
+		cfg.OIDC[name] = provider
+	}
+
+	if cfg.Auth.EmailVerificationTTLHours <= 0 {
+		cfg.Auth.EmailVerificationTTLHours = 24
+	}
+	if cfg.Auth.AccountDeletionGraceDays <= 0 {
+		cfg.Auth.AccountDeletionGraceDays = 14
+	}
 
 	return &cfg, nil
 }
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Audit actions written to the audit_logs table
+const (
+	AuditLoginAccountLocked = "login.account_locked"
+	AuditLoginIPLocked      = "login.ip_locked"
+	AuditResetAccountLocked = "password_reset.account_locked"
+	AuditResetIPLocked      = "password_reset.ip_locked"
+	AuditOTPPhoneLocked     = "phone_otp.phone_locked"
+	AuditOTPIPLocked        = "phone_otp.ip_locked"
+	AuditVerifyEmailLocked  = "email_verification.email_locked"
+	AuditVerifyIPLocked     = "email_verification.ip_locked"
+
+	AuditAccountDeletionRequested = "account.deletion_requested"
+	AuditAccountDeletionCancelled = "account.deletion_cancelled"
+	AuditAccountDeleted           = "account.deleted"
+)
+
+type AuditLog struct {
+	ID        uuid.UUID              `json:"id"`
+	UserID    *uuid.UUID             `json:"user_id,omitempty"` // nil when the subject is not a known user
+	Action    string                 `json:"action"`
+	Subject   string                 `json:"subject"` // e.g. the email or IP the event is about
+	IPAddress string                 `json:"ip_address"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// ErrBundlePurchased is returned by the bundle repository for a bundle that cannot be deleted
+// because it has been bought
+var ErrBundlePurchased = errors.New("bundle has been purchased")
+
+// Limits on bundles and passes
+const (
+	MinBundleCourses      = 2
+	MaxBundleCourses      = 50
+	MaxBundleTitleLen     = 255
+	MaxPurchaseAccessDays = 3650
+)
+
+// CourseAccessDays is how long buying a single course without a subscription gives access to it.
+// Keep in sync with grant_purchase.
+const CourseAccessDays = 365
+
+// Bundle is a set of an influencer's courses sold together at one price. Buying it subscribes the
+// buyer to each of the courses for AccessDays.
+type Bundle struct {
+	ID           uuid.UUID   `json:"id"`
+	InfluencerID uuid.UUID   `json:"influencer_id"`
+	Title        string      `json:"title"`
+	Description  string      `json:"description"`
+	Price        Money       `json:"price"`
+	AccessDays   int         `json:"access_days"`
+	Active       bool        `json:"active"` // inactive bundles cannot be bought
+	CourseIDs    []uuid.UUID `json:"course_ids"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+}
+
+// InfluencerPass is an all-access pass to an influencer's courses: buying it subscribes the buyer
+// to every published course of theirs, and to any published later, for AccessDays
+type InfluencerPass struct {
+	InfluencerID uuid.UUID `json:"influencer_id"`
+	Price        Money     `json:"price"`
+	AccessDays   int       `json:"access_days"`
+	Active       bool      `json:"active"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Purchase is a bundle, pass or single course bought by a completed payment. The access it gives is
+// held in the subscriptions it created, which run from StartedAt to ExpiresAt.
+type Purchase struct {
+	ID               uuid.UUID   `json:"id"`
+	UserID           uuid.UUID   `json:"user_id"`
+	PaymentID        uuid.UUID   `json:"payment_id"`
+	BundleID         *uuid.UUID  `json:"bundle_id,omitempty"`
+	PassInfluencerID *uuid.UUID  `json:"pass_influencer_id,omitempty"`
+	CourseID         *uuid.UUID  `json:"course_id,omitempty"` // a single course bought with a course quote
+	CourseIDs        []uuid.UUID `json:"course_ids"`          // courses subscribed to through the purchase
+	StartedAt        time.Time   `json:"started_at"`
+	ExpiresAt        time.Time   `json:"expires_at"`
+	CreatedAt        time.Time   `json:"created_at"`
+}
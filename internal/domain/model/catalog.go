@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Category groups courses by subject. Categories nest: a course listed under a category
+// is also found when browsing any of its ancestors.
+type Category struct {
+	ID        uuid.UUID   `json:"id"`
+	ParentID  *uuid.UUID  `json:"parent_id,omitempty"`
+	Name      string      `json:"name"`
+	Slug      string      `json:"slug"`
+	Position  int         `json:"position"` // order among siblings
+	Children  []*Category `json:"children,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// TagCount is a tag with the number of published courses carrying it
+type TagCount struct {
+	Name        string `json:"name"`
+	CourseCount int    `json:"course_count"`
+}
+
+// Collection is an admin-curated, ordered list of courses such as "Featured"
+type Collection struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
+	Description string    `json:"description"`
+	Position    int       `json:"position"` // order on the catalog page
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Limits on course tags
+const (
+	MaxCourseTags    = 10
+	MaxTagNameLength = 50
+)
@@ -6,14 +6,36 @@ import (
 	"github.com/gofrs/uuid"
 )
 
+// Course statuses; only published courses are visible outside the owner's dashboard.
+// A course moves draft -> in_review -> published -> archived; see CourseService for the rules.
+const (
+	CourseStatusDraft     = "draft"
+	CourseStatusInReview  = "in_review"
+	CourseStatusPublished = "published"
+	CourseStatusArchived  = "archived"
+)
+
 type Course struct {
     ID            uuid.UUID `json:"id,omitempty" gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
     InfluencerID  uuid.UUID `json:"influencer_id,omitempty" gorm:"type:uuid;not null"`
     Title         string    `json:"title" gorm:"type:varchar(255);not null"`
     Description   string    `json:"description" gorm:"type:text"`
-    Price         float64   `json:"price" gorm:"type:float"`
+    Price         Money     `json:"price"`   // base price
+    Prices        []Money   `json:"prices"`  // prices in other currencies
     CoverImageURL []string  `json:"cover_image_url" gorm:"type:text[]"`  // slice of strings, matches Postgres TEXT[]
     Status        string    `json:"status" gorm:"type:varchar(50)"`
+    CategoryID    *uuid.UUID `json:"category_id,omitempty"`
+    Tags          []string  `json:"tags"`
+
+    // Publishing workflow
+    SubmittedAt     *time.Time `json:"submitted_at,omitempty"`
+    ApprovedAt      *time.Time `json:"approved_at,omitempty"` // approved and waiting for PublishAt
+    ReviewedBy      *uuid.UUID `json:"reviewed_by,omitempty"`
+    ReviewedAt      *time.Time `json:"reviewed_at,omitempty"`
+    RejectionReason string     `json:"rejection_reason,omitempty"`
+    PublishAt       *time.Time `json:"publish_at,omitempty"`
+    UnpublishAt     *time.Time `json:"unpublish_at,omitempty"`
+    PublishedAt     *time.Time `json:"published_at,omitempty"`
     CreatedAt     time.Time `json:"created_at" gorm:"autoCreateTime"`
     UpdatedAt     time.Time `json:"updated_at" gorm:"autoUpdateTime"`
     
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// CoursePriceChange is one entry of a course's price history. Amount is nil from the time
+// the course stopped being sold in the currency.
+type CoursePriceChange struct {
+	ID        uuid.UUID `json:"id"`
+	CourseID  uuid.UUID `json:"course_id"`
+	Currency  string    `json:"currency"`
+	Amount    *int64    `json:"amount"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// PriceIn returns the course's price in the currency, base or listed, and whether it is sold in it
+func (c *Course) PriceIn(currency string) (Money, bool) {
+	if c.Price.Currency == currency {
+		return c.Price, true
+	}
+	for _, price := range c.Prices {
+		if price.Currency == currency {
+			return price, true
+		}
+	}
+	return Money{}, false
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// CourseRevision is a snapshot of a course's content and lessons. A course has at most one
+// draft revision (Version nil) where edits to a published course are made; publishing it
+// records it as the next version. Published versions are the course's history. Prices are
+// not part of a revision; they change through the course's price list.
+type CourseRevision struct {
+	ID            uuid.UUID        `json:"id"`
+	CourseID      uuid.UUID        `json:"course_id"`
+	Version       *int             `json:"version"`
+	Title         string           `json:"title"`
+	Description   string           `json:"description"`
+	CoverImageURL []string         `json:"cover_image_url"`
+	Lessons       []RevisionLesson `json:"lessons"`
+	RestoredFrom  *int             `json:"restored_from,omitempty"` // set on versions made by a rollback
+	CreatedBy     *uuid.UUID       `json:"created_by,omitempty"`
+	CreatedAt     time.Time        `json:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at"`
+	PublishedBy   *uuid.UUID       `json:"published_by,omitempty"`
+	PublishedAt   *time.Time       `json:"published_at,omitempty"`
+}
+
+// IsDraft reports whether the revision is the course's unpublished draft
+func (r *CourseRevision) IsDraft() bool {
+	return r.Version == nil
+}
+
+// RevisionLesson is a lesson as it is in a revision. ID is the live lesson it updates,
+// or nil for a lesson the revision adds.
+type RevisionLesson struct {
+	ID       *uuid.UUID `json:"id,omitempty"`
+	Title    string     `json:"title"`
+	VideoURL []string   `json:"video_url"`
+	Order    int        `json:"order"`
+}
+
+// MaxRevisionLessons caps the lessons in one revision
+const MaxRevisionLessons = 200
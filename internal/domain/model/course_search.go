@@ -0,0 +1,61 @@
+package model
+
+import "github.com/gofrs/uuid"
+
+// Price facet buckets of course search
+const (
+	PriceBucketFree    = "free"
+	PriceBucketUnder20 = "under_20"
+	PriceBucket20To50  = "20_to_50"
+	PriceBucket50To100 = "50_to_100"
+	PriceBucketOver100 = "over_100"
+)
+
+// Course search facets
+const (
+	SearchFacetPrice  = "price"
+	SearchFacetRating = "rating"
+)
+
+// MaxSearchQueryLength caps the search text, in characters
+const MaxSearchQueryLength = 200
+
+// Search highlights wrap each matched word in these private-use runes
+const (
+	HighlightStart = "\uE000"
+	HighlightEnd   = "\uE001"
+)
+
+// CourseSearch is a full-text query over course titles, descriptions and lesson titles
+type CourseSearch struct {
+	Query       string
+	PriceBucket string   // one of the PriceBucket constants; empty for any price
+	MinRating   *float64 // minimum average rating
+	// VisibleTo limits matches to published courses and those owned by this user;
+	// uuid.Nil is an anonymous visitor. Nil means no visibility restriction.
+	VisibleTo *uuid.UUID
+}
+
+// CourseSearchHit is one matched course
+type CourseSearchHit struct {
+	Course               *Course
+	Relevance            float64
+	TitleHighlight       string
+	DescriptionHighlight string
+	AverageRating        float64
+	RatingCount          int
+}
+
+// FacetBucket is the number of matches in one facet value
+type FacetBucket struct {
+	Value string
+	Count int
+}
+
+// CourseSearchResult is one page of hits. Fuzzy is set when nothing matched the words
+// themselves and the hits are titles similar to the query instead.
+type CourseSearchResult struct {
+	Hits   *Page[*CourseSearchHit]
+	Fuzzy  bool
+	Facets map[string][]FacetBucket // by SearchFacet constant, in display order
+}
@@ -0,0 +1,9 @@
+package model
+
+// EmailMessage is a rendered email ready to be handed to a Mailer
+type EmailMessage struct {
+	To       string
+	Subject  string
+	TextBody string
+	HTMLBody string
+}
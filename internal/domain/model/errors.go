@@ -0,0 +1,10 @@
+package model
+
+import "errors"
+
+// Errors repositories wrap with what they looked up or wrote, so callers can tell them apart with
+// errors.Is: fmt.Errorf("course %w", ErrNotFound) reads "course not found"
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrAlreadyInUse = errors.New("already in use")
+)
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// ErrIdentityLinked is returned by the identity repository for a provider account already linked to a user
+var ErrIdentityLinked = errors.New("identity already linked")
+
+// UserIdentity links an account at an external OIDC provider to a local user
+type UserIdentity struct {
+	ID          uuid.UUID  `json:"id"`
+	UserID      uuid.UUID  `json:"user_id"`
+	Provider    string     `json:"provider"`
+	Subject     string     `json:"subject"`
+	Email       string     `json:"email,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+}
+
+// ExternalIdentity is what a provider asserts about the user in a verified ID token
+type ExternalIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	FirstName     string
+	LastName      string
+}
+
+// OIDCAuthorization is where to send the user to log in at a provider, and the state the
+// provider must send back
+type OIDCAuthorization struct {
+	URL   string
+	State string
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Influencer application statuses
+const (
+	ApplicationPending  = "pending"
+	ApplicationApproved = "approved"
+	ApplicationRejected = "rejected"
+)
+
+// ErrApplicationOpen is returned by the application repository when the user already has one pending
+var ErrApplicationOpen = errors.New("application already open")
+
+// InfluencerApplication is a user's request to become an influencer, reviewed by an admin
+type InfluencerApplication struct {
+	ID             uuid.UUID  `json:"id"`
+	UserID         uuid.UUID  `json:"user_id"`
+	Bio            string     `json:"bio"`
+	PayoutWallet   string     `json:"payout_wallet,omitempty"` // E.164 mobile money number withdrawals are paid to
+	IDDocumentURLs []string   `json:"id_document_urls"`
+	Status         string     `json:"status"`
+	ReviewNote     string     `json:"review_note,omitempty"`
+	ReviewedBy     *uuid.UUID `json:"reviewed_by,omitempty"`
+	ReviewedAt     *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// InfluencerProfile is the public face of an influencer
+type InfluencerProfile struct {
+	UserID      uuid.UUID         `json:"user_id"`
+	DisplayName string            `json:"display_name"`
+	AvatarURL   string            `json:"avatar_url,omitempty"`
+	Bio         string            `json:"bio"`
+	SocialLinks map[string]string `json:"social_links"` // platform name -> profile URL
+	Stats       *InfluencerStats  `json:"stats,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// InfluencerStats aggregates an influencer's catalog and audience
+type InfluencerStats struct {
+	CourseCount   int     `json:"course_count"`  // published courses
+	StudentCount  int     `json:"student_count"` // distinct users who subscribed to one of their courses
+	RatingCount   int     `json:"rating_count"`
+	AverageRating float64 `json:"average_rating"` // 0 while there are no ratings
+	FollowerCount int     `json:"follower_count"`
+}
@@ -0,0 +1,84 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Filters for the list endpoints; zero values do not filter
+
+// UserFilter narrows GET /users
+type UserFilter struct {
+	Role   string
+	Search string // matched against email, first and last name
+}
+
+// CourseFilter narrows GET /courses
+type CourseFilter struct {
+	Status       string
+	InfluencerID *uuid.UUID
+	Currency     string // courses sold in this currency; MinPrice and MaxPrice apply to their price in it
+	MinPrice     *int64 // minor units
+	MaxPrice     *int64
+	Category     string // category slug; courses in its subcategories are included
+	Tag          string
+	// AwaitingReview limits the list to courses in review that no admin has approved yet
+	AwaitingReview bool
+	// VisibleTo limits the list to published courses and those owned by this user;
+	// uuid.Nil is an anonymous visitor. Nil means no visibility restriction.
+	VisibleTo *uuid.UUID
+}
+
+// LessonFilter narrows GET /lessons
+type LessonFilter struct {
+	CourseID *uuid.UUID
+	// VisibleTo limits the list to lessons of published courses and of courses owned by this user;
+	// uuid.Nil is an anonymous visitor. Nil means no visibility restriction.
+	VisibleTo *uuid.UUID
+}
+
+// RatingFilter narrows GET /ratings
+type RatingFilter struct {
+	CourseID *uuid.UUID
+	UserID   *uuid.UUID
+	MinScore int
+}
+
+// SubscriptionFilter narrows GET /subscriptions
+type SubscriptionFilter struct {
+	UserID   *uuid.UUID
+	CourseID *uuid.UUID
+	Status   string
+}
+
+// WithdrawalFilter narrows GET /withdrawals
+type WithdrawalFilter struct {
+	InfluencerID *uuid.UUID
+	Status       string
+	From         *time.Time // requested at or after
+	To           *time.Time // requested before
+}
+
+// PaymentFilter narrows GET /payments
+type PaymentFilter struct {
+	UserID *uuid.UUID
+	Status string
+	From   *time.Time // created at or after
+	To     *time.Time // created before
+}
+
+// CouponFilter narrows GET /coupons
+type CouponFilter struct {
+	CourseID *uuid.UUID
+	Active   *bool
+	// OwnedBy limits the list to coupons for courses of this influencer
+	OwnedBy *uuid.UUID
+}
+
+// BundleFilter narrows GET /bundles
+type BundleFilter struct {
+	InfluencerID *uuid.UUID
+	CourseID     *uuid.UUID // bundles that include the course
+	Active       *bool
+}
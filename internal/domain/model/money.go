@@ -0,0 +1,116 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Money is an amount in a currency's minor units (cents for USD) with its ISO 4217 code.
+// Amounts are never floating point, so sums and comparisons are exact.
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// DefaultCurrency is the currency of amounts recorded before prices had one
+const DefaultCurrency = "USD"
+
+// Currencies maps the supported currency codes to their number of minor-unit digits;
+// it matches the currencies table
+var Currencies = map[string]int{
+	"USD": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"KES": 2,
+	"ETB": 2,
+	"SOS": 2,
+	"DJF": 0,
+}
+
+// Money errors
+var (
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+	ErrInvalidAmount       = errors.New("invalid amount")
+)
+
+// NormalizeCurrency upper-cases a currency code and checks that it is supported
+func NormalizeCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if _, ok := Currencies[code]; !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedCurrency, code)
+	}
+	return code, nil
+}
+
+// NewMoney checks an amount in minor units and its currency; prices and payments are never negative
+func NewMoney(amount int64, currency string) (Money, error) {
+	currency, err := NormalizeCurrency(currency)
+	if err != nil {
+		return Money{}, err
+	}
+	if amount < 0 {
+		return Money{}, fmt.Errorf("%w: %d", ErrInvalidAmount, amount)
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// ParseMoney reads a decimal amount in major units, such as "19.99", exactly; it fails
+// if the amount has more decimals than the currency has minor units
+func ParseMoney(value, currency string) (Money, error) {
+	currency, err := NormalizeCurrency(currency)
+	if err != nil {
+		return Money{}, err
+	}
+	digits := Currencies[currency]
+
+	value = strings.TrimSpace(value)
+	negative := strings.HasPrefix(value, "-")
+	whole, fraction, _ := strings.Cut(strings.TrimPrefix(value, "-"), ".")
+	if whole == "" || len(fraction) > digits || !isDigits(whole) || !isDigits(fraction) {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+
+	amount, err := strconv.ParseInt(whole+fraction+strings.Repeat("0", digits-len(fraction)), 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+	if negative {
+		amount = -amount
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// Decimal formats the amount in major units, such as "19.99"
+func (m Money) Decimal() string {
+	digits := Currencies[m.Currency]
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+
+	text := strconv.FormatInt(amount, 10)
+	if digits == 0 {
+		return sign + text
+	}
+	if len(text) <= digits {
+		text = strings.Repeat("0", digits-len(text)+1) + text
+	}
+	return sign + text[:len(text)-digits] + "." + text[len(text)-digits:]
+}
+
+// String formats the money for people, such as "19.99 USD"
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,77 @@
+package model
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		currency string
+		want     Money
+		wantErr  error
+	}{
+		{name: "two decimals", value: "19.99", currency: "USD", want: Money{Amount: 1999, Currency: "USD"}},
+		{name: "one decimal is padded", value: "19.9", currency: "usd", want: Money{Amount: 1990, Currency: "USD"}},
+		{name: "whole amount", value: " 20 ", currency: "EUR", want: Money{Amount: 2000, Currency: "EUR"}},
+		{name: "smallest unit", value: "0.01", currency: "USD", want: Money{Amount: 1, Currency: "USD"}},
+		{name: "extra decimals are not rounded", value: "19.999", currency: "USD", wantErr: ErrInvalidAmount},
+		{name: "half a cent is not rounded", value: "0.005", currency: "USD", wantErr: ErrInvalidAmount},
+		{name: "DJF has no minor units", value: "1500", currency: "DJF", want: Money{Amount: 1500, Currency: "DJF"}},
+		{name: "DJF takes no decimals", value: "1500.5", currency: "DJF", wantErr: ErrInvalidAmount},
+		{name: "DJF with a trailing point", value: "1500.", currency: "DJF", want: Money{Amount: 1500, Currency: "DJF"}},
+		{name: "largest amount", value: "92233720368547758.07", currency: "USD", want: Money{Amount: 9223372036854775807, Currency: "USD"}},
+		{name: "overflow", value: "92233720368547758.08", currency: "USD", wantErr: ErrInvalidAmount},
+		{name: "DJF overflow", value: "9223372036854775808", currency: "DJF", wantErr: ErrInvalidAmount},
+		{name: "negative", value: "-1.50", currency: "USD", want: Money{Amount: -150, Currency: "USD"}},
+		{name: "missing whole part", value: ".50", currency: "USD", wantErr: ErrInvalidAmount},
+		{name: "not a number", value: "1e3", currency: "USD", wantErr: ErrInvalidAmount},
+		{name: "unsupported currency", value: "1.00", currency: "XYZ", wantErr: ErrUnsupportedCurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMoney(tt.value, tt.currency)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParseMoney(%q, %q) error = %v, want %v", tt.value, tt.currency, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseMoney(%q, %q) error = %v", tt.value, tt.currency, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseMoney(%q, %q) = %+v, want %+v", tt.value, tt.currency, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyDecimal(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{Money{Amount: 1999, Currency: "USD"}, "19.99"},
+		{Money{Amount: 1990, Currency: "USD"}, "19.90"},
+		{Money{Amount: 5, Currency: "USD"}, "0.05"},
+		{Money{Amount: 0, Currency: "USD"}, "0.00"},
+		{Money{Amount: -150, Currency: "EUR"}, "-1.50"},
+		{Money{Amount: 1500, Currency: "DJF"}, "1500"},
+		{Money{Amount: 9223372036854775807, Currency: "USD"}, "92233720368547758.07"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.Decimal(); got != tt.want {
+			t.Errorf("%+v.Decimal() = %q, want %q", tt.money, got, tt.want)
+		}
+		// Decimal and ParseMoney are inverses
+		parsed, err := ParseMoney(tt.want, tt.money.Currency)
+		if err != nil || parsed != tt.money {
+			t.Errorf("ParseMoney(%q, %q) = %+v, %v; want %+v", tt.want, tt.money.Currency, parsed, err, tt.money)
+		}
+	}
+}
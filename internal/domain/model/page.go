@@ -0,0 +1,33 @@
+package model
+
+import "errors"
+
+// Page sizes for list endpoints
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// List errors returned by repositories for a bad PageRequest
+var (
+	ErrInvalidCursor = errors.New("invalid or expired cursor")
+	ErrInvalidSort   = errors.New("unsupported sort field")
+)
+
+// PageRequest selects one page of a keyset-paginated list
+type PageRequest struct {
+	Limit  int    // rows per page; DefaultPageLimit when zero, capped at MaxPageLimit
+	Cursor string // NextCursor of the previous page; empty for the first page
+	Sort   string // a sortable field, prefixed with "-" for descending order; empty for the list's default
+}
+
+// Page is one page of a list. NextCursor continues after the last item and is empty on the last page.
+type Page[T any] struct {
+	Items      []T
+	NextCursor string
+}
+
+// HasMore reports whether another page follows
+func (p *Page[T]) HasMore() bool {
+	return p.NextCursor != ""
+}
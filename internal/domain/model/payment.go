@@ -7,13 +7,14 @@ import (
 )
 
 type Payment struct {
-	ID             uuid.UUID `json:"id"`
-	ExternalRef    string    `json:"external_ref"`
-	UserID         uuid.UUID `json:"user_id"`
-	SubscriptionID uuid.UUID `json:"subscription_id"`
-	Amount         float64   `json:"amount"`
-	Status         string    `json:"status"`       // e.g., "pending", "completed", "failed"
-	ProcessedAt    time.Time `json:"processed_at"` // time the payment was processed
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	ID             uuid.UUID  `json:"id"`
+	ExternalRef    string     `json:"external_ref"`
+	UserID         uuid.UUID  `json:"user_id"`
+	SubscriptionID uuid.UUID  `json:"subscription_id"` // uuid.Nil for quote payments that make a purchase
+	Amount         Money      `json:"amount"`
+	QuoteID        *uuid.UUID `json:"quote_id,omitempty"` // the price quote paid, if any
+	Status         string     `json:"status"`             // e.g., "pending", "completed", "failed"
+	ProcessedAt    time.Time  `json:"processed_at"`       // time the payment was processed
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
 }
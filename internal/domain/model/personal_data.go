@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// PersonalDataExport is everything stored about one user, for GET /users/me/export
+type PersonalDataExport struct {
+	ExportedAt             time.Time                `json:"exported_at"`
+	User                   *User                    `json:"user"`
+	Identities             []*UserIdentity          `json:"identities"`
+	Subscriptions          []*Subscription          `json:"subscriptions"`
+	Ratings                []*Rating                `json:"ratings"`
+	Payments               []*Payment               `json:"payments"`
+	Withdrawals            []*Withdrawal            `json:"withdrawals"` // only influencers have any
+	InfluencerApplications []*InfluencerApplication `json:"influencer_applications"`
+	InfluencerProfile      *InfluencerProfile       `json:"influencer_profile,omitempty"`
+	Following              []*InfluencerProfile     `json:"following"`
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Purposes of one-time codes sent by SMS
+const (
+	OTPPurposeLogin  = "login"  // log in or register with a phone number
+	OTPPurposeVerify = "verify" // add a phone number to a logged in account
+)
+
+// PhoneOTP is a one-time code sent to a phone number
+type PhoneOTP struct {
+	ID          uuid.UUID  `json:"id"`
+	PhoneNumber string     `json:"phone_number"`
+	Purpose     string     `json:"purpose"`
+	UserID      *uuid.UUID `json:"user_id,omitempty"` // the account verifying the number, for OTPPurposeVerify
+	CodeHash    string     `json:"-"`
+	Attempts    int        `json:"attempts"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	ConsumedAt  *time.Time `json:"consumed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
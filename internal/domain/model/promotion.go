@@ -0,0 +1,107 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Coupon discount types
+const (
+	DiscountPercentage = "percentage"
+	DiscountFixed      = "fixed"
+)
+
+// PriceQuoteTTL is how long a quoted price can be paid
+const PriceQuoteTTL = 30 * time.Minute
+
+// ErrQuoteNotRedeemable is returned by the payment repository when the quote a payment pays has
+// already been paid or its coupon has no redemptions left
+var ErrQuoteNotRedeemable = errors.New("price quote cannot be redeemed")
+
+// Errors returned by the promotion repository
+var (
+	ErrCouponRedeemed = errors.New("coupon has been redeemed")
+	ErrSaleOverlaps   = errors.New("sale overlaps another sale")
+)
+
+// Coupon is a code that takes a discount off a course price. A fixed discount only applies to
+// prices in its own currency.
+type Coupon struct {
+	ID             uuid.UUID  `json:"id"`
+	Code           string     `json:"code"` // upper-case
+	DiscountType   string     `json:"discount_type"`
+	PercentOff     int        `json:"percent_off,omitempty"` // percentage coupons, 1-100
+	AmountOff      *Money     `json:"amount_off,omitempty"`  // fixed coupons
+	CourseID       *uuid.UUID `json:"course_id,omitempty"`   // nil for a site-wide coupon
+	MaxRedemptions *int       `json:"max_redemptions,omitempty"`
+	MaxPerUser     *int       `json:"max_per_user,omitempty"`
+	StartsAt       *time.Time `json:"starts_at,omitempty"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	Active         bool       `json:"active"`
+	Redemptions    int        `json:"redemptions"` // paid quotes that used it
+	CreatedBy      *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// Discount returns what the coupon takes off price, never more than the price itself; ok is false
+// if the coupon does not apply to prices in that currency
+func (c *Coupon) Discount(price Money) (discount Money, ok bool) {
+	discount = Money{Currency: price.Currency}
+	switch c.DiscountType {
+	case DiscountPercentage:
+		// Rounded to the nearest minor unit
+		discount.Amount = (price.Amount*int64(c.PercentOff) + 50) / 100
+	case DiscountFixed:
+		if c.AmountOff == nil || c.AmountOff.Currency != price.Currency {
+			return Money{}, false
+		}
+		discount.Amount = c.AmountOff.Amount
+	default:
+		return Money{}, false
+	}
+
+	if discount.Amount > price.Amount {
+		discount.Amount = price.Amount
+	}
+	return discount, true
+}
+
+// CourseSale is a reduced price for a course in one currency for a limited time
+type CourseSale struct {
+	ID        uuid.UUID  `json:"id"`
+	CourseID  uuid.UUID  `json:"course_id"`
+	Price     Money      `json:"price"`
+	StartsAt  time.Time  `json:"starts_at"`
+	EndsAt    time.Time  `json:"ends_at"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// PriceQuote is the price a user will be charged for a course, bundle or influencer pass (exactly
+// one of CourseID, BundleID and PassInfluencerID is set), worked out before paying.
+// Total is the list price, or the sale price during a course sale, less the coupon discount.
+type PriceQuote struct {
+	ID               uuid.UUID  `json:"id"`
+	UserID           uuid.UUID  `json:"user_id"`
+	CourseID         *uuid.UUID `json:"course_id,omitempty"`
+	BundleID         *uuid.UUID `json:"bundle_id,omitempty"`
+	PassInfluencerID *uuid.UUID `json:"pass_influencer_id,omitempty"`
+	ListPrice        Money      `json:"list_price"`
+	SaleID           *uuid.UUID `json:"sale_id,omitempty"`
+	SalePrice        *Money     `json:"sale_price,omitempty"`
+	CouponID         *uuid.UUID `json:"coupon_id,omitempty"`
+	CouponCode       string     `json:"coupon_code,omitempty"`
+	Discount         Money      `json:"discount"`
+	Total            Money      `json:"total"`
+	ExpiresAt        time.Time  `json:"expires_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// IsPurchase reports whether the quote is for a bundle or pass, whose payment always makes a
+// Purchase. A course quote makes one only when it is paid without a subscription.
+func (q *PriceQuote) IsPurchase() bool {
+	return q.BundleID != nil || q.PassInfluencerID != nil
+}
@@ -0,0 +1,93 @@
+package model
+
+import "testing"
+
+func TestCouponDiscount(t *testing.T) {
+	usd := func(amount int64) Money { return Money{Amount: amount, Currency: "USD"} }
+	tenDollarsOff := usd(1000)
+
+	tests := []struct {
+		name   string
+		coupon Coupon
+		price  Money
+		want   Money
+		wantOK bool
+	}{
+		{
+			name:   "percentage",
+			coupon: Coupon{DiscountType: DiscountPercentage, PercentOff: 25},
+			price:  usd(2000),
+			want:   usd(500),
+			wantOK: true,
+		},
+		{
+			name:   "percentage rounds half up",
+			coupon: Coupon{DiscountType: DiscountPercentage, PercentOff: 50},
+			price:  usd(1999),
+			want:   usd(1000), // 999.5 cents
+			wantOK: true,
+		},
+		{
+			name:   "percentage rounds down below half",
+			coupon: Coupon{DiscountType: DiscountPercentage, PercentOff: 15},
+			price:  usd(1999),
+			want:   usd(300), // 299.85 cents
+			wantOK: true,
+		},
+		{
+			name:   "percentage in a currency without minor units",
+			coupon: Coupon{DiscountType: DiscountPercentage, PercentOff: 33},
+			price:  Money{Amount: 1000, Currency: "DJF"},
+			want:   Money{Amount: 330, Currency: "DJF"},
+			wantOK: true,
+		},
+		{
+			name:   "hundred percent is the whole price",
+			coupon: Coupon{DiscountType: DiscountPercentage, PercentOff: 100},
+			price:  usd(1999),
+			want:   usd(1999),
+			wantOK: true,
+		},
+		{
+			name:   "fixed",
+			coupon: Coupon{DiscountType: DiscountFixed, AmountOff: &tenDollarsOff},
+			price:  usd(2500),
+			want:   usd(1000),
+			wantOK: true,
+		},
+		{
+			name:   "fixed is capped at the price",
+			coupon: Coupon{DiscountType: DiscountFixed, AmountOff: &tenDollarsOff},
+			price:  usd(799),
+			want:   usd(799),
+			wantOK: true,
+		},
+		{
+			name:   "fixed in another currency does not apply",
+			coupon: Coupon{DiscountType: DiscountFixed, AmountOff: &tenDollarsOff},
+			price:  Money{Amount: 2500, Currency: "EUR"},
+		},
+		{
+			name:   "fixed without an amount does not apply",
+			coupon: Coupon{DiscountType: DiscountFixed},
+			price:  usd(2500),
+		},
+		{
+			name:   "unknown discount type",
+			coupon: Coupon{DiscountType: "bogus", PercentOff: 10},
+			price:  usd(2500),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.coupon.Discount(tt.price)
+			if ok != tt.wantOK {
+				t.Fatalf("Discount(%s) ok = %v, want %v", tt.price, ok, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Errorf("Discount(%s) = %s, want %s", tt.price, got, tt.want)
+			}
+		})
+	}
+}
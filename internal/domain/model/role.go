@@ -0,0 +1,53 @@
+package model
+
+// Roles, matching the user_role enum
+const (
+	RoleAdmin      = "admin"
+	RoleInfluencer = "influencer"
+	RoleUser       = "user"
+)
+
+// Permissions seeded in the permissions table
+const (
+	PermUsersRead      = "users:read"
+	PermUsersUpdate    = "users:update"
+	PermUsersDelete    = "users:delete"
+	PermSessionsRevoke = "sessions:revoke"
+
+	PermInfluencersReview = "influencers:review"
+
+	PermCatalogManage = "catalog:manage"
+
+	PermCoursesRead   = "courses:read"
+	PermCoursesCreate = "courses:create"
+	PermCoursesUpdate = "courses:update"
+	PermCoursesDelete = "courses:delete"
+	PermCoursesReview = "courses:review"
+
+	PermLessonsRead   = "lessons:read"
+	PermLessonsCreate = "lessons:create"
+	PermLessonsUpdate = "lessons:update"
+	PermLessonsDelete = "lessons:delete"
+
+	PermRatingsRead   = "ratings:read"
+	PermRatingsCreate = "ratings:create"
+	PermRatingsUpdate = "ratings:update"
+	PermRatingsDelete = "ratings:delete"
+
+	PermSubscriptionsRead   = "subscriptions:read"
+	PermSubscriptionsCreate = "subscriptions:create"
+	PermSubscriptionsUpdate = "subscriptions:update"
+	PermSubscriptionsDelete = "subscriptions:delete"
+
+	PermWithdrawalsRead   = "withdrawals:read"
+	PermWithdrawalsCreate = "withdrawals:create"
+	PermWithdrawalsUpdate = "withdrawals:update"
+	PermWithdrawalsDelete = "withdrawals:delete"
+
+	PermPaymentsRead   = "payments:read"
+	PermPaymentsCreate = "payments:create"
+	PermPaymentsUpdate = "payments:update"
+	PermPaymentsDelete = "payments:delete"
+
+	PermPromotionsManage = "promotions:manage"
+)
@@ -0,0 +1,7 @@
+package model
+
+// SMSMessage is a text message ready to be handed to an SMSSender
+type SMSMessage struct {
+	To   string // E.164 phone number
+	Body string
+}
@@ -6,12 +6,30 @@ import (
 	"github.com/gofrs/uuid"
 )
 
+// Token types stored in the tokens table
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
 type Token struct {
 	ID        uuid.UUID  `json:"id"`
 	UserID    uuid.UUID  `json:"user_id"`
 	Token     string     `json:"token"`
+	Type      string     `json:"token_type"`
+	FamilyID  *uuid.UUID `json:"family_id,omitempty"` // shared by every token issued from one login
 	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"` // set once a refresh token has been rotated
 	CreatedAt time.Time  `json:"created_at"`
 	UpdatedAt time.Time  `json:"updated_at"`
 	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
+
+// AuthTokens is the signed access/refresh token pair returned on login
+type AuthTokens struct {
+	AccessToken      string    `json:"access_token"`
+	RefreshToken     string    `json:"refresh_token"`
+	TokenType        string    `json:"token_type"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
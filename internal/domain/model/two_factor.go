@@ -0,0 +1,19 @@
+package model
+
+// LoginResult is the outcome of the password step of a login. Tokens is nil when a
+// second step is needed: MFARequired asks for a TOTP or recovery code, and
+// MFAEnrollmentRequired means the user's role requires 2FA and it must be set up first.
+// Either way MFAToken identifies the pending login.
+type LoginResult struct {
+	User                  *User       `json:"user"`
+	Tokens                *AuthTokens `json:"tokens,omitempty"`
+	MFARequired           bool        `json:"mfa_required"`
+	MFAEnrollmentRequired bool        `json:"mfa_enrollment_required"`
+	MFAToken              string      `json:"mfa_token,omitempty"`
+}
+
+// TOTPEnrollment is returned when a user starts setting up an authenticator app
+type TOTPEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"` // encode as a QR code for the authenticator app
+}
@@ -7,15 +7,28 @@ import (
 )
 
 type User struct {
-	ID               uuid.UUID  `json:"id"`
-	Email            string     `json:"email" binding:"required,email"`
-	Password         string     `json:"password" binding:"required,min=6"`
-	FirstName        string     `json:"first_name"`
-	LastName         string     `json:"last_name"`
-	Role             string     `json:"role"`
-	WalletID         *string    `json:"wallet_id,omitempty"` // omit if nil
-	ResetToken       *uuid.UUID `json:"reset_token,omitempty"`        // omit if not used
-	ResetTokenExpiry *time.Time `json:"reset_token_expiry,omitempty"` // omit if not used
-	CreatedAt        time.Time  `json:"created_at"`
-	UpdatedAt        time.Time  `json:"updated_at"`
+	ID                  uuid.UUID  `json:"id"`
+	Email               string     `json:"email,omitempty"` // empty for accounts created by phone
+	Password            string     `json:"-"`               // bcrypt hash, never serialized
+	FirstName           string     `json:"first_name"`
+	LastName            string     `json:"last_name"`
+	Role                string     `json:"role"`
+	WalletID            *string    `json:"wallet_id,omitempty"` // omit if nil
+	ResetToken          *uuid.UUID `json:"-"`
+	ResetTokenExpiry    *time.Time `json:"-"`
+	EmailVerifiedAt     *time.Time `json:"email_verified_at,omitempty"` // nil until the email is verified
+	TOTPSecret          string     `json:"-"`                           // never serialized
+	TOTPEnabledAt       *time.Time `json:"totp_enabled_at,omitempty"`   // nil unless 2FA is active
+	PhoneNumber         string     `json:"phone_number,omitempty"`      // E.164, only set once verified
+	PhoneVerifiedAt     *time.Time `json:"phone_verified_at,omitempty"`
+	WalletPhone         string     `json:"wallet_phone,omitempty"`          // verified number used as the default payment wallet
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty"` // set while a deletion request is pending
+	DeletedAt           *time.Time `json:"deleted_at,omitempty"`            // set once the account is anonymized
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// HasVerifiedContact reports whether the user has proven they own their email or phone number
+func (u *User) HasVerifiedContact() bool {
+	return u.EmailVerifiedAt != nil || u.PhoneVerifiedAt != nil
 }
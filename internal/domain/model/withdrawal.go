@@ -9,7 +9,7 @@ import (
 type Withdrawal struct {
 	ID           uuid.UUID `json:"id"`
 	InfluencerID uuid.UUID `json:"influencer_id"`
-	Amount       float64   `json:"amount"`
+	Amount       Money     `json:"amount"`
 	Status       string    `json:"status"` // e.g., "pending", "approved", "rejected"
 	RequestedAt  time.Time `json:"requested_at"`
 	ProcessedAt  time.Time  `json:"processed_at,omitempty"` // Nullable, if not yet processed
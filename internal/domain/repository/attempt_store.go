@@ -0,0 +1,20 @@
+package repository
+
+import "time"
+
+// AttemptStore counts failed attempts per key and holds temporary locks.
+// Keys are opaque, e.g. "login:account:alice@example.com" or "login:ip:10.0.0.1".
+type AttemptStore interface {
+	// RegisterFailure increments the counter for key, starting a new window if none is open,
+	// and returns the count within the current window
+	RegisterFailure(key string, window time.Duration) (int, error)
+
+	// Reset clears the counter for key
+	Reset(key string) error
+
+	// Lock blocks key for the given duration
+	Lock(key string, duration time.Duration) error
+
+	// LockedUntil returns when the lock on key expires, or the zero time if it is not locked
+	LockedUntil(key string) (time.Time, error)
+}
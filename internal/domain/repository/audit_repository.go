@@ -0,0 +1,8 @@
+package repository
+
+import "kaabe-app/internal/domain/model"
+
+// AuditRepository records security-relevant events
+type AuditRepository interface {
+	Create(entry *model.AuditLog) error
+}
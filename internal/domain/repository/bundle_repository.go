@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"kaabe-app/internal/domain/model"
+
+	"github.com/gofrs/uuid"
+)
+
+// BundleRepository stores course bundles, influencer passes and the purchases made of them
+type BundleRepository interface {
+	CreateBundle(bundle *model.Bundle) error
+	// UpdateBundle returns an error wrapping model.ErrNotFound if the bundle does not exist
+	UpdateBundle(bundle *model.Bundle) error
+	// DeleteBundle returns false if the bundle does not exist, and model.ErrBundlePurchased if
+	// anyone has paid for it
+	DeleteBundle(bundleID uuid.UUID) (bool, error)
+	GetBundle(bundleID uuid.UUID) (*model.Bundle, error)
+	ListBundles(filter model.BundleFilter, page model.PageRequest) (*model.Page[*model.Bundle], error)
+
+	SetPass(pass *model.InfluencerPass) error
+	// GetPass returns an error wrapping model.ErrNotFound if the influencer has no pass
+	GetPass(influencerID uuid.UUID) (*model.InfluencerPass, error)
+
+	ListPurchases(userID uuid.UUID, page model.PageRequest) (*model.Page[*model.Purchase], error)
+	// GrantPassCourse subscribes holders of an unexpired pass from the course's influencer to the
+	// course, returning how many were subscribed
+	GrantPassCourse(courseID uuid.UUID) (int, error)
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"kaabe-app/internal/domain/model"
+
+	"github.com/gofrs/uuid"
+)
+
+// CatalogRepository stores the course taxonomy: categories, tags and curated collections
+type CatalogRepository interface {
+	CreateCategory(category *model.Category) error
+	UpdateCategory(category *model.Category) error
+	// DeleteCategory returns false if the category does not exist
+	DeleteCategory(categoryID uuid.UUID) (bool, error)
+	GetCategory(categoryID uuid.UUID) (*model.Category, error)
+	// ListCategories returns every category as a flat list, siblings in display order
+	ListCategories() ([]*model.Category, error)
+
+	ListPopularTags(limit int) ([]*model.TagCount, error)
+
+	CreateCollection(collection *model.Collection) error
+	UpdateCollection(collection *model.Collection) error
+	// DeleteCollection returns false if the collection does not exist
+	DeleteCollection(collectionID uuid.UUID) (bool, error)
+	GetCollection(collectionID uuid.UUID) (*model.Collection, error)
+	GetCollectionBySlug(slug string) (*model.Collection, error)
+	ListCollections() ([]*model.Collection, error)
+	// SetCollectionCourses replaces the collection's courses, in the given order
+	SetCollectionCourses(collectionID uuid.UUID, courseIDs []uuid.UUID) error
+	// ListCollectionCourses returns the collection's published courses in display order
+	ListCollectionCourses(collectionID uuid.UUID) ([]*model.Course, error)
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"kaabe-app/internal/domain/model"
+	"time"
 
 	"github.com/gofrs/uuid"
 )
@@ -12,5 +13,31 @@ type CourseRepository interface {
 	Update(course *model.Course) error
 	Delete(courseID uuid.UUID) error
 	GetByID(courseID uuid.UUID) (*model.Course, error)
-	GetAll() ([]*model.Course, error)
+	List(filter model.CourseFilter, page model.PageRequest) (*model.Page[*model.Course], error)
+	// ListPublishedByInfluencer returns the influencer's public catalog
+	ListPublishedByInfluencer(influencerID uuid.UUID) ([]*model.Course, error)
+	// SetTaxonomy puts the course in a category (nil for none) and replaces its tags;
+	// it returns false if the course does not exist
+	SetTaxonomy(courseID uuid.UUID, categoryID *uuid.UUID, tags []string) (bool, error)
+	CountLessons(courseID uuid.UUID) (int, error)
+	// Transition moves the course from one status to another; it returns false if the course
+	// is not in the from status (any more)
+	Transition(courseID uuid.UUID, from, to string) (bool, error)
+	// Review records an admin decision on a course awaiting review and returns its new status,
+	// or "" if it is not awaiting review
+	Review(courseID, reviewerID uuid.UUID, approve bool, reason string) (string, error)
+	// Schedule sets the release and withdrawal times; nil clears one
+	Schedule(courseID uuid.UUID, publishAt, unpublishAt *time.Time) (bool, error)
+	// ListDueReleases returns approved courses due to be published and published courses due to be archived
+	ListDueReleases(limit int) ([]*model.Course, error)
+	// SetPrice sets the course's price in the price's currency: its base price if that is the
+	// base currency, otherwise an entry of its price list. It returns false if the course does not exist.
+	SetPrice(courseID uuid.UUID, price model.Money) (bool, error)
+	// DeletePrice removes a price list entry; it returns false if there was none in that currency
+	DeletePrice(courseID uuid.UUID, currency string) (bool, error)
+	// ListPriceHistory returns one page of the course's price changes, newest first; an empty
+	// currency lists all of them
+	ListPriceHistory(courseID uuid.UUID, currency string, page model.PageRequest) (*model.Page[*model.CoursePriceChange], error)
+	// Search returns one page of full-text matches, most relevant first, with facet counts
+	Search(search model.CourseSearch, page model.PageRequest) (*model.CourseSearchResult, error)
 }
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"kaabe-app/internal/domain/model"
+
+	"github.com/gofrs/uuid"
+)
+
+// CourseRevisionRepository stores the draft and published versions of courses
+type CourseRevisionRepository interface {
+	// CreateDraft returns the course's draft, copying it from the live course if there is none
+	CreateDraft(courseID, userID uuid.UUID) (*model.CourseRevision, error)
+	GetDraft(courseID uuid.UUID) (*model.CourseRevision, error)
+	// SaveDraft replaces the content of the course's draft; it returns false if there is no draft
+	SaveDraft(draft *model.CourseRevision) (bool, error)
+	// DeleteDraft returns false if there is no draft
+	DeleteDraft(courseID uuid.UUID) (bool, error)
+	// PublishDraft applies the draft to the live course and lessons in one transaction and returns
+	// the new version, or 0 if the course is not published or has no draft
+	PublishDraft(courseID, userID uuid.UUID) (int, error)
+	// Rollback puts the given version back live as a new version, which it returns; 0 if the course
+	// is not published or the version does not exist
+	Rollback(courseID uuid.UUID, version int, userID uuid.UUID) (int, error)
+	GetVersion(courseID uuid.UUID, version int) (*model.CourseRevision, error)
+	// ListVersions returns one page of the course's published versions
+	ListVersions(courseID uuid.UUID, page model.PageRequest) (*model.Page[*model.CourseRevision], error)
+}
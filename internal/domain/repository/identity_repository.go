@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"kaabe-app/internal/domain/model"
+
+	"github.com/gofrs/uuid"
+)
+
+// IdentityRepository stores links between local users and external OIDC accounts
+type IdentityRepository interface {
+	Create(identity *model.UserIdentity) error
+	// FindBySubject returns nil when the external account is not linked to anyone
+	FindBySubject(provider, subject string) (*model.UserIdentity, error)
+	ListByUser(userID uuid.UUID) ([]*model.UserIdentity, error)
+	TouchLogin(identityID uuid.UUID) error
+	// Delete reports false if the user had no identity at that provider
+	Delete(userID uuid.UUID, provider string) (bool, error)
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"kaabe-app/internal/domain/model"
+
+	"github.com/gofrs/uuid"
+)
+
+// InfluencerApplicationRepository stores influencer applications and their review
+type InfluencerApplicationRepository interface {
+	Create(application *model.InfluencerApplication) error
+	Get(applicationID uuid.UUID) (*model.InfluencerApplication, error)
+	// FindLatestByUser returns nil when the user never applied
+	FindLatestByUser(userID uuid.UUID) (*model.InfluencerApplication, error)
+	ListByUser(userID uuid.UUID) ([]*model.InfluencerApplication, error)
+	// List returns the applications with the given status, or all of them for an empty status
+	List(status string) ([]*model.InfluencerApplication, error)
+	// Review decides a pending application and grants the influencer role on approval;
+	// it returns nil if the application is not pending
+	Review(applicationID uuid.UUID, approve bool, reviewerID uuid.UUID, note string) (*model.InfluencerApplication, error)
+	IsApprovedCreator(userID uuid.UUID) (bool, error)
+}
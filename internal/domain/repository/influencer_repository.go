@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"kaabe-app/internal/domain/model"
+
+	"github.com/gofrs/uuid"
+)
+
+// InfluencerRepository stores influencer profiles and the users following them
+type InfluencerRepository interface {
+	UpsertProfile(profile *model.InfluencerProfile) error
+	// GetProfile returns the profile of an active influencer, built from their name if they never filled it in
+	GetProfile(influencerID uuid.UUID) (*model.InfluencerProfile, error)
+	GetStats(influencerID uuid.UUID) (*model.InfluencerStats, error)
+	// Follow returns false if the user already follows the influencer
+	Follow(followerID, influencerID uuid.UUID) (bool, error)
+	// Unfollow returns false if the user did not follow the influencer
+	Unfollow(followerID, influencerID uuid.UUID) (bool, error)
+	ListFollowers(influencerID uuid.UUID) ([]*model.User, error)
+	ListFollowing(userID uuid.UUID) ([]*model.InfluencerProfile, error)
+}
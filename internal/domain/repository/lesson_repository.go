@@ -11,5 +11,5 @@ type LessonRepository interface {
 	Update(lesson *model.Lesson) error
 	Delete(lessonID uuid.UUID) error
 	GetByID(lessonID uuid.UUID) (*model.Lesson, error)
-	GetAll() ([]*model.Lesson, error)
+	List(filter model.LessonFilter, page model.PageRequest) (*model.Page[*model.Lesson], error)
 }
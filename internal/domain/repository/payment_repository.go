@@ -11,6 +11,7 @@ type PaymentRepository interface {
 	Update(payment *model.Payment) error
 	Delete(paymentID uuid.UUID) error
 	GetByID(paymentID uuid.UUID) (*model.Payment, error)
-	GetAll() ([]*model.Payment, error)
+	List(filter model.PaymentFilter, page model.PageRequest) (*model.Page[*model.Payment], error)
 	GetByExternalRef(externalRef string) (*model.Payment, error)
+	GetByUserID(userID uuid.UUID) ([]*model.Payment, error)
 }
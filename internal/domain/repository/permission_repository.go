@@ -0,0 +1,9 @@
+package repository
+
+import "github.com/gofrs/uuid"
+
+// PermissionRepository resolves roles and permissions for authorization checks
+type PermissionRepository interface {
+	GetUserRole(userID uuid.UUID) (string, error)
+	HasPermission(userID uuid.UUID, permission string) (bool, error)
+}
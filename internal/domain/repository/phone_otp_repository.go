@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"kaabe-app/internal/domain/model"
+
+	"github.com/gofrs/uuid"
+)
+
+// PhoneOTPRepository stores one-time codes sent by SMS
+type PhoneOTPRepository interface {
+	// Create stores a new code and invalidates earlier unused codes for the same number and purpose
+	Create(otp *model.PhoneOTP) error
+	// FindActive returns the latest unused, unexpired code, or nil if there is none
+	FindActive(phoneNumber, purpose string) (*model.PhoneOTP, error)
+	// RegisterAttempt counts a wrong guess and returns the number of guesses so far
+	RegisterAttempt(otpID uuid.UUID) (int, error)
+	// Consume marks the code used; it reports false if it was already used
+	Consume(otpID uuid.UUID) (bool, error)
+	PurgeExpired() (int, error)
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"kaabe-app/internal/domain/model"
+
+	"github.com/gofrs/uuid"
+)
+
+// PromotionRepository stores coupons, course sales and the price quotes made with them
+type PromotionRepository interface {
+	CreateCoupon(coupon *model.Coupon) error
+	UpdateCoupon(coupon *model.Coupon) error
+	// DeleteCoupon returns false if the coupon does not exist
+	DeleteCoupon(couponID uuid.UUID) (bool, error)
+	GetCoupon(couponID uuid.UUID) (*model.Coupon, error)
+	GetCouponByCode(code string) (*model.Coupon, error)
+	ListCoupons(filter model.CouponFilter, page model.PageRequest) (*model.Page[*model.Coupon], error)
+	// CountRedemptions counts the coupon's paid quotes by userID, or by everyone if userID is nil
+	CountRedemptions(couponID uuid.UUID, userID *uuid.UUID) (int, error)
+
+	// CreateSale returns "sale overlaps another sale" if the course already has a sale in the
+	// currency at that time
+	CreateSale(sale *model.CourseSale) error
+	// DeleteSale returns false if the course has no such sale
+	DeleteSale(courseID, saleID uuid.UUID) (bool, error)
+	// GetActiveSale returns nil if no sale of the course is running in the currency
+	GetActiveSale(courseID uuid.UUID, currency string) (*model.CourseSale, error)
+	// ListSales returns the course's sales that have not ended, soonest first
+	ListSales(courseID uuid.UUID) ([]*model.CourseSale, error)
+
+	CreateQuote(quote *model.PriceQuote) error
+	GetQuote(quoteID uuid.UUID) (*model.PriceQuote, error)
+}
@@ -11,5 +11,6 @@ type RatingRepository interface {
 	Update(rating *model.Rating) error
 	Delete(ratingID uuid.UUID) error
 	GetByID(ratingID uuid.UUID) (*model.Rating, error)
-	Getall() ([]*model.Rating, error)
+	List(filter model.RatingFilter, page model.PageRequest) (*model.Page[*model.Rating], error)
+	GetByUserID(userID uuid.UUID) ([]*model.Rating, error)
 }
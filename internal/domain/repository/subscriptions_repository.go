@@ -12,6 +12,7 @@ type SubscriptionRepository interface {
 	Update(Subscription  *model.Subscription) error
 	Delete(SubscriptionID uuid.UUID) error
 	Get(SubscriptionID uuid.UUID) (*model.Subscription, error)
-	List() ([]*model.Subscription, error)
+	List(filter model.SubscriptionFilter, page model.PageRequest) (*model.Page[*model.Subscription], error)
+	ListByUser(userID uuid.UUID) ([]*model.Subscription, error)
 
 }
\ No newline at end of file
@@ -1,8 +1,23 @@
 package repository
 
-import "kaabe-app/internal/domain/model"
+import (
+	"kaabe-app/internal/domain/model"
+
+	"github.com/gofrs/uuid"
+)
 
 type TokenRepository interface {
 	FindByToken(token string) (*model.Token, error)
 	Create(token *model.Token) error
+
+	// Refresh token rotation
+	FindRefreshToken(token string) (*model.Token, error)
+	MarkUsed(tokenID uuid.UUID) (bool, error)
+	RevokeFamily(familyID uuid.UUID) error
+
+	// Revocation
+	Revoke(token string) error
+	RevokeAllForUser(userID uuid.UUID) error
+	RevokeAllForUserExcept(userID, familyID uuid.UUID) error
+	PurgeExpired() (int, error)
 }
@@ -0,0 +1,18 @@
+package repository
+
+import "github.com/gofrs/uuid"
+
+// TwoFactorRepository stores TOTP secrets and recovery codes
+type TwoFactorRepository interface {
+	// SetSecret stores a pending secret; it reports false if 2FA is already enabled
+	SetSecret(userID uuid.UUID, secret string) (bool, error)
+	Enable(userID uuid.UUID) (bool, error)
+	Disable(userID uuid.UUID) error
+
+	// UseStep records an accepted TOTP time step; it reports false on replay
+	UseStep(userID uuid.UUID, step int64) (bool, error)
+
+	ReplaceRecoveryCodes(userID uuid.UUID, codeHashes []string) error
+	ConsumeRecoveryCode(userID uuid.UUID, codeHash string) (bool, error)
+	CountRecoveryCodes(userID uuid.UUID) (int, error)
+}
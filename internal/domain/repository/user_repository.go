@@ -2,6 +2,7 @@ package repository
 
 import (
 	"kaabe-app/internal/domain/model"
+	"time"
 
 	"github.com/gofrs/uuid"
 )
@@ -12,11 +13,27 @@ type UserRepository interface {
 	Delete(userID uuid.UUID) error
 	Get(userID uuid.UUID) (*model.User, error)
 	FindByEmail(email string) (*model.User, error)
-	List() ([]*model.User, error)
+	List(filter model.UserFilter, page model.PageRequest) (*model.Page[*model.User], error)
 
 	// Password reset
 	SetResetToken(email string, token uuid.UUID, expiry string) error
 	FindByResetToken(token uuid.UUID) (*model.User, error)
 	UpdatePassword(userID uuid.UUID, hashedPassword string) error
 	ClearResetToken(userID uuid.UUID) error
+
+	// Email verification
+	MarkEmailVerified(userID uuid.UUID) (bool, error)
+	ChangeEmail(userID uuid.UUID, email string) error
+
+	// Phone login
+	FindByPhone(phoneNumber string) (*model.User, error)
+	CreatePhoneUser(user *model.User) error
+	SetPhone(userID uuid.UUID, phoneNumber string) error
+	SetWalletPhone(userID uuid.UUID, phoneNumber string) (bool, error)
+
+	// Account deletion
+	RequestDeletion(userID uuid.UUID, scheduledAt time.Time) error
+	CancelDeletion(userID uuid.UUID) (bool, error)
+	ListDueForDeletion(limit int) ([]*model.User, error)
+	Anonymize(userID uuid.UUID) error
 }
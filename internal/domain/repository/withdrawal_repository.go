@@ -14,5 +14,6 @@ type WithdrawalRepository interface {
 	Update(Withdrawal  *model.Withdrawal) error
 	Delete(WithdrawalID uuid.UUID) error
 	Get(WithdrawalID uuid.UUID) (*model.Withdrawal, error)
-	List() ([]*model.Withdrawal, error)
+	List(filter model.WithdrawalFilter, page model.PageRequest) (*model.Page[*model.Withdrawal], error)
+	ListByUser(influencerID uuid.UUID) ([]*model.Withdrawal, error)
 }
\ No newline at end of file
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/repository"
+
+	"github.com/gofrs/uuid"
+)
+
+// Actor is the authenticated caller an access decision is made for
+type Actor struct {
+	UserID uuid.UUID
+	Role   string
+}
+
+// IsAdmin reports whether the actor has the admin role
+func (a Actor) IsAdmin() bool {
+	return a.Role == model.RoleAdmin
+}
+
+// AccessPolicy checks that an actor owns a resource (or is an admin) before acting on it.
+// Each method returns ErrForbidden when the actor is not allowed, an error wrapping
+// ErrNotFound when the resource does not exist, or the repository error otherwise.
+type AccessPolicy interface {
+	AuthorizeCourse(actor Actor, courseID uuid.UUID) error
+	AuthorizeLesson(actor Actor, lessonID uuid.UUID) error
+	AuthorizeRating(actor Actor, ratingID uuid.UUID) error
+	AuthorizeSubscription(actor Actor, subscriptionID uuid.UUID) error
+	AuthorizeWithdrawal(actor Actor, withdrawalID uuid.UUID) error
+	AuthorizePayment(actor Actor, paymentID uuid.UUID) error
+}
+
+type accessPolicy struct {
+	courseRepo       repository.CourseRepository
+	lessonRepo       repository.LessonRepository
+	ratingRepo       repository.RatingRepository
+	subscriptionRepo repository.SubscriptionRepository
+	withdrawalRepo   repository.WithdrawalRepository
+	paymentRepo      repository.PaymentRepository
+}
+
+// NewAccessPolicy creates the ownership policy
+func NewAccessPolicy(
+	courseRepo repository.CourseRepository,
+	lessonRepo repository.LessonRepository,
+	ratingRepo repository.RatingRepository,
+	subscriptionRepo repository.SubscriptionRepository,
+	withdrawalRepo repository.WithdrawalRepository,
+	paymentRepo repository.PaymentRepository,
+) AccessPolicy {
+	return &accessPolicy{
+		courseRepo:       courseRepo,
+		lessonRepo:       lessonRepo,
+		ratingRepo:       ratingRepo,
+		subscriptionRepo: subscriptionRepo,
+		withdrawalRepo:   withdrawalRepo,
+		paymentRepo:      paymentRepo,
+	}
+}
+
+// AuthorizeCourse allows the course's influencer and admins
+func (p *accessPolicy) AuthorizeCourse(actor Actor, courseID uuid.UUID) error {
+	course, err := p.courseRepo.GetByID(courseID)
+	if err != nil {
+		return lookupError("course", err)
+	}
+	return ownedBy(actor, course.InfluencerID)
+}
+
+// AuthorizeLesson allows the influencer owning the lesson's course and admins
+func (p *accessPolicy) AuthorizeLesson(actor Actor, lessonID uuid.UUID) error {
+	lesson, err := p.lessonRepo.GetByID(lessonID)
+	if err != nil {
+		return lookupError("lesson", err)
+	}
+	return p.AuthorizeCourse(actor, lesson.CourseID)
+}
+
+// AuthorizeRating allows the rating's author and admins
+func (p *accessPolicy) AuthorizeRating(actor Actor, ratingID uuid.UUID) error {
+	rating, err := p.ratingRepo.GetByID(ratingID)
+	if err != nil {
+		return lookupError("rating", err)
+	}
+	return ownedBy(actor, rating.UserID)
+}
+
+// AuthorizeSubscription allows the subscriber and admins
+func (p *accessPolicy) AuthorizeSubscription(actor Actor, subscriptionID uuid.UUID) error {
+	subscription, err := p.subscriptionRepo.Get(subscriptionID)
+	if err != nil {
+		return lookupError("subscription", err)
+	}
+	return ownedBy(actor, subscription.UserID)
+}
+
+// AuthorizeWithdrawal allows the requesting influencer and admins
+func (p *accessPolicy) AuthorizeWithdrawal(actor Actor, withdrawalID uuid.UUID) error {
+	withdrawal, err := p.withdrawalRepo.Get(withdrawalID)
+	if err != nil {
+		return lookupError("withdrawal", err)
+	}
+	return ownedBy(actor, withdrawal.InfluencerID)
+}
+
+// AuthorizePayment allows the paying user and admins
+func (p *accessPolicy) AuthorizePayment(actor Actor, paymentID uuid.UUID) error {
+	payment, err := p.paymentRepo.GetByID(paymentID)
+	if err != nil {
+		return lookupError("payment", err)
+	}
+	return ownedBy(actor, payment.UserID)
+}
+
+func ownedBy(actor Actor, ownerID uuid.UUID) error {
+	if actor.IsAdmin() || actor.UserID == ownerID {
+		return nil
+	}
+	return ErrForbidden
+}
+
+// lookupError maps the repository's not-found error to ErrNotFound
+func lookupError(what string, err error) error {
+	if errors.Is(err, model.ErrNotFound) {
+		return fmt.Errorf("%s %w", what, ErrNotFound)
+	}
+	return err
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/repository"
+	utils "kaabe-app/pkg/config"
+	"log"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// ErrNoDeletionPending is returned when cancelling a deletion that was never requested
+var ErrNoDeletionPending = errors.New("no account deletion is pending")
+
+// AccountService handles account deletion and the personal data export. Deleting an account
+// anonymizes the user instead of removing the row, so payments and withdrawals survive it.
+type AccountService interface {
+	RequestDeletion(userID uuid.UUID, currentPassword string) (*model.User, error)
+	CancelDeletion(userID uuid.UUID) error
+	ProcessDueDeletions(limit int) (int, error)
+	ExportPersonalData(userID uuid.UUID) (*model.PersonalDataExport, error)
+}
+
+type accountService struct {
+	userRepo         repository.UserRepository
+	identityRepo     repository.IdentityRepository
+	subscriptionRepo repository.SubscriptionRepository
+	ratingRepo       repository.RatingRepository
+	paymentRepo      repository.PaymentRepository
+	withdrawalRepo   repository.WithdrawalRepository
+	applicationRepo  repository.InfluencerApplicationRepository
+	influencerRepo   repository.InfluencerRepository
+	auditRepo        repository.AuditRepository
+	notifier         NotificationService
+	gracePeriod      time.Duration
+}
+
+// NewAccountService creates an AccountService; accounts are anonymized gracePeriod after the request
+func NewAccountService(
+	userRepo repository.UserRepository,
+	identityRepo repository.IdentityRepository,
+	subscriptionRepo repository.SubscriptionRepository,
+	ratingRepo repository.RatingRepository,
+	paymentRepo repository.PaymentRepository,
+	withdrawalRepo repository.WithdrawalRepository,
+	applicationRepo repository.InfluencerApplicationRepository,
+	influencerRepo repository.InfluencerRepository,
+	auditRepo repository.AuditRepository,
+	notifier NotificationService,
+	gracePeriod time.Duration,
+) AccountService {
+	return &accountService{
+		userRepo:         userRepo,
+		identityRepo:     identityRepo,
+		subscriptionRepo: subscriptionRepo,
+		ratingRepo:       ratingRepo,
+		paymentRepo:      paymentRepo,
+		withdrawalRepo:   withdrawalRepo,
+		applicationRepo:  applicationRepo,
+		influencerRepo:   influencerRepo,
+		auditRepo:        auditRepo,
+		notifier:         notifier,
+		gracePeriod:      gracePeriod,
+	}
+}
+
+// RequestDeletion schedules the account to be anonymized once the grace period has passed.
+// The user stays able to log in and cancel until then.
+func (s *accountService) RequestDeletion(userID uuid.UUID, currentPassword string) (*model.User, error) {
+	user, err := s.userRepo.Get(userID)
+	if err != nil || user.DeletedAt != nil {
+		return nil, errors.New("user not found")
+	}
+
+	// Accounts created through social or phone login have no password to confirm
+	if user.Password != "" && !utils.CheckPasswordHash(currentPassword, user.Password) {
+		return nil, ErrInvalidCurrentPassword
+	}
+
+	scheduledAt := time.Now().Add(s.gracePeriod)
+	if err := s.userRepo.RequestDeletion(userID, scheduledAt); err != nil {
+		return nil, err
+	}
+	user.DeletionScheduledAt = &scheduledAt
+
+	s.audit(userID, model.AuditAccountDeletionRequested, map[string]interface{}{
+		"scheduled_at": scheduledAt.UTC().Format(time.RFC3339),
+	})
+
+	if user.Email != "" {
+		if err := s.notifier.SendAccountDeletionScheduled(user, scheduledAt); err != nil {
+			log.Printf("Error sending deletion notice: %v", err)
+		}
+	}
+
+	return user, nil
+}
+
+// CancelDeletion keeps the account if its deletion is still pending
+func (s *accountService) CancelDeletion(userID uuid.UUID) error {
+	cancelled, err := s.userRepo.CancelDeletion(userID)
+	if err != nil {
+		return err
+	}
+	if !cancelled {
+		return ErrNoDeletionPending
+	}
+
+	s.audit(userID, model.AuditAccountDeletionCancelled, nil)
+	return nil
+}
+
+// ProcessDueDeletions anonymizes up to limit accounts whose grace period has ended and
+// returns how many were deleted
+func (s *accountService) ProcessDueDeletions(limit int) (int, error) {
+	users, err := s.userRepo.ListDueForDeletion(limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list accounts due for deletion: %v", err)
+	}
+
+	deleted := 0
+	for _, user := range users {
+		if err := s.userRepo.Anonymize(user.ID); err != nil {
+			log.Printf("Error deleting account %s: %v", user.ID, err)
+			continue
+		}
+		deleted++
+
+		s.audit(user.ID, model.AuditAccountDeleted, nil)
+
+		// user still holds the details from before anonymization
+		if user.Email != "" {
+			if err := s.notifier.SendAccountDeleted(user); err != nil {
+				log.Printf("Error sending deletion confirmation: %v", err)
+			}
+		}
+	}
+
+	return deleted, nil
+}
+
+// ExportPersonalData collects everything stored about the user
+func (s *accountService) ExportPersonalData(userID uuid.UUID) (*model.PersonalDataExport, error) {
+	user, err := s.userRepo.Get(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	identities, err := s.identityRepo.ListByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export identities: %v", err)
+	}
+	subscriptions, err := s.subscriptionRepo.ListByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export subscriptions: %v", err)
+	}
+	ratings, err := s.ratingRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export ratings: %v", err)
+	}
+	payments, err := s.paymentRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export payments: %v", err)
+	}
+	withdrawals, err := s.withdrawalRepo.ListByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export withdrawals: %v", err)
+	}
+	applications, err := s.applicationRepo.ListByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export influencer applications: %v", err)
+	}
+	// Only influencers have a profile; everyone else gets none
+	profile, _ := s.influencerRepo.GetProfile(userID)
+	following, err := s.influencerRepo.ListFollowing(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export followed influencers: %v", err)
+	}
+
+	return &model.PersonalDataExport{
+		ExportedAt:             time.Now(),
+		User:                   user,
+		Identities:             identities,
+		Subscriptions:          subscriptions,
+		Ratings:                ratings,
+		Payments:               payments,
+		Withdrawals:            withdrawals,
+		InfluencerApplications: applications,
+		InfluencerProfile:      profile,
+		Following:              following,
+	}, nil
+}
+
+// audit records an account event; failures are logged and never block the caller
+func (s *accountService) audit(userID uuid.UUID, action string, metadata map[string]interface{}) {
+	entry := &model.AuditLog{
+		UserID:   &userID,
+		Action:   action,
+		Metadata: metadata,
+	}
+	if err := s.auditRepo.Create(entry); err != nil {
+		log.Printf("Error writing audit log: %v", err)
+	}
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"fmt"
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/repository"
+	"log"
+	"strings"
+	"time"
+)
+
+// TooManyAttemptsError is returned while an account or IP is backing off or locked out
+type TooManyAttemptsError struct {
+	RetryAfter time.Duration
+}
+
+func (e *TooManyAttemptsError) Error() string {
+	return fmt.Sprintf("too many attempts, try again in %s", e.RetryAfter.Round(time.Second))
+}
+
+// ThrottlePolicy describes how failures on one key are punished.
+// The first FreeAttempts failures in Window cost nothing; after that every failure
+// blocks the key for BaseDelay, doubling each time, until MaxAttempts is reached
+// and the key is locked out for Lockout.
+type ThrottlePolicy struct {
+	FreeAttempts int
+	MaxAttempts  int
+	Window       time.Duration
+	BaseDelay    time.Duration
+	Lockout      time.Duration
+}
+
+// delayFor returns how long the key is blocked after its nth failure, and whether that is a lockout
+func (p ThrottlePolicy) delayFor(failures int) (time.Duration, bool) {
+	if failures >= p.MaxAttempts {
+		return p.Lockout, true
+	}
+	if failures <= p.FreeAttempts {
+		return 0, false
+	}
+
+	shift := uint(failures - p.FreeAttempts - 1)
+	delay := p.BaseDelay << shift
+	// A delay that no longer fits is past the lockout anyway
+	if shift >= 63 || delay>>shift != p.BaseDelay || delay > p.Lockout {
+		delay = p.Lockout
+	}
+	return delay, false
+}
+
+// AttemptLimiter tracks failures per account and per IP for one kind of action
+type AttemptLimiter struct {
+	store         repository.AttemptStore
+	auditRepo     repository.AuditRepository
+	name          string
+	accountPolicy ThrottlePolicy
+	ipPolicy      ThrottlePolicy
+	accountAudit  string
+	ipAudit       string
+}
+
+// Limits for login, password reset, SMS code and verification email requests
+var (
+	loginAccountPolicy = ThrottlePolicy{FreeAttempts: 3, MaxAttempts: 10, Window: 15 * time.Minute, BaseDelay: 2 * time.Second, Lockout: 15 * time.Minute}
+	loginIPPolicy      = ThrottlePolicy{FreeAttempts: 20, MaxAttempts: 50, Window: 15 * time.Minute, BaseDelay: time.Second, Lockout: 30 * time.Minute}
+	resetAccountPolicy = ThrottlePolicy{FreeAttempts: 3, MaxAttempts: 5, Window: time.Hour, BaseDelay: time.Minute, Lockout: time.Hour}
+	resetIPPolicy      = ThrottlePolicy{FreeAttempts: 10, MaxAttempts: 20, Window: time.Hour, BaseDelay: 30 * time.Second, Lockout: time.Hour}
+	otpPhonePolicy     = ThrottlePolicy{FreeAttempts: 3, MaxAttempts: 6, Window: time.Hour, BaseDelay: time.Minute, Lockout: time.Hour}
+	otpIPPolicy        = ThrottlePolicy{FreeAttempts: 10, MaxAttempts: 30, Window: time.Hour, BaseDelay: 30 * time.Second, Lockout: time.Hour}
+	verifyEmailPolicy  = ThrottlePolicy{FreeAttempts: 3, MaxAttempts: 5, Window: time.Hour, BaseDelay: time.Minute, Lockout: time.Hour}
+	verifyIPPolicy     = ThrottlePolicy{FreeAttempts: 10, MaxAttempts: 20, Window: time.Hour, BaseDelay: 30 * time.Second, Lockout: time.Hour}
+)
+
+// NewLoginLimiter limits failed logins
+func NewLoginLimiter(store repository.AttemptStore, auditRepo repository.AuditRepository) *AttemptLimiter {
+	return &AttemptLimiter{
+		store:         store,
+		auditRepo:     auditRepo,
+		name:          "login",
+		accountPolicy: loginAccountPolicy,
+		ipPolicy:      loginIPPolicy,
+		accountAudit:  model.AuditLoginAccountLocked,
+		ipAudit:       model.AuditLoginIPLocked,
+	}
+}
+
+// NewPasswordResetLimiter limits password reset requests; every request counts as an attempt
+func NewPasswordResetLimiter(store repository.AttemptStore, auditRepo repository.AuditRepository) *AttemptLimiter {
+	return &AttemptLimiter{
+		store:         store,
+		auditRepo:     auditRepo,
+		name:          "password_reset",
+		accountPolicy: resetAccountPolicy,
+		ipPolicy:      resetIPPolicy,
+		accountAudit:  model.AuditResetAccountLocked,
+		ipAudit:       model.AuditResetIPLocked,
+	}
+}
+
+// NewPhoneOTPLimiter limits SMS code requests; every request counts since each one costs an SMS
+func NewPhoneOTPLimiter(store repository.AttemptStore, auditRepo repository.AuditRepository) *AttemptLimiter {
+	return &AttemptLimiter{
+		store:         store,
+		auditRepo:     auditRepo,
+		name:          "phone_otp",
+		accountPolicy: otpPhonePolicy,
+		ipPolicy:      otpIPPolicy,
+		accountAudit:  model.AuditOTPPhoneLocked,
+		ipAudit:       model.AuditOTPIPLocked,
+	}
+}
+
+// NewVerificationResendLimiter limits verification email resends; every request counts as an attempt
+func NewVerificationResendLimiter(store repository.AttemptStore, auditRepo repository.AuditRepository) *AttemptLimiter {
+	return &AttemptLimiter{
+		store:         store,
+		auditRepo:     auditRepo,
+		name:          "email_verification",
+		accountPolicy: verifyEmailPolicy,
+		ipPolicy:      verifyIPPolicy,
+		accountAudit:  model.AuditVerifyEmailLocked,
+		ipAudit:       model.AuditVerifyIPLocked,
+	}
+}
+
+// Check returns a TooManyAttemptsError if the account or IP is currently blocked.
+// Store errors are logged and do not block the request.
+func (l *AttemptLimiter) Check(account, ip string) error {
+	var retryAfter time.Duration
+
+	for _, key := range []string{l.accountKey(account), l.ipKey(ip)} {
+		until, err := l.store.LockedUntil(key)
+		if err != nil {
+			log.Printf("Attempt store unavailable for %s: %v", key, err)
+			continue
+		}
+		if wait := time.Until(until); wait > retryAfter {
+			retryAfter = wait
+		}
+	}
+
+	if retryAfter > 0 {
+		return &TooManyAttemptsError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// Failure records a failed attempt for the account and IP and applies backoff or lockout
+func (l *AttemptLimiter) Failure(account, ip string) {
+	l.register(l.accountKey(account), l.accountPolicy, l.accountAudit, account, ip)
+	l.register(l.ipKey(ip), l.ipPolicy, l.ipAudit, ip, ip)
+}
+
+// Success clears the account's failures. The IP counter is left alone so one valid
+// account cannot be used to reset an IP that is guessing at others.
+func (l *AttemptLimiter) Success(account string) {
+	if err := l.store.Reset(l.accountKey(account)); err != nil {
+		log.Printf("Error resetting %s attempts: %v", l.name, err)
+	}
+}
+
+func (l *AttemptLimiter) register(key string, policy ThrottlePolicy, auditAction, subject, ip string) {
+	failures, err := l.store.RegisterFailure(key, policy.Window)
+	if err != nil {
+		log.Printf("Attempt store unavailable for %s: %v", key, err)
+		return
+	}
+
+	delay, lockout := policy.delayFor(failures)
+	if delay == 0 {
+		return
+	}
+
+	if err := l.store.Lock(key, delay); err != nil {
+		log.Printf("Error locking %s: %v", key, err)
+		return
+	}
+
+	if !lockout {
+		return
+	}
+
+	log.Printf("Locked %s for %s after %d failed attempts", key, delay, failures)
+	entry := &model.AuditLog{
+		Action:    auditAction,
+		Subject:   subject,
+		IPAddress: ip,
+		Metadata: map[string]interface{}{
+			"failures":        failures,
+			"lockout_seconds": int(delay.Seconds()),
+		},
+	}
+	if err := l.auditRepo.Create(entry); err != nil {
+		log.Printf("Error writing audit log: %v", err)
+	}
+}
+
+func (l *AttemptLimiter) accountKey(account string) string {
+	return l.name + ":account:" + strings.ToLower(strings.TrimSpace(account))
+}
+
+func (l *AttemptLimiter) ipKey(ip string) string {
+	return l.name + ":ip:" + ip
+}
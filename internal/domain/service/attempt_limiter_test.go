@@ -0,0 +1,158 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"kaabe-app/internal/domain/model"
+	"testing"
+	"time"
+)
+
+// memoryStore is an AttemptStore kept in maps
+type memoryStore struct {
+	failures map[string]int
+	locks    map[string]time.Time
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{failures: map[string]int{}, locks: map[string]time.Time{}}
+}
+
+func (s *memoryStore) RegisterFailure(key string, window time.Duration) (int, error) {
+	s.failures[key]++
+	return s.failures[key], nil
+}
+
+func (s *memoryStore) Reset(key string) error {
+	delete(s.failures, key)
+	return nil
+}
+
+func (s *memoryStore) Lock(key string, duration time.Duration) error {
+	s.locks[key] = time.Now().Add(duration)
+	return nil
+}
+
+func (s *memoryStore) LockedUntil(key string) (time.Time, error) {
+	return s.locks[key], nil
+}
+
+// auditRecorder is an AuditRepository that keeps what it is given
+type auditRecorder struct {
+	entries []*model.AuditLog
+}
+
+func (a *auditRecorder) Create(entry *model.AuditLog) error {
+	a.entries = append(a.entries, entry)
+	return nil
+}
+
+func TestThrottlePolicyDelayFor(t *testing.T) {
+	policy := ThrottlePolicy{FreeAttempts: 3, MaxAttempts: 10, Window: 15 * time.Minute, BaseDelay: 2 * time.Second, Lockout: 15 * time.Minute}
+	capped := ThrottlePolicy{FreeAttempts: 0, MaxAttempts: 100, Window: time.Hour, BaseDelay: time.Minute, Lockout: 5 * time.Minute}
+
+	tests := []struct {
+		name        string
+		policy      ThrottlePolicy
+		failures    int
+		wantDelay   time.Duration
+		wantLockout bool
+	}{
+		{name: "first failure is free", policy: policy, failures: 1},
+		{name: "last free failure", policy: policy, failures: 3},
+		{name: "first delay", policy: policy, failures: 4, wantDelay: 2 * time.Second},
+		{name: "delay doubles", policy: policy, failures: 5, wantDelay: 4 * time.Second},
+		{name: "delay keeps doubling", policy: policy, failures: 9, wantDelay: 64 * time.Second},
+		{name: "lockout at the limit", policy: policy, failures: 10, wantDelay: 15 * time.Minute, wantLockout: true},
+		{name: "lockout past the limit", policy: policy, failures: 12, wantDelay: 15 * time.Minute, wantLockout: true},
+		{name: "no free failures", policy: capped, failures: 1, wantDelay: time.Minute},
+		{name: "delay is capped at the lockout", policy: capped, failures: 4, wantDelay: 5 * time.Minute},
+		{name: "huge failure counts stay capped", policy: capped, failures: 80, wantDelay: 5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay, lockout := tt.policy.delayFor(tt.failures)
+			if delay != tt.wantDelay || lockout != tt.wantLockout {
+				t.Errorf("delayFor(%d) = %s, %v; want %s, %v", tt.failures, delay, lockout, tt.wantDelay, tt.wantLockout)
+			}
+		})
+	}
+}
+
+func TestAttemptLimiterBackoff(t *testing.T) {
+	store := newMemoryStore()
+	audit := &auditRecorder{}
+	limiter := NewPasswordResetLimiter(store, audit)
+	const email, ip = "Learner@example.com", "10.0.0.1"
+
+	// Free attempts leave the account unblocked
+	for i := 0; i < resetAccountPolicy.FreeAttempts; i++ {
+		limiter.Failure(email, ip)
+	}
+	if err := limiter.Check(email, ip); err != nil {
+		t.Fatalf("Check after %d free failures = %v, want nil", resetAccountPolicy.FreeAttempts, err)
+	}
+
+	// The next one backs off for the base delay; the account is keyed case-insensitively
+	limiter.Failure(email, ip)
+	var tooMany *TooManyAttemptsError
+	if err := limiter.Check("learner@example.com", "10.0.0.2"); !errors.As(err, &tooMany) {
+		t.Fatalf("Check after backoff = %v, want TooManyAttemptsError", err)
+	}
+	if tooMany.RetryAfter <= 0 || tooMany.RetryAfter > resetAccountPolicy.BaseDelay {
+		t.Errorf("RetryAfter = %s, want at most %s", tooMany.RetryAfter, resetAccountPolicy.BaseDelay)
+	}
+	if len(audit.entries) != 0 {
+		t.Errorf("backoff wrote %d audit entries, want none", len(audit.entries))
+	}
+
+	// Reaching the limit locks the account out and audits it
+	limiter.Failure(email, ip)
+	if err := limiter.Check(email, ip); !errors.As(err, &tooMany) || tooMany.RetryAfter <= resetAccountPolicy.BaseDelay {
+		t.Fatalf("Check after lockout = %v, want a lockout of %s", err, resetAccountPolicy.Lockout)
+	}
+	if len(audit.entries) != 1 || audit.entries[0].Action != model.AuditResetAccountLocked {
+		t.Fatalf("audit entries = %+v, want one %s", audit.entries, model.AuditResetAccountLocked)
+	}
+
+	// Another account from another IP is unaffected
+	if err := limiter.Check("other@example.com", "10.0.0.2"); err != nil {
+		t.Errorf("Check for another account = %v, want nil", err)
+	}
+}
+
+func TestAttemptLimiterBlocksIPAcrossAccounts(t *testing.T) {
+	store := newMemoryStore()
+	limiter := NewPasswordResetLimiter(store, &auditRecorder{})
+	const ip = "10.0.0.1"
+
+	for i := 0; i <= resetIPPolicy.FreeAttempts; i++ {
+		limiter.Failure(fmt.Sprintf("user%d@example.com", i), ip)
+	}
+
+	var tooMany *TooManyAttemptsError
+	if err := limiter.Check("fresh@example.com", ip); !errors.As(err, &tooMany) {
+		t.Fatalf("Check from a backed-off IP = %v, want TooManyAttemptsError", err)
+	}
+	if err := limiter.Check("fresh@example.com", "10.0.0.2"); err != nil {
+		t.Errorf("Check from another IP = %v, want nil", err)
+	}
+}
+
+func TestAttemptLimiterSuccessClearsAccount(t *testing.T) {
+	store := newMemoryStore()
+	limiter := NewLoginLimiter(store, &auditRecorder{})
+	const email, ip = "learner@example.com", "10.0.0.1"
+
+	for i := 0; i < loginAccountPolicy.FreeAttempts; i++ {
+		limiter.Failure(email, ip)
+	}
+	limiter.Success(email)
+
+	// The counter starts over, so the next failure is free again
+	limiter.Failure(email, ip)
+	if err := limiter.Check(email, ip); err != nil {
+		t.Errorf("Check after success and one failure = %v, want nil", err)
+	}
+}
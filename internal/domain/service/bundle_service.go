@@ -0,0 +1,218 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/repository"
+	"log"
+	"strings"
+
+	"github.com/gofrs/uuid"
+)
+
+// Bundle and pass errors
+var (
+	ErrBundleNotFound  = errors.New("bundle not found")
+	ErrBundlePurchased = errors.New("bundle has been purchased; deactivate it instead")
+	ErrInvalidBundle   = errors.New("invalid bundle")
+	ErrPassNotFound    = errors.New("pass not found")
+	ErrInvalidPass     = errors.New("invalid pass")
+)
+
+// BundleService manages course bundles and influencer all-access passes. Both are bought with a
+// quote from PromotionService; the completed payment subscribes the buyer to the courses.
+type BundleService interface {
+	CreateBundle(bundle *model.Bundle) error
+	UpdateBundle(bundle *model.Bundle) error
+	DeleteBundle(bundleID uuid.UUID) error
+	GetBundle(bundleID uuid.UUID) (*model.Bundle, error)
+	ListBundles(filter model.BundleFilter, page model.PageRequest) (*model.Page[*model.Bundle], error)
+
+	SetPass(pass *model.InfluencerPass) error
+	GetPass(influencerID uuid.UUID) (*model.InfluencerPass, error)
+
+	ListPurchases(userID uuid.UUID, page model.PageRequest) (*model.Page[*model.Purchase], error)
+
+	// OnCoursePublished subscribes the influencer's pass holders to the new course
+	OnCoursePublished(course *model.Course)
+}
+
+type bundleServiceImpl struct {
+	repo       repository.BundleRepository
+	courseRepo repository.CourseRepository
+}
+
+// NewBundleService creates the bundle service
+func NewBundleService(repo repository.BundleRepository, courseRepo repository.CourseRepository) BundleService {
+	return &bundleServiceImpl{repo: repo, courseRepo: courseRepo}
+}
+
+// CreateBundle implements BundleService.
+func (b *bundleServiceImpl) CreateBundle(bundle *model.Bundle) error {
+	if err := b.prepareBundle(bundle); err != nil {
+		return err
+	}
+
+	if err := b.repo.CreateBundle(bundle); err != nil {
+		return bundleError(err)
+	}
+
+	log.Printf("Bundle created: %s (%s) with %d courses", bundle.Title, bundle.ID, len(bundle.CourseIDs))
+	return nil
+}
+
+// UpdateBundle implements BundleService. Buyers keep the courses and access period they paid for.
+func (b *bundleServiceImpl) UpdateBundle(bundle *model.Bundle) error {
+	if err := b.prepareBundle(bundle); err != nil {
+		return err
+	}
+
+	if err := b.repo.UpdateBundle(bundle); err != nil {
+		return bundleError(err)
+	}
+	return nil
+}
+
+// DeleteBundle implements BundleService. Only bundles nobody has paid for can be deleted.
+func (b *bundleServiceImpl) DeleteBundle(bundleID uuid.UUID) error {
+	deleted, err := b.repo.DeleteBundle(bundleID)
+	if err != nil {
+		return bundleError(err)
+	}
+	if !deleted {
+		return ErrBundleNotFound
+	}
+	return nil
+}
+
+// GetBundle implements BundleService.
+func (b *bundleServiceImpl) GetBundle(bundleID uuid.UUID) (*model.Bundle, error) {
+	bundle, err := b.repo.GetBundle(bundleID)
+	if err != nil {
+		return nil, bundleError(err)
+	}
+	return bundle, nil
+}
+
+// ListBundles implements BundleService.
+func (b *bundleServiceImpl) ListBundles(filter model.BundleFilter, page model.PageRequest) (*model.Page[*model.Bundle], error) {
+	bundles, err := b.repo.ListBundles(filter, page)
+	if err != nil {
+		return nil, listError("bundles", err)
+	}
+	return bundles, nil
+}
+
+// SetPass implements BundleService. Passes already bought keep their access period.
+func (b *bundleServiceImpl) SetPass(pass *model.InfluencerPass) error {
+	price, err := positiveMoney(pass.Price)
+	if err != nil {
+		return err
+	}
+	pass.Price = price
+
+	if pass.AccessDays < 1 || pass.AccessDays > model.MaxPurchaseAccessDays {
+		return fmt.Errorf("%w: access_days must be between 1 and %d", ErrInvalidPass, model.MaxPurchaseAccessDays)
+	}
+
+	if err := b.repo.SetPass(pass); err != nil {
+		return fmt.Errorf("failed to save pass: %v", err)
+	}
+
+	log.Printf("Pass set for influencer %s: %s for %d days", pass.InfluencerID, pass.Price, pass.AccessDays)
+	return nil
+}
+
+// GetPass implements BundleService.
+func (b *bundleServiceImpl) GetPass(influencerID uuid.UUID) (*model.InfluencerPass, error) {
+	pass, err := b.repo.GetPass(influencerID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, ErrPassNotFound
+		}
+		return nil, fmt.Errorf("failed to get pass: %v", err)
+	}
+	return pass, nil
+}
+
+// ListPurchases implements BundleService.
+func (b *bundleServiceImpl) ListPurchases(userID uuid.UUID, page model.PageRequest) (*model.Page[*model.Purchase], error) {
+	purchases, err := b.repo.ListPurchases(userID, page)
+	if err != nil {
+		return nil, listError("purchases", err)
+	}
+	return purchases, nil
+}
+
+// OnCoursePublished implements BundleService and CoursePublishedHook. Failures are logged; they
+// never fail the publish.
+func (b *bundleServiceImpl) OnCoursePublished(course *model.Course) {
+	granted, err := b.repo.GrantPassCourse(course.ID)
+	if err != nil {
+		log.Printf("Error granting course %s to pass holders: %v", course.ID, err)
+		return
+	}
+	if granted > 0 {
+		log.Printf("Course %s granted to %d pass holders", course.ID, granted)
+	}
+}
+
+// prepareBundle checks the bundle's details and that its courses are published courses of the
+// bundle's influencer. Repeated course IDs keep their first position.
+func (b *bundleServiceImpl) prepareBundle(bundle *model.Bundle) error {
+	bundle.Title = strings.TrimSpace(bundle.Title)
+	if bundle.Title == "" || len(bundle.Title) > model.MaxBundleTitleLen {
+		return fmt.Errorf("%w: the title must be 1 to %d characters", ErrInvalidBundle, model.MaxBundleTitleLen)
+	}
+	if bundle.AccessDays < 1 || bundle.AccessDays > model.MaxPurchaseAccessDays {
+		return fmt.Errorf("%w: access_days must be between 1 and %d", ErrInvalidBundle, model.MaxPurchaseAccessDays)
+	}
+
+	price, err := positiveMoney(bundle.Price)
+	if err != nil {
+		return err
+	}
+	bundle.Price = price
+
+	seen := make(map[uuid.UUID]bool, len(bundle.CourseIDs))
+	ordered := make([]uuid.UUID, 0, len(bundle.CourseIDs))
+	for _, courseID := range bundle.CourseIDs {
+		if seen[courseID] {
+			continue
+		}
+		seen[courseID] = true
+
+		course, err := b.courseRepo.GetByID(courseID)
+		if err != nil {
+			if errors.Is(err, model.ErrNotFound) {
+				return fmt.Errorf("%w: %s", ErrCourseNotFound, courseID)
+			}
+			return err
+		}
+		if course.InfluencerID != bundle.InfluencerID {
+			return fmt.Errorf("%w: course %s belongs to another influencer", ErrInvalidBundle, courseID)
+		}
+		if course.Status != model.CourseStatusPublished {
+			return fmt.Errorf("%w: course %s is not published", ErrInvalidBundle, courseID)
+		}
+		ordered = append(ordered, courseID)
+	}
+
+	if len(ordered) < model.MinBundleCourses || len(ordered) > model.MaxBundleCourses {
+		return fmt.Errorf("%w: a bundle has %d to %d courses", ErrInvalidBundle, model.MinBundleCourses, model.MaxBundleCourses)
+	}
+	bundle.CourseIDs = ordered
+	return nil
+}
+
+// bundleError maps bundle repository errors to service errors
+func bundleError(err error) error {
+	switch {
+	case errors.Is(err, model.ErrBundlePurchased):
+		return ErrBundlePurchased
+	case errors.Is(err, model.ErrNotFound):
+		return ErrBundleNotFound
+	}
+	return fmt.Errorf("bundle operation failed: %v", err)
+}
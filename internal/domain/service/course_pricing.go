@@ -0,0 +1,81 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"kaabe-app/internal/domain/model"
+	"log"
+
+	"github.com/gofrs/uuid"
+)
+
+// Pricing errors
+var (
+	ErrBasePriceRequired = errors.New("the base price cannot be removed; change it, or change the base currency")
+	ErrPriceNotFound     = errors.New("course has no price in that currency")
+)
+
+// SetCoursePrice implements CourseService. A price in the base currency changes the base price;
+// any other currency adds or changes an entry of the price list. Unlike content, prices of a
+// published course change directly, since every change is kept in the price history.
+func (c *courseServiceImpl) SetCoursePrice(courseID uuid.UUID, price model.Money) (*model.Course, error) {
+	price, err := model.NewMoney(price.Amount, price.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := c.repo.SetPrice(courseID, price)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set course price: %v", err)
+	}
+	if !updated {
+		return nil, ErrCourseNotFound
+	}
+
+	log.Printf("Course %s price set to %s", courseID, price)
+	return c.getCourse(courseID)
+}
+
+// DeleteCoursePrice implements CourseService. The course stops being sold in that currency.
+func (c *courseServiceImpl) DeleteCoursePrice(courseID uuid.UUID, currency string) (*model.Course, error) {
+	currency, err := model.NormalizeCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	course, err := c.getCourse(courseID)
+	if err != nil {
+		return nil, err
+	}
+	if course.Price.Currency == currency {
+		return nil, ErrBasePriceRequired
+	}
+
+	deleted, err := c.repo.DeletePrice(courseID, currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete course price: %v", err)
+	}
+	if !deleted {
+		return nil, ErrPriceNotFound
+	}
+	return c.getCourse(courseID)
+}
+
+// ListCoursePriceHistory implements CourseService. An empty currency lists changes in all currencies.
+func (c *courseServiceImpl) ListCoursePriceHistory(courseID uuid.UUID, currency string, page model.PageRequest) (*model.Page[*model.CoursePriceChange], error) {
+	if currency != "" {
+		var err error
+		if currency, err = model.NormalizeCurrency(currency); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := c.getCourse(courseID); err != nil {
+		return nil, err
+	}
+
+	history, err := c.repo.ListPriceHistory(courseID, currency, page)
+	if err != nil {
+		return nil, listError("course price history", err)
+	}
+	return history, nil
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"kaabe-app/internal/domain/model"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Publishing workflow errors
+var (
+	ErrInvalidCourseTransition = errors.New("course cannot move to that status from its current one")
+	ErrCourseInReview          = errors.New("course is in review; withdraw it before editing")
+	ErrCourseNotInReview       = errors.New("course is not awaiting review")
+	ErrCourseHasNoLessons      = errors.New("course needs at least one lesson before it can be published")
+	ErrCourseHasNoCover        = errors.New("course needs a cover image before it can be published")
+	ErrRejectionReasonRequired = errors.New("a rejection needs a reason")
+	ErrInvalidSchedule         = errors.New("scheduled times must be in the future, and unpublish_at after publish_at")
+)
+
+// courseTransitions lists the status changes the workflow allows; publishing only happens
+// through review, and an archived course goes back to draft to be resubmitted
+var courseTransitions = map[string][]string{
+	model.CourseStatusDraft:     {model.CourseStatusInReview},
+	model.CourseStatusInReview:  {model.CourseStatusDraft, model.CourseStatusPublished},
+	model.CourseStatusPublished: {model.CourseStatusArchived},
+	model.CourseStatusArchived:  {model.CourseStatusDraft},
+}
+
+func canTransition(from, to string) bool {
+	for _, allowed := range courseTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// SubmitCourse implements CourseService. The course must be ready to publish when it is sent to review.
+func (c *courseServiceImpl) SubmitCourse(courseID uuid.UUID) (*model.Course, error) {
+	course, err := c.getCourse(courseID)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.checkPublishable(course); err != nil {
+		return nil, err
+	}
+	return c.transition(course, model.CourseStatusInReview)
+}
+
+// WithdrawCourse implements CourseService. It takes a course out of review, including an approved one
+// waiting for its release time.
+func (c *courseServiceImpl) WithdrawCourse(courseID uuid.UUID) (*model.Course, error) {
+	course, err := c.getCourse(courseID)
+	if err != nil {
+		return nil, err
+	}
+	if course.Status != model.CourseStatusInReview {
+		return nil, ErrCourseNotInReview
+	}
+	return c.transition(course, model.CourseStatusDraft)
+}
+
+// ReviewCourse implements CourseService. An approved course goes live at once, or at its publish_at
+// if that is still ahead; a rejected one returns to draft with the reason.
+func (c *courseServiceImpl) ReviewCourse(reviewer Actor, courseID uuid.UUID, approve bool, reason string) (*model.Course, error) {
+	reason = strings.TrimSpace(reason)
+	if !approve && reason == "" {
+		return nil, ErrRejectionReasonRequired
+	}
+
+	course, err := c.getCourse(courseID)
+	if err != nil {
+		return nil, err
+	}
+	if course.Status != model.CourseStatusInReview || course.ApprovedAt != nil {
+		return nil, ErrCourseNotInReview
+	}
+	if approve {
+		// Lessons may have been removed since it was submitted
+		if err := c.checkPublishable(course); err != nil {
+			return nil, err
+		}
+	}
+
+	status, err := c.repo.Review(courseID, reviewer.UserID, approve, reason)
+	if err != nil {
+		return nil, fmt.Errorf("failed to review course: %v", err)
+	}
+	if status == "" {
+		return nil, ErrCourseNotInReview
+	}
+
+	course, err = c.getCourse(courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Course %s reviewed by %s: %s", courseID, reviewer.UserID, status)
+	if status == model.CourseStatusPublished {
+		c.coursePublished(course)
+	}
+	return course, nil
+}
+
+// ArchiveCourse implements CourseService.
+func (c *courseServiceImpl) ArchiveCourse(courseID uuid.UUID) (*model.Course, error) {
+	course, err := c.getCourse(courseID)
+	if err != nil {
+		return nil, err
+	}
+	return c.transition(course, model.CourseStatusArchived)
+}
+
+// RestoreCourse implements CourseService. An archived course comes back as a draft.
+func (c *courseServiceImpl) RestoreCourse(courseID uuid.UUID) (*model.Course, error) {
+	course, err := c.getCourse(courseID)
+	if err != nil {
+		return nil, err
+	}
+	return c.transition(course, model.CourseStatusDraft)
+}
+
+// ScheduleCourse implements CourseService. publishAt delays the release of an approved course and
+// unpublishAt archives it later; nil clears either. Clearing publishAt on an approved course releases it now.
+func (c *courseServiceImpl) ScheduleCourse(courseID uuid.UUID, publishAt, unpublishAt *time.Time) (*model.Course, error) {
+	now := time.Now()
+	if publishAt != nil && !publishAt.After(now) {
+		return nil, ErrInvalidSchedule
+	}
+	if unpublishAt != nil && (!unpublishAt.After(now) || (publishAt != nil && !unpublishAt.After(*publishAt))) {
+		return nil, ErrInvalidSchedule
+	}
+
+	course, err := c.getCourse(courseID)
+	if err != nil {
+		return nil, err
+	}
+	switch course.Status {
+	case model.CourseStatusArchived:
+		return nil, ErrInvalidCourseTransition
+	case model.CourseStatusPublished:
+		// Already live, so only the withdrawal time applies
+		if publishAt != nil {
+			return nil, ErrInvalidSchedule
+		}
+	}
+
+	scheduled, err := c.repo.Schedule(courseID, publishAt, unpublishAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to schedule course: %v", err)
+	}
+	if !scheduled {
+		return nil, ErrCourseNotFound
+	}
+
+	if course.Status == model.CourseStatusInReview && course.ApprovedAt != nil && publishAt == nil {
+		return c.transition(course, model.CourseStatusPublished)
+	}
+	return c.getCourse(courseID)
+}
+
+// ListReviewQueue implements CourseService. Courses waiting longest come first by default.
+func (c *courseServiceImpl) ListReviewQueue(page model.PageRequest) (*model.Page[*model.Course], error) {
+	if page.Sort == "" {
+		page.Sort = "submitted_at"
+	}
+	return c.repo.List(model.CourseFilter{AwaitingReview: true}, page)
+}
+
+// ProcessScheduledReleases implements CourseService. It publishes approved courses whose publish_at
+// has come and archives published courses whose unpublish_at has come.
+func (c *courseServiceImpl) ProcessScheduledReleases(limit int) (published, archived int, err error) {
+	courses, err := c.repo.ListDueReleases(limit)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list due course releases: %v", err)
+	}
+
+	for _, course := range courses {
+		to := model.CourseStatusPublished
+		if course.Status == model.CourseStatusPublished {
+			to = model.CourseStatusArchived
+		}
+
+		if _, err := c.transition(course, to); err != nil {
+			// Changed since it was listed; the next run picks it up again if still due
+			log.Printf("Scheduled release of course %s to %s skipped: %v", course.ID, to, err)
+			continue
+		}
+		if to == model.CourseStatusPublished {
+			published++
+		} else {
+			archived++
+		}
+	}
+	return published, archived, nil
+}
+
+// transition moves the course to the given status if the workflow allows it and runs the
+// publish hooks when it goes live
+func (c *courseServiceImpl) transition(course *model.Course, to string) (*model.Course, error) {
+	if !canTransition(course.Status, to) {
+		return nil, ErrInvalidCourseTransition
+	}
+
+	moved, err := c.repo.Transition(course.ID, course.Status, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to change course status: %v", err)
+	}
+	if !moved {
+		// Another request changed it first
+		return nil, ErrInvalidCourseTransition
+	}
+
+	updated, err := c.getCourse(course.ID)
+	if err != nil {
+		return nil, err
+	}
+	if to == model.CourseStatusPublished {
+		c.coursePublished(updated)
+	}
+	return updated, nil
+}
+
+// checkPublishable makes sure the course has a cover image and at least one lesson
+func (c *courseServiceImpl) checkPublishable(course *model.Course) error {
+	if len(course.CoverImageURL) == 0 {
+		return ErrCourseHasNoCover
+	}
+
+	lessons, err := c.repo.CountLessons(course.ID)
+	if err != nil {
+		return fmt.Errorf("failed to count lessons: %v", err)
+	}
+	if lessons == 0 {
+		return ErrCourseHasNoLessons
+	}
+	return nil
+}
+
+func (c *courseServiceImpl) getCourse(courseID uuid.UUID) (*model.Course, error) {
+	course, err := c.repo.GetByID(courseID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, ErrCourseNotFound
+		}
+		return nil, err
+	}
+	return course, nil
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"kaabe-app/internal/domain/model"
+	"testing"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{model.CourseStatusDraft, model.CourseStatusInReview, true},
+		{model.CourseStatusInReview, model.CourseStatusDraft, true},
+		{model.CourseStatusInReview, model.CourseStatusPublished, true},
+		{model.CourseStatusPublished, model.CourseStatusArchived, true},
+		{model.CourseStatusArchived, model.CourseStatusDraft, true},
+
+		// Publishing only happens through review
+		{model.CourseStatusDraft, model.CourseStatusPublished, false},
+		{model.CourseStatusArchived, model.CourseStatusPublished, false},
+		{model.CourseStatusArchived, model.CourseStatusInReview, false},
+		{model.CourseStatusPublished, model.CourseStatusDraft, false},
+		{model.CourseStatusPublished, model.CourseStatusInReview, false},
+		{model.CourseStatusDraft, model.CourseStatusArchived, false},
+		{model.CourseStatusInReview, model.CourseStatusArchived, false},
+
+		// Staying put is not a transition
+		{model.CourseStatusDraft, model.CourseStatusDraft, false},
+		{model.CourseStatusPublished, model.CourseStatusPublished, false},
+
+		{"", model.CourseStatusInReview, false},
+		{model.CourseStatusDraft, "deleted", false},
+	}
+
+	for _, tt := range tests {
+		if got := canTransition(tt.from, tt.to); got != tt.want {
+			t.Errorf("canTransition(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"kaabe-app/internal/domain/model"
+	"log"
+
+	"github.com/gofrs/uuid"
+)
+
+// Revision errors
+var (
+	ErrCourseLive         = errors.New("course is published; make changes in its draft revision")
+	ErrCourseNotPublished = errors.New("course is not published; edit it directly")
+	ErrNoCourseDraft      = errors.New("course has no draft revision")
+	ErrRevisionNotFound   = errors.New("revision not found")
+	ErrInvalidRevision    = fmt.Errorf("a revision takes at most %d lessons, each listed once", model.MaxRevisionLessons)
+)
+
+// StartCourseDraft implements CourseService. It returns the course's draft, starting one from the
+// live course if there is none.
+func (c *courseServiceImpl) StartCourseDraft(actor Actor, courseID uuid.UUID) (*model.CourseRevision, error) {
+	if err := c.requirePublished(courseID); err != nil {
+		return nil, err
+	}
+
+	draft, err := c.revisionRepo.CreateDraft(courseID, actor.UserID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, ErrCourseNotFound
+		}
+		return nil, fmt.Errorf("failed to start course draft: %v", err)
+	}
+	return draft, nil
+}
+
+// GetCourseDraft implements CourseService.
+func (c *courseServiceImpl) GetCourseDraft(courseID uuid.UUID) (*model.CourseRevision, error) {
+	draft, err := c.revisionRepo.GetDraft(courseID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, ErrNoCourseDraft
+		}
+		return nil, err
+	}
+	return draft, nil
+}
+
+// SaveCourseDraft implements CourseService. The draft's content and lessons are replaced as a whole;
+// lessons keep the ID of the live lesson they change, and new ones have none.
+func (c *courseServiceImpl) SaveCourseDraft(draft *model.CourseRevision) (*model.CourseRevision, error) {
+	if len(draft.Lessons) > model.MaxRevisionLessons {
+		return nil, ErrInvalidRevision
+	}
+	seen := make(map[uuid.UUID]bool, len(draft.Lessons))
+	for _, lesson := range draft.Lessons {
+		if lesson.ID == nil {
+			continue
+		}
+		if seen[*lesson.ID] {
+			return nil, ErrInvalidRevision
+		}
+		seen[*lesson.ID] = true
+	}
+
+	saved, err := c.revisionRepo.SaveDraft(draft)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save course draft: %v", err)
+	}
+	if !saved {
+		return nil, ErrNoCourseDraft
+	}
+	return c.GetCourseDraft(draft.CourseID)
+}
+
+// DiscardCourseDraft implements CourseService.
+func (c *courseServiceImpl) DiscardCourseDraft(courseID uuid.UUID) error {
+	deleted, err := c.revisionRepo.DeleteDraft(courseID)
+	if err != nil {
+		return fmt.Errorf("failed to discard course draft: %v", err)
+	}
+	if !deleted {
+		return ErrNoCourseDraft
+	}
+	return nil
+}
+
+// PublishCourseDraft implements CourseService. The actor must be a reviewer: a draft going live skips
+// the in_review queue, so publishing it is the review. The course and its lessons change in one
+// transaction, so subscribers see either the old version or the new one.
+func (c *courseServiceImpl) PublishCourseDraft(actor Actor, courseID uuid.UUID) (*model.CourseRevision, error) {
+	if err := c.requirePublished(courseID); err != nil {
+		return nil, err
+	}
+
+	draft, err := c.GetCourseDraft(courseID)
+	if err != nil {
+		return nil, err
+	}
+	// The same bar as a course going live through review
+	if len(draft.CoverImageURL) == 0 {
+		return nil, ErrCourseHasNoCover
+	}
+	if len(draft.Lessons) == 0 {
+		return nil, ErrCourseHasNoLessons
+	}
+
+	version, err := c.revisionRepo.PublishDraft(courseID, actor.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to publish course draft: %v", err)
+	}
+	if version == 0 {
+		// Unpublished or published by another request in the meantime
+		return nil, ErrNoCourseDraft
+	}
+	return c.GetCourseRevision(courseID, version)
+}
+
+// ListCourseRevisions implements CourseService.
+func (c *courseServiceImpl) ListCourseRevisions(courseID uuid.UUID, page model.PageRequest) (*model.Page[*model.CourseRevision], error) {
+	revisions, err := c.revisionRepo.ListVersions(courseID, page)
+	if err != nil {
+		return nil, listError("course revisions", err)
+	}
+	return revisions, nil
+}
+
+// GetCourseRevision implements CourseService.
+func (c *courseServiceImpl) GetCourseRevision(courseID uuid.UUID, version int) (*model.CourseRevision, error) {
+	revision, err := c.revisionRepo.GetVersion(courseID, version)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, ErrRevisionNotFound
+		}
+		return nil, err
+	}
+	return revision, nil
+}
+
+// RollbackCourse implements CourseService. The chosen version goes live again and is recorded as the
+// newest version; any draft is kept.
+func (c *courseServiceImpl) RollbackCourse(actor Actor, courseID uuid.UUID, version int) (*model.CourseRevision, error) {
+	if err := c.requirePublished(courseID); err != nil {
+		return nil, err
+	}
+	if _, err := c.GetCourseRevision(courseID, version); err != nil {
+		return nil, err
+	}
+
+	newVersion, err := c.revisionRepo.Rollback(courseID, version, actor.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to roll back course: %v", err)
+	}
+	if newVersion == 0 {
+		return nil, ErrCourseNotPublished
+	}
+
+	log.Printf("Course %s rolled back to version %d by %s", courseID, version, actor.UserID)
+	return c.GetCourseRevision(courseID, newVersion)
+}
+
+// requirePublished fails unless the course is live; only live courses are edited through revisions
+func (c *courseServiceImpl) requirePublished(courseID uuid.UUID) error {
+	course, err := c.getCourse(courseID)
+	if err != nil {
+		return err
+	}
+	if course.Status != model.CourseStatusPublished {
+		return ErrCourseNotPublished
+	}
+	return nil
+}
@@ -1,33 +1,114 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"kaabe-app/internal/domain/model"
 	"kaabe-app/internal/domain/repository"
 
 	"log"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/gofrs/uuid"
 )
 
 // CourseService interface
 type CourseService interface {
-	CreateCourse(InfluencerID uuid.UUID, Title, Description string, Price float64, CoverImageURL []string, Status string) (*model.Course, error)
+	// CreateCourse creates a draft; UpdateCourse edits a course's content but never its status
+	CreateCourse(InfluencerID uuid.UUID, Title, Description string, Price model.Money, CoverImageURL []string) (*model.Course, error)
 	UpdateCourse(course *model.Course) error
 	DeleteCourse(courseID uuid.UUID) error
 	GetCourseByID(courseID uuid.UUID) (*model.Course, error)
-	GetAllCourses() ([]*model.Course, error)
+	GetAllCourses(filter model.CourseFilter, page model.PageRequest) (*model.Page[*model.Course], error)
+	// ListVisibleCourses and GetVisibleCourse serve the public catalog: published courses for
+	// everyone, drafts and archived courses only for their owner and admins.
+	// A zero Actor is an anonymous visitor.
+	ListVisibleCourses(viewer Actor, filter model.CourseFilter, page model.PageRequest) (*model.Page[*model.Course], error)
+	GetVisibleCourse(viewer Actor, courseID uuid.UUID) (*model.Course, error)
+	// SearchCourses runs a full-text search over the courses the viewer may see
+	SearchCourses(viewer Actor, search model.CourseSearch, page model.PageRequest) (*model.CourseSearchResult, error)
+
+	// Taxonomy: categories, tags and curated collections
+	SetCourseTaxonomy(courseID uuid.UUID, categoryID *uuid.UUID, tags []string) (*model.Course, error)
+	ListCategoryTree() ([]*model.Category, error)
+	CreateCategory(category *model.Category) error
+	UpdateCategory(category *model.Category) error
+	DeleteCategory(categoryID uuid.UUID) error
+	ListPopularTags(limit int) ([]*model.TagCount, error)
+	ListCollections() ([]*model.Collection, error)
+	// GetCollection returns a collection by slug with its published courses in display order
+	GetCollection(slug string) (*model.Collection, []*model.Course, error)
+	CreateCollection(collection *model.Collection) error
+	UpdateCollection(collection *model.Collection) error
+	DeleteCollection(collectionID uuid.UUID) error
+	SetCollectionCourses(collectionID uuid.UUID, courseIDs []uuid.UUID) error
+
+	// Publishing workflow
+	SubmitCourse(courseID uuid.UUID) (*model.Course, error)
+	WithdrawCourse(courseID uuid.UUID) (*model.Course, error)
+	ReviewCourse(reviewer Actor, courseID uuid.UUID, approve bool, reason string) (*model.Course, error)
+	ArchiveCourse(courseID uuid.UUID) (*model.Course, error)
+	RestoreCourse(courseID uuid.UUID) (*model.Course, error)
+	ScheduleCourse(courseID uuid.UUID, publishAt, unpublishAt *time.Time) (*model.Course, error)
+	ListReviewQueue(page model.PageRequest) (*model.Page[*model.Course], error)
+	ProcessScheduledReleases(limit int) (published, archived int, err error)
+
+	// Revisions: a published course changes only by publishing its draft or rolling back
+	StartCourseDraft(actor Actor, courseID uuid.UUID) (*model.CourseRevision, error)
+	GetCourseDraft(courseID uuid.UUID) (*model.CourseRevision, error)
+	SaveCourseDraft(draft *model.CourseRevision) (*model.CourseRevision, error)
+	DiscardCourseDraft(courseID uuid.UUID) error
+	PublishCourseDraft(actor Actor, courseID uuid.UUID) (*model.CourseRevision, error)
+	ListCourseRevisions(courseID uuid.UUID, page model.PageRequest) (*model.Page[*model.CourseRevision], error)
+	GetCourseRevision(courseID uuid.UUID, version int) (*model.CourseRevision, error)
+	RollbackCourse(actor Actor, courseID uuid.UUID, version int) (*model.CourseRevision, error)
+
+	// Pricing: a base price plus a price list in other currencies, with every change kept in the history
+	SetCoursePrice(courseID uuid.UUID, price model.Money) (*model.Course, error)
+	DeleteCoursePrice(courseID uuid.UUID, currency string) (*model.Course, error)
+	ListCoursePriceHistory(courseID uuid.UUID, currency string, page model.PageRequest) (*model.Page[*model.CoursePriceChange], error)
+}
+
+// ErrCourseNotFound is returned for missing courses and for courses the viewer may not see
+var ErrCourseNotFound = errors.New("course not found")
+
+// ErrInvalidSearchQuery is returned for an empty or overlong search text
+var ErrInvalidSearchQuery = fmt.Errorf("search query must be between 1 and %d characters", model.MaxSearchQueryLength)
+
+// CoursePublishedHook is told about every course that goes live, whether on approval or at its
+// scheduled release time
+type CoursePublishedHook interface {
+	OnCoursePublished(course *model.Course)
 }
 
 // courseServiceImpl struct implementing CourseService
 type courseServiceImpl struct {
-	repo      repository.CourseRepository
-	tokenRepo repository.TokenRepository
+	repo            repository.CourseRepository
+	tokenRepo       repository.TokenRepository
+	applicationRepo repository.InfluencerApplicationRepository
+	catalogRepo     repository.CatalogRepository
+	revisionRepo    repository.CourseRevisionRepository
+	publishedHooks  []CoursePublishedHook
 }
 
 // CreateCourse implements CourseService.
-func (c *courseServiceImpl) CreateCourse(InfluencerID uuid.UUID, Title string, Description string, Price float64 , CoverImageURL []string, Status string) (*model.Course, error) {
+func (c *courseServiceImpl) CreateCourse(InfluencerID uuid.UUID, Title string, Description string, Price model.Money, CoverImageURL []string) (*model.Course, error) {
+	Price, err := model.NewMoney(Price.Amount, Price.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only admins and influencers whose application was approved may own courses
+	approved, err := c.applicationRepo.IsApprovedCreator(InfluencerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check creator approval: %v", err)
+	}
+	if !approved {
+		return nil, ErrInfluencerNotApproved
+	}
+
 	// Generate a new UUID for the course ID
 	neoCourse, err := uuid.NewV4()
 	if err != nil {
@@ -42,7 +123,7 @@ func (c *courseServiceImpl) CreateCourse(InfluencerID uuid.UUID, Title string, D
 		Description:   Description,
 		Price:         Price,
 		CoverImageURL: CoverImageURL,
-		Status:        Status,
+		Status:        model.CourseStatusDraft,
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
 	}
@@ -75,12 +156,12 @@ func (c *courseServiceImpl) DeleteCourse(courseID uuid.UUID) error {
 }
 
 // GetAllCourses implements CourseService.
-func (c *courseServiceImpl) GetAllCourses() ([]*model.Course, error) {
-	course, err := c.repo.GetAll()
+func (c *courseServiceImpl) GetAllCourses(filter model.CourseFilter, page model.PageRequest) (*model.Page[*model.Course], error) {
+	courses, err := c.repo.List(filter, page)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get all courses: %v", err)
+		return nil, listError("courses", err)
 	}
-	return course, nil
+	return courses, nil
 }
 
 // GetCourseByID implements CourseService.
@@ -92,13 +173,74 @@ func (c *courseServiceImpl) GetCourseByID(courseID uuid.UUID) (*model.Course, er
 	return course, nil
 }
 
+// ListVisibleCourses implements CourseService.
+func (c *courseServiceImpl) ListVisibleCourses(viewer Actor, filter model.CourseFilter, page model.PageRequest) (*model.Page[*model.Course], error) {
+	if !viewer.IsAdmin() {
+		filter.VisibleTo = &viewer.UserID
+	}
+	return c.GetAllCourses(filter, page)
+}
+
+// GetVisibleCourse implements CourseService.
+func (c *courseServiceImpl) GetVisibleCourse(viewer Actor, courseID uuid.UUID) (*model.Course, error) {
+	course, err := c.repo.GetByID(courseID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, ErrCourseNotFound
+		}
+		return nil, fmt.Errorf("could not find course with ID %s: %v", courseID, err)
+	}
+
+	// Unpublished courses are reported as missing so their existence does not leak
+	if course.Status != model.CourseStatusPublished && !viewer.IsAdmin() && course.InfluencerID != viewer.UserID {
+		return nil, ErrCourseNotFound
+	}
+	return course, nil
+}
+
+// SearchCourses implements CourseService.
+func (c *courseServiceImpl) SearchCourses(viewer Actor, search model.CourseSearch, page model.PageRequest) (*model.CourseSearchResult, error) {
+	search.Query = strings.TrimSpace(search.Query)
+	if search.Query == "" || utf8.RuneCountInString(search.Query) > model.MaxSearchQueryLength {
+		return nil, ErrInvalidSearchQuery
+	}
+
+	if !viewer.IsAdmin() {
+		search.VisibleTo = &viewer.UserID
+	}
+
+	result, err := c.repo.Search(search, page)
+	if err != nil {
+		return nil, listError("course search results", err)
+	}
+	return result, nil
+}
+
 // UpdateCourse implements CourseService.
 func (c *courseServiceImpl) UpdateCourse(course *model.Course) error {
-	_, err := c.repo.GetByID(course.ID)
+	price, err := model.NewMoney(course.Price.Amount, course.Price.Currency)
+	if err != nil {
+		return err
+	}
+	course.Price = price
+
+	existing, err := c.repo.GetByID(course.ID)
 	if err != nil {
 		return fmt.Errorf("could not find course with ID %s", course.ID)
 	}
 
+	// The reviewer approves what was submitted, so it cannot change underneath them
+	if existing.Status == model.CourseStatusInReview {
+		return ErrCourseInReview
+	}
+	// Subscribers may be mid-lesson, so a live course only changes through its draft revision
+	if existing.Status == model.CourseStatusPublished {
+		return ErrCourseLive
+	}
+
+	// Status only changes through the publishing workflow
+	course.Status = existing.Status
+
 	if err := c.repo.Update(course); err != nil {
 		return fmt.Errorf("failed to update course with ID %s: %v", course.ID, err)
 	}
@@ -106,10 +248,21 @@ func (c *courseServiceImpl) UpdateCourse(course *model.Course) error {
 	return nil
 }
 
-// NewCourseService creates a new instance of CourseService
-func NewCourseService(coureRepo repository.CourseRepository, tokenRepo repository.TokenRepository) CourseService {
+// coursePublished runs the registered hooks for a newly published course
+func (c *courseServiceImpl) coursePublished(course *model.Course) {
+	for _, hook := range c.publishedHooks {
+		hook.OnCoursePublished(course)
+	}
+}
+
+// NewCourseService creates a new instance of CourseService; publishedHooks run whenever a course is published
+func NewCourseService(coureRepo repository.CourseRepository, tokenRepo repository.TokenRepository, applicationRepo repository.InfluencerApplicationRepository, catalogRepo repository.CatalogRepository, revisionRepo repository.CourseRevisionRepository, publishedHooks ...CoursePublishedHook) CourseService {
 	return &courseServiceImpl{
-		repo:      coureRepo,
-		tokenRepo: tokenRepo,
+		repo:            coureRepo,
+		tokenRepo:       tokenRepo,
+		applicationRepo: applicationRepo,
+		catalogRepo:     catalogRepo,
+		revisionRepo:    revisionRepo,
+		publishedHooks:  publishedHooks,
 	}
 }
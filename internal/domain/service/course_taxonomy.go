@@ -0,0 +1,316 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"kaabe-app/internal/domain/model"
+	"log"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gofrs/uuid"
+)
+
+// Taxonomy errors
+var (
+	ErrCategoryNotFound    = errors.New("category not found")
+	ErrCategoryCycle       = errors.New("a category cannot be nested under itself or one of its subcategories")
+	ErrCategoryHasChildren = errors.New("category has subcategories; move or delete them first")
+	ErrCollectionNotFound  = errors.New("collection not found")
+	ErrSlugTaken           = errors.New("slug already in use")
+	ErrInvalidSlug         = errors.New("slug may only contain lowercase letters, digits and single hyphens")
+	ErrInvalidTags         = fmt.Errorf("a course takes at most %d tags of up to %d characters", model.MaxCourseTags, model.MaxTagNameLength)
+)
+
+var (
+	slugPattern   = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	slugSeparator = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// SetCourseTaxonomy implements CourseService.
+func (c *courseServiceImpl) SetCourseTaxonomy(courseID uuid.UUID, categoryID *uuid.UUID, tags []string) (*model.Course, error) {
+	tags, err := normalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := c.repo.SetTaxonomy(courseID, categoryID, tags)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, fmt.Errorf("failed to set course taxonomy: %v", err)
+	}
+	if !updated {
+		return nil, ErrCourseNotFound
+	}
+
+	return c.repo.GetByID(courseID)
+}
+
+// ListCategoryTree implements CourseService. It returns the top-level categories with their
+// subcategories nested under Children.
+func (c *courseServiceImpl) ListCategoryTree() ([]*model.Category, error) {
+	categories, err := c.catalogRepo.ListCategories()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %v", err)
+	}
+
+	byID := make(map[uuid.UUID]*model.Category, len(categories))
+	for _, category := range categories {
+		byID[category.ID] = category
+	}
+
+	roots := make([]*model.Category, 0)
+	for _, category := range categories {
+		if category.ParentID == nil {
+			roots = append(roots, category)
+			continue
+		}
+		if parent, ok := byID[*category.ParentID]; ok {
+			parent.Children = append(parent.Children, category)
+		}
+	}
+	return roots, nil
+}
+
+// CreateCategory implements CourseService.
+func (c *courseServiceImpl) CreateCategory(category *model.Category) error {
+	if err := prepareSlug(&category.Slug, category.Name); err != nil {
+		return err
+	}
+	if category.ParentID != nil {
+		if _, err := c.getCategory(*category.ParentID); err != nil {
+			return err
+		}
+	}
+
+	if err := c.catalogRepo.CreateCategory(category); err != nil {
+		return categoryError(err)
+	}
+
+	log.Printf("Category created: %s (%s)", category.Slug, category.ID)
+	return nil
+}
+
+// UpdateCategory implements CourseService.
+func (c *courseServiceImpl) UpdateCategory(category *model.Category) error {
+	if err := prepareSlug(&category.Slug, category.Name); err != nil {
+		return err
+	}
+	if category.ParentID != nil {
+		if err := c.checkCategoryParent(category.ID, *category.ParentID); err != nil {
+			return err
+		}
+	}
+
+	if err := c.catalogRepo.UpdateCategory(category); err != nil {
+		return categoryError(err)
+	}
+	return nil
+}
+
+// DeleteCategory implements CourseService. Courses in the category become uncategorized.
+func (c *courseServiceImpl) DeleteCategory(categoryID uuid.UUID) error {
+	deleted, err := c.catalogRepo.DeleteCategory(categoryID)
+	if err != nil {
+		return categoryError(err)
+	}
+	if !deleted {
+		return ErrCategoryNotFound
+	}
+	return nil
+}
+
+// ListPopularTags implements CourseService.
+func (c *courseServiceImpl) ListPopularTags(limit int) ([]*model.TagCount, error) {
+	tags, err := c.catalogRepo.ListPopularTags(limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %v", err)
+	}
+	return tags, nil
+}
+
+// ListCollections implements CourseService.
+func (c *courseServiceImpl) ListCollections() ([]*model.Collection, error) {
+	collections, err := c.catalogRepo.ListCollections()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %v", err)
+	}
+	return collections, nil
+}
+
+// GetCollection implements CourseService.
+func (c *courseServiceImpl) GetCollection(slug string) (*model.Collection, []*model.Course, error) {
+	collection, err := c.catalogRepo.GetCollectionBySlug(slug)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, nil, ErrCollectionNotFound
+		}
+		return nil, nil, err
+	}
+
+	courses, err := c.catalogRepo.ListCollectionCourses(collection.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list collection courses: %v", err)
+	}
+	return collection, courses, nil
+}
+
+// CreateCollection implements CourseService.
+func (c *courseServiceImpl) CreateCollection(collection *model.Collection) error {
+	if err := prepareSlug(&collection.Slug, collection.Name); err != nil {
+		return err
+	}
+
+	if err := c.catalogRepo.CreateCollection(collection); err != nil {
+		return collectionError(err)
+	}
+
+	log.Printf("Collection created: %s (%s)", collection.Slug, collection.ID)
+	return nil
+}
+
+// UpdateCollection implements CourseService.
+func (c *courseServiceImpl) UpdateCollection(collection *model.Collection) error {
+	if err := prepareSlug(&collection.Slug, collection.Name); err != nil {
+		return err
+	}
+
+	if err := c.catalogRepo.UpdateCollection(collection); err != nil {
+		return collectionError(err)
+	}
+	return nil
+}
+
+// DeleteCollection implements CourseService.
+func (c *courseServiceImpl) DeleteCollection(collectionID uuid.UUID) error {
+	deleted, err := c.catalogRepo.DeleteCollection(collectionID)
+	if err != nil {
+		return fmt.Errorf("failed to delete collection: %v", err)
+	}
+	if !deleted {
+		return ErrCollectionNotFound
+	}
+	return nil
+}
+
+// SetCollectionCourses implements CourseService. The courses are shown in the given order;
+// repeated IDs keep their first position.
+func (c *courseServiceImpl) SetCollectionCourses(collectionID uuid.UUID, courseIDs []uuid.UUID) error {
+	if _, err := c.catalogRepo.GetCollection(collectionID); err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return ErrCollectionNotFound
+		}
+		return err
+	}
+
+	seen := make(map[uuid.UUID]bool, len(courseIDs))
+	ordered := make([]uuid.UUID, 0, len(courseIDs))
+	for _, courseID := range courseIDs {
+		if seen[courseID] {
+			continue
+		}
+		seen[courseID] = true
+
+		if _, err := c.repo.GetByID(courseID); err != nil {
+			if errors.Is(err, model.ErrNotFound) {
+				return fmt.Errorf("%w: %s", ErrCourseNotFound, courseID)
+			}
+			return err
+		}
+		ordered = append(ordered, courseID)
+	}
+
+	return c.catalogRepo.SetCollectionCourses(collectionID, ordered)
+}
+
+func (c *courseServiceImpl) getCategory(categoryID uuid.UUID) (*model.Category, error) {
+	category, err := c.catalogRepo.GetCategory(categoryID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, err
+	}
+	return category, nil
+}
+
+// checkCategoryParent makes sure moving the category under parentID keeps the hierarchy a tree
+func (c *courseServiceImpl) checkCategoryParent(categoryID, parentID uuid.UUID) error {
+	categories, err := c.catalogRepo.ListCategories()
+	if err != nil {
+		return fmt.Errorf("failed to list categories: %v", err)
+	}
+
+	parents := make(map[uuid.UUID]*uuid.UUID, len(categories))
+	for _, category := range categories {
+		parents[category.ID] = category.ParentID
+	}
+	if _, ok := parents[parentID]; !ok {
+		return ErrCategoryNotFound
+	}
+
+	// Walk up from the new parent; reaching the category itself means it would become its own ancestor
+	for id := &parentID; id != nil; id = parents[*id] {
+		if *id == categoryID {
+			return ErrCategoryCycle
+		}
+	}
+	return nil
+}
+
+// prepareSlug derives the slug from name when it is empty and checks its format
+func prepareSlug(slug *string, name string) error {
+	if *slug == "" {
+		*slug = strings.Trim(slugSeparator.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	}
+	if !slugPattern.MatchString(*slug) {
+		return ErrInvalidSlug
+	}
+	return nil
+}
+
+// normalizeTags lower-cases tags, collapses their whitespace and drops empty and repeated ones
+func normalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.Join(strings.Fields(strings.ToLower(tag)), " ")
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > model.MaxTagNameLength {
+			return nil, ErrInvalidTags
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > model.MaxCourseTags {
+		return nil, ErrInvalidTags
+	}
+	return normalized, nil
+}
+
+func categoryError(err error) error {
+	switch err.Error() {
+	case "category not found", "parent category not found":
+		return ErrCategoryNotFound
+	case "slug already in use":
+		return ErrSlugTaken
+	case "category has subcategories":
+		return ErrCategoryHasChildren
+	}
+	return err
+}
+
+func collectionError(err error) error {
+	switch err.Error() {
+	case "collection not found":
+		return ErrCollectionNotFound
+	case "slug already in use":
+		return ErrSlugTaken
+	}
+	return err
+}
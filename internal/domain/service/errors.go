@@ -0,0 +1,24 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"kaabe-app/internal/domain/model"
+)
+
+// ErrForbidden is returned when the caller is authenticated but not allowed to perform an action
+var ErrForbidden = errors.New("forbidden")
+
+// ErrNotFound is wrapped by AccessPolicy errors when the resource being authorized does not exist
+var ErrNotFound = errors.New("not found")
+
+// ErrEmailNotVerified is returned when an action requires a verified email address or phone number
+var ErrEmailNotVerified = errors.New("email address not verified")
+
+// listError wraps a repository list failure, keeping bad cursors and sort fields recognisable
+func listError(what string, err error) error {
+	if errors.Is(err, model.ErrInvalidCursor) || errors.Is(err, model.ErrInvalidSort) {
+		return err
+	}
+	return fmt.Errorf("failed to list %s: %v", what, err)
+}
@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+	"kaabe-app/internal/domain/model"
+)
+
+// IdentityProvider is an external OpenID Connect provider used for social login.
+// The OIDC client implementation lives in the gateway package.
+type IdentityProvider interface {
+	// Name is the key used in URLs and in user_identities.provider, e.g. "google"
+	Name() string
+
+	// AuthCodeURL returns the provider page the user is sent to; the provider echoes
+	// state back on the redirect and puts nonce in the ID token
+	AuthCodeURL(state, nonce string) (string, error)
+
+	// Exchange trades an authorization code for a verified ID token and returns its claims
+	Exchange(ctx context.Context, code, nonce string) (*model.ExternalIdentity, error)
+}
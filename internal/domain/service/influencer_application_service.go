@@ -0,0 +1,155 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/repository"
+	utils "kaabe-app/pkg/config"
+	"log"
+	"strings"
+
+	"github.com/gofrs/uuid"
+)
+
+// Influencer onboarding errors
+var (
+	ErrInfluencerApplicationRequired = errors.New("influencer accounts are granted after review; register as a user and apply")
+	ErrAlreadyInfluencer             = errors.New("user is already an influencer")
+	ErrApplicationPending            = errors.New("an application is already pending review")
+	ErrApplicationNotFound           = errors.New("application not found")
+	ErrApplicationReviewed           = errors.New("application has already been reviewed")
+	ErrReviewNoteRequired            = errors.New("a note is required when rejecting an application")
+	ErrInfluencerNotApproved         = errors.New("creator is not an approved influencer")
+)
+
+// InfluencerApplicationService runs influencer onboarding: users apply, admins approve or reject,
+// and approval grants the influencer role
+type InfluencerApplicationService interface {
+	Apply(userID uuid.UUID, bio, payoutWallet string, idDocumentURLs []string) (*model.InfluencerApplication, error)
+	GetOwnApplication(userID uuid.UUID) (*model.InfluencerApplication, error)
+	GetApplication(applicationID uuid.UUID) (*model.InfluencerApplication, error)
+	ListApplications(status string) ([]*model.InfluencerApplication, error)
+	Approve(applicationID, reviewerID uuid.UUID, note string) (*model.InfluencerApplication, error)
+	Reject(applicationID, reviewerID uuid.UUID, note string) (*model.InfluencerApplication, error)
+}
+
+type influencerApplicationService struct {
+	repo               repository.InfluencerApplicationRepository
+	userRepo           repository.UserRepository
+	notifier           NotificationService
+	defaultCountryCode string
+}
+
+// NewInfluencerApplicationService creates an InfluencerApplicationService; defaultCountryCode is
+// used for payout numbers entered without an international prefix
+func NewInfluencerApplicationService(repo repository.InfluencerApplicationRepository, userRepo repository.UserRepository, notifier NotificationService, defaultCountryCode string) InfluencerApplicationService {
+	return &influencerApplicationService{
+		repo:               repo,
+		userRepo:           userRepo,
+		notifier:           notifier,
+		defaultCountryCode: defaultCountryCode,
+	}
+}
+
+// Apply submits an application for review
+func (s *influencerApplicationService) Apply(userID uuid.UUID, bio, payoutWallet string, idDocumentURLs []string) (*model.InfluencerApplication, error) {
+	user, err := s.userRepo.Get(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if user.Role != model.RoleUser {
+		return nil, ErrAlreadyInfluencer
+	}
+	// Payouts go to whoever proves they own the account, so the contact must be verified first
+	if !user.HasVerifiedContact() {
+		return nil, ErrEmailNotVerified
+	}
+
+	wallet, err := utils.NormalizePhoneNumber(payoutWallet, s.defaultCountryCode)
+	if err != nil {
+		return nil, err
+	}
+
+	application := &model.InfluencerApplication{
+		UserID:         userID,
+		Bio:            strings.TrimSpace(bio),
+		PayoutWallet:   wallet,
+		IDDocumentURLs: idDocumentURLs,
+	}
+	if err := s.repo.Create(application); err != nil {
+		if errors.Is(err, model.ErrApplicationOpen) {
+			return nil, ErrApplicationPending
+		}
+		return nil, err
+	}
+
+	log.Printf("Influencer application %s submitted by user %s", application.ID, userID)
+	return application, nil
+}
+
+// GetOwnApplication returns the user's most recent application
+func (s *influencerApplicationService) GetOwnApplication(userID uuid.UUID) (*model.InfluencerApplication, error) {
+	application, err := s.repo.FindLatestByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get application: %v", err)
+	}
+	if application == nil {
+		return nil, ErrApplicationNotFound
+	}
+	return application, nil
+}
+
+// GetApplication returns one application for review
+func (s *influencerApplicationService) GetApplication(applicationID uuid.UUID) (*model.InfluencerApplication, error) {
+	application, err := s.repo.Get(applicationID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, ErrApplicationNotFound
+		}
+		return nil, err
+	}
+	return application, nil
+}
+
+// ListApplications returns the review queue for a status, or every application for an empty status
+func (s *influencerApplicationService) ListApplications(status string) ([]*model.InfluencerApplication, error) {
+	return s.repo.List(status)
+}
+
+// Approve accepts a pending application and grants the influencer role
+func (s *influencerApplicationService) Approve(applicationID, reviewerID uuid.UUID, note string) (*model.InfluencerApplication, error) {
+	return s.review(applicationID, true, reviewerID, note)
+}
+
+// Reject turns a pending application down; the user may apply again
+func (s *influencerApplicationService) Reject(applicationID, reviewerID uuid.UUID, note string) (*model.InfluencerApplication, error) {
+	if strings.TrimSpace(note) == "" {
+		return nil, ErrReviewNoteRequired
+	}
+	return s.review(applicationID, false, reviewerID, note)
+}
+
+func (s *influencerApplicationService) review(applicationID uuid.UUID, approve bool, reviewerID uuid.UUID, note string) (*model.InfluencerApplication, error) {
+	application, err := s.repo.Review(applicationID, approve, reviewerID, strings.TrimSpace(note))
+	if err != nil {
+		return nil, err
+	}
+	if application == nil {
+		// Tell a missing application apart from one that was already decided
+		if _, err := s.GetApplication(applicationID); err != nil {
+			return nil, err
+		}
+		return nil, ErrApplicationReviewed
+	}
+
+	log.Printf("Influencer application %s %s by %s", application.ID, application.Status, reviewerID)
+
+	if user, err := s.userRepo.Get(application.UserID); err == nil && user.Email != "" {
+		if err := s.notifier.SendInfluencerApplicationReviewed(user, application); err != nil {
+			log.Printf("Error sending application decision: %v", err)
+		}
+	}
+
+	return application, nil
+}
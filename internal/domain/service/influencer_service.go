@@ -0,0 +1,183 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/repository"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/gofrs/uuid"
+)
+
+// Influencer profile and follower errors
+var (
+	ErrInfluencerNotFound = errors.New("influencer not found")
+	ErrCannotFollowSelf   = errors.New("you cannot follow yourself")
+	ErrInvalidProfileLink = errors.New("profile links must be http or https URLs")
+)
+
+// InfluencerService serves public influencer profiles and lets users follow influencers.
+// It is also a CoursePublishedHook that tells followers about new courses.
+type InfluencerService interface {
+	GetPublicProfile(influencerID uuid.UUID) (*model.InfluencerProfile, []*model.Course, error)
+	UpdateProfile(userID uuid.UUID, displayName, avatarURL, bio string, socialLinks map[string]string) (*model.InfluencerProfile, error)
+	Follow(followerID, influencerID uuid.UUID) error
+	Unfollow(followerID, influencerID uuid.UUID) error
+	ListFollowing(userID uuid.UUID) ([]*model.InfluencerProfile, error)
+	OnCoursePublished(course *model.Course)
+}
+
+type influencerService struct {
+	repo       repository.InfluencerRepository
+	courseRepo repository.CourseRepository
+	notifier   NotificationService
+}
+
+// NewInfluencerService creates an InfluencerService
+func NewInfluencerService(repo repository.InfluencerRepository, courseRepo repository.CourseRepository, notifier NotificationService) InfluencerService {
+	return &influencerService{
+		repo:       repo,
+		courseRepo: courseRepo,
+		notifier:   notifier,
+	}
+}
+
+// GetPublicProfile returns an influencer's profile with their stats and published courses
+func (s *influencerService) GetPublicProfile(influencerID uuid.UUID) (*model.InfluencerProfile, []*model.Course, error) {
+	profile, err := s.getProfile(influencerID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stats, err := s.repo.GetStats(influencerID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get influencer stats: %v", err)
+	}
+	profile.Stats = stats
+
+	courses, err := s.courseRepo.ListPublishedByInfluencer(influencerID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get influencer courses: %v", err)
+	}
+
+	return profile, courses, nil
+}
+
+// UpdateProfile replaces the influencer's own profile
+func (s *influencerService) UpdateProfile(userID uuid.UUID, displayName, avatarURL, bio string, socialLinks map[string]string) (*model.InfluencerProfile, error) {
+	if _, err := s.getProfile(userID); err != nil {
+		return nil, err
+	}
+
+	avatarURL = strings.TrimSpace(avatarURL)
+	if avatarURL != "" && !isWebURL(avatarURL) {
+		return nil, ErrInvalidProfileLink
+	}
+
+	links := make(map[string]string, len(socialLinks))
+	for platform, link := range socialLinks {
+		platform = strings.ToLower(strings.TrimSpace(platform))
+		link = strings.TrimSpace(link)
+		if platform == "" || link == "" {
+			continue
+		}
+		if !isWebURL(link) {
+			return nil, ErrInvalidProfileLink
+		}
+		links[platform] = link
+	}
+
+	profile := &model.InfluencerProfile{
+		UserID:      userID,
+		DisplayName: strings.TrimSpace(displayName),
+		AvatarURL:   avatarURL,
+		Bio:         strings.TrimSpace(bio),
+		SocialLinks: links,
+	}
+	if err := s.repo.UpsertProfile(profile); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Influencer profile updated for %s", userID)
+	return profile, nil
+}
+
+// Follow makes the user follow an influencer; following twice is not an error
+func (s *influencerService) Follow(followerID, influencerID uuid.UUID) error {
+	if followerID == influencerID {
+		return ErrCannotFollowSelf
+	}
+	if _, err := s.getProfile(influencerID); err != nil {
+		return err
+	}
+
+	if _, err := s.repo.Follow(followerID, influencerID); err != nil {
+		return fmt.Errorf("failed to follow influencer: %v", err)
+	}
+	return nil
+}
+
+// Unfollow stops following an influencer; unfollowing someone not followed is not an error
+func (s *influencerService) Unfollow(followerID, influencerID uuid.UUID) error {
+	if _, err := s.repo.Unfollow(followerID, influencerID); err != nil {
+		return fmt.Errorf("failed to unfollow influencer: %v", err)
+	}
+	return nil
+}
+
+// ListFollowing returns the profiles of the influencers the user follows
+func (s *influencerService) ListFollowing(userID uuid.UUID) ([]*model.InfluencerProfile, error) {
+	return s.repo.ListFollowing(userID)
+}
+
+// OnCoursePublished emails the influencer's followers about the new course. Sending happens in the
+// background so publishing does not wait on one email per follower.
+func (s *influencerService) OnCoursePublished(course *model.Course) {
+	go s.notifyFollowers(course)
+}
+
+func (s *influencerService) notifyFollowers(course *model.Course) {
+	influencer, err := s.repo.GetProfile(course.InfluencerID)
+	if err != nil {
+		// Admin-owned courses have no public profile and no followers
+		return
+	}
+
+	followers, err := s.repo.ListFollowers(course.InfluencerID)
+	if err != nil {
+		log.Printf("Error listing followers of %s: %v", course.InfluencerID, err)
+		return
+	}
+
+	for _, follower := range followers {
+		if follower.Email == "" {
+			continue
+		}
+		if err := s.notifier.SendNewCourseFromFollowed(follower, influencer, course); err != nil {
+			log.Printf("Error notifying follower %s about course %s: %v", follower.ID, course.ID, err)
+		}
+	}
+}
+
+func (s *influencerService) getProfile(influencerID uuid.UUID) (*model.InfluencerProfile, error) {
+	profile, err := s.repo.GetProfile(influencerID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, ErrInfluencerNotFound
+		}
+		return nil, err
+	}
+	return profile, nil
+}
+
+// isWebURL reports whether link is an absolute http or https URL
+func isWebURL(link string) bool {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+	return (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
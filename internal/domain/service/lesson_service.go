@@ -1,6 +1,7 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"kaabe-app/internal/domain/model"
 	"kaabe-app/internal/domain/repository"
@@ -16,24 +17,49 @@ type LessonService interface {
 	UpdateLesson(lesson *model.Lesson) error
 	DeleteLesson(LessonID uuid.UUID) error
 	GetLessonByID(lessonID uuid.UUID) (*model.Lesson, error) 
-	GetAllLessons() ([]*model.Lesson, error)
+	GetAllLessons(filter model.LessonFilter, page model.PageRequest) (*model.Page[*model.Lesson], error)
+	// GetVisibleLesson and ListVisibleLessons follow the course catalog: lessons of published
+	// courses for everyone, lessons of other courses only for the course's owner and admins
+	GetVisibleLesson(viewer Actor, lessonID uuid.UUID) (*model.Lesson, error)
+	ListVisibleLessons(viewer Actor, filter model.LessonFilter, page model.PageRequest) (*model.Page[*model.Lesson], error)
 }
 
+// ErrLessonNotFound is returned for missing lessons and for lessons of courses the viewer may not see
+var ErrLessonNotFound = errors.New("lesson not found")
+
 // lessonServiceImpl struct implementing lessonService
 type lessonServiceImpl struct {
-	repo      repository.LessonRepository
-	tokenRepo repository.TokenRepository
+	repo       repository.LessonRepository
+	courseRepo repository.CourseRepository
+	tokenRepo  repository.TokenRepository
 }
 
-func NewLessonService(lessonRepo repository.LessonRepository, tokenRepo repository.TokenRepository) LessonService {
+func NewLessonService(lessonRepo repository.LessonRepository, courseRepo repository.CourseRepository, tokenRepo repository.TokenRepository) LessonService {
 	return &lessonServiceImpl{
-		repo:      lessonRepo,
-		tokenRepo: tokenRepo,
+		repo:       lessonRepo,
+		courseRepo: courseRepo,
+		tokenRepo:  tokenRepo,
+	}
+}
+
+// checkCourseEditable refuses lesson changes on a published course; those go through its draft revision
+func (l *lessonServiceImpl) checkCourseEditable(courseID uuid.UUID) error {
+	course, err := l.courseRepo.GetByID(courseID)
+	if err != nil {
+		return fmt.Errorf("could not find course with ID %s: %v", courseID, err)
+	}
+	if course.Status == model.CourseStatusPublished {
+		return ErrCourseLive
 	}
+	return nil
 }
 
 // CreateLesson implements LessonService.
 func (l *lessonServiceImpl) CreateLesson(CourseID uuid.UUID, title string, VideoURL []string, Order int) (*model.Lesson, error) {
+	if err := l.checkCourseEditable(CourseID); err != nil {
+		return nil, err
+	}
+
 	// Generate a new UUID for the lesson ID
 
 	neoLesson, err := uuid.NewV4()
@@ -66,10 +92,13 @@ func (l *lessonServiceImpl) CreateLesson(CourseID uuid.UUID, title string, Video
 
 // DeleteLesson implements LessonService.
 func (l *lessonServiceImpl) DeleteLesson(LessonID uuid.UUID) error {
-	_, err := l.repo.GetByID(LessonID)
+	existing, err := l.repo.GetByID(LessonID)
 	if err != nil {
 		return fmt.Errorf("could not find lesson with ID %s: %v", LessonID, err)
 	}
+	if err := l.checkCourseEditable(existing.CourseID); err != nil {
+		return err
+	}
 
 	if err := l.repo.Delete(LessonID); err != nil {
 		return fmt.Errorf("failed to delete lesson with ID %s: %v", LessonID, err)
@@ -80,12 +109,12 @@ func (l *lessonServiceImpl) DeleteLesson(LessonID uuid.UUID) error {
 }
 
 // GetAllLessons implements LessonService.
-func (l *lessonServiceImpl) GetAllLessons() ([]*model.Lesson, error) {
-	lesson, err := l.repo.GetAll()
+func (l *lessonServiceImpl) GetAllLessons(filter model.LessonFilter, page model.PageRequest) (*model.Page[*model.Lesson], error) {
+	lessons, err := l.repo.List(filter, page)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get all lesson: %v", err)
+		return nil, listError("lessons", err)
 	}
-	return lesson, nil
+	return lessons, nil
 }
 
 // GetLessonByID retrieves a lesson by its ID.
@@ -97,12 +126,48 @@ func (l *lessonServiceImpl) GetLessonByID(lessonID uuid.UUID) (*model.Lesson, er
 	return lesson, nil
 }
 
+// GetVisibleLesson implements LessonService.
+func (l *lessonServiceImpl) GetVisibleLesson(viewer Actor, lessonID uuid.UUID) (*model.Lesson, error) {
+	lesson, err := l.repo.GetByID(lessonID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, ErrLessonNotFound
+		}
+		return nil, err
+	}
+
+	course, err := l.courseRepo.GetByID(lesson.CourseID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, ErrLessonNotFound
+		}
+		return nil, err
+	}
+
+	// Lessons of unpublished courses are reported as missing, like the courses themselves
+	if course.Status != model.CourseStatusPublished && !viewer.IsAdmin() && course.InfluencerID != viewer.UserID {
+		return nil, ErrLessonNotFound
+	}
+	return lesson, nil
+}
+
+// ListVisibleLessons implements LessonService.
+func (l *lessonServiceImpl) ListVisibleLessons(viewer Actor, filter model.LessonFilter, page model.PageRequest) (*model.Page[*model.Lesson], error) {
+	if !viewer.IsAdmin() {
+		filter.VisibleTo = &viewer.UserID
+	}
+	return l.GetAllLessons(filter, page)
+}
+
 // UpdateLesson implements LessonService.
 func (l *lessonServiceImpl) UpdateLesson(lesson *model.Lesson) error {
-	_, err := l.repo.GetByID(lesson.ID)
+	existing, err := l.repo.GetByID(lesson.ID)
 	if err != nil {
 		return fmt.Errorf("could not find lesson with ID %s", lesson.ID)
 	}
+	if err := l.checkCourseEditable(existing.CourseID); err != nil {
+		return err
+	}
 
 	if err := l.repo.Update(lesson); err != nil {
 		return fmt.Errorf("failed to update lesson with ID %s: %v", lesson.ID, err)
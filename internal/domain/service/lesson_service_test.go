@@ -0,0 +1,93 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/repository"
+	"testing"
+
+	"github.com/gofrs/uuid"
+)
+
+// fakeLessonRepo serves lessons from a map
+type fakeLessonRepo struct {
+	repository.LessonRepository
+	lessons map[uuid.UUID]*model.Lesson
+}
+
+func (r *fakeLessonRepo) GetByID(lessonID uuid.UUID) (*model.Lesson, error) {
+	lesson, ok := r.lessons[lessonID]
+	if !ok {
+		return nil, fmt.Errorf("lesson %w", model.ErrNotFound)
+	}
+	return lesson, nil
+}
+
+// fakeCourseRepo serves courses from a map
+type fakeCourseRepo struct {
+	repository.CourseRepository
+	courses map[uuid.UUID]*model.Course
+}
+
+func (r *fakeCourseRepo) GetByID(courseID uuid.UUID) (*model.Course, error) {
+	course, ok := r.courses[courseID]
+	if !ok {
+		return nil, fmt.Errorf("course %w", model.ErrNotFound)
+	}
+	return course, nil
+}
+
+func TestGetVisibleLesson(t *testing.T) {
+	owner := Actor{UserID: newTestUUID(t), Role: model.RoleInfluencer}
+	learner := Actor{UserID: newTestUUID(t), Role: model.RoleUser}
+	admin := Actor{UserID: newTestUUID(t), Role: model.RoleAdmin}
+	anonymous := Actor{}
+
+	courseRepo := &fakeCourseRepo{courses: map[uuid.UUID]*model.Course{}}
+	lessonRepo := &fakeLessonRepo{lessons: map[uuid.UUID]*model.Lesson{}}
+	lessonIn := map[string]uuid.UUID{}
+	for _, status := range []string{model.CourseStatusDraft, model.CourseStatusInReview, model.CourseStatusPublished, model.CourseStatusArchived} {
+		course := &model.Course{ID: newTestUUID(t), InfluencerID: owner.UserID, Status: status}
+		lesson := &model.Lesson{ID: newTestUUID(t), CourseID: course.ID}
+		courseRepo.courses[course.ID] = course
+		lessonRepo.lessons[lesson.ID] = lesson
+		lessonIn[status] = lesson.ID
+	}
+	svc := NewLessonService(lessonRepo, courseRepo, nil)
+
+	tests := []struct {
+		name    string
+		viewer  Actor
+		status  string
+		visible bool
+	}{
+		{"published for a learner", learner, model.CourseStatusPublished, true},
+		{"published for a visitor", anonymous, model.CourseStatusPublished, true},
+		{"draft for a learner", learner, model.CourseStatusDraft, false},
+		{"in review for a visitor", anonymous, model.CourseStatusInReview, false},
+		{"archived for a learner", learner, model.CourseStatusArchived, false},
+		{"draft for its owner", owner, model.CourseStatusDraft, true},
+		{"archived for its owner", owner, model.CourseStatusArchived, true},
+		{"in review for an admin", admin, model.CourseStatusInReview, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lesson, err := svc.GetVisibleLesson(tt.viewer, lessonIn[tt.status])
+			if tt.visible {
+				if err != nil || lesson == nil {
+					t.Fatalf("GetVisibleLesson = %v, %v; want the lesson", lesson, err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrLessonNotFound) {
+				t.Errorf("GetVisibleLesson error = %v, want %v", err, ErrLessonNotFound)
+			}
+		})
+	}
+
+	if _, err := svc.GetVisibleLesson(admin, newTestUUID(t)); !errors.Is(err, ErrLessonNotFound) {
+		t.Errorf("GetVisibleLesson for a missing lesson = %v, want %v", err, ErrLessonNotFound)
+	}
+}
@@ -0,0 +1,9 @@
+package service
+
+import "kaabe-app/internal/domain/model"
+
+// Mailer delivers rendered email messages. The SMTP and outbox implementations
+// live in the gateway package.
+type Mailer interface {
+	Send(message *model.EmailMessage) error
+}
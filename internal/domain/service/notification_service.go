@@ -0,0 +1,384 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"kaabe-app/internal/domain/model"
+	"log"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// NotificationService renders templated account emails and hands them to a Mailer
+type NotificationService interface {
+	SendWelcome(user *model.User) error
+	SendEmailVerification(user *model.User, token string, expiresAt time.Time) error
+	SendPasswordReset(user *model.User, token string, expiresAt time.Time) error
+	SendEmailChangeConfirmation(user *model.User, newEmail, token string, expiresAt time.Time) error
+	SendEmailChanged(user *model.User, oldEmail string) error
+	SendPasswordChanged(user *model.User) error
+	SendAccountDeletionScheduled(user *model.User, scheduledAt time.Time) error
+	SendAccountDeleted(user *model.User) error
+	SendPurchaseReceipt(user *model.User, payment *model.Payment) error
+	SendWithdrawalStatus(user *model.User, withdrawal *model.Withdrawal) error
+	SendInfluencerApplicationReviewed(user *model.User, application *model.InfluencerApplication) error
+	SendNewCourseFromFollowed(user *model.User, influencer *model.InfluencerProfile, course *model.Course) error
+}
+
+type notificationService struct {
+	mailer      Mailer
+	appName     string
+	frontendURL string
+}
+
+// NewNotificationService creates a NotificationService; frontendURL is the base for links in emails
+func NewNotificationService(mailer Mailer, appName, frontendURL string) NotificationService {
+	return &notificationService{
+		mailer:      mailer,
+		appName:     appName,
+		frontendURL: strings.TrimRight(frontendURL, "/"),
+	}
+}
+
+// SendWelcome greets a newly registered user
+func (n *notificationService) SendWelcome(user *model.User) error {
+	return n.send(user.Email, "Welcome to "+n.appName, welcomeTemplate, map[string]interface{}{
+		"Name":     user.FirstName,
+		"AppName":  n.appName,
+		"LoginURL": n.link("/login", nil),
+	})
+}
+
+// SendEmailVerification emails a link that proves ownership of the address
+func (n *notificationService) SendEmailVerification(user *model.User, token string, expiresAt time.Time) error {
+	return n.send(user.Email, "Verify your "+n.appName+" email", emailVerificationTemplate, map[string]interface{}{
+		"Name":      user.FirstName,
+		"AppName":   n.appName,
+		"VerifyURL": n.link("/verify-email", url.Values{"token": {token}}),
+		"ExpiresIn": time.Until(expiresAt).Round(time.Minute).String(),
+	})
+}
+
+// SendPasswordReset emails a reset link containing the token
+func (n *notificationService) SendPasswordReset(user *model.User, token string, expiresAt time.Time) error {
+	return n.send(user.Email, "Reset your "+n.appName+" password", passwordResetTemplate, map[string]interface{}{
+		"Name":      user.FirstName,
+		"AppName":   n.appName,
+		"ResetURL":  n.link("/reset-password", url.Values{"token": {token}}),
+		"ExpiresIn": time.Until(expiresAt).Round(time.Minute).String(),
+	})
+}
+
+// SendEmailChangeConfirmation asks the new address to confirm it belongs to the user
+func (n *notificationService) SendEmailChangeConfirmation(user *model.User, newEmail, token string, expiresAt time.Time) error {
+	return n.send(newEmail, "Confirm your new "+n.appName+" email", emailChangeTemplate, map[string]interface{}{
+		"Name":       user.FirstName,
+		"AppName":    n.appName,
+		"NewEmail":   newEmail,
+		"ConfirmURL": n.link("/confirm-email-change", url.Values{"token": {token}}),
+		"ExpiresIn":  time.Until(expiresAt).Round(time.Minute).String(),
+	})
+}
+
+// SendEmailChanged warns the old address that the account email was changed
+func (n *notificationService) SendEmailChanged(user *model.User, oldEmail string) error {
+	return n.send(oldEmail, "Your "+n.appName+" email was changed", emailChangedTemplate, map[string]interface{}{
+		"Name":     user.FirstName,
+		"AppName":  n.appName,
+		"NewEmail": user.Email,
+	})
+}
+
+// SendPasswordChanged warns the user that their password was changed
+func (n *notificationService) SendPasswordChanged(user *model.User) error {
+	return n.send(user.Email, "Your "+n.appName+" password was changed", passwordChangedTemplate, map[string]interface{}{
+		"Name":     user.FirstName,
+		"AppName":  n.appName,
+		"ResetURL": n.link("/forgot-password", nil),
+	})
+}
+
+// SendAccountDeletionScheduled confirms a deletion request and says how to cancel it
+func (n *notificationService) SendAccountDeletionScheduled(user *model.User, scheduledAt time.Time) error {
+	return n.send(user.Email, "Your "+n.appName+" account will be deleted", accountDeletionScheduledTemplate, map[string]interface{}{
+		"Name":        user.FirstName,
+		"AppName":     n.appName,
+		"ScheduledAt": scheduledAt.Format("2 Jan 2006 15:04 MST"),
+		"AccountURL":  n.link("/account", nil),
+	})
+}
+
+// SendAccountDeleted tells the user their account was deleted; user must be the record from before anonymization
+func (n *notificationService) SendAccountDeleted(user *model.User) error {
+	return n.send(user.Email, "Your "+n.appName+" account was deleted", accountDeletedTemplate, map[string]interface{}{
+		"Name":    user.FirstName,
+		"AppName": n.appName,
+	})
+}
+
+// SendPurchaseReceipt confirms a completed payment
+func (n *notificationService) SendPurchaseReceipt(user *model.User, payment *model.Payment) error {
+	return n.send(user.Email, "Your "+n.appName+" receipt", purchaseReceiptTemplate, map[string]interface{}{
+		"Name":        user.FirstName,
+		"AppName":     n.appName,
+		"Reference":   payment.ExternalRef,
+		"Amount":      payment.Amount.String(),
+		"ProcessedAt": payment.ProcessedAt.Format("2 Jan 2006 15:04 MST"),
+	})
+}
+
+// SendWithdrawalStatus tells an influencer their payout request changed status
+func (n *notificationService) SendWithdrawalStatus(user *model.User, withdrawal *model.Withdrawal) error {
+	return n.send(user.Email, "Withdrawal "+withdrawal.Status, withdrawalStatusTemplate, map[string]interface{}{
+		"Name":    user.FirstName,
+		"AppName": n.appName,
+		"Amount":  withdrawal.Amount.String(),
+		"Status":  withdrawal.Status,
+	})
+}
+
+// SendInfluencerApplicationReviewed tells the applicant whether they were approved as an influencer
+func (n *notificationService) SendInfluencerApplicationReviewed(user *model.User, application *model.InfluencerApplication) error {
+	return n.send(user.Email, "Your "+n.appName+" influencer application was "+application.Status, influencerApplicationTemplate, map[string]interface{}{
+		"Name":     user.FirstName,
+		"AppName":  n.appName,
+		"Approved": application.Status == model.ApplicationApproved,
+		"Note":     application.ReviewNote,
+	})
+}
+
+// SendNewCourseFromFollowed tells a follower that an influencer they follow published a course
+func (n *notificationService) SendNewCourseFromFollowed(user *model.User, influencer *model.InfluencerProfile, course *model.Course) error {
+	return n.send(user.Email, influencer.DisplayName+" published a new course", newCourseFromFollowedTemplate, map[string]interface{}{
+		"Name":          user.FirstName,
+		"AppName":       n.appName,
+		"Influencer":    influencer.DisplayName,
+		"Title":         course.Title,
+		"CourseURL":     n.link("/courses/"+course.ID.String(), nil),
+		"InfluencerURL": n.link("/influencers/"+influencer.UserID.String(), nil),
+	})
+}
+
+func (n *notificationService) link(path string, query url.Values) string {
+	link := n.frontendURL + path
+	if len(query) > 0 {
+		link += "?" + query.Encode()
+	}
+	return link
+}
+
+// send renders both bodies of a template and delivers the message
+func (n *notificationService) send(to, subject string, tmpl emailTemplate, data map[string]interface{}) error {
+	if to == "" {
+		return fmt.Errorf("cannot send %q: recipient has no email address", subject)
+	}
+
+	var text, html bytes.Buffer
+	if err := tmpl.text.Execute(&text, data); err != nil {
+		return fmt.Errorf("failed to render email: %v", err)
+	}
+	if err := tmpl.html.Execute(&html, data); err != nil {
+		return fmt.Errorf("failed to render email: %v", err)
+	}
+
+	message := &model.EmailMessage{
+		To:       to,
+		Subject:  subject,
+		TextBody: text.String(),
+		HTMLBody: html.String(),
+	}
+
+	if err := n.mailer.Send(message); err != nil {
+		log.Printf("Error sending %q to %s: %v", subject, to, err)
+		return fmt.Errorf("failed to send email: %v", err)
+	}
+
+	log.Printf("Email %q sent to %s", subject, to)
+	return nil
+}
+
+// emailTemplate pairs the plain-text and HTML versions of one email
+type emailTemplate struct {
+	text *template.Template
+	html *htmltemplate.Template
+}
+
+func newEmailTemplate(name, text, html string) emailTemplate {
+	return emailTemplate{
+		text: template.Must(template.New(name).Parse(text)),
+		html: htmltemplate.Must(htmltemplate.New(name).Parse(html)),
+	}
+}
+
+var welcomeTemplate = newEmailTemplate("welcome",
+	`Hi {{.Name}},
+
+Welcome to {{.AppName}}! Your account is ready.
+
+Log in: {{.LoginURL}}
+`,
+	`<p>Hi {{.Name}},</p>
+<p>Welcome to {{.AppName}}! Your account is ready.</p>
+<p><a href="{{.LoginURL}}">Log in</a></p>
+`)
+
+var emailVerificationTemplate = newEmailTemplate("email-verification",
+	`Hi {{.Name}},
+
+Please confirm your email address for {{.AppName}} by opening the link below
+within {{.ExpiresIn}}:
+
+{{.VerifyURL}}
+
+If you did not create an account, you can ignore this email.
+`,
+	`<p>Hi {{.Name}},</p>
+<p>Please confirm your email address for {{.AppName}} within {{.ExpiresIn}}.</p>
+<p><a href="{{.VerifyURL}}">Verify email</a></p>
+<p>If you did not create an account, you can ignore this email.</p>
+`)
+
+var passwordResetTemplate = newEmailTemplate("password-reset",
+	`Hi {{.Name}},
+
+We received a request to reset your {{.AppName}} password.
+Open the link below within {{.ExpiresIn}} to choose a new one:
+
+{{.ResetURL}}
+
+If you did not ask for this, you can ignore this email.
+`,
+	`<p>Hi {{.Name}},</p>
+<p>We received a request to reset your {{.AppName}} password.
+Open the link below within {{.ExpiresIn}} to choose a new one:</p>
+<p><a href="{{.ResetURL}}">Reset password</a></p>
+<p>If you did not ask for this, you can ignore this email.</p>
+`)
+
+var emailChangeTemplate = newEmailTemplate("email-change",
+	`Hi {{.Name}},
+
+Please confirm that {{.NewEmail}} should become the email address of your
+{{.AppName}} account by opening the link below within {{.ExpiresIn}}:
+
+{{.ConfirmURL}}
+
+If you did not ask for this, you can ignore this email.
+`,
+	`<p>Hi {{.Name}},</p>
+<p>Please confirm that {{.NewEmail}} should become the email address of your {{.AppName}} account within {{.ExpiresIn}}.</p>
+<p><a href="{{.ConfirmURL}}">Confirm new email</a></p>
+<p>If you did not ask for this, you can ignore this email.</p>
+`)
+
+var emailChangedTemplate = newEmailTemplate("email-changed",
+	`Hi {{.Name}},
+
+The email address of your {{.AppName}} account was changed to {{.NewEmail}}.
+If you did not make this change, contact support immediately.
+`,
+	`<p>Hi {{.Name}},</p>
+<p>The email address of your {{.AppName}} account was changed to {{.NewEmail}}.</p>
+<p>If you did not make this change, contact support immediately.</p>
+`)
+
+var passwordChangedTemplate = newEmailTemplate("password-changed",
+	`Hi {{.Name}},
+
+The password of your {{.AppName}} account was just changed and your other
+sessions were signed out.
+
+If this was not you, reset your password now: {{.ResetURL}}
+`,
+	`<p>Hi {{.Name}},</p>
+<p>The password of your {{.AppName}} account was just changed and your other sessions were signed out.</p>
+<p>If this was not you, <a href="{{.ResetURL}}">reset your password now</a>.</p>
+`)
+
+var accountDeletionScheduledTemplate = newEmailTemplate("account-deletion-scheduled",
+	`Hi {{.Name}},
+
+We received a request to delete your {{.AppName}} account. It will be deleted
+on {{.ScheduledAt}}. Until then you can log in and cancel the request:
+
+{{.AccountURL}}
+
+Payment records are kept for accounting, without your name or contact details.
+`,
+	`<p>Hi {{.Name}},</p>
+<p>We received a request to delete your {{.AppName}} account. It will be deleted on {{.ScheduledAt}}.</p>
+<p>Until then you can <a href="{{.AccountURL}}">log in and cancel the request</a>.</p>
+<p>Payment records are kept for accounting, without your name or contact details.</p>
+`)
+
+var accountDeletedTemplate = newEmailTemplate("account-deleted",
+	`Hi {{.Name}},
+
+Your {{.AppName}} account has been deleted. This is the last email we will
+send to this address.
+`,
+	`<p>Hi {{.Name}},</p>
+<p>Your {{.AppName}} account has been deleted. This is the last email we will send to this address.</p>
+`)
+
+var purchaseReceiptTemplate = newEmailTemplate("purchase-receipt",
+	`Hi {{.Name}},
+
+Thank you for your purchase on {{.AppName}}.
+
+Reference: {{.Reference}}
+Amount:    {{.Amount}}
+Date:      {{.ProcessedAt}}
+`,
+	`<p>Hi {{.Name}},</p>
+<p>Thank you for your purchase on {{.AppName}}.</p>
+<table>
+<tr><td>Reference</td><td>{{.Reference}}</td></tr>
+<tr><td>Amount</td><td>{{.Amount}}</td></tr>
+<tr><td>Date</td><td>{{.ProcessedAt}}</td></tr>
+</table>
+`)
+
+var withdrawalStatusTemplate = newEmailTemplate("withdrawal-status",
+	`Hi {{.Name}},
+
+Your withdrawal request of {{.Amount}} is now {{.Status}}.
+`,
+	`<p>Hi {{.Name}},</p>
+<p>Your withdrawal request of {{.Amount}} is now <strong>{{.Status}}</strong>.</p>
+`)
+
+var influencerApplicationTemplate = newEmailTemplate("influencer-application",
+	`Hi {{.Name}},
+
+{{if .Approved}}Your application to become an influencer on {{.AppName}} was approved.
+You can now publish courses and request payouts.{{else}}Your application to become an influencer on {{.AppName}} was not approved.
+You are welcome to apply again.{{end}}
+{{if .Note}}
+Reviewer note: {{.Note}}
+{{end}}`,
+	`<p>Hi {{.Name}},</p>
+{{if .Approved}}<p>Your application to become an influencer on {{.AppName}} was approved. You can now publish courses and request payouts.</p>
+{{else}}<p>Your application to become an influencer on {{.AppName}} was not approved. You are welcome to apply again.</p>
+{{end}}{{if .Note}}<p>Reviewer note: {{.Note}}</p>
+{{end}}`)
+
+var newCourseFromFollowedTemplate = newEmailTemplate("new-course-from-followed",
+	`Hi {{.Name}},
+
+{{.Influencer}}, whom you follow on {{.AppName}}, just published a new course:
+
+{{.Title}}
+{{.CourseURL}}
+
+You receive this email because you follow {{.Influencer}}. To stop, unfollow
+them on their profile: {{.InfluencerURL}}
+`,
+	`<p>Hi {{.Name}},</p>
+<p>{{.Influencer}}, whom you follow on {{.AppName}}, just published a new course:</p>
+<p><a href="{{.CourseURL}}">{{.Title}}</a></p>
+<p>You receive this email because you follow {{.Influencer}}. To stop, <a href="{{.InfluencerURL}}">unfollow them on their profile</a>.</p>
+`)
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"kaabe-app/internal/domain/model"
 	"kaabe-app/internal/domain/repository"
@@ -10,26 +11,80 @@ import (
 	"github.com/gofrs/uuid"
 )
 
+// ErrSubscriptionRequired is returned for a payment without a subscription that does not pay a
+// price quote either
+var ErrSubscriptionRequired = errors.New("subscription_id is required unless paying a price quote")
+
 type PaymentService interface {
-	CreatePayment(externalRef string, userID, subscriptionID uuid.UUID, amount float64, status string, processedAt time.Time) (*model.Payment, error)
+	// CreatePayment records a payment; with a quoteID it pays that price quote, redeeming its coupon.
+	// An empty externalRef is generated: that is the reference the user pays the provider with.
+	// A bundle or pass quote is paid without a subscription, and so may a course quote: completing the
+	// payment subscribes the user to the courses it covers. Callers other than admins and the payment webhook create it pending,
+	// except for a quote brought down to zero, which has nothing left to pay and is completed at once.
+	CreatePayment(externalRef string, userID, subscriptionID uuid.UUID, amount model.Money, quoteID *uuid.UUID, status string, processedAt time.Time) (*model.Payment, error)
+	// UpdatePayment changes a payment; completing a bundle or pass payment grants the purchase, and
+	// moving it to any other status revokes it. Only admins and the payment webhook call it.
 	UpdatePayment(payment *model.Payment) error
 	DeletePayment(paymentID uuid.UUID) error
 	GetPaymentByID(paymentID uuid.UUID) (*model.Payment, error)
-	GetAllPayments() ([]*model.Payment, error)
+	GetAllPayments(filter model.PaymentFilter, page model.PageRequest) (*model.Page[*model.Payment], error)
 	GetPaymentByExternalRef(ref string) (*model.Payment, error) 
 }
 
 // paymentServiceImpl is the implementation
 type PaymentServiceImpl struct {
-	repo repository.PaymentRepository
+	repo             repository.PaymentRepository
+	userRepo         repository.UserRepository
+	promotionRepo    repository.PromotionRepository
+	subscriptionRepo repository.SubscriptionRepository
+	notifier         NotificationService
+	requireVerified  bool
 }
 
 // CreatePayment implements PaymentService.
-func (p *PaymentServiceImpl) CreatePayment(externalRef string, userID uuid.UUID, subscriptionID uuid.UUID, amount float64, status string, processedAt time.Time) (*model.Payment, error) {
+func (p *PaymentServiceImpl) CreatePayment(externalRef string, userID uuid.UUID, subscriptionID uuid.UUID, amount model.Money, quoteID *uuid.UUID, status string, processedAt time.Time) (*model.Payment, error) {
+	var quote *model.PriceQuote
+	var err error
+	if quoteID != nil {
+		quote, amount, err = p.checkQuote(*quoteID, userID, subscriptionID, amount)
+	} else {
+		amount, err = positiveMoney(amount)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if quote != nil && quote.IsPurchase() {
+		// The purchase makes its own subscriptions
+		subscriptionID = uuid.Nil
+	} else if quote == nil && subscriptionID == uuid.Nil {
+		return nil, ErrSubscriptionRequired
+	}
+
+	// Nothing is left to pay on a quote a coupon brings down to zero, so no provider will ever
+	// complete it: it is completed here, which settles its purchase
+	if quote != nil && amount.Amount == 0 {
+		status = "completed"
+		processedAt = time.Now()
+	}
+
+	if p.requireVerified {
+		user, err := p.userRepo.Get(userID)
+		if err != nil {
+			return nil, fmt.Errorf("user not found")
+		}
+		if !user.HasVerifiedContact() {
+			return nil, ErrEmailNotVerified
+		}
+	}
+
 	newID, err := uuid.NewV4()
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate UUID: %v", err)
 	}
+	if externalRef == "" {
+		externalRef = fmt.Sprintf("kb_%x", newID.Bytes())
+	}
 
 	payment := &model.Payment{
 		ID:             newID,
@@ -37,6 +92,7 @@ func (p *PaymentServiceImpl) CreatePayment(externalRef string, userID uuid.UUID,
 		UserID:         userID,
 		SubscriptionID: subscriptionID,
 		Amount:         amount,
+		QuoteID:        quoteID,
 		Status:         status,
 		ProcessedAt:    processedAt,
 		CreatedAt:      time.Now(),
@@ -46,9 +102,16 @@ func (p *PaymentServiceImpl) CreatePayment(externalRef string, userID uuid.UUID,
 	log.Printf("Creating payment: %+v", payment)
 
 	if err := p.repo.Create(payment); err != nil {
+		if errors.Is(err, model.ErrQuoteNotRedeemable) {
+			return nil, ErrQuoteUnavailable
+		}
 		return nil, fmt.Errorf("failed to create payment: %v", err)
 	}
 
+	if payment.Status == "completed" {
+		p.sendReceipt(payment)
+	}
+
 	return payment, nil
 }
 
@@ -68,10 +131,10 @@ func (p *PaymentServiceImpl) DeletePayment(paymentID uuid.UUID) error {
 }
 
 // GetAllPayment implements PaymentService.
-func (p *PaymentServiceImpl) GetAllPayments() ([]*model.Payment, error) {
-	payments, err := p.repo.GetAll()
+func (p *PaymentServiceImpl) GetAllPayments(filter model.PaymentFilter, page model.PageRequest) (*model.Page[*model.Payment], error) {
+	payments, err := p.repo.List(filter, page)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get payments: %v", err)
+		return nil, listError("payments", err)
 	}
 	return payments, nil
 }
@@ -87,26 +150,122 @@ func (p *PaymentServiceImpl) GetPaymentByID(paymentID uuid.UUID) (*model.Payment
 
 // UpdatePayment implements PaymentService.
 func (p *PaymentServiceImpl) UpdatePayment(payment *model.Payment) error {
-	_, err := p.repo.GetByID(payment.ID)
+	existing, err := p.repo.GetByID(payment.ID)
 	if err != nil {
 		return fmt.Errorf("payment not found: %v", err)
 	}
 
+	// A quote payment's amount was checked against the quote when it was made
+	if existing.QuoteID != nil {
+		payment.Amount = existing.Amount
+	} else {
+		amount, err := positiveMoney(payment.Amount)
+		if err != nil {
+			return err
+		}
+		payment.Amount = amount
+	}
+
+	// The quote paid never changes, and neither does what it bought: the quoted course's
+	// subscription, or none for purchases
+	payment.QuoteID = existing.QuoteID
+	if existing.QuoteID != nil || existing.SubscriptionID == uuid.Nil {
+		payment.SubscriptionID = existing.SubscriptionID
+	} else if payment.SubscriptionID == uuid.Nil {
+		return ErrSubscriptionRequired
+	}
+
 	if err := p.repo.Update(payment); err != nil {
 		return fmt.Errorf("failed to update payment: %v", err)
 	}
 
 	log.Printf("Updated payment: %+v", payment)
+
+	if payment.Status == "completed" && existing.Status != "completed" {
+		p.sendReceipt(payment)
+	}
 	return nil
 }
 
+// sendReceipt emails the paying user; failures are logged and never fail the payment
+func (p *PaymentServiceImpl) sendReceipt(payment *model.Payment) {
+	user, err := p.userRepo.Get(payment.UserID)
+	if err != nil {
+		log.Printf("Receipt not sent, user lookup failed: %v", err)
+		return
+	}
+
+	if err := p.notifier.SendPurchaseReceipt(user, payment); err != nil {
+		log.Printf("Receipt not sent: %v", err)
+	}
+}
+
+// checkQuote checks a payment against the price quote it pays, which must be the payer's, unexpired
+// and for the same amount. A course quote pays the payer's subscription to that course.
+// A quote a coupon brings down to zero is paid with a zero amount, and that payment is completed
+// as soon as it is made.
+func (p *PaymentServiceImpl) checkQuote(quoteID, userID, subscriptionID uuid.UUID, amount model.Money) (*model.PriceQuote, model.Money, error) {
+	amount, err := model.NewMoney(amount.Amount, amount.Currency)
+	if err != nil {
+		return nil, model.Money{}, err
+	}
+
+	quote, err := p.promotionRepo.GetQuote(quoteID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, model.Money{}, ErrQuoteNotFound
+		}
+		return nil, model.Money{}, fmt.Errorf("failed to get price quote: %v", err)
+	}
+	if quote.UserID != userID {
+		return nil, model.Money{}, ErrQuoteNotFound
+	}
+	if !time.Now().Before(quote.ExpiresAt) {
+		return nil, model.Money{}, ErrQuoteExpired
+	}
+	if amount != quote.Total {
+		return nil, model.Money{}, fmt.Errorf("%w: the quote is for %s", ErrQuoteMismatch, quote.Total)
+	}
+
+	if quote.CourseID != nil && subscriptionID != uuid.Nil {
+		subscription, err := p.subscriptionRepo.Get(subscriptionID)
+		if err != nil {
+			if errors.Is(err, model.ErrNotFound) {
+				return nil, model.Money{}, fmt.Errorf("%w: subscription %s not found", ErrQuoteMismatch, subscriptionID)
+			}
+			return nil, model.Money{}, fmt.Errorf("failed to get subscription: %v", err)
+		}
+		if subscription.UserID != userID || subscription.CourseID != *quote.CourseID {
+			return nil, model.Money{}, fmt.Errorf("%w: the quote is for course %s", ErrQuoteMismatch, *quote.CourseID)
+		}
+	}
+	return quote, amount, nil
+}
+
+// positiveMoney checks an amount that changes hands, which cannot be zero
+func positiveMoney(money model.Money) (model.Money, error) {
+	money, err := model.NewMoney(money.Amount, money.Currency)
+	if err != nil {
+		return model.Money{}, err
+	}
+	if money.Amount == 0 {
+		return model.Money{}, fmt.Errorf("%w: must be more than zero", model.ErrInvalidAmount)
+	}
+	return money, nil
+}
+
 func (p *PaymentServiceImpl) GetPaymentByExternalRef(ref string) (*model.Payment, error) {
 	return p.repo.GetByExternalRef(ref)
 }
 
-// NewPaymentService initializes the service
-func NewPaymentService(paymentRepo repository.PaymentRepository) PaymentService {
+// NewPaymentService creates the payment service; requireVerified blocks purchases by users with an unverified email
+func NewPaymentService(paymentRepo repository.PaymentRepository, userRepo repository.UserRepository, promotionRepo repository.PromotionRepository, subscriptionRepo repository.SubscriptionRepository, notifier NotificationService, requireVerified bool) PaymentService {
 	return &PaymentServiceImpl{
-		repo: paymentRepo,
+		repo:             paymentRepo,
+		userRepo:         userRepo,
+		promotionRepo:    promotionRepo,
+		subscriptionRepo: subscriptionRepo,
+		notifier:         notifier,
+		requireVerified:  requireVerified,
 	}
 }
@@ -0,0 +1,296 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/repository"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// fakePaymentRepo records created and updated payments; createErr makes Create fail
+type fakePaymentRepo struct {
+	repository.PaymentRepository
+	created   []*model.Payment
+	updated   []*model.Payment
+	createErr error
+}
+
+func (r *fakePaymentRepo) GetByID(paymentID uuid.UUID) (*model.Payment, error) {
+	for _, payment := range r.created {
+		if payment.ID == paymentID {
+			stored := *payment
+			return &stored, nil
+		}
+	}
+	return nil, fmt.Errorf("payment %w", model.ErrNotFound)
+}
+
+func (r *fakePaymentRepo) Update(payment *model.Payment) error {
+	r.updated = append(r.updated, payment)
+	return nil
+}
+
+func (r *fakePaymentRepo) Create(payment *model.Payment) error {
+	if r.createErr != nil {
+		return r.createErr
+	}
+	r.created = append(r.created, payment)
+	return nil
+}
+
+// fakePromotionRepo serves price quotes from a map
+type fakePromotionRepo struct {
+	repository.PromotionRepository
+	quotes map[uuid.UUID]*model.PriceQuote
+}
+
+func (r *fakePromotionRepo) GetQuote(quoteID uuid.UUID) (*model.PriceQuote, error) {
+	quote, ok := r.quotes[quoteID]
+	if !ok {
+		return nil, fmt.Errorf("price quote %w", model.ErrNotFound)
+	}
+	return quote, nil
+}
+
+// fakeSubscriptionRepo serves subscriptions from a map
+type fakeSubscriptionRepo struct {
+	repository.SubscriptionRepository
+	subscriptions map[uuid.UUID]*model.Subscription
+}
+
+func (r *fakeSubscriptionRepo) Get(subscriptionID uuid.UUID) (*model.Subscription, error) {
+	subscription, ok := r.subscriptions[subscriptionID]
+	if !ok {
+		return nil, fmt.Errorf("subscription %w", model.ErrNotFound)
+	}
+	return subscription, nil
+}
+
+// fakeUserRepo serves users from a map
+type fakeUserRepo struct {
+	repository.UserRepository
+	users map[uuid.UUID]*model.User
+}
+
+func (r *fakeUserRepo) Get(userID uuid.UUID) (*model.User, error) {
+	user, ok := r.users[userID]
+	if !ok {
+		return nil, fmt.Errorf("user %w", model.ErrNotFound)
+	}
+	return user, nil
+}
+
+// fakeNotifier records the receipts it is asked to send
+type fakeNotifier struct {
+	NotificationService
+	receipts []*model.Payment
+}
+
+func (n *fakeNotifier) SendPurchaseReceipt(user *model.User, payment *model.Payment) error {
+	n.receipts = append(n.receipts, payment)
+	return nil
+}
+
+func newTestUUID(t *testing.T) uuid.UUID {
+	t.Helper()
+	id, err := uuid.NewV4()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func TestCreatePaymentWithQuote(t *testing.T) {
+	usd := func(amount int64) model.Money { return model.Money{Amount: amount, Currency: "USD"} }
+
+	payer := newTestUUID(t)
+	otherUser := newTestUUID(t)
+	courseID := newTestUUID(t)
+	otherCourseID := newTestUUID(t)
+	bundleID := newTestUUID(t)
+	influencerID := newTestUUID(t)
+
+	quote := func(total model.Money, expiresIn time.Duration) *model.PriceQuote {
+		return &model.PriceQuote{
+			ID:        newTestUUID(t),
+			UserID:    payer,
+			CourseID:  &courseID,
+			ListPrice: total,
+			Total:     total,
+			ExpiresAt: time.Now().Add(expiresIn),
+		}
+	}
+	courseQuote := quote(usd(1999), time.Hour)
+	freeQuote := quote(usd(0), time.Hour)
+	expiredQuote := quote(usd(1999), -time.Minute)
+	othersQuote := quote(usd(1999), time.Hour)
+	othersQuote.UserID = otherUser
+	bundleQuote := quote(usd(4999), time.Hour)
+	bundleQuote.CourseID, bundleQuote.BundleID = nil, &bundleID
+	passQuote := quote(usd(9999), time.Hour)
+	passQuote.CourseID, passQuote.PassInfluencerID = nil, &influencerID
+
+	subscription := &model.Subscription{ID: newTestUUID(t), UserID: payer, CourseID: courseID}
+	otherCourseSubscription := &model.Subscription{ID: newTestUUID(t), UserID: payer, CourseID: otherCourseID}
+	othersSubscription := &model.Subscription{ID: newTestUUID(t), UserID: otherUser, CourseID: courseID}
+	missingSubscription := newTestUUID(t)
+	missingQuote := newTestUUID(t)
+
+	tests := []struct {
+		name             string
+		quoteID          *uuid.UUID
+		subscriptionID   uuid.UUID
+		amount           model.Money
+		createErr        error
+		wantErr          error
+		wantSubscription uuid.UUID
+		wantCompleted    bool
+	}{
+		{name: "course quote paid for its subscription", quoteID: &courseQuote.ID, subscriptionID: subscription.ID, amount: usd(1999), wantSubscription: subscription.ID},
+		{name: "course quote without a subscription makes a purchase", quoteID: &courseQuote.ID, amount: usd(1999), wantSubscription: uuid.Nil},
+		{name: "amount currency is normalised", quoteID: &courseQuote.ID, amount: model.Money{Amount: 1999, Currency: "usd"}, wantSubscription: uuid.Nil},
+		{name: "quote brought down to zero is completed", quoteID: &freeQuote.ID, amount: usd(0), wantSubscription: uuid.Nil, wantCompleted: true},
+		{name: "unknown quote", quoteID: &missingQuote, amount: usd(1999), wantErr: ErrQuoteNotFound},
+		{name: "another user's quote", quoteID: &othersQuote.ID, amount: usd(1999), wantErr: ErrQuoteNotFound},
+		{name: "expired quote", quoteID: &expiredQuote.ID, amount: usd(1999), wantErr: ErrQuoteExpired},
+		{name: "amount below the quote", quoteID: &courseQuote.ID, amount: usd(1000), wantErr: ErrQuoteMismatch},
+		{name: "amount above the quote", quoteID: &courseQuote.ID, amount: usd(2999), wantErr: ErrQuoteMismatch},
+		{name: "amount in another currency", quoteID: &courseQuote.ID, amount: model.Money{Amount: 1999, Currency: "EUR"}, wantErr: ErrQuoteMismatch},
+		{name: "unsupported currency", quoteID: &courseQuote.ID, amount: model.Money{Amount: 1999, Currency: "XYZ"}, wantErr: model.ErrUnsupportedCurrency},
+		{name: "subscription to another course", quoteID: &courseQuote.ID, subscriptionID: otherCourseSubscription.ID, amount: usd(1999), wantErr: ErrQuoteMismatch},
+		{name: "another user's subscription", quoteID: &courseQuote.ID, subscriptionID: othersSubscription.ID, amount: usd(1999), wantErr: ErrQuoteMismatch},
+		{name: "unknown subscription", quoteID: &courseQuote.ID, subscriptionID: missingSubscription, amount: usd(1999), wantErr: ErrQuoteMismatch},
+		{name: "quote already paid", quoteID: &courseQuote.ID, amount: usd(1999), createErr: model.ErrQuoteNotRedeemable, wantErr: ErrQuoteUnavailable},
+		{name: "bundle quote without a subscription", quoteID: &bundleQuote.ID, amount: usd(4999), wantSubscription: uuid.Nil},
+		{name: "bundle quote ignores a subscription", quoteID: &bundleQuote.ID, subscriptionID: otherCourseSubscription.ID, amount: usd(4999), wantSubscription: uuid.Nil},
+		{name: "bundle quote amount mismatch", quoteID: &bundleQuote.ID, amount: usd(1999), wantErr: ErrQuoteMismatch},
+		{name: "pass quote without a subscription", quoteID: &passQuote.ID, amount: usd(9999), wantSubscription: uuid.Nil},
+		{name: "no quote and no subscription", amount: usd(1999), wantErr: ErrSubscriptionRequired},
+		{name: "no quote pays its subscription", subscriptionID: subscription.ID, amount: usd(1999), wantSubscription: subscription.ID},
+		{name: "no quote cannot be free", subscriptionID: subscription.ID, amount: usd(0), wantErr: model.ErrInvalidAmount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paymentRepo := &fakePaymentRepo{createErr: tt.createErr}
+			promotionRepo := &fakePromotionRepo{quotes: map[uuid.UUID]*model.PriceQuote{}}
+			for _, q := range []*model.PriceQuote{courseQuote, freeQuote, expiredQuote, othersQuote, bundleQuote, passQuote} {
+				promotionRepo.quotes[q.ID] = q
+			}
+			subscriptionRepo := &fakeSubscriptionRepo{subscriptions: map[uuid.UUID]*model.Subscription{}}
+			for _, s := range []*model.Subscription{subscription, otherCourseSubscription, othersSubscription} {
+				subscriptionRepo.subscriptions[s.ID] = s
+			}
+			notifier := &fakeNotifier{}
+			userRepo := &fakeUserRepo{users: map[uuid.UUID]*model.User{payer: {ID: payer}}}
+			svc := NewPaymentService(paymentRepo, userRepo, promotionRepo, subscriptionRepo, notifier, false)
+
+			payment, err := svc.CreatePayment("ref-1", payer, tt.subscriptionID, tt.amount, tt.quoteID, "pending", time.Time{})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("CreatePayment error = %v, want %v", err, tt.wantErr)
+				}
+				if len(paymentRepo.created) != 0 {
+					t.Errorf("a rejected payment was stored")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreatePayment error = %v", err)
+			}
+			if len(paymentRepo.created) != 1 {
+				t.Fatalf("stored %d payments, want 1", len(paymentRepo.created))
+			}
+			if payment.SubscriptionID != tt.wantSubscription {
+				t.Errorf("SubscriptionID = %s, want %s", payment.SubscriptionID, tt.wantSubscription)
+			}
+			if payment.Amount.Currency != "USD" {
+				t.Errorf("Amount = %s, want a USD amount", payment.Amount)
+			}
+
+			// Only a payment with nothing left to pay skips the provider
+			wantStatus := "pending"
+			if tt.wantCompleted {
+				wantStatus = "completed"
+			}
+			if payment.Status != wantStatus {
+				t.Errorf("Status = %q, want %q", payment.Status, wantStatus)
+			}
+			if tt.wantCompleted && (payment.ProcessedAt.IsZero() || len(notifier.receipts) != 1) {
+				t.Errorf("completed payment processed at %s with %d receipts, want a processing time and one receipt", payment.ProcessedAt, len(notifier.receipts))
+			}
+		})
+	}
+}
+
+func TestUpdatePaymentKeepsWhatAQuoteBought(t *testing.T) {
+	bundleID := newTestUUID(t)
+	quote := &model.PriceQuote{
+		ID:        newTestUUID(t),
+		UserID:    newTestUUID(t),
+		BundleID:  &bundleID,
+		Total:     model.Money{Amount: 4999, Currency: "USD"},
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	paymentRepo := &fakePaymentRepo{}
+	promotionRepo := &fakePromotionRepo{quotes: map[uuid.UUID]*model.PriceQuote{quote.ID: quote}}
+	svc := NewPaymentService(paymentRepo, nil, promotionRepo, &fakeSubscriptionRepo{}, nil, false)
+
+	payment, err := svc.CreatePayment("ref-1", quote.UserID, uuid.Nil, quote.Total, &quote.ID, "pending", time.Now())
+	if err != nil {
+		t.Fatalf("CreatePayment error = %v", err)
+	}
+
+	// An admin marking the payment failed cannot change the amount or attach a subscription
+	update := &model.Payment{
+		ID:             payment.ID,
+		ExternalRef:    payment.ExternalRef,
+		UserID:         payment.UserID,
+		SubscriptionID: newTestUUID(t),
+		Amount:         model.Money{Amount: 1, Currency: "EUR"},
+		Status:         "failed",
+	}
+	if err := svc.UpdatePayment(update); err != nil {
+		t.Fatalf("UpdatePayment error = %v", err)
+	}
+
+	stored := paymentRepo.updated[0]
+	if stored.Amount != quote.Total || stored.SubscriptionID != uuid.Nil || stored.QuoteID == nil || *stored.QuoteID != quote.ID {
+		t.Errorf("updated payment = %+v, want the quoted amount, no subscription and the quote", stored)
+	}
+	if stored.Status != "failed" {
+		t.Errorf("Status = %q, want failed", stored.Status)
+	}
+}
+
+func TestCreatePaymentGeneratesExternalRef(t *testing.T) {
+	payer := newTestUUID(t)
+	subscription := &model.Subscription{ID: newTestUUID(t), UserID: payer, CourseID: newTestUUID(t)}
+	subscriptionRepo := &fakeSubscriptionRepo{subscriptions: map[uuid.UUID]*model.Subscription{subscription.ID: subscription}}
+	svc := NewPaymentService(&fakePaymentRepo{}, nil, &fakePromotionRepo{}, subscriptionRepo, nil, false)
+	amount := model.Money{Amount: 1999, Currency: "USD"}
+
+	first, err := svc.CreatePayment("", payer, subscription.ID, amount, nil, "pending", time.Time{})
+	if err != nil {
+		t.Fatalf("CreatePayment error = %v", err)
+	}
+	second, err := svc.CreatePayment("", payer, subscription.ID, amount, nil, "pending", time.Time{})
+	if err != nil {
+		t.Fatalf("CreatePayment error = %v", err)
+	}
+	if first.ExternalRef == "" || first.ExternalRef == second.ExternalRef {
+		t.Errorf("external refs = %q and %q, want two different generated refs", first.ExternalRef, second.ExternalRef)
+	}
+
+	given, err := svc.CreatePayment("waafi-123", payer, subscription.ID, amount, nil, "pending", time.Time{})
+	if err != nil {
+		t.Fatalf("CreatePayment error = %v", err)
+	}
+	if given.ExternalRef != "waafi-123" {
+		t.Errorf("ExternalRef = %q, want the one given", given.ExternalRef)
+	}
+}
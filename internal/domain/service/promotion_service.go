@@ -0,0 +1,404 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/repository"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Promotion errors
+var (
+	ErrCouponNotFound      = errors.New("coupon not found")
+	ErrCouponCodeTaken     = errors.New("coupon code already in use")
+	ErrCouponRedeemed      = errors.New("coupon has been redeemed; deactivate it instead")
+	ErrInvalidCoupon       = errors.New("invalid coupon")
+	ErrCouponNotApplicable = errors.New("coupon is not valid for this purchase")
+	ErrCouponExhausted     = errors.New("coupon has no redemptions left")
+	ErrSaleNotFound        = errors.New("sale not found")
+	ErrSaleOverlaps        = errors.New("the course already has a sale in that currency at that time")
+	ErrInvalidSale         = errors.New("invalid sale")
+	ErrQuoteNotFound       = errors.New("price quote not found")
+	ErrQuoteExpired        = errors.New("price quote has expired; request a new one")
+	ErrQuoteMismatch       = errors.New("payment amount does not match the price quote")
+	ErrQuoteUnavailable    = errors.New("price quote has already been paid or its coupon has no redemptions left")
+)
+
+var couponCodePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,40}$`)
+
+// PromotionService runs coupons and course sales and quotes the price a user will pay
+type PromotionService interface {
+	CreateCoupon(coupon *model.Coupon) error
+	UpdateCoupon(coupon *model.Coupon) error
+	DeleteCoupon(couponID uuid.UUID) error
+	GetCoupon(couponID uuid.UUID) (*model.Coupon, error)
+	ListCoupons(filter model.CouponFilter, page model.PageRequest) (*model.Page[*model.Coupon], error)
+
+	CreateSale(sale *model.CourseSale) error
+	DeleteSale(courseID, saleID uuid.UUID) error
+	ListSales(courseID uuid.UUID) ([]*model.CourseSale, error)
+
+	// QuotePrice works out what userID will be charged for the course in currency (the course's
+	// base currency if empty), with the running sale and the coupon, if a code is given, applied.
+	// The quote is kept for model.PriceQuoteTTL; pass its ID to CreatePayment.
+	QuotePrice(userID, courseID uuid.UUID, currency, couponCode string) (*model.PriceQuote, error)
+	// QuoteBundle and QuotePass quote a bundle or an influencer's all-access pass the same way. They
+	// are priced in one currency only, and only site-wide coupons apply to them.
+	QuoteBundle(userID, bundleID uuid.UUID, currency, couponCode string) (*model.PriceQuote, error)
+	QuotePass(userID, influencerID uuid.UUID, currency, couponCode string) (*model.PriceQuote, error)
+}
+
+type promotionServiceImpl struct {
+	repo       repository.PromotionRepository
+	courseRepo repository.CourseRepository
+	bundleRepo repository.BundleRepository
+}
+
+// NewPromotionService creates the promotion service
+func NewPromotionService(repo repository.PromotionRepository, courseRepo repository.CourseRepository, bundleRepo repository.BundleRepository) PromotionService {
+	return &promotionServiceImpl{repo: repo, courseRepo: courseRepo, bundleRepo: bundleRepo}
+}
+
+// CreateCoupon implements PromotionService.
+func (p *promotionServiceImpl) CreateCoupon(coupon *model.Coupon) error {
+	if err := p.prepareCoupon(coupon); err != nil {
+		return err
+	}
+
+	if err := p.repo.CreateCoupon(coupon); err != nil {
+		return couponError(err)
+	}
+
+	log.Printf("Coupon created: %s (%s)", coupon.Code, coupon.ID)
+	return nil
+}
+
+// UpdateCoupon implements PromotionService. Quotes already made keep their discount.
+func (p *promotionServiceImpl) UpdateCoupon(coupon *model.Coupon) error {
+	if err := p.prepareCoupon(coupon); err != nil {
+		return err
+	}
+
+	if err := p.repo.UpdateCoupon(coupon); err != nil {
+		return couponError(err)
+	}
+	return nil
+}
+
+// DeleteCoupon implements PromotionService. Only coupons nobody has paid with can be deleted.
+func (p *promotionServiceImpl) DeleteCoupon(couponID uuid.UUID) error {
+	deleted, err := p.repo.DeleteCoupon(couponID)
+	if err != nil {
+		return couponError(err)
+	}
+	if !deleted {
+		return ErrCouponNotFound
+	}
+	return nil
+}
+
+// GetCoupon implements PromotionService.
+func (p *promotionServiceImpl) GetCoupon(couponID uuid.UUID) (*model.Coupon, error) {
+	coupon, err := p.repo.GetCoupon(couponID)
+	if err != nil {
+		return nil, couponError(err)
+	}
+	return coupon, nil
+}
+
+// ListCoupons implements PromotionService.
+func (p *promotionServiceImpl) ListCoupons(filter model.CouponFilter, page model.PageRequest) (*model.Page[*model.Coupon], error) {
+	coupons, err := p.repo.ListCoupons(filter, page)
+	if err != nil {
+		return nil, listError("coupons", err)
+	}
+	return coupons, nil
+}
+
+// CreateSale implements PromotionService. The sale price must be below the course's price in
+// the currency, and the sale must not have ended already.
+func (p *promotionServiceImpl) CreateSale(sale *model.CourseSale) error {
+	price, err := model.NewMoney(sale.Price.Amount, sale.Price.Currency)
+	if err != nil {
+		return err
+	}
+	sale.Price = price
+
+	if !sale.EndsAt.After(sale.StartsAt) {
+		return fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidSale)
+	}
+	if !sale.EndsAt.After(time.Now()) {
+		return fmt.Errorf("%w: the sale would already be over", ErrInvalidSale)
+	}
+
+	course, err := p.getCourse(sale.CourseID)
+	if err != nil {
+		return err
+	}
+	regular, ok := course.PriceIn(price.Currency)
+	if !ok {
+		return ErrPriceNotFound
+	}
+	if price.Amount >= regular.Amount {
+		return fmt.Errorf("%w: the sale price must be below the course price of %s", ErrInvalidSale, regular)
+	}
+
+	if err := p.repo.CreateSale(sale); err != nil {
+		if errors.Is(err, model.ErrSaleOverlaps) {
+			return ErrSaleOverlaps
+		}
+		return fmt.Errorf("failed to create sale: %v", err)
+	}
+
+	log.Printf("Sale created for course %s: %s from %s to %s", sale.CourseID, sale.Price, sale.StartsAt, sale.EndsAt)
+	return nil
+}
+
+// DeleteSale implements PromotionService.
+func (p *promotionServiceImpl) DeleteSale(courseID, saleID uuid.UUID) error {
+	deleted, err := p.repo.DeleteSale(courseID, saleID)
+	if err != nil {
+		return fmt.Errorf("failed to delete sale: %v", err)
+	}
+	if !deleted {
+		return ErrSaleNotFound
+	}
+	return nil
+}
+
+// ListSales implements PromotionService. Sales that have ended are left out.
+func (p *promotionServiceImpl) ListSales(courseID uuid.UUID) ([]*model.CourseSale, error) {
+	if _, err := p.getCourse(courseID); err != nil {
+		return nil, err
+	}
+
+	sales, err := p.repo.ListSales(courseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sales: %v", err)
+	}
+	return sales, nil
+}
+
+// QuotePrice implements PromotionService. A sale only applies if it is cheaper than the regular
+// price; the coupon discount is taken off whichever of the two applies.
+func (p *promotionServiceImpl) QuotePrice(userID, courseID uuid.UUID, currency, couponCode string) (*model.PriceQuote, error) {
+	course, err := p.getCourse(courseID)
+	if err != nil {
+		return nil, err
+	}
+	// Only published courses are on sale; others are as good as missing to buyers
+	if course.Status != model.CourseStatusPublished {
+		return nil, ErrCourseNotFound
+	}
+
+	if currency == "" {
+		currency = course.Price.Currency
+	}
+	if currency, err = model.NormalizeCurrency(currency); err != nil {
+		return nil, err
+	}
+	listPrice, ok := course.PriceIn(currency)
+	if !ok {
+		return nil, ErrPriceNotFound
+	}
+
+	quote := &model.PriceQuote{UserID: userID, CourseID: &courseID, ListPrice: listPrice}
+
+	price := listPrice
+	sale, err := p.repo.GetActiveSale(courseID, currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up sale: %v", err)
+	}
+	if sale != nil && sale.Price.Amount < listPrice.Amount {
+		quote.SaleID = &sale.ID
+		quote.SalePrice = &sale.Price
+		price = sale.Price
+	}
+
+	return p.createQuote(quote, price, couponCode)
+}
+
+// QuoteBundle implements PromotionService. Inactive bundles are as good as missing to buyers.
+func (p *promotionServiceImpl) QuoteBundle(userID, bundleID uuid.UUID, currency, couponCode string) (*model.PriceQuote, error) {
+	bundle, err := p.bundleRepo.GetBundle(bundleID)
+	if err != nil {
+		return nil, bundleError(err)
+	}
+	if !bundle.Active {
+		return nil, ErrBundleNotFound
+	}
+
+	price, err := singlePrice(bundle.Price, currency)
+	if err != nil {
+		return nil, err
+	}
+	return p.createQuote(&model.PriceQuote{UserID: userID, BundleID: &bundle.ID, ListPrice: price}, price, couponCode)
+}
+
+// QuotePass implements PromotionService. Inactive passes are as good as missing to buyers.
+func (p *promotionServiceImpl) QuotePass(userID, influencerID uuid.UUID, currency, couponCode string) (*model.PriceQuote, error) {
+	pass, err := p.bundleRepo.GetPass(influencerID)
+	if err != nil {
+		return nil, bundleError(err)
+	}
+	if !pass.Active {
+		return nil, ErrPassNotFound
+	}
+
+	price, err := singlePrice(pass.Price, currency)
+	if err != nil {
+		return nil, err
+	}
+	return p.createQuote(&model.PriceQuote{UserID: userID, PassInfluencerID: &pass.InfluencerID, ListPrice: price}, price, couponCode)
+}
+
+// createQuote takes the coupon discount, if a code is given, off price and stores the quote
+func (p *promotionServiceImpl) createQuote(quote *model.PriceQuote, price model.Money, couponCode string) (*model.PriceQuote, error) {
+	now := time.Now()
+	quote.Discount = model.Money{Currency: price.Currency}
+	quote.ExpiresAt = now.Add(model.PriceQuoteTTL)
+
+	if strings.TrimSpace(couponCode) != "" {
+		coupon, err := p.applicableCoupon(quote.UserID, quote.CourseID, couponCode, now)
+		if err != nil {
+			return nil, err
+		}
+		discount, ok := coupon.Discount(price)
+		if !ok {
+			return nil, fmt.Errorf("%w: it only applies to prices in %s", ErrCouponNotApplicable, coupon.AmountOff.Currency)
+		}
+		quote.CouponID = &coupon.ID
+		quote.CouponCode = coupon.Code
+		quote.Discount = discount
+	}
+
+	quote.Total = model.Money{Amount: price.Amount - quote.Discount.Amount, Currency: price.Currency}
+
+	if err := p.repo.CreateQuote(quote); err != nil {
+		return nil, fmt.Errorf("failed to create price quote: %v", err)
+	}
+	return quote, nil
+}
+
+// singlePrice checks a price set in one currency only against the currency asked for, if any
+func singlePrice(price model.Money, currency string) (model.Money, error) {
+	if currency == "" {
+		return price, nil
+	}
+	currency, err := model.NormalizeCurrency(currency)
+	if err != nil {
+		return model.Money{}, err
+	}
+	if currency != price.Currency {
+		return model.Money{}, ErrPriceNotFound
+	}
+	return price, nil
+}
+
+// applicableCoupon looks up a coupon code and checks it can be used by userID now on the course, or
+// on a bundle or pass if courseID is nil. The limits are checked again when the quote is paid.
+func (p *promotionServiceImpl) applicableCoupon(userID uuid.UUID, courseID *uuid.UUID, code string, now time.Time) (*model.Coupon, error) {
+	coupon, err := p.repo.GetCouponByCode(strings.ToUpper(strings.TrimSpace(code)))
+	if err != nil {
+		return nil, couponError(err)
+	}
+
+	switch {
+	case !coupon.Active:
+		return nil, fmt.Errorf("%w: it is no longer active", ErrCouponNotApplicable)
+	case coupon.StartsAt != nil && now.Before(*coupon.StartsAt):
+		return nil, fmt.Errorf("%w: it cannot be used yet", ErrCouponNotApplicable)
+	case coupon.ExpiresAt != nil && !now.Before(*coupon.ExpiresAt):
+		return nil, fmt.Errorf("%w: it has expired", ErrCouponNotApplicable)
+	case coupon.CourseID != nil && courseID == nil:
+		return nil, fmt.Errorf("%w: it is for a single course", ErrCouponNotApplicable)
+	case coupon.CourseID != nil && *coupon.CourseID != *courseID:
+		return nil, fmt.Errorf("%w: it is for another course", ErrCouponNotApplicable)
+	}
+
+	if coupon.MaxRedemptions != nil && coupon.Redemptions >= *coupon.MaxRedemptions {
+		return nil, ErrCouponExhausted
+	}
+	if coupon.MaxPerUser != nil {
+		used, err := p.repo.CountRedemptions(coupon.ID, &userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count coupon redemptions: %v", err)
+		}
+		if used >= *coupon.MaxPerUser {
+			return nil, fmt.Errorf("%w: you have already used it %d times", ErrCouponExhausted, used)
+		}
+	}
+	return coupon, nil
+}
+
+// prepareCoupon normalizes the code and checks the discount, limits and dates
+func (p *promotionServiceImpl) prepareCoupon(coupon *model.Coupon) error {
+	coupon.Code = strings.ToUpper(strings.TrimSpace(coupon.Code))
+	if !couponCodePattern.MatchString(coupon.Code) {
+		return fmt.Errorf("%w: the code must be 3 to 40 letters, digits, hyphens or underscores", ErrInvalidCoupon)
+	}
+
+	switch coupon.DiscountType {
+	case model.DiscountPercentage:
+		if coupon.PercentOff < 1 || coupon.PercentOff > 100 {
+			return fmt.Errorf("%w: percent_off must be between 1 and 100", ErrInvalidCoupon)
+		}
+		coupon.AmountOff = nil
+	case model.DiscountFixed:
+		if coupon.AmountOff == nil {
+			return fmt.Errorf("%w: a fixed coupon needs amount_off", ErrInvalidCoupon)
+		}
+		amountOff, err := positiveMoney(*coupon.AmountOff)
+		if err != nil {
+			return err
+		}
+		coupon.AmountOff = &amountOff
+		coupon.PercentOff = 0
+	default:
+		return fmt.Errorf("%w: discount_type must be %s or %s", ErrInvalidCoupon, model.DiscountPercentage, model.DiscountFixed)
+	}
+
+	if (coupon.MaxRedemptions != nil && *coupon.MaxRedemptions < 1) || (coupon.MaxPerUser != nil && *coupon.MaxPerUser < 1) {
+		return fmt.Errorf("%w: redemption limits must be at least 1", ErrInvalidCoupon)
+	}
+	if coupon.StartsAt != nil && coupon.ExpiresAt != nil && !coupon.ExpiresAt.After(*coupon.StartsAt) {
+		return fmt.Errorf("%w: expires_at must be after starts_at", ErrInvalidCoupon)
+	}
+
+	if coupon.CourseID != nil {
+		if _, err := p.getCourse(*coupon.CourseID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *promotionServiceImpl) getCourse(courseID uuid.UUID) (*model.Course, error) {
+	course, err := p.courseRepo.GetByID(courseID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, ErrCourseNotFound
+		}
+		return nil, err
+	}
+	return course, nil
+}
+
+// couponError maps coupon repository errors to service errors
+func couponError(err error) error {
+	switch {
+	case errors.Is(err, model.ErrNotFound):
+		return ErrCouponNotFound
+	case errors.Is(err, model.ErrAlreadyInUse):
+		return ErrCouponCodeTaken
+	case errors.Is(err, model.ErrCouponRedeemed):
+		return ErrCouponRedeemed
+	}
+	return fmt.Errorf("coupon operation failed: %v", err)
+}
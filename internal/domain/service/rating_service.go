@@ -15,7 +15,7 @@ type RatingService interface {
 	UpdateRating(rating *model.Rating) error
 	DeleteRating(RatingID uuid.UUID) error
 	GetRatingByID(ratingID uuid.UUID) (*model.Rating, error)
-	GetAllRatings() ([]*model.Rating, error)
+	GetAllRatings(filter model.RatingFilter, page model.PageRequest) (*model.Page[*model.Rating], error)
 }
 
 // RatingServiceImpl struct implementing ratingService
@@ -73,14 +73,13 @@ func (r *RatingServiceImpl) DeleteRating(RatingID uuid.UUID) error {
 }
 
 // GetAllRatings implements RatingService.
-func (r *RatingServiceImpl) GetAllRatings() ([]*model.Rating, error) {
-	// Retrieve all ratings from the repository
-	rating, err := r.repo.Getall()
+func (r *RatingServiceImpl) GetAllRatings(filter model.RatingFilter, page model.PageRequest) (*model.Page[*model.Rating], error) {
+	// Retrieve one page of ratings from the repository
+	ratings, err := r.repo.List(filter, page)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get all rating: %v", err)
+		return nil, listError("ratings", err)
 	}
-	return rating, nil
-
+	return ratings, nil
 }
 
 // GetRatingByID implements RatingService.
@@ -0,0 +1,9 @@
+package service
+
+import "kaabe-app/internal/domain/model"
+
+// SMSSender delivers text messages. The log and outbox implementations for development
+// live in the gateway package; a provider integration only needs to satisfy this interface.
+type SMSSender interface {
+	Send(message *model.SMSMessage) error
+}
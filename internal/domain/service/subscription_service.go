@@ -13,7 +13,7 @@ type SubscriptionService interface{
 	CreateSubscription(userID uuid.UUID, courseID uuid.UUID, StartedAt time.Time , ExpiresAt time.Time, status string ) (*model.Subscription, error)
 	UpdateSubscription(Subscription  *model.Subscription) error
   GetSubscriptionByID(SubscriptionID uuid.UUID) (*model.Subscription, error)
-	GetAllSubscription()([]*model.Subscription, error)
+	GetAllSubscription(filter model.SubscriptionFilter, page model.PageRequest) (*model.Page[*model.Subscription], error)
 	DeleteSubscription(SubscriptionID uuid.UUID) error
 } 
 
@@ -76,12 +76,12 @@ func (s *SubscriptionServiceImpl) DeleteSubscription(SubscriptionID uuid.UUID) e
 	return nil
 }
 
-func  (s *SubscriptionServiceImpl)GetAllSubscription()([]*model.Subscription, error) {
-	 Subscription, err := s.repo.List()
+func (s *SubscriptionServiceImpl) GetAllSubscription(filter model.SubscriptionFilter, page model.PageRequest) (*model.Page[*model.Subscription], error) {
+	subscriptions, err := s.repo.List(filter, page)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get all lesson: %v", err)
+		return nil, listError("subscriptions", err)
 	}
-	return Subscription, nil
+	return subscriptions, nil
 }
 
 func (s *SubscriptionServiceImpl) GetSubscriptionByID(SubscriptionID uuid.UUID) (*model.Subscription, error) {
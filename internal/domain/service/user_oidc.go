@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kaabe-app/internal/domain/model"
+	utils "kaabe-app/pkg/config"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Social login errors
+var (
+	ErrUnknownIdentityProvider = errors.New("unknown identity provider")
+	ErrInvalidOIDCState        = errors.New("invalid or expired login state")
+	ErrIdentityEmailInUse      = errors.New("an account with this email already exists; log in and link the provider from your profile")
+	ErrIdentityLinkedElsewhere = errors.New("this external account is linked to another user")
+	ErrIdentityAlreadyLinked   = errors.New("a different account from this provider is already linked")
+	ErrIdentityNotLinked       = errors.New("identity provider is not linked")
+	ErrLastLoginMethod         = errors.New("cannot unlink the only way to log in; set a password first")
+)
+
+// oidcStateTTL is how long a user has to finish logging in at the provider
+const oidcStateTTL = 10 * time.Minute
+
+// oidcExchangeTimeout bounds the calls made to the provider after the redirect
+const oidcExchangeTimeout = 15 * time.Second
+
+// IdentityProviders lists the configured provider names
+func (s *userService) IdentityProviders() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BeginOIDCLogin returns the provider URL to send the user to and the state to expect back
+func (s *userService) BeginOIDCLogin(provider string) (*model.OIDCAuthorization, error) {
+	return s.authorizationURL(utils.PurposeOIDCLogin, "", provider)
+}
+
+// CompleteOIDCLogin handles the redirect back from the provider. A known identity logs its
+// user in; an unknown one creates an account, unless the email already belongs to a local
+// account, which must link the provider itself so nobody can take it over through a provider.
+// firstName and lastName are only used when the ID token carries no name (Apple).
+func (s *userService) CompleteOIDCLogin(provider, code, state, firstName, lastName string) (*model.LoginResult, error) {
+	external, err := s.exchangeCode(utils.PurposeOIDCLogin, provider, code, state)
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err := s.identityRepo.FindBySubject(external.Provider, external.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up identity: %v", err)
+	}
+
+	var user *model.User
+	if identity != nil {
+		user, err = s.repo.Get(identity.UserID)
+		if err != nil {
+			return nil, errors.New("user not found")
+		}
+	} else {
+		if external.FirstName == "" && external.LastName == "" {
+			external.FirstName, external.LastName = firstName, lastName
+		}
+		user, identity, err = s.registerExternalUser(external)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.identityRepo.TouchLogin(identity.ID); err != nil {
+		log.Printf("Error recording identity login: %v", err)
+	}
+
+	return s.completeLogin(user)
+}
+
+// BeginIdentityLink returns the provider URL for linking an external account to the user
+func (s *userService) BeginIdentityLink(userID uuid.UUID, provider string) (*model.OIDCAuthorization, error) {
+	return s.authorizationURL(utils.PurposeOIDCLink, userID.String(), provider)
+}
+
+// CompleteIdentityLink links the external account from the provider redirect to the user
+func (s *userService) CompleteIdentityLink(userID uuid.UUID, provider, code, state string) (*model.UserIdentity, error) {
+	claims, err := utils.ValidatePurposeToken(state, utils.PurposeOIDCLink)
+	if err != nil || claims.UserID != userID.String() {
+		return nil, ErrInvalidOIDCState
+	}
+
+	external, err := s.exchangeCode(utils.PurposeOIDCLink, provider, code, state)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.identityRepo.FindBySubject(external.Provider, external.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up identity: %v", err)
+	}
+	if existing != nil {
+		if existing.UserID != userID {
+			return nil, ErrIdentityLinkedElsewhere
+		}
+		return existing, nil
+	}
+
+	identity := &model.UserIdentity{
+		UserID:   userID,
+		Provider: external.Provider,
+		Subject:  external.Subject,
+		Email:    external.Email,
+	}
+	if err := s.identityRepo.Create(identity); err != nil {
+		if errors.Is(err, model.ErrIdentityLinked) {
+			return nil, ErrIdentityAlreadyLinked
+		}
+		return nil, err
+	}
+
+	log.Printf("Linked %s identity to user %s", identity.Provider, userID)
+	return identity, nil
+}
+
+// ListIdentities returns the external accounts linked to the user
+func (s *userService) ListIdentities(userID uuid.UUID) ([]*model.UserIdentity, error) {
+	identities, err := s.identityRepo.ListByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list identities: %v", err)
+	}
+	return identities, nil
+}
+
+// UnlinkIdentity removes a linked provider, refusing when it is the user's only way to log in
+func (s *userService) UnlinkIdentity(userID uuid.UUID, provider string) error {
+	user, err := s.repo.Get(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	identities, err := s.identityRepo.ListByUser(userID)
+	if err != nil {
+		return fmt.Errorf("failed to list identities: %v", err)
+	}
+	if user.Password == "" && user.PhoneNumber == "" && len(identities) <= 1 {
+		return ErrLastLoginMethod
+	}
+
+	deleted, err := s.identityRepo.Delete(userID, provider)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrIdentityNotLinked
+	}
+
+	log.Printf("Unlinked %s identity from user %s", provider, userID)
+	return nil
+}
+
+// authorizationURL signs a state for the round trip and asks the provider for its login URL
+func (s *userService) authorizationURL(purpose, userID, provider string) (*model.OIDCAuthorization, error) {
+	idp, ok := s.providers[provider]
+	if !ok {
+		return nil, ErrUnknownIdentityProvider
+	}
+
+	nonce, err := uuid.NewV4()
+	if err != nil {
+		return nil, errors.New("failed to generate nonce")
+	}
+
+	state, err := utils.GenerateOIDCStateToken(purpose, userID, provider, nonce.String(), time.Now().Add(oidcStateTTL).Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate state: %v", err)
+	}
+
+	authURL, err := idp.AuthCodeURL(state, nonce.String())
+	if err != nil {
+		log.Printf("Error building %s authorization URL: %v", provider, err)
+		return nil, fmt.Errorf("%s login is unavailable", provider)
+	}
+	return &model.OIDCAuthorization{URL: authURL, State: state}, nil
+}
+
+// exchangeCode checks the state and trades the code for the provider's verified identity
+func (s *userService) exchangeCode(purpose, provider, code, state string) (*model.ExternalIdentity, error) {
+	idp, ok := s.providers[provider]
+	if !ok {
+		return nil, ErrUnknownIdentityProvider
+	}
+
+	claims, err := utils.ValidatePurposeToken(state, purpose)
+	if err != nil || claims.Provider != provider || claims.Nonce == "" {
+		return nil, ErrInvalidOIDCState
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), oidcExchangeTimeout)
+	defer cancel()
+
+	external, err := idp.Exchange(ctx, code, claims.Nonce)
+	if err != nil {
+		log.Printf("OIDC exchange failed: %v", err)
+		return nil, fmt.Errorf("%s login failed", provider)
+	}
+	return external, nil
+}
+
+// registerExternalUser creates a password-less account for a first-time social login
+func (s *userService) registerExternalUser(external *model.ExternalIdentity) (*model.User, *model.UserIdentity, error) {
+	email := strings.TrimSpace(external.Email)
+	if email == "" {
+		return nil, nil, fmt.Errorf("%s did not share an email address", external.Provider)
+	}
+	if _, err := s.repo.FindByEmail(email); err == nil {
+		return nil, nil, ErrIdentityEmailInUse
+	}
+
+	// An empty password hash never matches, so the account can only log in through the
+	// provider until the user sets a password with the reset flow
+	user := &model.User{
+		Email:     email,
+		FirstName: external.FirstName,
+		LastName:  external.LastName,
+		Role:      model.RoleUser,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := s.repo.Create(user); err != nil {
+		return nil, nil, fmt.Errorf("failed to create user: %v", err)
+	}
+
+	identity := &model.UserIdentity{
+		UserID:   user.ID,
+		Provider: external.Provider,
+		Subject:  external.Subject,
+		Email:    email,
+	}
+	if err := s.identityRepo.Create(identity); err != nil {
+		// Another request registered the same external account first; drop our copy
+		if delErr := s.repo.Delete(user.ID); delErr != nil {
+			log.Printf("Error removing user %s after failed identity link: %v", user.ID, delErr)
+		}
+		return nil, nil, err
+	}
+
+	if external.EmailVerified {
+		if _, err := s.repo.MarkEmailVerified(user.ID); err != nil {
+			log.Printf("Error marking email verified: %v", err)
+		} else {
+			now := time.Now()
+			user.EmailVerifiedAt = &now
+		}
+		if err := s.notifier.SendWelcome(user); err != nil {
+			log.Printf("Welcome email not sent: %v", err)
+		}
+	} else if err := s.sendVerification(user); err != nil {
+		log.Printf("Verification email not sent: %v", err)
+	}
+
+	log.Printf("Registered user %s through %s", user.ID, external.Provider)
+	return user, identity, nil
+}
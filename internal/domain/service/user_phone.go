@@ -0,0 +1,225 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"kaabe-app/internal/domain/model"
+	utils "kaabe-app/pkg/config"
+	"log"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Phone login errors
+var (
+	ErrInvalidPhoneCode = errors.New("invalid or expired code")
+	ErrPhoneInUse       = errors.New("phone number already in use")
+	ErrPhoneNotVerified = errors.New("no verified phone number on this account")
+)
+
+// One-time code settings
+const (
+	otpDigits      = 6
+	otpTTL         = 5 * time.Minute
+	otpMaxAttempts = 5 // wrong guesses before a code stops working
+)
+
+// RequestPhoneLoginCode texts a login code to the number; the same code registers a new account
+func (s *userService) RequestPhoneLoginCode(phoneNumber, ip string) error {
+	phone, err := utils.NormalizePhoneNumber(phoneNumber, s.opts.DefaultCountryCode)
+	if err != nil {
+		return err
+	}
+
+	return s.sendPhoneCode(phone, model.OTPPurposeLogin, nil, ip)
+}
+
+// CompletePhoneLogin checks a login code and logs in the account with that number, creating
+// one if the number is new. Names are only used for new accounts.
+func (s *userService) CompletePhoneLogin(phoneNumber, code, firstName, lastName, ip string) (*model.LoginResult, error) {
+	phone, err := utils.NormalizePhoneNumber(phoneNumber, s.opts.DefaultCountryCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.loginLimiter.Check(phone, ip); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkPhoneCode(phone, model.OTPPurposeLogin, code, nil); err != nil {
+		if errors.Is(err, ErrInvalidPhoneCode) {
+			s.loginLimiter.Failure(phone, ip)
+		}
+		return nil, err
+	}
+
+	user, err := s.repo.FindByPhone(phone)
+	if err != nil {
+		if !errors.Is(err, model.ErrNotFound) {
+			return nil, err
+		}
+		user, err = s.registerPhoneUser(phone, firstName, lastName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := s.completeLogin(user)
+	if err != nil {
+		return nil, err
+	}
+	if result.Tokens != nil {
+		s.loginLimiter.Success(phone)
+	}
+	return result, nil
+}
+
+// RequestPhoneVerification texts a code that adds the number to the user's account
+func (s *userService) RequestPhoneVerification(userID uuid.UUID, phoneNumber, ip string) error {
+	phone, err := utils.NormalizePhoneNumber(phoneNumber, s.opts.DefaultCountryCode)
+	if err != nil {
+		return err
+	}
+
+	if owner, err := s.repo.FindByPhone(phone); err == nil && owner.ID != userID {
+		return ErrPhoneInUse
+	}
+
+	return s.sendPhoneCode(phone, model.OTPPurposeVerify, &userID, ip)
+}
+
+// VerifyPhone checks a verification code and stores the number on the account,
+// optionally making it the default payment wallet
+func (s *userService) VerifyPhone(userID uuid.UUID, phoneNumber, code string, useAsWallet bool) (*model.User, error) {
+	phone, err := utils.NormalizePhoneNumber(phoneNumber, s.opts.DefaultCountryCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkPhoneCode(phone, model.OTPPurposeVerify, code, &userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SetPhone(userID, phone); err != nil {
+		if errors.Is(err, model.ErrAlreadyInUse) {
+			return nil, ErrPhoneInUse
+		}
+		return nil, err
+	}
+	log.Printf("Phone number verified for user %s", userID)
+
+	if useAsWallet {
+		return s.UsePhoneAsWallet(userID)
+	}
+	return s.repo.Get(userID)
+}
+
+// UsePhoneAsWallet makes the user's verified number their default WaafiPay wallet
+func (s *userService) UsePhoneAsWallet(userID uuid.UUID) (*model.User, error) {
+	user, err := s.repo.Get(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if user.PhoneNumber == "" || user.PhoneVerifiedAt == nil {
+		return nil, ErrPhoneNotVerified
+	}
+
+	updated, err := s.repo.SetWalletPhone(userID, user.PhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrPhoneNotVerified
+	}
+
+	user.WalletPhone = user.PhoneNumber
+	return user, nil
+}
+
+// sendPhoneCode stores a new one-time code and texts it to the number
+func (s *userService) sendPhoneCode(phone, purpose string, userID *uuid.UUID, ip string) error {
+	if err := s.otpLimiter.Check(phone, ip); err != nil {
+		return err
+	}
+	// Every request counts, successful or not, so the endpoint cannot be used to flood a number
+	s.otpLimiter.Failure(phone, ip)
+
+	code, err := utils.GenerateOTP(otpDigits)
+	if err != nil {
+		return fmt.Errorf("failed to generate code: %v", err)
+	}
+
+	otp := &model.PhoneOTP{
+		PhoneNumber: phone,
+		Purpose:     purpose,
+		UserID:      userID,
+		CodeHash:    utils.HashOTP(phone, code),
+		ExpiresAt:   time.Now().Add(otpTTL),
+	}
+	if err := s.otpRepo.Create(otp); err != nil {
+		return err
+	}
+
+	message := &model.SMSMessage{
+		To:   phone,
+		Body: fmt.Sprintf("Your %s code is %s. It expires in %d minutes. Never share it with anyone.", s.opts.Issuer, code, int(otpTTL.Minutes())),
+	}
+	if err := s.sms.Send(message); err != nil {
+		log.Printf("Error sending SMS to %s: %v", phone, err)
+		return errors.New("failed to send code")
+	}
+	return nil
+}
+
+// checkPhoneCode accepts the latest code sent to the number for the purpose and uses it up.
+// A code requested by a logged in user only works for that user.
+func (s *userService) checkPhoneCode(phone, purpose, code string, userID *uuid.UUID) error {
+	otp, err := s.otpRepo.FindActive(phone, purpose)
+	if err != nil {
+		return fmt.Errorf("failed to verify code: %v", err)
+	}
+	if otp == nil || otp.Attempts >= otpMaxAttempts {
+		return ErrInvalidPhoneCode
+	}
+
+	wrongUser := userID != nil && (otp.UserID == nil || *otp.UserID != *userID)
+	if wrongUser || utils.HashOTP(phone, code) != otp.CodeHash {
+		if _, err := s.otpRepo.RegisterAttempt(otp.ID); err != nil {
+			log.Printf("Error counting code attempt: %v", err)
+		}
+		return ErrInvalidPhoneCode
+	}
+
+	consumed, err := s.otpRepo.Consume(otp.ID)
+	if err != nil {
+		return fmt.Errorf("failed to verify code: %v", err)
+	}
+	if !consumed {
+		return ErrInvalidPhoneCode
+	}
+	return nil
+}
+
+// registerPhoneUser creates an account for a number that was just verified
+func (s *userService) registerPhoneUser(phone, firstName, lastName string) (*model.User, error) {
+	now := time.Now()
+	user := &model.User{
+		PhoneNumber:     phone,
+		PhoneVerifiedAt: &now,
+		FirstName:       firstName,
+		LastName:        lastName,
+		Role:            model.RoleUser,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if err := s.repo.CreatePhoneUser(user); err != nil {
+		if errors.Is(err, model.ErrAlreadyInUse) {
+			return nil, ErrPhoneInUse
+		}
+		return nil, fmt.Errorf("failed to create user: %v", err)
+	}
+
+	log.Printf("Registered user %s by phone", user.ID)
+	return user, nil
+}
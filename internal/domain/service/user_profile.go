@@ -0,0 +1,180 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"kaabe-app/internal/domain/model"
+	utils "kaabe-app/pkg/config"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Self-service profile errors
+var (
+	ErrInvalidCurrentPassword = errors.New("current password is incorrect")
+	ErrEmailInUse             = errors.New("email already in use")
+	ErrSameEmail              = errors.New("new email is the same as the current one")
+)
+
+// emailChangeTTL is how long the confirmation link sent to a new address stays valid
+const emailChangeTTL = time.Hour
+
+// UpdateProfile changes the fields a user may edit on their own account; nil fields are left as they are
+func (s *userService) UpdateProfile(userID uuid.UUID, firstName, lastName, walletID *string) (*model.User, error) {
+	user, err := s.repo.Get(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if firstName != nil {
+		user.FirstName = *firstName
+	}
+	if lastName != nil {
+		user.LastName = *lastName
+	}
+	if walletID != nil {
+		// An empty wallet ID removes the wallet
+		if *walletID == "" {
+			user.WalletID = nil
+		} else {
+			user.WalletID = walletID
+		}
+	}
+
+	if err := s.repo.Update(user); err != nil {
+		return nil, fmt.Errorf("failed to update profile: %v", err)
+	}
+	return user, nil
+}
+
+// ChangePassword replaces the password after checking the current one and signs out every
+// other session; the session making the request stays logged in. Accounts created through
+// social or phone login have no password yet and set their first one here.
+func (s *userService) ChangePassword(userID uuid.UUID, accessToken, currentPassword, newPassword string) error {
+	user, err := s.repo.Get(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	if !s.checkCurrentPassword(user, currentPassword) {
+		return ErrInvalidCurrentPassword
+	}
+
+	hashedPassword, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash new password: %v", err)
+	}
+
+	if err := s.repo.UpdatePassword(user.ID, hashedPassword); err != nil {
+		return fmt.Errorf("failed to update password: %v", err)
+	}
+
+	if err := s.revokeOtherSessions(user.ID, accessToken); err != nil {
+		return err
+	}
+
+	log.Printf("Password changed for user: %s", user.ID)
+	if err := s.notifier.SendPasswordChanged(user); err != nil {
+		log.Printf("Password change notice not sent: %v", err)
+	}
+	return nil
+}
+
+// RequestEmailChange sends a confirmation link to the new address; the email only
+// changes once that link is used
+func (s *userService) RequestEmailChange(userID uuid.UUID, currentPassword, newEmail string) error {
+	user, err := s.repo.Get(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	if !s.checkCurrentPassword(user, currentPassword) {
+		return ErrInvalidCurrentPassword
+	}
+
+	newEmail = strings.TrimSpace(newEmail)
+	if strings.EqualFold(newEmail, user.Email) {
+		return ErrSameEmail
+	}
+	if _, err := s.repo.FindByEmail(newEmail); err == nil {
+		return ErrEmailInUse
+	}
+
+	expiresAt := time.Now().Add(emailChangeTTL)
+	token, err := utils.GenerateEmailChangeToken(user.ID.String(), user.Email, newEmail, expiresAt.Unix())
+	if err != nil {
+		return fmt.Errorf("failed to generate confirmation token: %v", err)
+	}
+
+	if err := s.notifier.SendEmailChangeConfirmation(user, newEmail, token, expiresAt); err != nil {
+		return fmt.Errorf("failed to send confirmation email: %v", err)
+	}
+	return nil
+}
+
+// ConfirmEmailChange applies the email change in a confirmation token and warns the old address
+func (s *userService) ConfirmEmailChange(token string) error {
+	claims, err := utils.ValidatePurposeToken(token, utils.PurposeEmailChange)
+	if err != nil || claims.NewEmail == "" {
+		return errors.New("invalid or expired confirmation token")
+	}
+
+	userID, err := uuid.FromString(claims.UserID)
+	if err != nil {
+		return errors.New("invalid or expired confirmation token")
+	}
+
+	user, err := s.repo.Get(userID)
+	if err != nil {
+		return errors.New("invalid or expired confirmation token")
+	}
+
+	// The token is bound to the email it was requested from, so it works only once
+	if user.Email != claims.Email {
+		return errors.New("invalid or expired confirmation token")
+	}
+
+	if err := s.repo.ChangeEmail(user.ID, claims.NewEmail); err != nil {
+		if errors.Is(err, model.ErrAlreadyInUse) {
+			return ErrEmailInUse
+		}
+		return err
+	}
+
+	oldEmail := user.Email
+	user.Email = claims.NewEmail
+	log.Printf("Email changed for user: %s", user.ID)
+	if err := s.notifier.SendEmailChanged(user, oldEmail); err != nil {
+		log.Printf("Email change notice not sent: %v", err)
+	}
+	return nil
+}
+
+// checkCurrentPassword confirms a sensitive change; password-less accounts have nothing to confirm
+func (s *userService) checkCurrentPassword(user *model.User, currentPassword string) bool {
+	if user.Password == "" {
+		return true
+	}
+	return utils.CheckPasswordHash(currentPassword, user.Password)
+}
+
+// revokeOtherSessions revokes every session of the user except the one the access token belongs to
+func (s *userService) revokeOtherSessions(userID uuid.UUID, accessToken string) error {
+	current, err := s.tokenRepo.FindByToken(accessToken)
+	if err != nil {
+		return fmt.Errorf("failed to look up token: %v", err)
+	}
+
+	// Tokens issued before sessions had families cannot be told apart; sign everything out
+	if current == nil || current.FamilyID == nil {
+		return s.LogoutAll(userID)
+	}
+
+	if err := s.tokenRepo.RevokeAllForUserExcept(userID, *current.FamilyID); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %v", err)
+	}
+	return nil
+}
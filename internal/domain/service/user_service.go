@@ -16,8 +16,17 @@ type UserService interface {
 	// Create a new user
 	RegisterUser(email, password, firstName, lastName, role, walletID string) (*model.User, error)
 
-	// Authenticate user
-	AuthenticateUser(email, password string) (*model.User, error)
+	// Authenticate user and issue an access/refresh token pair, or start the second step when
+	// two-factor authentication applies; ip is used for brute-force protection
+	AuthenticateUser(email, password, ip string) (*model.LoginResult, error)
+
+	// Exchange a refresh token for a new token pair
+	RefreshTokens(refreshToken string) (*model.AuthTokens, error)
+
+	// Session revocation
+	Logout(accessToken string) error
+	LogoutAll(userID uuid.UUID) error
+	RevokeUserSessions(userID uuid.UUID) error
 
 	// Get user by ID
 	GetUserByID(userID uuid.UUID) (*model.User, error)
@@ -29,20 +38,92 @@ type UserService interface {
 	DeleteUser(userID uuid.UUID) error
 
 	// List all users
-	ListUsers() ([]*model.User, error)
+	ListUsers(filter model.UserFilter, page model.PageRequest) (*model.Page[*model.User], error)
 
 	// Forgot/reset password
-	ForgotPassword(email string) error
+	ForgotPassword(email, ip string) error
 	ResetPassword(token uuid.UUID, newPassword string) error
+
+	// Email verification
+	VerifyEmail(token string) error
+	ResendVerificationEmail(email, ip string) error
+
+	// Self-service profile
+	UpdateProfile(userID uuid.UUID, firstName, lastName, walletID *string) (*model.User, error)
+	ChangePassword(userID uuid.UUID, accessToken, currentPassword, newPassword string) error
+	RequestEmailChange(userID uuid.UUID, currentPassword, newEmail string) error
+	ConfirmEmailChange(token string) error
+
+	// Two-factor authentication
+	CompleteTwoFactorLogin(mfaToken, code, ip string) (*model.User, *model.AuthTokens, error)
+	BeginTwoFactorEnrollment(userID uuid.UUID) (*model.TOTPEnrollment, error)
+	ActivateTwoFactor(userID uuid.UUID, code string) ([]string, error)
+	DisableTwoFactor(userID uuid.UUID, code string) error
+	RegenerateRecoveryCodes(userID uuid.UUID, code string) ([]string, error)
+	BeginRequiredEnrollment(mfaToken string) (*model.TOTPEnrollment, error)
+	CompleteRequiredEnrollment(mfaToken, code, ip string) (*model.User, *model.AuthTokens, []string, error)
+
+	// Social login through OIDC providers
+	IdentityProviders() []string
+	BeginOIDCLogin(provider string) (*model.OIDCAuthorization, error)
+	CompleteOIDCLogin(provider, code, state, firstName, lastName string) (*model.LoginResult, error)
+	BeginIdentityLink(userID uuid.UUID, provider string) (*model.OIDCAuthorization, error)
+	CompleteIdentityLink(userID uuid.UUID, provider, code, state string) (*model.UserIdentity, error)
+	ListIdentities(userID uuid.UUID) ([]*model.UserIdentity, error)
+	UnlinkIdentity(userID uuid.UUID, provider string) error
+
+	// Phone number login with SMS codes
+	RequestPhoneLoginCode(phoneNumber, ip string) error
+	CompletePhoneLogin(phoneNumber, code, firstName, lastName, ip string) (*model.LoginResult, error)
+	RequestPhoneVerification(userID uuid.UUID, phoneNumber, ip string) error
+	VerifyPhone(userID uuid.UUID, phoneNumber, code string, useAsWallet bool) (*model.User, error)
+	UsePhoneAsWallet(userID uuid.UUID) (*model.User, error)
+}
+
+// UserServiceOptions holds the account security settings from config
+type UserServiceOptions struct {
+	Issuer               string        // shown in authenticator apps
+	VerificationTTL      time.Duration // lifetime of email verification links
+	RequireVerifiedLogin bool          // block login until the email is verified
+	RequireTwoFactor     bool          // admins and influencers must use 2FA
+	DefaultCountryCode   string        // assumed for phone numbers typed without one, e.g. "252"
 }
 
+// ErrRefreshTokenReused is returned when an already rotated refresh token is presented again
+var ErrRefreshTokenReused = errors.New("refresh token reuse detected")
+
+// ErrEmailAlreadyVerified is returned when verifying an address that is already verified
+var ErrEmailAlreadyVerified = errors.New("email already verified")
+
 type userService struct {
-	repo      repository.UserRepository
-	tokenRepo repository.TokenRepository
+	repo          repository.UserRepository
+	tokenRepo     repository.TokenRepository
+	twoFactorRepo repository.TwoFactorRepository
+	identityRepo  repository.IdentityRepository
+	providers     map[string]IdentityProvider
+	notifier      NotificationService
+	loginLimiter  *AttemptLimiter
+	resetLimiter  *AttemptLimiter
+	otpRepo       repository.PhoneOTPRepository
+	sms           SMSSender
+	otpLimiter    *AttemptLimiter
+	verifyLimiter *AttemptLimiter
+	opts          UserServiceOptions
 }
 
 // Register a new user
 func (s *userService) RegisterUser(email, password, firstName, lastName, role, walletID string) (*model.User, error) {
+	// Public registration only creates learners; influencers apply and are approved by an admin
+	if role == "" {
+		role = model.RoleUser
+	}
+	if role == model.RoleInfluencer {
+		return nil, ErrInfluencerApplicationRequired
+	}
+	if role != model.RoleUser {
+		return nil, fmt.Errorf("invalid user role: %s", role)
+	}
+
 	// Check if user already exists
 	if _, err := s.repo.FindByEmail(email); err == nil {
 		return nil, errors.New("user already exists")
@@ -66,61 +147,250 @@ func (s *userService) RegisterUser(email, password, firstName, lastName, role, w
 		FirstName: firstName,
 		LastName:  lastName,
 		Role:      role,
-		WalletID:  &walletID,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
+	// The wallet is optional; an empty string is not a valid UUID for the column
+	if walletID != "" {
+		user.WalletID = &walletID
+	}
 
 	// Save user
 	if err := s.repo.Create(user); err != nil {
 		return nil, err
 	}
 
+	if err := s.sendVerification(user); err != nil {
+		log.Printf("Verification email not sent: %v", err)
+	}
+
 	return user, nil
 }
 
+// Token lifetimes for issued JWTs
+const (
+	accessTokenTTL  = 15 * time.Minute
+	refreshTokenTTL = 7 * 24 * time.Hour
+)
+
 // Authenticate a user
-func (s *userService) AuthenticateUser(email, password string) (*model.User, error) {
-    user, err := s.repo.FindByEmail(email)
-    if err != nil {
-        return nil, errors.New("invalid email or password")
-    }
-
-    // Check the password hash
-    if !utils.CheckPasswordHash(password, user.Password) {
-        return nil, errors.New("invalid email or password")
-    }
-
-    // Validate role from DB
-    validRoles := map[string]bool{
-        "user":       true,
-        "admin":      true,
-        "influencer": true,
-    }
-    if !validRoles[user.Role] {
-        user.Role = "user"
-    }
-
-    // Create token (optional session feature)
-    newToken, err := uuid.NewV4()
-    if err != nil {
-        return nil, errors.New("failed to generate token")
-    }
-
-    token := &model.Token{
-        ID:        newToken,
-        UserID:    user.ID,
-        Token:     newToken.String(),
-        ExpiresAt: time.Now().Add(24 * time.Hour),
-    }
-
-    if err := s.tokenRepo.Create(token); err != nil {
-        return nil, errors.New("failed to save token")
-    }
-
-    return user, nil
+func (s *userService) AuthenticateUser(email, password, ip string) (*model.LoginResult, error) {
+	if err := s.loginLimiter.Check(email, ip); err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.FindByEmail(email)
+	if err != nil {
+		s.loginLimiter.Failure(email, ip)
+		return nil, errors.New("invalid email or password")
+	}
+
+	// Check the password hash
+	if !utils.CheckPasswordHash(password, user.Password) {
+		s.loginLimiter.Failure(email, ip)
+		return nil, errors.New("invalid email or password")
+	}
+
+	result, err := s.completeLogin(user)
+	if err != nil {
+		return nil, err
+	}
+	if result.Tokens != nil {
+		s.loginLimiter.Success(email)
+	}
+	return result, nil
 }
 
+// completeLogin finishes a login once the user has proven who they are, with a password
+// or an external identity: it enforces email verification and 2FA, then starts a session
+func (s *userService) completeLogin(user *model.User) (*model.LoginResult, error) {
+	if s.opts.RequireVerifiedLogin && !user.HasVerifiedContact() {
+		return nil, ErrEmailNotVerified
+	}
+
+	// Validate role from DB
+	validRoles := map[string]bool{
+		"user":       true,
+		"admin":      true,
+		"influencer": true,
+	}
+	if !validRoles[user.Role] {
+		user.Role = "user"
+	}
+
+	// The first step was accepted but the login is not finished until the second factor is given
+	if user.TOTPEnabledAt != nil {
+		mfaToken, err := s.pendingLoginToken(user, utils.PurposeMFALogin, mfaLoginTTL)
+		if err != nil {
+			return nil, err
+		}
+		return &model.LoginResult{User: user, MFARequired: true, MFAToken: mfaToken}, nil
+	}
+	if s.requiresTwoFactor(user) {
+		mfaToken, err := s.pendingLoginToken(user, utils.PurposeMFAEnrollment, mfaEnrollmentTTL)
+		if err != nil {
+			return nil, err
+		}
+		return &model.LoginResult{User: user, MFAEnrollmentRequired: true, MFAToken: mfaToken}, nil
+	}
+
+	tokens, err := s.startSession(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.LoginResult{User: user, Tokens: tokens}, nil
+}
+
+// startSession issues tokens in a new refresh token family; every login starts one
+func (s *userService) startSession(user *model.User) (*model.AuthTokens, error) {
+	familyID, err := uuid.NewV4()
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+
+	return s.issueTokens(user, familyID)
+}
+
+// RefreshTokens rotates a refresh token, revoking its whole family if it was already used
+func (s *userService) RefreshTokens(refreshToken string) (*model.AuthTokens, error) {
+	claims, err := utils.ValidateToken(refreshToken, true)
+	if err != nil {
+		return nil, errors.New("invalid or expired refresh token")
+	}
+
+	stored, err := s.tokenRepo.FindRefreshToken(refreshToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up refresh token: %v", err)
+	}
+	if stored == nil || stored.FamilyID == nil || stored.UserID.String() != claims.UserID {
+		return nil, errors.New("invalid or expired refresh token")
+	}
+
+	if stored.DeletedAt != nil {
+		return nil, errors.New("refresh token has been revoked")
+	}
+
+	// Mark as used atomically so two concurrent refreshes cannot both succeed
+	fresh := stored.UsedAt == nil
+	if fresh {
+		fresh, err = s.tokenRepo.MarkUsed(stored.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to rotate refresh token: %v", err)
+		}
+	}
+	if !fresh {
+		log.Printf("Refresh token reuse detected for user %s, revoking family %s", stored.UserID, *stored.FamilyID)
+		if err := s.tokenRepo.RevokeFamily(*stored.FamilyID); err != nil {
+			log.Printf("Error revoking token family: %v", err)
+		}
+		return nil, ErrRefreshTokenReused
+	}
+
+	user, err := s.repo.Get(stored.UserID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	return s.issueTokens(user, *stored.FamilyID)
+}
+
+// issueTokens signs a new access/refresh pair in the given family and records both for revocation checks
+func (s *userService) issueTokens(user *model.User, familyID uuid.UUID) (*model.AuthTokens, error) {
+	now := time.Now()
+	accessExpiry := now.Add(accessTokenTTL)
+	refreshExpiry := now.Add(refreshTokenTTL)
+
+	accessToken, err := utils.GenereteToken(user.ID.String(), accessExpiry.Unix())
+	if err != nil {
+		log.Printf("Error signing access token: %v", err)
+		return nil, errors.New("failed to generate token")
+	}
+
+	refreshToken, err := utils.GenerateRefreshToken(user.ID.String(), refreshExpiry.Unix())
+	if err != nil {
+		log.Printf("Error signing refresh token: %v", err)
+		return nil, errors.New("failed to generate token")
+	}
+
+	if err := s.storeToken(user.ID, accessToken, model.TokenTypeAccess, familyID, accessExpiry); err != nil {
+		return nil, err
+	}
+	if err := s.storeToken(user.ID, refreshToken, model.TokenTypeRefresh, familyID, refreshExpiry); err != nil {
+		return nil, err
+	}
+
+	return &model.AuthTokens{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		TokenType:        "Bearer",
+		ExpiresAt:        accessExpiry,
+		RefreshExpiresAt: refreshExpiry,
+	}, nil
+}
+
+// Logout revokes the session the access token belongs to, including its refresh token
+func (s *userService) Logout(accessToken string) error {
+	token, err := s.tokenRepo.FindByToken(accessToken)
+	if err != nil {
+		return fmt.Errorf("failed to look up token: %v", err)
+	}
+	if token == nil {
+		return errors.New("token not found")
+	}
+
+	if token.FamilyID != nil {
+		if err := s.tokenRepo.RevokeFamily(*token.FamilyID); err != nil {
+			return fmt.Errorf("failed to revoke session: %v", err)
+		}
+		return nil
+	}
+
+	if err := s.tokenRepo.Revoke(accessToken); err != nil {
+		return fmt.Errorf("failed to revoke token: %v", err)
+	}
+	return nil
+}
+
+// LogoutAll revokes every session of the user
+func (s *userService) LogoutAll(userID uuid.UUID) error {
+	if err := s.tokenRepo.RevokeAllForUser(userID); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %v", err)
+	}
+	return nil
+}
+
+// RevokeUserSessions revokes every session of another user (admin only, enforced by the route)
+func (s *userService) RevokeUserSessions(userID uuid.UUID) error {
+	if _, err := s.repo.Get(userID); err != nil {
+		return errors.New("user not found")
+	}
+
+	log.Printf("Revoking all sessions of user %s", userID)
+	return s.LogoutAll(userID)
+}
+
+// storeToken persists an issued token so it can be revoked later
+func (s *userService) storeToken(userID uuid.UUID, value, tokenType string, familyID uuid.UUID, expiresAt time.Time) error {
+	tokenID, err := uuid.NewV4()
+	if err != nil {
+		return errors.New("failed to generate token")
+	}
+
+	token := &model.Token{
+		ID:        tokenID,
+		UserID:    userID,
+		Token:     value,
+		Type:      tokenType,
+		FamilyID:  &familyID,
+		ExpiresAt: expiresAt,
+	}
+
+	if err := s.tokenRepo.Create(token); err != nil {
+		return errors.New("failed to save token")
+	}
+	return nil
+}
 
 // Get user by ID
 func (s *userService) GetUserByID(userID uuid.UUID) (*model.User, error) {
@@ -133,13 +403,33 @@ func (s *userService) GetUserByID(userID uuid.UUID) (*model.User, error) {
 
 // Update user
 func (s *userService) UpdateUser(user *model.User) error {
-	_, err := s.repo.Get(user.ID)
+	existing, err := s.repo.Get(user.ID)
 	if err != nil {
 		return errors.New("user not found")
 	}
+
+	// Passwords only change through the reset and change-password flows; keep the stored hash
+	user.Password = existing.Password
+	user.CreatedAt = existing.CreatedAt
+	user.EmailVerifiedAt = existing.EmailVerifiedAt
+	user.TOTPEnabledAt = existing.TOTPEnabledAt
+	user.PhoneNumber = existing.PhoneNumber
+	user.PhoneVerifiedAt = existing.PhoneVerifiedAt
+	user.WalletPhone = existing.WalletPhone
+	// A new address is unverified until its owner confirms it; update_user clears the timestamp too
+	emailChanged := user.Email != existing.Email
+	if emailChanged {
+		user.EmailVerifiedAt = nil
+	}
 	if err := s.repo.Update(user); err != nil {
 		return fmt.Errorf("failed to update user: %v", err)
 	}
+
+	if emailChanged {
+		if err := s.sendVerification(user); err != nil {
+			log.Printf("Verification email not sent to changed address: %v", err)
+		}
+	}
 	return nil
 }
 
@@ -150,7 +440,8 @@ func (s *userService) DeleteUser(userID uuid.UUID) error {
 		log.Printf("User not found: %v", err)
 		return fmt.Errorf("user not found")
 	}
-	if err := s.repo.Delete(userID); err != nil {
+	// Anonymize rather than delete, so the user's payments and withdrawals are kept
+	if err := s.repo.Anonymize(userID); err != nil {
 		log.Printf("Error deleting user: %v", err)
 		return err
 	}
@@ -159,18 +450,25 @@ func (s *userService) DeleteUser(userID uuid.UUID) error {
 }
 
 // List users
-func (s *userService) ListUsers() ([]*model.User, error) {
-	users, err := s.repo.List()
+func (s *userService) ListUsers(filter model.UserFilter, page model.PageRequest) (*model.Page[*model.User], error) {
+	users, err := s.repo.List(filter, page)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list users: %v", err)
+		return nil, listError("users", err)
 	}
 	return users, nil
 }
 
 // ForgotPassword sets a reset token and expiry for the user
-func (s *userService) ForgotPassword(email string) error {
+func (s *userService) ForgotPassword(email, ip string) error {
+	if err := s.resetLimiter.Check(email, ip); err != nil {
+		return err
+	}
+	// Every request counts, successful or not, so reset emails cannot be used for flooding
+	s.resetLimiter.Failure(email, ip)
+
 	// Just check if the user exists
-	if _, err := s.repo.FindByEmail(email); err != nil {
+	user, err := s.repo.FindByEmail(email)
+	if err != nil {
 		return errors.New("email not found")
 	}
 
@@ -180,21 +478,22 @@ func (s *userService) ForgotPassword(email string) error {
 		return errors.New("failed to generate reset token")
 	}
 
-	expiry := time.Now().Add(15 * time.Minute).Format(time.RFC3339)
+	expiresAt := time.Now().Add(15 * time.Minute)
+	expiry := expiresAt.Format(time.RFC3339)
 
 	// Store reset token
 	if err := s.repo.SetResetToken(email, resetToken, expiry); err != nil {
 		return fmt.Errorf("failed to store reset token: %v", err)
 	}
 
-	// 🚀 Simulate email sending (for now)
-	log.Printf("Reset token sent to %s: %s", email, resetToken.String())
+	if err := s.notifier.SendPasswordReset(user, resetToken.String(), expiresAt); err != nil {
+		return fmt.Errorf("failed to send reset email: %v", err)
+	}
 
 	return nil
 }
 
-
-// ResetPassword updates the user's password using a valid reset token
+// ResetPassword updates the user's password using a valid reset token and signs out every session
 func (s *userService) ResetPassword(token uuid.UUID, newPassword string) error {
 	user, err := s.repo.FindByResetToken(token)
 	if err != nil {
@@ -214,14 +513,122 @@ func (s *userService) ResetPassword(token uuid.UUID, newPassword string) error {
 		return fmt.Errorf("failed to clear reset token: %v", err)
 	}
 
+	// Whoever knew the old password must not stay signed in
+	if err := s.LogoutAll(user.ID); err != nil {
+		return err
+	}
+
 	log.Printf("Password reset successful for user: %s", user.Email)
 	return nil
 }
 
+// VerifyEmail marks the address in a verification token as verified and sends the welcome email
+func (s *userService) VerifyEmail(token string) error {
+	claims, err := utils.ValidatePurposeToken(token, utils.PurposeEmailVerification)
+	if err != nil {
+		return errors.New("invalid or expired verification token")
+	}
+
+	userID, err := uuid.FromString(claims.UserID)
+	if err != nil {
+		return errors.New("invalid or expired verification token")
+	}
+
+	user, err := s.repo.Get(userID)
+	if err != nil {
+		return errors.New("invalid or expired verification token")
+	}
+
+	// A token issued before an email change must not verify the new address
+	if user.Email != claims.Email {
+		return errors.New("invalid or expired verification token")
+	}
+
+	verified, err := s.repo.MarkEmailVerified(user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to verify email: %v", err)
+	}
+	if !verified {
+		return ErrEmailAlreadyVerified
+	}
+
+	log.Printf("Email verified for user: %s", user.ID)
+	if err := s.notifier.SendWelcome(user); err != nil {
+		log.Printf("Welcome email not sent: %v", err)
+	}
+	return nil
+}
+
+// ResendVerificationEmail sends a fresh verification link. Unknown and already verified
+// addresses are ignored so the endpoint cannot be used to discover accounts.
+func (s *userService) ResendVerificationEmail(email, ip string) error {
+	if err := s.verifyLimiter.Check(email, ip); err != nil {
+		return err
+	}
+	// Every request counts, like password resets, so the endpoint cannot be used for flooding
+	s.verifyLimiter.Failure(email, ip)
+
+	user, err := s.repo.FindByEmail(email)
+	if err != nil || user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	return s.sendVerification(user)
+}
+
+// sendVerification signs a verification token for the user's current email and mails it
+func (s *userService) sendVerification(user *model.User) error {
+	if user.Email == "" {
+		return nil
+	}
+
+	expiresAt := time.Now().Add(s.opts.VerificationTTL)
+
+	token, err := utils.GeneratePurposeToken(user.ID.String(), user.Email, utils.PurposeEmailVerification, expiresAt.Unix())
+	if err != nil {
+		return fmt.Errorf("failed to generate verification token: %v", err)
+	}
+
+	if err := s.notifier.SendEmailVerification(user, token, expiresAt); err != nil {
+		return fmt.Errorf("failed to send verification email: %v", err)
+	}
+	return nil
+}
+
 // Factory
-func NewUserService(userRepo repository.UserRepository, tokenRepo repository.TokenRepository) UserService {
+func NewUserService(
+	userRepo repository.UserRepository,
+	tokenRepo repository.TokenRepository,
+	twoFactorRepo repository.TwoFactorRepository,
+	identityRepo repository.IdentityRepository,
+	providers []IdentityProvider,
+	notifier NotificationService,
+	loginLimiter *AttemptLimiter,
+	resetLimiter *AttemptLimiter,
+	otpRepo repository.PhoneOTPRepository,
+	sms SMSSender,
+	otpLimiter *AttemptLimiter,
+	verifyLimiter *AttemptLimiter,
+	opts UserServiceOptions,
+) UserService {
+	providersByName := make(map[string]IdentityProvider, len(providers))
+	for _, provider := range providers {
+		providersByName[provider.Name()] = provider
+	}
+
 	return &userService{
-		repo:      userRepo,
-		tokenRepo: tokenRepo,
+		repo:          userRepo,
+		tokenRepo:     tokenRepo,
+		twoFactorRepo: twoFactorRepo,
+		identityRepo:  identityRepo,
+		providers:     providersByName,
+		notifier:      notifier,
+		loginLimiter:  loginLimiter,
+		resetLimiter:  resetLimiter,
+		otpRepo:       otpRepo,
+		sms:           sms,
+		otpLimiter:    otpLimiter,
+		verifyLimiter: verifyLimiter,
+		opts:          opts,
 	}
 }
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"errors"
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/repository"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// fakeTokenRepo keeps issued tokens in memory and revokes them by setting DeletedAt
+type fakeTokenRepo struct {
+	repository.TokenRepository
+	tokens []*model.Token
+}
+
+func (r *fakeTokenRepo) Create(token *model.Token) error {
+	r.tokens = append(r.tokens, token)
+	return nil
+}
+
+func (r *fakeTokenRepo) FindRefreshToken(value string) (*model.Token, error) {
+	for _, token := range r.tokens {
+		if token.Token == value && token.Type == model.TokenTypeRefresh {
+			return token, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeTokenRepo) MarkUsed(tokenID uuid.UUID) (bool, error) {
+	for _, token := range r.tokens {
+		if token.ID == tokenID && token.UsedAt == nil {
+			now := time.Now()
+			token.UsedAt = &now
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeTokenRepo) RevokeFamily(familyID uuid.UUID) error {
+	r.revoke(func(token *model.Token) bool { return token.FamilyID != nil && *token.FamilyID == familyID })
+	return nil
+}
+
+func (r *fakeTokenRepo) RevokeAllForUser(userID uuid.UUID) error {
+	r.revoke(func(token *model.Token) bool { return token.UserID == userID })
+	return nil
+}
+
+func (r *fakeTokenRepo) revoke(match func(*model.Token) bool) {
+	now := time.Now()
+	for _, token := range r.tokens {
+		if match(token) && token.DeletedAt == nil {
+			token.DeletedAt = &now
+		}
+	}
+}
+
+func TestRefreshTokens(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-access-secret")
+	t.Setenv("JWT_REFRESH_SECRET", "test-refresh-secret")
+
+	user := &model.User{ID: newTestUUID(t)}
+	tokenRepo := &fakeTokenRepo{}
+	svc := &userService{
+		repo:      &fakeUserRepo{users: map[uuid.UUID]*model.User{user.ID: user}},
+		tokenRepo: tokenRepo,
+	}
+
+	login, err := svc.startSession(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherLogin, err := svc.startSession(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Rotation hands out a new pair and spends the old refresh token
+	rotated, err := svc.RefreshTokens(login.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshTokens = %v, want new tokens", err)
+	}
+	if rotated.RefreshToken == login.RefreshToken {
+		t.Error("RefreshTokens returned the same refresh token")
+	}
+	if stored, _ := tokenRepo.FindRefreshToken(login.RefreshToken); stored.UsedAt == nil {
+		t.Error("the rotated refresh token was not marked used")
+	}
+
+	// Presenting the spent token again revokes every token issued from that login
+	if _, err := svc.RefreshTokens(login.RefreshToken); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("RefreshTokens with a used token = %v, want %v", err, ErrRefreshTokenReused)
+	}
+	if _, err := svc.RefreshTokens(rotated.RefreshToken); err == nil {
+		t.Error("the refresh token rotated from a reused one still works")
+	}
+	for _, token := range tokenRepo.tokens {
+		revoked := token.DeletedAt != nil
+		inFamily := *token.FamilyID == *tokenRepo.tokens[0].FamilyID
+		if revoked != inFamily {
+			t.Errorf("%s token in family %s revoked = %v, want %v", token.Type, *token.FamilyID, revoked, inFamily)
+		}
+	}
+
+	// Other sessions keep working
+	if _, err := svc.RefreshTokens(otherLogin.RefreshToken); err != nil {
+		t.Errorf("RefreshTokens for another session = %v, want new tokens", err)
+	}
+
+	if _, err := svc.RefreshTokens(login.AccessToken); err == nil {
+		t.Error("RefreshTokens accepted an access token")
+	}
+}
+
+// fakeResetUserRepo serves one user by reset token and records the password it is given
+type fakeResetUserRepo struct {
+	fakeUserRepo
+	resetToken uuid.UUID
+	password   string
+}
+
+func (r *fakeResetUserRepo) FindByResetToken(token uuid.UUID) (*model.User, error) {
+	if token != r.resetToken {
+		return nil, errors.New("user not found")
+	}
+	for _, user := range r.users {
+		return user, nil
+	}
+	return nil, errors.New("user not found")
+}
+
+func (r *fakeResetUserRepo) UpdatePassword(userID uuid.UUID, hashedPassword string) error {
+	r.password = hashedPassword
+	return nil
+}
+
+func (r *fakeResetUserRepo) ClearResetToken(userID uuid.UUID) error {
+	r.resetToken = uuid.Nil
+	return nil
+}
+
+func TestResetPasswordRevokesSessions(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-access-secret")
+	t.Setenv("JWT_REFRESH_SECRET", "test-refresh-secret")
+
+	user := &model.User{ID: newTestUUID(t)}
+	userRepo := &fakeResetUserRepo{
+		fakeUserRepo: fakeUserRepo{users: map[uuid.UUID]*model.User{user.ID: user}},
+		resetToken:   newTestUUID(t),
+	}
+	tokenRepo := &fakeTokenRepo{}
+	svc := &userService{repo: userRepo, tokenRepo: tokenRepo}
+
+	for i := 0; i < 2; i++ {
+		if _, err := svc.startSession(user); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := svc.ResetPassword(userRepo.resetToken, "n3w-Passw0rd!"); err != nil {
+		t.Fatalf("ResetPassword = %v, want nil", err)
+	}
+	if userRepo.password == "" {
+		t.Error("the new password was not stored")
+	}
+	for _, token := range tokenRepo.tokens {
+		if token.DeletedAt == nil {
+			t.Errorf("%s token %s survived the password reset", token.Type, token.ID)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"kaabe-app/internal/domain/model"
+	utils "kaabe-app/pkg/config"
+	"log"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Two-factor errors
+var (
+	ErrInvalidTwoFactorCode    = errors.New("invalid two-factor code")
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorRequired       = errors.New("two-factor authentication is required for this role")
+)
+
+// Lifetimes of the tokens that carry a half-finished login to the second step
+const (
+	mfaLoginTTL      = 5 * time.Minute
+	mfaEnrollmentTTL = 15 * time.Minute
+)
+
+// recoveryCodeCount is how many recovery codes are issued at a time
+const recoveryCodeCount = 10
+
+// CompleteTwoFactorLogin finishes a login started by AuthenticateUser with a TOTP or recovery code
+func (s *userService) CompleteTwoFactorLogin(mfaToken, code, ip string) (*model.User, *model.AuthTokens, error) {
+	user, err := s.userFromPendingLogin(mfaToken, utils.PurposeMFALogin)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.loginLimiter.Check(loginAccount(user), ip); err != nil {
+		return nil, nil, err
+	}
+
+	if user.TOTPEnabledAt == nil {
+		return nil, nil, ErrTwoFactorNotEnabled
+	}
+
+	if err := s.verifySecondFactor(user, code, true); err != nil {
+		if errors.Is(err, ErrInvalidTwoFactorCode) {
+			s.loginLimiter.Failure(loginAccount(user), ip)
+		}
+		return nil, nil, err
+	}
+	s.loginLimiter.Success(loginAccount(user))
+
+	tokens, err := s.startSession(user)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, tokens, nil
+}
+
+// BeginTwoFactorEnrollment creates a new pending TOTP secret for the user
+func (s *userService) BeginTwoFactorEnrollment(userID uuid.UUID) (*model.TOTPEnrollment, error) {
+	user, err := s.repo.Get(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if user.TOTPEnabledAt != nil {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate secret: %v", err)
+	}
+
+	stored, err := s.twoFactorRepo.SetSecret(user.ID, secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store secret: %v", err)
+	}
+	if !stored {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	return &model.TOTPEnrollment{
+		Secret:          secret,
+		ProvisioningURI: utils.TOTPProvisioningURI(secret, s.opts.Issuer, user.Email),
+	}, nil
+}
+
+// ActivateTwoFactor confirms the pending secret with a code and returns fresh recovery codes
+func (s *userService) ActivateTwoFactor(userID uuid.UUID, code string) ([]string, error) {
+	user, err := s.repo.Get(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if user.TOTPEnabledAt != nil {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, errors.New("two-factor enrollment has not been started")
+	}
+
+	if err := s.verifySecondFactor(user, code, false); err != nil {
+		return nil, err
+	}
+
+	enabled, err := s.twoFactorRepo.Enable(user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enable two-factor authentication: %v", err)
+	}
+	if !enabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	log.Printf("Two-factor authentication enabled for user %s", user.ID)
+	return s.replaceRecoveryCodes(user.ID)
+}
+
+// DisableTwoFactor turns 2FA off after checking a current code
+func (s *userService) DisableTwoFactor(userID uuid.UUID, code string) error {
+	user, err := s.repo.Get(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+	if user.TOTPEnabledAt == nil {
+		return ErrTwoFactorNotEnabled
+	}
+	if s.requiresTwoFactor(user) {
+		return ErrTwoFactorRequired
+	}
+
+	if err := s.verifySecondFactor(user, code, true); err != nil {
+		return err
+	}
+
+	if err := s.twoFactorRepo.Disable(user.ID); err != nil {
+		return err
+	}
+
+	log.Printf("Two-factor authentication disabled for user %s", user.ID)
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes; it needs a code from the authenticator app
+func (s *userService) RegenerateRecoveryCodes(userID uuid.UUID, code string) ([]string, error) {
+	user, err := s.repo.Get(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if user.TOTPEnabledAt == nil {
+		return nil, ErrTwoFactorNotEnabled
+	}
+
+	if err := s.verifySecondFactor(user, code, false); err != nil {
+		return nil, err
+	}
+
+	return s.replaceRecoveryCodes(user.ID)
+}
+
+// BeginRequiredEnrollment starts enrollment for a user whose login is waiting on mandatory 2FA
+func (s *userService) BeginRequiredEnrollment(mfaToken string) (*model.TOTPEnrollment, error) {
+	user, err := s.userFromPendingLogin(mfaToken, utils.PurposeMFAEnrollment)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.BeginTwoFactorEnrollment(user.ID)
+}
+
+// CompleteRequiredEnrollment activates 2FA and finishes the pending login
+func (s *userService) CompleteRequiredEnrollment(mfaToken, code, ip string) (*model.User, *model.AuthTokens, []string, error) {
+	user, err := s.userFromPendingLogin(mfaToken, utils.PurposeMFAEnrollment)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if err := s.loginLimiter.Check(loginAccount(user), ip); err != nil {
+		return nil, nil, nil, err
+	}
+
+	recoveryCodes, err := s.ActivateTwoFactor(user.ID, code)
+	if err != nil {
+		if errors.Is(err, ErrInvalidTwoFactorCode) {
+			s.loginLimiter.Failure(loginAccount(user), ip)
+		}
+		return nil, nil, nil, err
+	}
+	s.loginLimiter.Success(loginAccount(user))
+
+	tokens, err := s.startSession(user)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return user, tokens, recoveryCodes, nil
+}
+
+// loginAccount is the account key a user's second step is throttled under: the email they sign in
+// with, the phone number of accounts created by phone, and the user ID for accounts with neither
+func loginAccount(user *model.User) string {
+	switch {
+	case user.Email != "":
+		return user.Email
+	case user.PhoneNumber != "":
+		return user.PhoneNumber
+	}
+	return user.ID.String()
+}
+
+// requiresTwoFactor reports whether config makes 2FA mandatory for the user's role
+func (s *userService) requiresTwoFactor(user *model.User) bool {
+	if !s.opts.RequireTwoFactor {
+		return false
+	}
+	return user.Role == model.RoleAdmin || user.Role == model.RoleInfluencer
+}
+
+// verifySecondFactor accepts a TOTP code, or a recovery code when allowRecovery is set
+func (s *userService) verifySecondFactor(user *model.User, code string, allowRecovery bool) error {
+	if step, ok := utils.ValidateTOTP(user.TOTPSecret, code, time.Now()); ok {
+		fresh, err := s.twoFactorRepo.UseStep(user.ID, step)
+		if err != nil {
+			return fmt.Errorf("failed to verify code: %v", err)
+		}
+		if !fresh {
+			return ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+
+	if !allowRecovery {
+		return ErrInvalidTwoFactorCode
+	}
+
+	used, err := s.twoFactorRepo.ConsumeRecoveryCode(user.ID, utils.HashRecoveryCode(code))
+	if err != nil {
+		return fmt.Errorf("failed to verify code: %v", err)
+	}
+	if !used {
+		return ErrInvalidTwoFactorCode
+	}
+
+	if remaining, err := s.twoFactorRepo.CountRecoveryCodes(user.ID); err == nil {
+		log.Printf("Recovery code used for user %s, %d left", user.ID, remaining)
+	}
+	return nil
+}
+
+// replaceRecoveryCodes generates a new set of recovery codes and stores their hashes
+func (s *userService) replaceRecoveryCodes(userID uuid.UUID) ([]string, error) {
+	codes, err := utils.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate recovery codes: %v", err)
+	}
+
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i] = utils.HashRecoveryCode(code)
+	}
+
+	if err := s.twoFactorRepo.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// pendingLoginToken signs the token that carries a half-finished login to the second step
+func (s *userService) pendingLoginToken(user *model.User, purpose string, ttl time.Duration) (string, error) {
+	token, err := utils.GeneratePurposeToken(user.ID.String(), user.Email, purpose, time.Now().Add(ttl).Unix())
+	if err != nil {
+		log.Printf("Error signing %s token: %v", purpose, err)
+		return "", errors.New("failed to generate token")
+	}
+	return token, nil
+}
+
+// userFromPendingLogin validates a pending login token and loads its user
+func (s *userService) userFromPendingLogin(mfaToken, purpose string) (*model.User, error) {
+	claims, err := utils.ValidatePurposeToken(mfaToken, purpose)
+	if err != nil {
+		return nil, errors.New("invalid or expired mfa token")
+	}
+
+	userID, err := uuid.FromString(claims.UserID)
+	if err != nil {
+		return nil, errors.New("invalid or expired mfa token")
+	}
+
+	user, err := s.repo.Get(userID)
+	if err != nil || user.Email != claims.Email {
+		return nil, errors.New("invalid or expired mfa token")
+	}
+	return user, nil
+}
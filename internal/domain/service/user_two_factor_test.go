@@ -0,0 +1,145 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"kaabe-app/internal/domain/model"
+	"kaabe-app/internal/domain/repository"
+	utils "kaabe-app/pkg/config"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+func TestLoginAccount(t *testing.T) {
+	id := newTestUUID(t)
+
+	tests := []struct {
+		name string
+		user *model.User
+		want string
+	}{
+		{"email account", &model.User{ID: id, Email: "learner@example.com", PhoneNumber: "+25377000001"}, "learner@example.com"},
+		{"phone account", &model.User{ID: id, PhoneNumber: "+25377000001"}, "+25377000001"},
+		{"neither", &model.User{ID: id}, id.String()},
+	}
+
+	for _, tt := range tests {
+		if got := loginAccount(tt.user); got != tt.want {
+			t.Errorf("%s: loginAccount = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLoginLimiterKeepsPhoneAccountsApart(t *testing.T) {
+	limiter := NewLoginLimiter(newMemoryStore(), &auditRecorder{})
+	attacked := &model.User{ID: newTestUUID(t), PhoneNumber: "+25377000001"}
+	other := &model.User{ID: newTestUUID(t), PhoneNumber: "+25377000002"}
+	const ip = "10.0.0.1"
+
+	for i := 0; i < loginAccountPolicy.MaxAttempts; i++ {
+		limiter.Failure(loginAccount(attacked), ip)
+	}
+
+	if err := limiter.Check(loginAccount(attacked), "10.0.0.2"); err == nil {
+		t.Errorf("Check for the attacked account = nil, want it locked out")
+	}
+	if err := limiter.Check(loginAccount(other), "10.0.0.2"); err != nil {
+		t.Errorf("Check for another phone account = %v, want nil", err)
+	}
+}
+
+// fakeTwoFactorRepo keeps used TOTP steps and recovery code hashes in maps
+type fakeTwoFactorRepo struct {
+	repository.TwoFactorRepository
+	usedSteps     map[int64]bool
+	recoveryCodes map[string]bool
+}
+
+func (r *fakeTwoFactorRepo) UseStep(userID uuid.UUID, step int64) (bool, error) {
+	if r.usedSteps[step] {
+		return false, nil
+	}
+	r.usedSteps[step] = true
+	return true, nil
+}
+
+func (r *fakeTwoFactorRepo) ConsumeRecoveryCode(userID uuid.UUID, codeHash string) (bool, error) {
+	if !r.recoveryCodes[codeHash] {
+		return false, nil
+	}
+	delete(r.recoveryCodes, codeHash)
+	return true, nil
+}
+
+func (r *fakeTwoFactorRepo) CountRecoveryCodes(userID uuid.UUID) (int, error) {
+	return len(r.recoveryCodes), nil
+}
+
+// currentTOTP computes the RFC 6238 code an authenticator app shows for the secret right now
+func currentTOTP(t *testing.T, secret string) string {
+	t.Helper()
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(time.Now().Unix()/30))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	return fmt.Sprintf("%06d", (binary.BigEndian.Uint32(sum[offset:offset+4])&0x7fffffff)%1000000)
+}
+
+func TestVerifySecondFactor(t *testing.T) {
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recoveryCodes, err := utils.GenerateRecoveryCodes(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &fakeTwoFactorRepo{usedSteps: map[int64]bool{}, recoveryCodes: map[string]bool{}}
+	for _, code := range recoveryCodes {
+		repo.recoveryCodes[utils.HashRecoveryCode(code)] = true
+	}
+	svc := &userService{twoFactorRepo: repo}
+	user := &model.User{ID: newTestUUID(t), TOTPSecret: secret}
+
+	// A code from the authenticator app works once; replaying it fails
+	code := currentTOTP(t, secret)
+	if err := svc.verifySecondFactor(user, code, false); err != nil {
+		t.Fatalf("verifySecondFactor with the current code = %v, want nil", err)
+	}
+	if err := svc.verifySecondFactor(user, code, true); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Errorf("verifySecondFactor replaying the code = %v, want %v", err, ErrInvalidTwoFactorCode)
+	}
+
+	// Recovery codes are refused where they are not allowed, and otherwise work once
+	if err := svc.verifySecondFactor(user, recoveryCodes[0], false); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Errorf("verifySecondFactor with a recovery code where none is allowed = %v, want %v", err, ErrInvalidTwoFactorCode)
+	}
+	if err := svc.verifySecondFactor(user, strings.ToUpper(recoveryCodes[0]), true); err != nil {
+		t.Fatalf("verifySecondFactor with a recovery code = %v, want nil", err)
+	}
+	if err := svc.verifySecondFactor(user, recoveryCodes[0], true); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Errorf("verifySecondFactor reusing a recovery code = %v, want %v", err, ErrInvalidTwoFactorCode)
+	}
+	if len(repo.recoveryCodes) != 1 {
+		t.Errorf("%d recovery codes left, want 1", len(repo.recoveryCodes))
+	}
+
+	if err := svc.verifySecondFactor(user, "000000-x", true); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Errorf("verifySecondFactor with garbage = %v, want %v", err, ErrInvalidTwoFactorCode)
+	}
+}
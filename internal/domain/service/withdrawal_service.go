@@ -4,28 +4,41 @@ import (
 	"kaabe-app/internal/domain/model"
 	"kaabe-app/internal/domain/repository"
 
+	"errors"
 	"fmt"
 	"github.com/gofrs/uuid"
 	"log"
 	"time"
 )
 
+var (
+	ErrWithdrawalNotPending   = errors.New("withdrawal has been processed; only pending withdrawals can be changed")
+	ErrWithdrawalAmountLocked = errors.New("an approved withdrawal's amount cannot change")
+)
+
 // Service
 type WithdrawalService interface {
-	CreateWithdrawal(InfluencerID uuid.UUID, amount float64, status string, RequestedAt time.Time, ProcessedAt time.Time) (*model.Withdrawal, error)
+	CreateWithdrawal(InfluencerID uuid.UUID, amount model.Money, status string, RequestedAt time.Time, ProcessedAt time.Time) (*model.Withdrawal, error)
 	UpdateWithdrawal(withdrawal *model.Withdrawal) error
 	DeleteWithdrawal(withdrawalID uuid.UUID) error
 	GetWithdrawalByID(withdrawalID uuid.UUID) (*model.Withdrawal, error)
-	GetAllWithdrawal() ([]*model.Withdrawal, error)
+	GetAllWithdrawal(filter model.WithdrawalFilter, page model.PageRequest) (*model.Page[*model.Withdrawal], error)
 }
 
 type withdrawalService struct {
 	repo      repository.WithdrawalRepository
 	tokenRepo repository.TokenRepository
+	userRepo  repository.UserRepository
+	notifier  NotificationService
 }
 
 // CreateWithdrawal implements WithdrawalService.
-func (s *withdrawalService) CreateWithdrawal(InfluencerID uuid.UUID, amount float64, status string, RequestedAt time.Time, ProcessedAt time.Time) (*model.Withdrawal, error) {
+func (s *withdrawalService) CreateWithdrawal(InfluencerID uuid.UUID, amount model.Money, status string, RequestedAt time.Time, ProcessedAt time.Time) (*model.Withdrawal, error) {
+	amount, err := positiveMoney(amount)
+	if err != nil {
+		return nil, err
+	}
+
 		// Generate a new UUID for the lesson ID
 
 	neoLesson, err := uuid.NewV4()
@@ -72,12 +85,12 @@ func (s *withdrawalService) DeleteWithdrawal(withdrawalID uuid.UUID) error {
 }
 
 // GetAllWithdrawal implements WithdrawalService.
-func (s *withdrawalService) GetAllWithdrawal() ([]*model.Withdrawal, error) {
-	Withdrawal, err := s.repo.List()
+func (s *withdrawalService) GetAllWithdrawal(filter model.WithdrawalFilter, page model.PageRequest) (*model.Page[*model.Withdrawal], error) {
+	withdrawals, err := s.repo.List(filter, page)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get all leWithdrawalsson: %v", err)
+		return nil, listError("withdrawals", err)
 	}
-	return Withdrawal, nil
+	return withdrawals, nil
 }
 
 // GetWithdrawalByID implements WithdrawalService.
@@ -91,21 +104,52 @@ func (s *withdrawalService) GetWithdrawalByID(withdrawalID uuid.UUID) (*model.Wi
 
 // UpdateWithdrawal implements WithdrawalService.
 func (s *withdrawalService) UpdateWithdrawal(withdrawal *model.Withdrawal) error {
-	_, err := s.repo.Get(withdrawal.ID)
+	amount, err := positiveMoney(withdrawal.Amount)
+	if err != nil {
+		return err
+	}
+	withdrawal.Amount = amount
+
+	existing, err := s.repo.Get(withdrawal.ID)
 	if err != nil {
 		return fmt.Errorf("could not find withdrawal with ID %s", withdrawal.ID)
 	}
 
+	// The payout has been made for the approved amount
+	if existing.Status == "approved" && withdrawal.Amount != existing.Amount {
+		return ErrWithdrawalAmountLocked
+	}
+
 	if err := s.repo.Update(withdrawal); err != nil {
 		return fmt.Errorf("failed to update withdrawal with ID %s: %v", withdrawal.ID, err)
 	}
 
+	if withdrawal.Status != existing.Status {
+		withdrawal.InfluencerID = existing.InfluencerID
+		s.notifyStatus(withdrawal)
+	}
+
 	return nil
 }
 
-func NewWithdrawalService(WithdrawalRepo repository.WithdrawalRepository, tokenRepo repository.TokenRepository) WithdrawalService {
+// notifyStatus emails the influencer about a status change; failures are only logged
+func (s *withdrawalService) notifyStatus(withdrawal *model.Withdrawal) {
+	influencer, err := s.userRepo.Get(withdrawal.InfluencerID)
+	if err != nil {
+		log.Printf("Withdrawal status email not sent, user lookup failed: %v", err)
+		return
+	}
+
+	if err := s.notifier.SendWithdrawalStatus(influencer, withdrawal); err != nil {
+		log.Printf("Withdrawal status email not sent: %v", err)
+	}
+}
+
+func NewWithdrawalService(WithdrawalRepo repository.WithdrawalRepository, tokenRepo repository.TokenRepository, userRepo repository.UserRepository, notifier NotificationService) WithdrawalService {
 	return &withdrawalService{
 		repo:      WithdrawalRepo,
 		tokenRepo: tokenRepo,
+		userRepo:  userRepo,
+		notifier:  notifier,
 	}
 }
@@ -0,0 +1,42 @@
+package job
+
+import (
+	"context"
+	"kaabe-app/internal/domain/service"
+	"log"
+	"time"
+)
+
+// accountDeletionBatchSize caps how many accounts one run anonymizes
+const accountDeletionBatchSize = 100
+
+// accountDeletionTask anonymizes accounts whose deletion grace period has ended
+type accountDeletionTask struct {
+	accountService service.AccountService
+	interval       time.Duration
+}
+
+// NewAccountDeletionTask creates a task that processes due account deletions every interval
+func NewAccountDeletionTask(accountService service.AccountService, interval time.Duration) Task {
+	return &accountDeletionTask{accountService: accountService, interval: interval}
+}
+
+func (t *accountDeletionTask) Name() string {
+	return "account-deletion"
+}
+
+func (t *accountDeletionTask) Interval() time.Duration {
+	return t.interval
+}
+
+func (t *accountDeletionTask) Run(ctx context.Context) error {
+	deleted, err := t.accountService.ProcessDueDeletions(accountDeletionBatchSize)
+	if err != nil {
+		return err
+	}
+
+	if deleted > 0 {
+		log.Printf("Deleted %d accounts after their grace period", deleted)
+	}
+	return nil
+}
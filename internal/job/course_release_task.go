@@ -0,0 +1,42 @@
+package job
+
+import (
+	"context"
+	"kaabe-app/internal/domain/service"
+	"log"
+	"time"
+)
+
+// courseReleaseBatchSize caps how many scheduled releases one run applies
+const courseReleaseBatchSize = 100
+
+// courseReleaseTask publishes and archives courses at their scheduled times
+type courseReleaseTask struct {
+	courseService service.CourseService
+	interval      time.Duration
+}
+
+// NewCourseReleaseTask creates a task that applies due course releases every interval
+func NewCourseReleaseTask(courseService service.CourseService, interval time.Duration) Task {
+	return &courseReleaseTask{courseService: courseService, interval: interval}
+}
+
+func (t *courseReleaseTask) Name() string {
+	return "course-release"
+}
+
+func (t *courseReleaseTask) Interval() time.Duration {
+	return t.interval
+}
+
+func (t *courseReleaseTask) Run(ctx context.Context) error {
+	published, archived, err := t.courseService.ProcessScheduledReleases(courseReleaseBatchSize)
+	if err != nil {
+		return err
+	}
+
+	if published > 0 || archived > 0 {
+		log.Printf("Scheduled course releases: %d published, %d archived", published, archived)
+	}
+	return nil
+}
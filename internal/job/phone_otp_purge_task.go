@@ -0,0 +1,39 @@
+package job
+
+import (
+	"context"
+	"kaabe-app/internal/domain/repository"
+	"log"
+	"time"
+)
+
+// phoneOTPPurgeTask deletes old rows from the phone_otps table
+type phoneOTPPurgeTask struct {
+	otpRepo  repository.PhoneOTPRepository
+	interval time.Duration
+}
+
+// NewPhoneOTPPurgeTask creates a task that purges expired SMS codes every interval
+func NewPhoneOTPPurgeTask(otpRepo repository.PhoneOTPRepository, interval time.Duration) Task {
+	return &phoneOTPPurgeTask{otpRepo: otpRepo, interval: interval}
+}
+
+func (t *phoneOTPPurgeTask) Name() string {
+	return "phone-otp-purge"
+}
+
+func (t *phoneOTPPurgeTask) Interval() time.Duration {
+	return t.interval
+}
+
+func (t *phoneOTPPurgeTask) Run(ctx context.Context) error {
+	purged, err := t.otpRepo.PurgeExpired()
+	if err != nil {
+		return err
+	}
+
+	if purged > 0 {
+		log.Printf("Purged %d expired phone codes", purged)
+	}
+	return nil
+}
@@ -1 +1,76 @@
-package job
\ No newline at end of file
+package job
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Processor runs registered tasks periodically until stopped
+type Processor struct {
+	tasks  []Task
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewProcessor creates an empty Processor
+func NewProcessor() *Processor {
+	return &Processor{}
+}
+
+// Register adds a task; it must be called before Start
+func (p *Processor) Register(task Task) {
+	p.tasks = append(p.tasks, task)
+}
+
+// Start launches one goroutine per task; each task runs once immediately and then on its interval
+func (p *Processor) Start(ctx context.Context) {
+	ctx, p.cancel = context.WithCancel(ctx)
+
+	for _, task := range p.tasks {
+		p.wg.Add(1)
+		go p.loop(ctx, task)
+	}
+
+	log.Printf("Job processor started with %d tasks", len(p.tasks))
+}
+
+// Stop cancels all tasks and waits for running ones to finish
+func (p *Processor) Stop() {
+	if p.cancel != nil {
+		p.cancel()
+	}
+	p.wg.Wait()
+	log.Println("Job processor stopped")
+}
+
+func (p *Processor) loop(ctx context.Context, task Task) {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(task.Interval())
+	defer ticker.Stop()
+
+	for {
+		p.run(ctx, task)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// run executes a task once, recovering from panics so one bad task cannot kill the others
+func (p *Processor) run(ctx context.Context, task Task) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Job %s panicked: %v", task.Name(), r)
+		}
+	}()
+
+	if err := task.Run(ctx); err != nil {
+		log.Printf("Job %s failed: %v", task.Name(), err)
+	}
+}
@@ -1 +1,13 @@
-package job
\ No newline at end of file
+package job
+
+import (
+	"context"
+	"time"
+)
+
+// Task is a unit of background work the Processor runs on a fixed interval
+type Task interface {
+	Name() string
+	Interval() time.Duration
+	Run(ctx context.Context) error
+}
@@ -0,0 +1,39 @@
+package job
+
+import (
+	"context"
+	"kaabe-app/internal/domain/repository"
+	"log"
+	"time"
+)
+
+// tokenPurgeTask deletes expired rows from the tokens table
+type tokenPurgeTask struct {
+	tokenRepo repository.TokenRepository
+	interval  time.Duration
+}
+
+// NewTokenPurgeTask creates a task that purges expired tokens every interval
+func NewTokenPurgeTask(tokenRepo repository.TokenRepository, interval time.Duration) Task {
+	return &tokenPurgeTask{tokenRepo: tokenRepo, interval: interval}
+}
+
+func (t *tokenPurgeTask) Name() string {
+	return "token-purge"
+}
+
+func (t *tokenPurgeTask) Interval() time.Duration {
+	return t.interval
+}
+
+func (t *tokenPurgeTask) Run(ctx context.Context) error {
+	purged, err := t.tokenRepo.PurgeExpired()
+	if err != nil {
+		return err
+	}
+
+	if purged > 0 {
+		log.Printf("Purged %d expired tokens", purged)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+-- Refresh token rotation: every login starts a token family, every refresh
+-- marks the presented refresh token as used and issues a new pair in the
+-- same family. Presenting a used refresh token revokes the whole family.
+
+ALTER TABLE tokens
+    ADD COLUMN IF NOT EXISTS token_type VARCHAR(20) NOT NULL DEFAULT 'access',
+    ADD COLUMN IF NOT EXISTS family_id UUID,
+    ADD COLUMN IF NOT EXISTS used_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_tokens_family_id ON tokens(family_id);
+CREATE INDEX IF NOT EXISTS idx_tokens_user_id ON tokens(user_id);
+
+-- Procedure: Create a token (replaces the 6-argument version, whose baseline definition in 005
+-- does not compile, so it may be missing)
+DROP PROCEDURE IF EXISTS create_token(UUID, UUID, TEXT, TIMESTAMP, TIMESTAMP, TIMESTAMP);
+
+CREATE OR REPLACE PROCEDURE create_token(
+    IN p_id UUID,
+    IN p_user_id UUID,
+    IN p_token TEXT,
+    IN p_token_type VARCHAR,
+    IN p_family_id UUID,
+    IN p_expires_at TIMESTAMP,
+    IN p_created_at TIMESTAMP,
+    IN p_updated_at TIMESTAMP
+)
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    INSERT INTO tokens (
+        id, user_id, token, token_type, family_id, expires_at, created_at, updated_at
+    ) VALUES (
+        p_id, p_user_id, p_token, p_token_type, p_family_id, p_expires_at, p_created_at, p_updated_at
+    );
+END;
+$$;
+
+-- Function: get an active token by value; columns are qualified since the output columns share
+-- their names
+DROP FUNCTION IF EXISTS get_token_by_token(TEXT);
+
+CREATE OR REPLACE FUNCTION get_token_by_token(p_token TEXT)
+RETURNS TABLE (
+    id UUID,
+    user_id UUID,
+    token TEXT,
+    token_type VARCHAR,
+    family_id UUID,
+    expires_at TIMESTAMP,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    deleted_at TIMESTAMP
+)
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    RETURN QUERY
+    SELECT
+        tokens.id, tokens.user_id, tokens.token, tokens.token_type, tokens.family_id,
+        tokens.expires_at, tokens.used_at, tokens.created_at, tokens.updated_at, tokens.deleted_at
+    FROM tokens
+    WHERE tokens.token = p_token AND tokens.deleted_at IS NULL;
+END;
+$$;
+
+-- Function: get a refresh token by value, including used and revoked ones
+-- (needed to detect reuse of an already-rotated token)
+CREATE OR REPLACE FUNCTION get_refresh_token(p_token TEXT)
+RETURNS TABLE (
+    id UUID,
+    user_id UUID,
+    token TEXT,
+    token_type VARCHAR,
+    family_id UUID,
+    expires_at TIMESTAMP,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    deleted_at TIMESTAMP
+)
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    RETURN QUERY
+    SELECT
+        tokens.id, tokens.user_id, tokens.token, tokens.token_type, tokens.family_id,
+        tokens.expires_at, tokens.used_at, tokens.created_at, tokens.updated_at, tokens.deleted_at
+    FROM tokens
+    WHERE tokens.token = p_token AND tokens.token_type = 'refresh';
+END;
+$$;
+
+-- Function: atomically mark a refresh token as used; returns 0 if it was already used
+CREATE OR REPLACE FUNCTION mark_token_used(p_id UUID)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    updated_count INTEGER;
+BEGIN
+    UPDATE tokens
+    SET used_at = NOW(),
+        updated_at = NOW()
+    WHERE id = p_id AND used_at IS NULL AND deleted_at IS NULL;
+
+    GET DIAGNOSTICS updated_count = ROW_COUNT;
+    RETURN updated_count;
+END;
+$$;
+
+-- Function: revoke every token in a family
+CREATE OR REPLACE FUNCTION revoke_token_family(p_family_id UUID)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    revoked_count INTEGER;
+BEGIN
+    UPDATE tokens
+    SET deleted_at = NOW(),
+        updated_at = NOW()
+    WHERE family_id = p_family_id AND deleted_at IS NULL;
+
+    GET DIAGNOSTICS revoked_count = ROW_COUNT;
+    RETURN revoked_count;
+END;
+$$;
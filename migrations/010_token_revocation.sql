@@ -0,0 +1,54 @@
+-- Token revocation: logout, logout everywhere and purge of expired rows
+
+-- Function: revoke a single token by value
+CREATE OR REPLACE FUNCTION revoke_token(p_token TEXT)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    revoked_count INTEGER;
+BEGIN
+    UPDATE tokens
+    SET deleted_at = NOW(),
+        updated_at = NOW()
+    WHERE token = p_token AND deleted_at IS NULL;
+
+    GET DIAGNOSTICS revoked_count = ROW_COUNT;
+    RETURN revoked_count;
+END;
+$$;
+
+-- Function: revoke every active token of a user
+CREATE OR REPLACE FUNCTION revoke_user_tokens(p_user_id UUID)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    revoked_count INTEGER;
+BEGIN
+    UPDATE tokens
+    SET deleted_at = NOW(),
+        updated_at = NOW()
+    WHERE user_id = p_user_id AND deleted_at IS NULL;
+
+    GET DIAGNOSTICS revoked_count = ROW_COUNT;
+    RETURN revoked_count;
+END;
+$$;
+
+-- Function: delete tokens that are past their expiry (revoked or not)
+CREATE OR REPLACE FUNCTION purge_expired_tokens()
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    purged_count INTEGER;
+BEGIN
+    DELETE FROM tokens WHERE expires_at < NOW();
+
+    GET DIAGNOSTICS purged_count = ROW_COUNT;
+    RETURN purged_count;
+END;
+$$;
+
+CREATE INDEX IF NOT EXISTS idx_tokens_expires_at ON tokens(expires_at);
@@ -0,0 +1,109 @@
+-- Role-based authorization: create the roles/permissions tables defined in
+-- 001_create_user.sql, add the role -> permission mapping and seed the
+-- permission matrix for admin, influencer and user.
+
+CALL create_roles_table();
+CALL create_permissions_table();
+CALL create_user_roles_table();
+CALL create_user_permissions_table();
+
+CREATE OR REPLACE PROCEDURE create_role_permissions_table()
+LANGUAGE plpgsql AS $$
+BEGIN
+    CREATE TABLE IF NOT EXISTS role_permissions (
+        role_id INT REFERENCES roles(id) ON DELETE CASCADE,
+        permission_id INT REFERENCES permissions(id) ON DELETE CASCADE,
+        PRIMARY KEY (role_id, permission_id)
+    );
+END;
+$$;
+
+CALL create_role_permissions_table();
+
+-- Seed roles (names match the user_role enum)
+INSERT INTO roles (name) VALUES ('admin'), ('influencer'), ('user')
+ON CONFLICT (name) DO NOTHING;
+
+-- Seed permissions
+INSERT INTO permissions (name) VALUES
+    ('users:read'), ('users:update'), ('users:delete'), ('sessions:revoke'),
+    ('courses:read'), ('courses:create'), ('courses:update'), ('courses:delete'),
+    ('lessons:read'), ('lessons:create'), ('lessons:update'), ('lessons:delete'),
+    ('ratings:read'), ('ratings:create'), ('ratings:update'), ('ratings:delete'),
+    ('subscriptions:read'), ('subscriptions:create'), ('subscriptions:update'), ('subscriptions:delete'),
+    ('withdrawals:read'), ('withdrawals:create'), ('withdrawals:update'), ('withdrawals:delete'),
+    ('payments:read'), ('payments:create'), ('payments:update'), ('payments:delete')
+ON CONFLICT (name) DO NOTHING;
+
+-- Admin gets every permission
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT roles.id, permissions.id
+FROM roles CROSS JOIN permissions
+WHERE roles.name = 'admin'
+ON CONFLICT DO NOTHING;
+
+-- Influencer: manages own catalog and payouts
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT roles.id, permissions.id
+FROM roles JOIN permissions ON permissions.name IN (
+    'courses:read', 'courses:create', 'courses:update', 'courses:delete',
+    'lessons:read', 'lessons:create', 'lessons:update', 'lessons:delete',
+    'ratings:read', 'ratings:create', 'ratings:update', 'ratings:delete',
+    'subscriptions:read', 'subscriptions:create',
+    'withdrawals:read', 'withdrawals:create', 'withdrawals:update',
+    'payments:read', 'payments:create'
+)
+WHERE roles.name = 'influencer'
+ON CONFLICT DO NOTHING;
+
+-- User: learner
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT roles.id, permissions.id
+FROM roles JOIN permissions ON permissions.name IN (
+    'courses:read',
+    'lessons:read',
+    'ratings:read', 'ratings:create', 'ratings:update', 'ratings:delete',
+    'subscriptions:read', 'subscriptions:create',
+    'payments:read', 'payments:create'
+)
+WHERE roles.name = 'user'
+ON CONFLICT DO NOTHING;
+
+-- Function: get the primary role of a user
+CREATE OR REPLACE FUNCTION get_user_role(p_user_id UUID)
+RETURNS TEXT
+LANGUAGE SQL
+AS $$
+    SELECT role::text FROM users WHERE id = p_user_id;
+$$;
+
+-- Function: check whether a user holds a permission, through users.role,
+-- any extra role in user_roles, or a direct grant in user_permissions
+CREATE OR REPLACE FUNCTION user_has_permission(p_user_id UUID, p_permission VARCHAR)
+RETURNS BOOLEAN
+LANGUAGE SQL
+AS $$
+    SELECT EXISTS (
+        SELECT 1
+        FROM users
+        JOIN roles ON roles.name = users.role::text
+        JOIN role_permissions ON role_permissions.role_id = roles.id
+        JOIN permissions ON permissions.id = role_permissions.permission_id
+        WHERE users.id = p_user_id AND permissions.name = p_permission
+
+        UNION ALL
+
+        SELECT 1
+        FROM user_roles
+        JOIN role_permissions ON role_permissions.role_id = user_roles.role_id
+        JOIN permissions ON permissions.id = role_permissions.permission_id
+        WHERE user_roles.user_id = p_user_id AND permissions.name = p_permission
+
+        UNION ALL
+
+        SELECT 1
+        FROM user_permissions
+        JOIN permissions ON permissions.id = user_permissions.permission_id
+        WHERE user_permissions.user_id = p_user_id AND permissions.name = p_permission
+    );
+$$;
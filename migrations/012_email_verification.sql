@@ -0,0 +1,65 @@
+-- Track when a user proved ownership of their email address
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMP;
+
+-- Accounts created before verification existed are treated as verified
+UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL;
+
+-- The user getters now return whole rows; the gateway selects the columns it needs,
+-- so adding a column no longer means recreating every function
+DROP FUNCTION IF EXISTS get_user_by_email(VARCHAR);
+DROP FUNCTION IF EXISTS get_user_by_id(UUID);
+DROP FUNCTION IF EXISTS get_all_users();
+DROP FUNCTION IF EXISTS get_user_by_reset_token(UUID);
+
+-- Function: get_user_by_email
+CREATE OR REPLACE FUNCTION get_user_by_email(p_email VARCHAR)
+RETURNS SETOF users
+LANGUAGE SQL
+AS $$
+    SELECT * FROM users WHERE users.email = p_email;
+$$;
+
+-- Function: get_user_by_id
+CREATE OR REPLACE FUNCTION get_user_by_id(p_id UUID)
+RETURNS SETOF users
+LANGUAGE SQL
+AS $$
+    SELECT * FROM users WHERE users.id = p_id;
+$$;
+
+-- Function: get_all_users
+CREATE OR REPLACE FUNCTION get_all_users()
+RETURNS SETOF users
+LANGUAGE SQL
+AS $$
+    SELECT * FROM users ORDER BY users.created_at DESC;
+$$;
+
+-- Function: get_user_by_reset_token
+CREATE OR REPLACE FUNCTION get_user_by_reset_token(p_token UUID)
+RETURNS SETOF users
+LANGUAGE SQL
+AS $$
+    SELECT * FROM users
+    WHERE users.reset_token = p_token
+      AND users.reset_token_expiry > CURRENT_TIMESTAMP;
+$$;
+
+-- Function: mark_email_verified
+-- Returns 1 when the email was verified now, 0 when it already was (or the user does not exist)
+CREATE OR REPLACE FUNCTION mark_email_verified(p_user_id UUID)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    updated_count INTEGER;
+BEGIN
+    UPDATE users
+    SET email_verified_at = CURRENT_TIMESTAMP,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = p_user_id AND email_verified_at IS NULL;
+
+    GET DIAGNOSTICS updated_count = ROW_COUNT;
+    RETURN updated_count;
+END;
+$$;
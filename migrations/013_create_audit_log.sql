@@ -0,0 +1,29 @@
+-- Security audit trail (lockouts, and later other sensitive account events)
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    action VARCHAR(100) NOT NULL,
+    subject VARCHAR(255),
+    ip_address VARCHAR(64),
+    metadata JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_user_id ON audit_logs (user_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_action_created_at ON audit_logs (action, created_at);
+
+-- Procedure: create_audit_log
+CREATE OR REPLACE PROCEDURE create_audit_log(
+    IN p_id UUID,
+    IN p_user_id UUID,
+    IN p_action VARCHAR,
+    IN p_subject VARCHAR,
+    IN p_ip_address VARCHAR,
+    IN p_metadata JSONB
+)
+LANGUAGE plpgsql AS $$
+BEGIN
+    INSERT INTO audit_logs (id, user_id, action, subject, ip_address, metadata)
+    VALUES (p_id, p_user_id, p_action, p_subject, p_ip_address, COALESCE(p_metadata, '{}'::jsonb));
+END;
+$$;
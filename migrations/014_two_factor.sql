@@ -0,0 +1,122 @@
+-- TOTP two-factor authentication
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_secret TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_enabled_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_last_step BIGINT NOT NULL DEFAULT 0;
+
+-- Single-use recovery codes, stored as SHA-256 hashes
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, code_hash)
+);
+
+-- Function: set_totp_secret
+-- Stores a pending secret; 2FA is only enforced once enable_totp has been called
+CREATE OR REPLACE FUNCTION set_totp_secret(p_user_id UUID, p_secret TEXT)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    updated_count INTEGER;
+BEGIN
+    UPDATE users
+    SET totp_secret = p_secret,
+        totp_enabled_at = NULL,
+        totp_last_step = 0,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = p_user_id AND totp_enabled_at IS NULL;
+
+    GET DIAGNOSTICS updated_count = ROW_COUNT;
+    RETURN updated_count;
+END;
+$$;
+
+-- Function: enable_totp
+CREATE OR REPLACE FUNCTION enable_totp(p_user_id UUID)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    updated_count INTEGER;
+BEGIN
+    UPDATE users
+    SET totp_enabled_at = CURRENT_TIMESTAMP,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = p_user_id AND totp_secret IS NOT NULL AND totp_enabled_at IS NULL;
+
+    GET DIAGNOSTICS updated_count = ROW_COUNT;
+    RETURN updated_count;
+END;
+$$;
+
+-- Procedure: disable_totp
+CREATE OR REPLACE PROCEDURE disable_totp(IN p_user_id UUID)
+LANGUAGE plpgsql AS $$
+BEGIN
+    UPDATE users
+    SET totp_secret = NULL,
+        totp_enabled_at = NULL,
+        totp_last_step = 0,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = p_user_id;
+
+    DELETE FROM recovery_codes WHERE user_id = p_user_id;
+END;
+$$;
+
+-- Function: use_totp_step
+-- Records the time step of an accepted code; returns 0 if that step (or a later one) was already used
+CREATE OR REPLACE FUNCTION use_totp_step(p_user_id UUID, p_step BIGINT)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    updated_count INTEGER;
+BEGIN
+    UPDATE users
+    SET totp_last_step = p_step
+    WHERE id = p_user_id AND totp_last_step < p_step;
+
+    GET DIAGNOSTICS updated_count = ROW_COUNT;
+    RETURN updated_count;
+END;
+$$;
+
+-- Procedure: replace_recovery_codes
+CREATE OR REPLACE PROCEDURE replace_recovery_codes(IN p_user_id UUID, IN p_code_hashes TEXT[])
+LANGUAGE plpgsql AS $$
+BEGIN
+    DELETE FROM recovery_codes WHERE user_id = p_user_id;
+
+    INSERT INTO recovery_codes (user_id, code_hash)
+    SELECT p_user_id, code_hash FROM unnest(p_code_hashes) AS code_hash;
+END;
+$$;
+
+-- Function: consume_recovery_code
+CREATE OR REPLACE FUNCTION consume_recovery_code(p_user_id UUID, p_code_hash VARCHAR)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    updated_count INTEGER;
+BEGIN
+    UPDATE recovery_codes
+    SET used_at = CURRENT_TIMESTAMP
+    WHERE user_id = p_user_id AND code_hash = p_code_hash AND used_at IS NULL;
+
+    GET DIAGNOSTICS updated_count = ROW_COUNT;
+    RETURN updated_count;
+END;
+$$;
+
+-- Function: count_recovery_codes
+CREATE OR REPLACE FUNCTION count_recovery_codes(p_user_id UUID)
+RETURNS INTEGER
+LANGUAGE SQL
+AS $$
+    SELECT COUNT(*)::INTEGER FROM recovery_codes WHERE user_id = p_user_id AND used_at IS NULL;
+$$;
@@ -0,0 +1,41 @@
+-- Self-service profile: confirmed email changes and "log out other devices"
+
+-- Function: change_user_email
+-- The new address was confirmed through a link sent to it, so it is marked verified
+CREATE OR REPLACE FUNCTION change_user_email(p_user_id UUID, p_email VARCHAR)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    updated_count INTEGER;
+BEGIN
+    UPDATE users
+    SET email = p_email,
+        email_verified_at = CURRENT_TIMESTAMP,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = p_user_id;
+
+    GET DIAGNOSTICS updated_count = ROW_COUNT;
+    RETURN updated_count;
+END;
+$$;
+
+-- Function: revoke every active token of a user except one session (token family)
+CREATE OR REPLACE FUNCTION revoke_user_tokens_except(p_user_id UUID, p_family_id UUID)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    revoked_count INTEGER;
+BEGIN
+    UPDATE tokens
+    SET deleted_at = NOW(),
+        updated_at = NOW()
+    WHERE user_id = p_user_id
+      AND deleted_at IS NULL
+      AND family_id IS DISTINCT FROM p_family_id;
+
+    GET DIAGNOSTICS revoked_count = ROW_COUNT;
+    RETURN revoked_count;
+END;
+$$;
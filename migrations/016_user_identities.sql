@@ -0,0 +1,78 @@
+-- External OIDC identities (Google, Apple, ...) linked to local users
+CREATE TABLE IF NOT EXISTS user_identities (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL,          -- the provider's stable "sub" claim
+    email VARCHAR(255),                     -- email reported by the provider at link time
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_login_at TIMESTAMP,
+    UNIQUE (provider, subject),
+    UNIQUE (user_id, provider)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities (user_id);
+
+-- Function: create_user_identity
+CREATE OR REPLACE FUNCTION create_user_identity(
+    p_user_id UUID,
+    p_provider VARCHAR,
+    p_subject VARCHAR,
+    p_email VARCHAR
+)
+RETURNS TABLE (id UUID, created_at TIMESTAMP)
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    RETURN QUERY
+    INSERT INTO user_identities (user_id, provider, subject, email)
+    VALUES (p_user_id, p_provider, p_subject, p_email)
+    RETURNING user_identities.id, user_identities.created_at;
+END;
+$$;
+
+-- Function: get_user_identity
+CREATE OR REPLACE FUNCTION get_user_identity(p_provider VARCHAR, p_subject VARCHAR)
+RETURNS SETOF user_identities
+LANGUAGE sql
+AS $$
+    SELECT * FROM user_identities WHERE provider = p_provider AND subject = p_subject;
+$$;
+
+-- Function: get_user_identities
+CREATE OR REPLACE FUNCTION get_user_identities(p_user_id UUID)
+RETURNS SETOF user_identities
+LANGUAGE sql
+AS $$
+    SELECT * FROM user_identities WHERE user_id = p_user_id ORDER BY created_at;
+$$;
+
+-- Function: touch_user_identity
+CREATE OR REPLACE FUNCTION touch_user_identity(p_id UUID)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    updated_count INTEGER;
+BEGIN
+    UPDATE user_identities SET last_login_at = CURRENT_TIMESTAMP WHERE id = p_id;
+
+    GET DIAGNOSTICS updated_count = ROW_COUNT;
+    RETURN updated_count;
+END;
+$$;
+
+-- Function: delete_user_identity
+CREATE OR REPLACE FUNCTION delete_user_identity(p_user_id UUID, p_provider VARCHAR)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    deleted_count INTEGER;
+BEGIN
+    DELETE FROM user_identities WHERE user_id = p_user_id AND provider = p_provider;
+
+    GET DIAGNOSTICS deleted_count = ROW_COUNT;
+    RETURN deleted_count;
+END;
+$$;
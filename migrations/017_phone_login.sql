@@ -0,0 +1,184 @@
+-- Phone number login with one-time codes, and the phone as the default WaafiPay wallet
+
+-- Accounts created by phone have no email; every account still needs one or the other
+ALTER TABLE users ALTER COLUMN email DROP NOT NULL;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone_number VARCHAR(20);      -- E.164, only set once verified
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone_verified_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS wallet_phone VARCHAR(20);      -- mobile money number used to pay
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_phone_number ON users (phone_number);
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_or_phone;
+ALTER TABLE users ADD CONSTRAINT users_email_or_phone CHECK (email IS NOT NULL OR phone_number IS NOT NULL);
+
+-- One-time codes sent by SMS, stored as SHA-256 hashes
+CREATE TABLE IF NOT EXISTS phone_otps (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    phone_number VARCHAR(20) NOT NULL,
+    purpose VARCHAR(20) NOT NULL,           -- login | verify
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE, -- set when a logged in user verifies a number
+    code_hash VARCHAR(64) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    consumed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_phone_otps_phone_purpose ON phone_otps (phone_number, purpose);
+
+-- Function: get_user_by_phone
+CREATE OR REPLACE FUNCTION get_user_by_phone(p_phone VARCHAR)
+RETURNS SETOF users
+LANGUAGE sql
+AS $$
+    SELECT * FROM users WHERE phone_number = p_phone;
+$$;
+
+-- Function: create_phone_user
+-- Creates an account for a number that was just verified with a one-time code
+CREATE OR REPLACE FUNCTION create_phone_user(
+    p_phone VARCHAR,
+    p_first_name VARCHAR,
+    p_last_name VARCHAR
+)
+RETURNS UUID
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    new_user_id UUID;
+BEGIN
+    INSERT INTO users (id, email, password, first_name, last_name, role, phone_number, phone_verified_at)
+    VALUES (uuid_generate_v4(), NULL, '', p_first_name, p_last_name, 'user', p_phone, CURRENT_TIMESTAMP)
+    RETURNING id INTO new_user_id;
+
+    RETURN new_user_id;
+END;
+$$;
+
+-- Function: set_user_phone
+-- Stores a verified number; a different number also drops the wallet set from the old one
+CREATE OR REPLACE FUNCTION set_user_phone(p_user_id UUID, p_phone VARCHAR)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    updated_count INTEGER;
+BEGIN
+    UPDATE users
+    SET wallet_phone = CASE WHEN phone_number IS DISTINCT FROM p_phone THEN NULL ELSE wallet_phone END,
+        phone_number = p_phone,
+        phone_verified_at = CURRENT_TIMESTAMP,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = p_user_id;
+
+    GET DIAGNOSTICS updated_count = ROW_COUNT;
+    RETURN updated_count;
+END;
+$$;
+
+-- Function: set_wallet_phone
+-- Only the user's own verified number can become the payment wallet
+CREATE OR REPLACE FUNCTION set_wallet_phone(p_user_id UUID, p_phone VARCHAR)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    updated_count INTEGER;
+BEGIN
+    UPDATE users
+    SET wallet_phone = p_phone,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = p_user_id AND phone_number = p_phone AND phone_verified_at IS NOT NULL;
+
+    GET DIAGNOSTICS updated_count = ROW_COUNT;
+    RETURN updated_count;
+END;
+$$;
+
+-- Function: create_phone_otp
+-- Supersedes any unused code for the same number and purpose
+CREATE OR REPLACE FUNCTION create_phone_otp(
+    p_phone VARCHAR,
+    p_purpose VARCHAR,
+    p_user_id UUID,
+    p_code_hash VARCHAR,
+    p_expires_at TIMESTAMP
+)
+RETURNS UUID
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    new_otp_id UUID;
+BEGIN
+    UPDATE phone_otps
+    SET consumed_at = CURRENT_TIMESTAMP
+    WHERE phone_number = p_phone AND purpose = p_purpose AND consumed_at IS NULL;
+
+    INSERT INTO phone_otps (phone_number, purpose, user_id, code_hash, expires_at)
+    VALUES (p_phone, p_purpose, p_user_id, p_code_hash, p_expires_at)
+    RETURNING id INTO new_otp_id;
+
+    RETURN new_otp_id;
+END;
+$$;
+
+-- Function: get_active_phone_otp
+CREATE OR REPLACE FUNCTION get_active_phone_otp(p_phone VARCHAR, p_purpose VARCHAR)
+RETURNS SETOF phone_otps
+LANGUAGE sql
+AS $$
+    SELECT * FROM phone_otps
+    WHERE phone_number = p_phone
+      AND purpose = p_purpose
+      AND consumed_at IS NULL
+      AND expires_at > CURRENT_TIMESTAMP
+    ORDER BY created_at DESC
+    LIMIT 1;
+$$;
+
+-- Function: register_phone_otp_attempt
+-- Counts a wrong guess and returns the number of guesses made on the code
+CREATE OR REPLACE FUNCTION register_phone_otp_attempt(p_id UUID)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    attempt_count INTEGER;
+BEGIN
+    UPDATE phone_otps SET attempts = attempts + 1 WHERE id = p_id
+    RETURNING attempts INTO attempt_count;
+
+    RETURN COALESCE(attempt_count, 0);
+END;
+$$;
+
+-- Function: consume_phone_otp
+-- Returns 0 if the code was already used, so it cannot be accepted twice
+CREATE OR REPLACE FUNCTION consume_phone_otp(p_id UUID)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    updated_count INTEGER;
+BEGIN
+    UPDATE phone_otps SET consumed_at = CURRENT_TIMESTAMP
+    WHERE id = p_id AND consumed_at IS NULL;
+
+    GET DIAGNOSTICS updated_count = ROW_COUNT;
+    RETURN updated_count;
+END;
+$$;
+
+-- Function: purge_phone_otps
+CREATE OR REPLACE FUNCTION purge_phone_otps()
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    deleted_count INTEGER;
+BEGIN
+    DELETE FROM phone_otps
+    WHERE expires_at < CURRENT_TIMESTAMP - INTERVAL '1 day';
+
+    GET DIAGNOSTICS deleted_count = ROW_COUNT;
+    RETURN deleted_count;
+END;
+$$;
@@ -0,0 +1,244 @@
+-- Account deletion by anonymization, and per-user reads for the personal data export
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deletion_requested_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deletion_scheduled_at TIMESTAMP;  -- end of the grace period
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;             -- set once the row is anonymized
+
+CREATE INDEX IF NOT EXISTS idx_users_deletion_scheduled_at ON users (deletion_scheduled_at) WHERE deleted_at IS NULL;
+
+-- Payments and withdrawals are accounting records and must outlive the account;
+-- a hard delete of a user (or a subscription) that has them now fails instead of cascading
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS fk_payment_user;
+ALTER TABLE payments ADD CONSTRAINT fk_payment_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT;
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS fk_payment_subscription;
+ALTER TABLE payments ADD CONSTRAINT fk_payment_subscription FOREIGN KEY (subscription_id) REFERENCES subscriptions(id) ON DELETE RESTRICT;
+ALTER TABLE withdrawals DROP CONSTRAINT IF EXISTS fk_withdrawals_influencer;
+ALTER TABLE withdrawals ADD CONSTRAINT fk_withdrawals_influencer FOREIGN KEY (influencer_id) REFERENCES users(id) ON DELETE RESTRICT;
+
+-- Function: get_all_users
+-- Anonymized accounts are kept for accounting only and are no longer listed
+CREATE OR REPLACE FUNCTION get_all_users()
+RETURNS SETOF users
+LANGUAGE SQL
+AS $$
+    SELECT * FROM users WHERE users.deleted_at IS NULL ORDER BY users.created_at DESC;
+$$;
+
+-- Function: request_user_deletion
+CREATE OR REPLACE FUNCTION request_user_deletion(p_user_id UUID, p_scheduled_at TIMESTAMP)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    updated_count INTEGER;
+BEGIN
+    UPDATE users
+    SET deletion_requested_at = CURRENT_TIMESTAMP,
+        deletion_scheduled_at = p_scheduled_at,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = p_user_id AND deleted_at IS NULL;
+
+    GET DIAGNOSTICS updated_count = ROW_COUNT;
+    RETURN updated_count;
+END;
+$$;
+
+-- Function: cancel_user_deletion
+-- Only a pending request can be cancelled; an anonymized account cannot be restored
+CREATE OR REPLACE FUNCTION cancel_user_deletion(p_user_id UUID)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    updated_count INTEGER;
+BEGIN
+    UPDATE users
+    SET deletion_requested_at = NULL,
+        deletion_scheduled_at = NULL,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = p_user_id AND deletion_scheduled_at IS NOT NULL AND deleted_at IS NULL;
+
+    GET DIAGNOSTICS updated_count = ROW_COUNT;
+    RETURN updated_count;
+END;
+$$;
+
+-- Function: get_users_due_for_deletion
+CREATE OR REPLACE FUNCTION get_users_due_for_deletion(p_limit INTEGER)
+RETURNS SETOF users
+LANGUAGE SQL
+AS $$
+    SELECT * FROM users
+    WHERE deleted_at IS NULL AND deletion_scheduled_at <= CURRENT_TIMESTAMP
+    ORDER BY deletion_scheduled_at
+    LIMIT p_limit;
+$$;
+
+-- Function: anonymize_user
+-- Replaces everything that identifies the person and drops their credentials and login methods.
+-- The row itself stays so payments, withdrawals and courses keep a valid owner.
+CREATE OR REPLACE FUNCTION anonymize_user(p_user_id UUID)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    updated_count INTEGER;
+BEGIN
+    UPDATE users
+    SET email = 'deleted-' || id || '@deleted.invalid',
+        password = '',
+        first_name = 'Deleted',
+        last_name = 'User',
+        wallet_id = NULL,
+        reset_token = NULL,
+        reset_token_expiry = NULL,
+        email_verified_at = NULL,
+        totp_secret = NULL,
+        totp_enabled_at = NULL,
+        phone_number = NULL,
+        phone_verified_at = NULL,
+        wallet_phone = NULL,
+        deletion_scheduled_at = NULL,
+        deleted_at = CURRENT_TIMESTAMP,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = p_user_id AND deleted_at IS NULL;
+
+    GET DIAGNOSTICS updated_count = ROW_COUNT;
+    IF updated_count = 0 THEN
+        RETURN 0;
+    END IF;
+
+    DELETE FROM tokens WHERE user_id = p_user_id;
+    DELETE FROM recovery_codes WHERE user_id = p_user_id;
+    DELETE FROM user_identities WHERE user_id = p_user_id;
+    DELETE FROM phone_otps WHERE user_id = p_user_id;
+
+    -- Scores still count towards course averages; the free-text comment may identify the author
+    UPDATE ratings SET comment = '', updated_at = NOW() WHERE user_id = p_user_id;
+
+    -- Lockout entries keep their action and time, but not the email or number they were about
+    UPDATE audit_logs SET subject = NULL, ip_address = NULL WHERE user_id = p_user_id;
+
+    RETURN updated_count;
+END;
+$$;
+
+-- Function: get_user_subscriptions
+CREATE OR REPLACE FUNCTION get_user_subscriptions(p_user_id UUID)
+RETURNS TABLE (
+    id UUID,
+    user_id UUID,
+    course_id UUID,
+    started_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ,
+    status subscription_status,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+)
+LANGUAGE plpgsql AS $$
+BEGIN
+    RETURN QUERY
+    SELECT
+        subscriptions.id,
+        subscriptions.user_id,
+        subscriptions.course_id,
+        subscriptions.started_at,
+        subscriptions.expires_at,
+        subscriptions.status,
+        subscriptions.created_at,
+        subscriptions.updated_at
+    FROM subscriptions
+    WHERE subscriptions.user_id = p_user_id AND subscriptions.deleted_at IS NULL
+    ORDER BY subscriptions.created_at DESC;
+END;
+$$;
+
+-- Function: get_user_ratings
+CREATE OR REPLACE FUNCTION get_user_ratings(p_user_id UUID)
+RETURNS TABLE (
+    id UUID,
+    user_id UUID,
+    course_id UUID,
+    score INT,
+    comment TEXT,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+)
+LANGUAGE plpgsql AS $$
+BEGIN
+    RETURN QUERY
+    SELECT
+        ratings.id,
+        ratings.user_id,
+        ratings.course_id,
+        ratings.score,
+        ratings.comment,
+        ratings.created_at,
+        ratings.updated_at
+    FROM ratings
+    WHERE ratings.user_id = p_user_id AND ratings.deleted_at IS NULL
+    ORDER BY ratings.created_at DESC;
+END;
+$$;
+
+-- Function: get_user_payments
+CREATE OR REPLACE FUNCTION get_user_payments(p_user_id UUID)
+RETURNS TABLE (
+    id UUID,
+    external_ref VARCHAR,
+    user_id UUID,
+    subscription_id UUID,
+    amount DOUBLE PRECISION,
+    status VARCHAR,
+    processed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+)
+LANGUAGE plpgsql AS $$
+BEGIN
+    RETURN QUERY
+    SELECT
+        payments.id,
+        payments.external_ref,
+        payments.user_id,
+        payments.subscription_id,
+        payments.amount,
+        payments.status,
+        payments.processed_at,
+        payments.created_at,
+        payments.updated_at
+    FROM payments
+    WHERE payments.user_id = p_user_id AND payments.deleted_at IS NULL
+    ORDER BY payments.created_at DESC;
+END;
+$$;
+
+-- Function: get_user_withdrawals
+CREATE OR REPLACE FUNCTION get_user_withdrawals(p_user_id UUID)
+RETURNS TABLE (
+    id UUID,
+    influencer_id UUID,
+    amount NUMERIC,
+    status withdrawal_status,
+    requested_at TIMESTAMPTZ,
+    processed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+)
+LANGUAGE plpgsql AS $$
+BEGIN
+    RETURN QUERY
+    SELECT
+        w.id,
+        w.influencer_id,
+        w.amount,
+        w.status,
+        w.requested_at,
+        w.processed_at,
+        w.created_at,
+        w.updated_at
+    FROM withdrawals w
+    WHERE w.influencer_id = p_user_id AND w.deleted_at IS NULL
+    ORDER BY w.created_at DESC;
+END;
+$$;
@@ -0,0 +1,199 @@
+-- Influencer onboarding: users apply, an admin reviews, and the influencer role is only granted on approval
+CREATE TABLE IF NOT EXISTS influencer_applications (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    bio TEXT NOT NULL DEFAULT '',
+    payout_wallet VARCHAR(20),                          -- E.164 mobile money number withdrawals are paid to
+    id_document_urls TEXT[] NOT NULL DEFAULT '{}',      -- uploaded identity documents
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',      -- pending | approved | rejected
+    review_note TEXT,
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A user has at most one open (pending) or approved application; rejected ones may be followed by a new one
+CREATE UNIQUE INDEX IF NOT EXISTS idx_influencer_applications_open
+    ON influencer_applications (user_id) WHERE status IN ('pending', 'approved');
+CREATE INDEX IF NOT EXISTS idx_influencer_applications_status ON influencer_applications (status, created_at);
+
+-- Influencers who registered before applications existed are approved as they are
+INSERT INTO influencer_applications (user_id, payout_wallet, status, review_note, reviewed_at)
+SELECT users.id, users.wallet_phone, 'approved', 'Approved automatically: influencer before onboarding review', CURRENT_TIMESTAMP
+FROM users
+WHERE users.role = 'influencer'
+  AND NOT EXISTS (
+      SELECT 1 FROM influencer_applications
+      WHERE influencer_applications.user_id = users.id AND influencer_applications.status = 'approved'
+  );
+
+-- Reviewing applications is an admin permission
+INSERT INTO permissions (name) VALUES ('influencers:review')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT roles.id, permissions.id
+FROM roles JOIN permissions ON permissions.name = 'influencers:review'
+WHERE roles.name = 'admin'
+ON CONFLICT DO NOTHING;
+
+-- Function: create_influencer_application
+CREATE OR REPLACE FUNCTION create_influencer_application(
+    p_user_id UUID,
+    p_bio TEXT,
+    p_payout_wallet VARCHAR,
+    p_id_document_urls TEXT[]
+)
+RETURNS SETOF influencer_applications
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    RETURN QUERY
+    INSERT INTO influencer_applications (user_id, bio, payout_wallet, id_document_urls)
+    VALUES (p_user_id, p_bio, p_payout_wallet, COALESCE(p_id_document_urls, '{}'))
+    RETURNING *;
+END;
+$$;
+
+-- Function: get_influencer_application
+CREATE OR REPLACE FUNCTION get_influencer_application(p_id UUID)
+RETURNS SETOF influencer_applications
+LANGUAGE sql
+AS $$
+    SELECT * FROM influencer_applications WHERE id = p_id;
+$$;
+
+-- Function: get_latest_influencer_application
+CREATE OR REPLACE FUNCTION get_latest_influencer_application(p_user_id UUID)
+RETURNS SETOF influencer_applications
+LANGUAGE sql
+AS $$
+    SELECT * FROM influencer_applications
+    WHERE user_id = p_user_id
+    ORDER BY created_at DESC
+    LIMIT 1;
+$$;
+
+-- Function: get_user_influencer_applications
+CREATE OR REPLACE FUNCTION get_user_influencer_applications(p_user_id UUID)
+RETURNS SETOF influencer_applications
+LANGUAGE sql
+AS $$
+    SELECT * FROM influencer_applications WHERE user_id = p_user_id ORDER BY created_at DESC;
+$$;
+
+-- Function: get_influencer_applications
+-- The review queue, oldest first; a NULL status lists every application
+CREATE OR REPLACE FUNCTION get_influencer_applications(p_status VARCHAR)
+RETURNS SETOF influencer_applications
+LANGUAGE sql
+AS $$
+    SELECT * FROM influencer_applications
+    WHERE p_status IS NULL OR status = p_status
+    ORDER BY created_at;
+$$;
+
+-- Function: review_influencer_application
+-- Decides a pending application; approval also grants the influencer role (admins keep theirs).
+-- Returns no row if the application does not exist or was already reviewed.
+CREATE OR REPLACE FUNCTION review_influencer_application(
+    p_id UUID,
+    p_approve BOOLEAN,
+    p_reviewer_id UUID,
+    p_note TEXT
+)
+RETURNS SETOF influencer_applications
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    reviewed influencer_applications;
+BEGIN
+    UPDATE influencer_applications
+    SET status = CASE WHEN p_approve THEN 'approved' ELSE 'rejected' END,
+        review_note = p_note,
+        reviewed_by = p_reviewer_id,
+        reviewed_at = CURRENT_TIMESTAMP,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = p_id AND status = 'pending'
+    RETURNING * INTO reviewed;
+
+    IF NOT FOUND THEN
+        RETURN;
+    END IF;
+
+    IF p_approve THEN
+        UPDATE users
+        SET role = 'influencer', updated_at = CURRENT_TIMESTAMP
+        WHERE id = reviewed.user_id AND role = 'user';
+    END IF;
+
+    RETURN NEXT reviewed;
+END;
+$$;
+
+-- Function: is_approved_creator
+-- Admins, and users with an approved application, may own courses
+CREATE OR REPLACE FUNCTION is_approved_creator(p_user_id UUID)
+RETURNS BOOLEAN
+LANGUAGE sql
+AS $$
+    SELECT EXISTS (SELECT 1 FROM users WHERE id = p_user_id AND role = 'admin')
+        OR EXISTS (
+            SELECT 1 FROM influencer_applications
+            WHERE user_id = p_user_id AND status = 'approved'
+        );
+$$;
+
+-- Function: anonymize_user
+-- Redefined from 018 to also scrub influencer applications, which hold ID documents and a payout number
+CREATE OR REPLACE FUNCTION anonymize_user(p_user_id UUID)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    updated_count INTEGER;
+BEGIN
+    UPDATE users
+    SET email = 'deleted-' || id || '@deleted.invalid',
+        password = '',
+        first_name = 'Deleted',
+        last_name = 'User',
+        wallet_id = NULL,
+        reset_token = NULL,
+        reset_token_expiry = NULL,
+        email_verified_at = NULL,
+        totp_secret = NULL,
+        totp_enabled_at = NULL,
+        phone_number = NULL,
+        phone_verified_at = NULL,
+        wallet_phone = NULL,
+        deletion_scheduled_at = NULL,
+        deleted_at = CURRENT_TIMESTAMP,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = p_user_id AND deleted_at IS NULL;
+
+    GET DIAGNOSTICS updated_count = ROW_COUNT;
+    IF updated_count = 0 THEN
+        RETURN 0;
+    END IF;
+
+    DELETE FROM tokens WHERE user_id = p_user_id;
+    DELETE FROM recovery_codes WHERE user_id = p_user_id;
+    DELETE FROM user_identities WHERE user_id = p_user_id;
+    DELETE FROM phone_otps WHERE user_id = p_user_id;
+
+    -- Scores still count towards course averages; the free-text comment may identify the author
+    UPDATE ratings SET comment = '', updated_at = NOW() WHERE user_id = p_user_id;
+
+    -- The review decision is kept, the documents and contact details are not
+    UPDATE influencer_applications
+    SET bio = '', payout_wallet = NULL, id_document_urls = '{}', updated_at = CURRENT_TIMESTAMP
+    WHERE user_id = p_user_id;
+
+    -- Lockout entries keep their action and time, but not the email or number they were about
+    UPDATE audit_logs SET subject = NULL, ip_address = NULL WHERE user_id = p_user_id;
+
+    RETURN updated_count;
+END;
+$$;
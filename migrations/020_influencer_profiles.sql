@@ -0,0 +1,257 @@
+-- Public influencer profiles and followers
+
+CREATE TABLE IF NOT EXISTS influencer_profiles (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    display_name VARCHAR(100) NOT NULL,
+    avatar_url TEXT,
+    bio TEXT NOT NULL DEFAULT '',
+    social_links JSONB NOT NULL DEFAULT '{}',   -- platform name -> profile URL
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS influencer_follows (
+    follower_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    influencer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (follower_id, influencer_id),
+    CONSTRAINT chk_influencer_follows_self CHECK (follower_id <> influencer_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_influencer_follows_influencer ON influencer_follows (influencer_id);
+
+-- Function: upsert_influencer_profile
+CREATE OR REPLACE FUNCTION upsert_influencer_profile(
+    p_user_id UUID,
+    p_display_name VARCHAR,
+    p_avatar_url TEXT,
+    p_bio TEXT,
+    p_social_links JSONB
+)
+RETURNS SETOF influencer_profiles
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    RETURN QUERY
+    INSERT INTO influencer_profiles (user_id, display_name, avatar_url, bio, social_links)
+    VALUES (p_user_id, p_display_name, p_avatar_url, COALESCE(p_bio, ''), COALESCE(p_social_links, '{}'))
+    ON CONFLICT (user_id) DO UPDATE
+    SET display_name = EXCLUDED.display_name,
+        avatar_url = EXCLUDED.avatar_url,
+        bio = EXCLUDED.bio,
+        social_links = EXCLUDED.social_links,
+        updated_at = CURRENT_TIMESTAMP
+    RETURNING *;
+END;
+$$;
+
+-- Function: get_influencer_profile
+-- Every active influencer has a public profile; until they fill one in, it is built from their name
+CREATE OR REPLACE FUNCTION get_influencer_profile(p_user_id UUID)
+RETURNS TABLE (
+    user_id UUID,
+    display_name VARCHAR,
+    avatar_url TEXT,
+    bio TEXT,
+    social_links JSONB,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP
+)
+LANGUAGE sql
+AS $$
+    SELECT
+        u.id,
+        COALESCE(p.display_name, TRIM(u.first_name || ' ' || u.last_name))::VARCHAR,
+        p.avatar_url,
+        COALESCE(p.bio, ''),
+        COALESCE(p.social_links, '{}'),
+        COALESCE(p.created_at, u.created_at),
+        COALESCE(p.updated_at, u.updated_at)
+    FROM users u
+    LEFT JOIN influencer_profiles p ON p.user_id = u.id
+    WHERE u.id = p_user_id AND u.role = 'influencer' AND u.deleted_at IS NULL;
+$$;
+
+-- Function: get_followed_influencers
+-- The influencers a user follows, most recently followed first
+CREATE OR REPLACE FUNCTION get_followed_influencers(p_user_id UUID)
+RETURNS TABLE (
+    user_id UUID,
+    display_name VARCHAR,
+    avatar_url TEXT,
+    bio TEXT,
+    social_links JSONB,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP
+)
+LANGUAGE sql
+AS $$
+    SELECT
+        u.id,
+        COALESCE(p.display_name, TRIM(u.first_name || ' ' || u.last_name))::VARCHAR,
+        p.avatar_url,
+        COALESCE(p.bio, ''),
+        COALESCE(p.social_links, '{}'),
+        COALESCE(p.created_at, u.created_at),
+        COALESCE(p.updated_at, u.updated_at)
+    FROM influencer_follows f
+    JOIN users u ON u.id = f.influencer_id
+    LEFT JOIN influencer_profiles p ON p.user_id = u.id
+    WHERE f.follower_id = p_user_id AND u.role = 'influencer' AND u.deleted_at IS NULL
+    ORDER BY f.created_at DESC;
+$$;
+
+-- Function: get_influencer_stats
+-- Counts published courses, distinct students who paid for one of the influencer's courses,
+-- ratings across all their live courses, and followers
+CREATE OR REPLACE FUNCTION get_influencer_stats(p_influencer_id UUID)
+RETURNS TABLE (
+    course_count BIGINT,
+    student_count BIGINT,
+    rating_count BIGINT,
+    average_rating NUMERIC,
+    follower_count BIGINT
+)
+LANGUAGE sql
+AS $$
+    SELECT
+        (SELECT COUNT(*) FROM courses c
+         WHERE c.influencer_id = p_influencer_id AND c.status = 'published' AND c.deleted_at IS NULL),
+        (SELECT COUNT(DISTINCT s.user_id) FROM subscriptions s
+         JOIN courses c ON c.id = s.course_id
+         WHERE c.influencer_id = p_influencer_id AND c.deleted_at IS NULL
+           AND s.deleted_at IS NULL AND s.status IN ('active', 'expired')),
+        (SELECT COUNT(*) FROM ratings r
+         JOIN courses c ON c.id = r.course_id
+         WHERE c.influencer_id = p_influencer_id AND c.deleted_at IS NULL AND r.deleted_at IS NULL),
+        (SELECT COALESCE(ROUND(AVG(r.score)::NUMERIC, 2), 0) FROM ratings r
+         JOIN courses c ON c.id = r.course_id
+         WHERE c.influencer_id = p_influencer_id AND c.deleted_at IS NULL AND r.deleted_at IS NULL),
+        (SELECT COUNT(*) FROM influencer_follows f
+         JOIN users u ON u.id = f.follower_id
+         WHERE f.influencer_id = p_influencer_id AND u.deleted_at IS NULL);
+$$;
+
+-- Function: get_influencer_courses
+-- The influencer's published catalog, newest first
+CREATE OR REPLACE FUNCTION get_influencer_courses(p_influencer_id UUID)
+RETURNS TABLE (
+    id UUID,
+    influencer_id UUID,
+    title VARCHAR,
+    description TEXT,
+    price FLOAT,
+    cover_image_url TEXT[],
+    status VARCHAR,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP
+)
+LANGUAGE sql
+AS $$
+    SELECT id, influencer_id, title, description, price, cover_image_url, status::VARCHAR, created_at, updated_at
+    FROM courses
+    WHERE influencer_id = p_influencer_id AND status = 'published' AND deleted_at IS NULL
+    ORDER BY created_at DESC;
+$$;
+
+-- Function: follow_influencer
+-- Returns 1 if the follow was added and 0 if it already existed or the target is not an active influencer
+CREATE OR REPLACE FUNCTION follow_influencer(p_follower_id UUID, p_influencer_id UUID)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    inserted_count INTEGER;
+BEGIN
+    INSERT INTO influencer_follows (follower_id, influencer_id)
+    SELECT p_follower_id, users.id
+    FROM users
+    WHERE users.id = p_influencer_id AND users.role = 'influencer' AND users.deleted_at IS NULL
+    ON CONFLICT DO NOTHING;
+
+    GET DIAGNOSTICS inserted_count = ROW_COUNT;
+    RETURN inserted_count;
+END;
+$$;
+
+-- Function: unfollow_influencer
+CREATE OR REPLACE FUNCTION unfollow_influencer(p_follower_id UUID, p_influencer_id UUID)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    deleted_count INTEGER;
+BEGIN
+    DELETE FROM influencer_follows WHERE follower_id = p_follower_id AND influencer_id = p_influencer_id;
+
+    GET DIAGNOSTICS deleted_count = ROW_COUNT;
+    RETURN deleted_count;
+END;
+$$;
+
+-- Function: get_influencer_followers
+-- Followers to notify about the influencer's new courses
+CREATE OR REPLACE FUNCTION get_influencer_followers(p_influencer_id UUID)
+RETURNS SETOF users
+LANGUAGE sql
+AS $$
+    SELECT users.* FROM influencer_follows
+    JOIN users ON users.id = influencer_follows.follower_id
+    WHERE influencer_follows.influencer_id = p_influencer_id AND users.deleted_at IS NULL
+    ORDER BY influencer_follows.created_at;
+$$;
+
+-- Function: anonymize_user
+-- Redefined from 019 to also drop the public profile and follow relations
+CREATE OR REPLACE FUNCTION anonymize_user(p_user_id UUID)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    updated_count INTEGER;
+BEGIN
+    UPDATE users
+    SET email = 'deleted-' || id || '@deleted.invalid',
+        password = '',
+        first_name = 'Deleted',
+        last_name = 'User',
+        wallet_id = NULL,
+        reset_token = NULL,
+        reset_token_expiry = NULL,
+        email_verified_at = NULL,
+        totp_secret = NULL,
+        totp_enabled_at = NULL,
+        phone_number = NULL,
+        phone_verified_at = NULL,
+        wallet_phone = NULL,
+        deletion_scheduled_at = NULL,
+        deleted_at = CURRENT_TIMESTAMP,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = p_user_id AND deleted_at IS NULL;
+
+    GET DIAGNOSTICS updated_count = ROW_COUNT;
+    IF updated_count = 0 THEN
+        RETURN 0;
+    END IF;
+
+    DELETE FROM tokens WHERE user_id = p_user_id;
+    DELETE FROM recovery_codes WHERE user_id = p_user_id;
+    DELETE FROM user_identities WHERE user_id = p_user_id;
+    DELETE FROM phone_otps WHERE user_id = p_user_id;
+    DELETE FROM influencer_profiles WHERE user_id = p_user_id;
+    DELETE FROM influencer_follows WHERE follower_id = p_user_id OR influencer_id = p_user_id;
+
+    -- Scores still count towards course averages; the free-text comment may identify the author
+    UPDATE ratings SET comment = '', updated_at = NOW() WHERE user_id = p_user_id;
+
+    -- The review decision is kept, the documents and contact details are not
+    UPDATE influencer_applications
+    SET bio = '', payout_wallet = NULL, id_document_urls = '{}', updated_at = CURRENT_TIMESTAMP
+    WHERE user_id = p_user_id;
+
+    -- Lockout entries keep their action and time, but not the email or number they were about
+    UPDATE audit_logs SET subject = NULL, ip_address = NULL WHERE user_id = p_user_id;
+
+    RETURN updated_count;
+END;
+$$;
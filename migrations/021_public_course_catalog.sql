@@ -0,0 +1,27 @@
+-- Public course catalog: anonymous visitors see published courses, owners also see their drafts
+
+CREATE INDEX IF NOT EXISTS idx_courses_published ON courses (created_at DESC) WHERE status = 'published' AND deleted_at IS NULL;
+
+-- Function: get_visible_courses
+-- Published courses plus, for a signed-in viewer, their own courses in any status.
+-- A NULL viewer is an anonymous visitor.
+CREATE OR REPLACE FUNCTION get_visible_courses(p_viewer_id UUID)
+RETURNS TABLE (
+    id UUID,
+    influencer_id UUID,
+    title VARCHAR,
+    description TEXT,
+    price FLOAT,
+    cover_image_url TEXT[],
+    status VARCHAR,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP
+)
+LANGUAGE sql
+AS $$
+    SELECT id, influencer_id, title, description, price, cover_image_url, status::VARCHAR, created_at, updated_at
+    FROM courses
+    WHERE deleted_at IS NULL
+      AND (status = 'published' OR (p_viewer_id IS NOT NULL AND influencer_id = p_viewer_id))
+    ORDER BY created_at DESC;
+$$;
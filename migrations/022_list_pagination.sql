@@ -0,0 +1,19 @@
+-- Keyset pagination for the list endpoints
+-- Lists are ordered by (sort column, id) and filtered on live rows, so each default order gets
+-- a matching partial index; the foreign-key filters get one too
+
+CREATE INDEX IF NOT EXISTS idx_users_created_at ON users (created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_courses_created_at ON courses (created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_courses_influencer ON courses (influencer_id, created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_lessons_course_order ON lessons (course_id, lesson_order, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_ratings_created_at ON ratings (created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_ratings_course ON ratings (course_id, created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_subscriptions_created_at ON subscriptions (created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_subscriptions_user ON subscriptions (user_id, created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_withdrawals_created_at ON withdrawals (created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_withdrawals_influencer ON withdrawals (influencer_id, created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_payments_created_at ON payments (created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_payments_user ON payments (user_id, created_at, id) WHERE deleted_at IS NULL;
+
+-- Replaced by the paginated course listing, which applies the same visibility rule
+DROP FUNCTION IF EXISTS get_visible_courses(UUID);
@@ -0,0 +1,224 @@
+-- Full-text course search
+-- Each course keeps a weighted tsvector of its title (A), description (B) and lesson titles (C),
+-- maintained by triggers. When a query has no full-text match, titles are matched by trigram
+-- similarity so misspelled queries still find something.
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS search_vector TSVECTOR;
+
+-- Function: course_search_vector
+CREATE OR REPLACE FUNCTION course_search_vector(p_course_id UUID, p_title VARCHAR, p_description TEXT)
+RETURNS TSVECTOR
+LANGUAGE sql
+STABLE
+AS $$
+    SELECT setweight(to_tsvector('english', COALESCE(p_title, '')), 'A')
+        || setweight(to_tsvector('english', COALESCE(p_description, '')), 'B')
+        || setweight(to_tsvector('english', COALESCE(
+               (SELECT string_agg(lessons.title, ' ') FROM lessons
+                WHERE lessons.course_id = p_course_id AND lessons.deleted_at IS NULL), '')), 'C');
+$$;
+
+-- Trigger: keep a course's vector current when its title or description changes
+CREATE OR REPLACE FUNCTION courses_search_vector_trigger()
+RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    NEW.search_vector := course_search_vector(NEW.id, NEW.title, NEW.description);
+    RETURN NEW;
+END;
+$$;
+
+DROP TRIGGER IF EXISTS trg_courses_search_vector ON courses;
+CREATE TRIGGER trg_courses_search_vector
+    BEFORE INSERT OR UPDATE OF title, description ON courses
+    FOR EACH ROW EXECUTE FUNCTION courses_search_vector_trigger();
+
+-- Trigger: rebuild the owning course's vector when its lessons change
+CREATE OR REPLACE FUNCTION lessons_search_vector_trigger()
+RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE courses SET search_vector = course_search_vector(id, title, description)
+        WHERE id = OLD.course_id;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        UPDATE courses SET search_vector = course_search_vector(id, title, description)
+        WHERE id = NEW.course_id AND (TG_OP = 'INSERT' OR NEW.course_id IS DISTINCT FROM OLD.course_id);
+    END IF;
+    RETURN NULL;
+END;
+$$;
+
+DROP TRIGGER IF EXISTS trg_lessons_search_vector ON lessons;
+CREATE TRIGGER trg_lessons_search_vector
+    AFTER INSERT OR UPDATE OF title, course_id, deleted_at OR DELETE ON lessons
+    FOR EACH ROW EXECUTE FUNCTION lessons_search_vector_trigger();
+
+UPDATE courses SET search_vector = course_search_vector(id, title, description);
+
+CREATE INDEX IF NOT EXISTS idx_courses_search_vector ON courses USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_courses_title_trgm ON courses USING GIN (title gin_trgm_ops);
+
+-- Function: course_price_bucket
+-- The price facet a course falls in; search filters and facet counts both use it
+CREATE OR REPLACE FUNCTION course_price_bucket(p_price FLOAT)
+RETURNS VARCHAR
+LANGUAGE sql
+IMMUTABLE
+AS $$
+    SELECT CASE
+        WHEN COALESCE(p_price, 0) = 0 THEN 'free'
+        WHEN p_price < 20 THEN 'under_20'
+        WHEN p_price < 50 THEN '20_to_50'
+        WHEN p_price < 100 THEN '50_to_100'
+        ELSE 'over_100'
+    END;
+$$;
+
+-- Function: match_courses
+-- Courses matching the query that the viewer may see, with a relevance score. Full-text matches
+-- win; only when there are none does it fall back to fuzzy title matches, flagged by fuzzy.
+-- A NULL viewer is an anonymous visitor; p_all lifts the visibility rule for admins.
+CREATE OR REPLACE FUNCTION match_courses(p_query TEXT, p_viewer_id UUID, p_all BOOLEAN)
+RETURNS TABLE (
+    course_id UUID,
+    relevance REAL,
+    fuzzy BOOLEAN
+)
+LANGUAGE plpgsql
+STABLE
+SET pg_trgm.word_similarity_threshold = 0.4
+AS $$
+DECLARE
+    tsq TSQUERY := websearch_to_tsquery('english', p_query);
+BEGIN
+    RETURN QUERY
+    SELECT c.id, ts_rank_cd(c.search_vector, tsq), FALSE
+    FROM courses c
+    WHERE c.deleted_at IS NULL
+      AND (p_all OR c.status = 'published' OR c.influencer_id = p_viewer_id)
+      AND c.search_vector @@ tsq;
+
+    IF FOUND THEN
+        RETURN;
+    END IF;
+
+    RETURN QUERY
+    SELECT c.id, word_similarity(p_query, c.title), TRUE
+    FROM courses c
+    WHERE c.deleted_at IS NULL
+      AND (p_all OR c.status = 'published' OR c.influencer_id = p_viewer_id)
+      AND p_query <% c.title;
+END;
+$$;
+
+-- Function: search_courses
+-- One page of matches, most relevant first, with highlighted title and description excerpts.
+-- Matched words are wrapped in U+E000 and U+E001 so the API can escape the text before marking them up.
+CREATE OR REPLACE FUNCTION search_courses(
+    p_query TEXT,
+    p_viewer_id UUID,
+    p_all BOOLEAN,
+    p_price_bucket VARCHAR,
+    p_min_rating NUMERIC,
+    p_limit INTEGER,
+    p_offset INTEGER
+)
+RETURNS TABLE (
+    id UUID,
+    influencer_id UUID,
+    title VARCHAR,
+    description TEXT,
+    price FLOAT,
+    cover_image_url TEXT[],
+    status VARCHAR,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    relevance REAL,
+    fuzzy BOOLEAN,
+    title_highlight TEXT,
+    description_highlight TEXT,
+    average_rating NUMERIC,
+    rating_count BIGINT
+)
+LANGUAGE sql
+STABLE
+AS $$
+    WITH page AS (
+        SELECT m.course_id, m.relevance, m.fuzzy,
+               COALESCE(r.average_rating, 0) AS average_rating,
+               COALESCE(r.rating_count, 0) AS rating_count
+        FROM match_courses(p_query, p_viewer_id, p_all) m
+        JOIN courses c ON c.id = m.course_id
+        LEFT JOIN LATERAL (
+            SELECT ROUND(AVG(ratings.score)::NUMERIC, 2) AS average_rating, COUNT(*) AS rating_count
+            FROM ratings WHERE ratings.course_id = c.id AND ratings.deleted_at IS NULL
+        ) r ON TRUE
+        WHERE (p_price_bucket IS NULL OR course_price_bucket(c.price) = p_price_bucket)
+          AND (p_min_rating IS NULL OR COALESCE(r.average_rating, 0) >= p_min_rating)
+        ORDER BY m.relevance DESC, c.id
+        LIMIT p_limit OFFSET p_offset
+    )
+    SELECT
+        c.id, c.influencer_id, c.title, c.description, c.price, c.cover_image_url, c.status::VARCHAR,
+        c.created_at, c.updated_at,
+        p.relevance, p.fuzzy,
+        CASE WHEN p.fuzzy THEN c.title
+             ELSE ts_headline('english', c.title, websearch_to_tsquery('english', p_query),
+                  'HighlightAll=true, StartSel=' || chr(57344) || ', StopSel=' || chr(57345))
+        END,
+        CASE WHEN p.fuzzy THEN left(COALESCE(c.description, ''), 200)
+             ELSE ts_headline('english', COALESCE(c.description, ''), websearch_to_tsquery('english', p_query),
+                  'MaxFragments=2, MaxWords=30, MinWords=10, StartSel=' || chr(57344) || ', StopSel=' || chr(57345))
+        END,
+        p.average_rating, p.rating_count
+    FROM page p
+    JOIN courses c ON c.id = p.course_id
+    ORDER BY p.relevance DESC, c.id;
+$$;
+
+-- Function: search_course_facets
+-- Match counts per price range and per minimum average rating. The counts cover every match,
+-- not just those left after the price and rating filters, so they stay put while drilling down.
+CREATE OR REPLACE FUNCTION search_course_facets(p_query TEXT, p_viewer_id UUID, p_all BOOLEAN)
+RETURNS TABLE (
+    facet VARCHAR,
+    bucket VARCHAR,
+    course_count BIGINT
+)
+LANGUAGE sql
+STABLE
+AS $$
+    WITH matched AS (
+        SELECT 1 AS hit, c.price, COALESCE(r.average_rating, 0) AS average_rating
+        FROM match_courses(p_query, p_viewer_id, p_all) m
+        JOIN courses c ON c.id = m.course_id
+        LEFT JOIN LATERAL (
+            SELECT AVG(ratings.score) AS average_rating
+            FROM ratings WHERE ratings.course_id = c.id AND ratings.deleted_at IS NULL
+        ) r ON TRUE
+    ),
+    price_buckets (bucket, position) AS (
+        VALUES ('free', 1), ('under_20', 2), ('20_to_50', 3), ('50_to_100', 4), ('over_100', 5)
+    ),
+    rating_buckets (bucket, min_rating, position) AS (
+        VALUES ('4_up', 4, 1), ('3_up', 3, 2), ('2_up', 2, 3), ('1_up', 1, 4)
+    )
+    SELECT facet, bucket, course_count FROM (
+        SELECT 'price'::VARCHAR AS facet, b.bucket::VARCHAR, COUNT(m.hit) AS course_count, b.position
+        FROM price_buckets b
+        LEFT JOIN matched m ON course_price_bucket(m.price) = b.bucket
+        GROUP BY b.bucket, b.position
+        UNION ALL
+        SELECT 'rating'::VARCHAR, b.bucket::VARCHAR, COUNT(m.hit), b.position
+        FROM rating_buckets b
+        LEFT JOIN matched m ON m.average_rating >= b.min_rating
+        GROUP BY b.bucket, b.position
+    ) facets
+    ORDER BY facet, position;
+$$;
@@ -0,0 +1,343 @@
+-- Course taxonomy: hierarchical categories, free-form tags and admin-curated collections
+
+CREATE TABLE IF NOT EXISTS categories (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    parent_id UUID REFERENCES categories(id) ON DELETE RESTRICT,   -- NULL for top-level categories
+    name VARCHAR(100) NOT NULL,
+    slug VARCHAR(100) NOT NULL UNIQUE,
+    position INTEGER NOT NULL DEFAULT 0,                         -- order among siblings
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_categories_parent CHECK (parent_id <> id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_categories_parent ON categories (parent_id);
+
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS category_id UUID REFERENCES categories(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_courses_category ON courses (category_id) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS tags (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(50) NOT NULL UNIQUE,                            -- lower-cased by the API
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS course_tags (
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    tag_id UUID NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (course_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_course_tags_tag ON course_tags (tag_id);
+
+CREATE TABLE IF NOT EXISTS collections (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL,
+    slug VARCHAR(100) NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    position INTEGER NOT NULL DEFAULT 0,                         -- order on the catalog page
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS collection_courses (
+    collection_id UUID NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    added_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (collection_id, course_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_collection_courses_order ON collection_courses (collection_id, position);
+
+-- Managing the taxonomy is an admin permission
+INSERT INTO permissions (name) VALUES ('catalog:manage')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT roles.id, permissions.id
+FROM roles JOIN permissions ON permissions.name = 'catalog:manage'
+WHERE roles.name = 'admin'
+ON CONFLICT DO NOTHING;
+
+-- Function: create_category
+CREATE OR REPLACE FUNCTION create_category(p_parent_id UUID, p_name VARCHAR, p_slug VARCHAR, p_position INTEGER)
+RETURNS SETOF categories
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    RETURN QUERY
+    INSERT INTO categories (parent_id, name, slug, position)
+    VALUES (p_parent_id, p_name, p_slug, p_position)
+    RETURNING *;
+END;
+$$;
+
+-- Function: update_category
+CREATE OR REPLACE FUNCTION update_category(p_id UUID, p_parent_id UUID, p_name VARCHAR, p_slug VARCHAR, p_position INTEGER)
+RETURNS SETOF categories
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    RETURN QUERY
+    UPDATE categories
+    SET parent_id = p_parent_id,
+        name = p_name,
+        slug = p_slug,
+        position = p_position,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = p_id
+    RETURNING *;
+END;
+$$;
+
+-- Function: delete_category
+-- Courses in the category become uncategorized; a category with subcategories cannot be deleted
+CREATE OR REPLACE FUNCTION delete_category(p_id UUID)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    deleted_count INTEGER;
+BEGIN
+    DELETE FROM categories WHERE id = p_id;
+
+    GET DIAGNOSTICS deleted_count = ROW_COUNT;
+    RETURN deleted_count;
+END;
+$$;
+
+-- Function: get_category
+CREATE OR REPLACE FUNCTION get_category(p_id UUID)
+RETURNS SETOF categories
+LANGUAGE sql
+AS $$
+    SELECT * FROM categories WHERE id = p_id;
+$$;
+
+-- Function: get_categories
+-- Every category, siblings in display order; the API assembles the tree
+CREATE OR REPLACE FUNCTION get_categories()
+RETURNS SETOF categories
+LANGUAGE sql
+AS $$
+    SELECT * FROM categories ORDER BY position, name;
+$$;
+
+-- Function: category_subtree
+-- The category and all of its descendants, for browsing a category with its subcategories
+CREATE OR REPLACE FUNCTION category_subtree(p_id UUID)
+RETURNS SETOF UUID
+LANGUAGE sql
+STABLE
+AS $$
+    WITH RECURSIVE subtree AS (
+        SELECT id FROM categories WHERE id = p_id
+        UNION
+        SELECT categories.id FROM categories JOIN subtree ON categories.parent_id = subtree.id
+    )
+    SELECT id FROM subtree;
+$$;
+
+-- Function: set_course_taxonomy
+-- Puts the course in a category (NULL for none) and replaces its tags, creating new tags as needed
+CREATE OR REPLACE FUNCTION set_course_taxonomy(p_course_id UUID, p_category_id UUID, p_tags TEXT[])
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    updated_count INTEGER;
+BEGIN
+    UPDATE courses
+    SET category_id = p_category_id, updated_at = CURRENT_TIMESTAMP
+    WHERE id = p_course_id AND deleted_at IS NULL;
+
+    GET DIAGNOSTICS updated_count = ROW_COUNT;
+    IF updated_count = 0 THEN
+        RETURN 0;
+    END IF;
+
+    INSERT INTO tags (name)
+    SELECT DISTINCT unnest(p_tags)
+    ON CONFLICT (name) DO NOTHING;
+
+    DELETE FROM course_tags
+    WHERE course_id = p_course_id
+      AND tag_id NOT IN (SELECT id FROM tags WHERE name = ANY(p_tags));
+
+    INSERT INTO course_tags (course_id, tag_id)
+    SELECT p_course_id, id FROM tags WHERE name = ANY(p_tags)
+    ON CONFLICT DO NOTHING;
+
+    RETURN updated_count;
+END;
+$$;
+
+-- Function: get_popular_tags
+-- Tags in use on published courses, most used first
+CREATE OR REPLACE FUNCTION get_popular_tags(p_limit INTEGER)
+RETURNS TABLE (
+    name VARCHAR,
+    course_count BIGINT
+)
+LANGUAGE sql
+AS $$
+    SELECT tags.name, COUNT(*)
+    FROM tags
+    JOIN course_tags ON course_tags.tag_id = tags.id
+    JOIN courses ON courses.id = course_tags.course_id
+    WHERE courses.status = 'published' AND courses.deleted_at IS NULL
+    GROUP BY tags.name
+    ORDER BY COUNT(*) DESC, tags.name
+    LIMIT p_limit;
+$$;
+
+-- Function: create_collection
+CREATE OR REPLACE FUNCTION create_collection(p_name VARCHAR, p_slug VARCHAR, p_description TEXT, p_position INTEGER)
+RETURNS SETOF collections
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    RETURN QUERY
+    INSERT INTO collections (name, slug, description, position)
+    VALUES (p_name, p_slug, COALESCE(p_description, ''), p_position)
+    RETURNING *;
+END;
+$$;
+
+-- Function: update_collection
+CREATE OR REPLACE FUNCTION update_collection(p_id UUID, p_name VARCHAR, p_slug VARCHAR, p_description TEXT, p_position INTEGER)
+RETURNS SETOF collections
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    RETURN QUERY
+    UPDATE collections
+    SET name = p_name,
+        slug = p_slug,
+        description = COALESCE(p_description, ''),
+        position = p_position,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = p_id
+    RETURNING *;
+END;
+$$;
+
+-- Function: delete_collection
+CREATE OR REPLACE FUNCTION delete_collection(p_id UUID)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    deleted_count INTEGER;
+BEGIN
+    DELETE FROM collections WHERE id = p_id;
+
+    GET DIAGNOSTICS deleted_count = ROW_COUNT;
+    RETURN deleted_count;
+END;
+$$;
+
+-- Function: get_collection
+CREATE OR REPLACE FUNCTION get_collection(p_id UUID)
+RETURNS SETOF collections
+LANGUAGE sql
+AS $$
+    SELECT * FROM collections WHERE id = p_id;
+$$;
+
+-- Function: get_collection_by_slug
+CREATE OR REPLACE FUNCTION get_collection_by_slug(p_slug VARCHAR)
+RETURNS SETOF collections
+LANGUAGE sql
+AS $$
+    SELECT * FROM collections WHERE slug = p_slug;
+$$;
+
+-- Function: get_collections
+CREATE OR REPLACE FUNCTION get_collections()
+RETURNS SETOF collections
+LANGUAGE sql
+AS $$
+    SELECT * FROM collections ORDER BY position, name;
+$$;
+
+-- Function: set_collection_courses
+-- Replaces the collection's courses; their order in p_course_ids is the display order
+CREATE OR REPLACE FUNCTION set_collection_courses(p_collection_id UUID, p_course_ids UUID[])
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    inserted_count INTEGER;
+BEGIN
+    DELETE FROM collection_courses WHERE collection_id = p_collection_id;
+
+    INSERT INTO collection_courses (collection_id, course_id, position)
+    SELECT p_collection_id, ids.course_id, ids.position
+    FROM unnest(p_course_ids) WITH ORDINALITY AS ids (course_id, position)
+    JOIN courses ON courses.id = ids.course_id AND courses.deleted_at IS NULL;
+
+    GET DIAGNOSTICS inserted_count = ROW_COUNT;
+    RETURN inserted_count;
+END;
+$$;
+
+-- Function: search_courses
+-- Redefined from 023 to return only the search columns; the API joins them to the course row,
+-- so course columns added later need no change here
+DROP FUNCTION IF EXISTS search_courses(TEXT, UUID, BOOLEAN, VARCHAR, NUMERIC, INTEGER, INTEGER);
+CREATE OR REPLACE FUNCTION search_courses(
+    p_query TEXT,
+    p_viewer_id UUID,
+    p_all BOOLEAN,
+    p_price_bucket VARCHAR,
+    p_min_rating NUMERIC,
+    p_limit INTEGER,
+    p_offset INTEGER
+)
+RETURNS TABLE (
+    course_id UUID,
+    relevance REAL,
+    fuzzy BOOLEAN,
+    title_highlight TEXT,
+    description_highlight TEXT,
+    average_rating NUMERIC,
+    rating_count BIGINT
+)
+LANGUAGE sql
+STABLE
+AS $$
+    WITH page AS (
+        SELECT m.course_id, m.relevance, m.fuzzy,
+               COALESCE(r.average_rating, 0) AS average_rating,
+               COALESCE(r.rating_count, 0) AS rating_count
+        FROM match_courses(p_query, p_viewer_id, p_all) m
+        JOIN courses c ON c.id = m.course_id
+        LEFT JOIN LATERAL (
+            SELECT ROUND(AVG(ratings.score)::NUMERIC, 2) AS average_rating, COUNT(*) AS rating_count
+            FROM ratings WHERE ratings.course_id = c.id AND ratings.deleted_at IS NULL
+        ) r ON TRUE
+        WHERE (p_price_bucket IS NULL OR course_price_bucket(c.price) = p_price_bucket)
+          AND (p_min_rating IS NULL OR COALESCE(r.average_rating, 0) >= p_min_rating)
+        ORDER BY m.relevance DESC, c.id
+        LIMIT p_limit OFFSET p_offset
+    )
+    SELECT
+        p.course_id, p.relevance, p.fuzzy,
+        CASE WHEN p.fuzzy THEN c.title
+             ELSE ts_headline('english', c.title, websearch_to_tsquery('english', p_query),
+                  'HighlightAll=true, StartSel=' || chr(57344) || ', StopSel=' || chr(57345))
+        END,
+        CASE WHEN p.fuzzy THEN left(COALESCE(c.description, ''), 200)
+             ELSE ts_headline('english', COALESCE(c.description, ''), websearch_to_tsquery('english', p_query),
+                  'MaxFragments=2, MaxWords=30, MinWords=10, StartSel=' || chr(57344) || ', StopSel=' || chr(57345))
+        END,
+        p.average_rating, p.rating_count
+    FROM page p
+    JOIN courses c ON c.id = p.course_id
+    ORDER BY p.relevance DESC, p.course_id;
+$$;
+
+-- Replaced by reading the course table directly with the API's course column list
+DROP FUNCTION IF EXISTS get_influencer_courses(UUID);
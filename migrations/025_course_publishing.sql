@@ -0,0 +1,148 @@
+-- Course publishing workflow: draft -> in_review -> published -> archived, with admin review
+-- and scheduled release. Status changes go through transition_course and review_course only.
+
+-- Must run outside a transaction block before 'in_review' is used below
+ALTER TYPE course_status ADD VALUE IF NOT EXISTS 'in_review' BEFORE 'published';
+
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS submitted_at TIMESTAMP;        -- last sent to review
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS approved_at TIMESTAMP;         -- set while an approved course waits for publish_at
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMP;
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS rejection_reason TEXT;         -- why the last review sent it back to draft
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS publish_at TIMESTAMP;          -- release an approved course at this time
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS unpublish_at TIMESTAMP;        -- archive a published course at this time
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS published_at TIMESTAMP;        -- when it last went live
+
+UPDATE courses SET published_at = updated_at WHERE status = 'published' AND published_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_courses_review_queue ON courses (submitted_at, id)
+    WHERE status = 'in_review' AND approved_at IS NULL AND deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_courses_publish_at ON courses (publish_at)
+    WHERE status = 'in_review' AND approved_at IS NOT NULL AND deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_courses_unpublish_at ON courses (unpublish_at)
+    WHERE status = 'published' AND deleted_at IS NULL;
+
+-- Reviewing courses is an admin permission
+INSERT INTO permissions (name) VALUES ('courses:review')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT roles.id, permissions.id
+FROM roles JOIN permissions ON permissions.name = 'courses:review'
+WHERE roles.name = 'admin'
+ON CONFLICT DO NOTHING;
+
+-- Function: count_course_lessons
+CREATE OR REPLACE FUNCTION count_course_lessons(p_course_id UUID)
+RETURNS INTEGER
+LANGUAGE sql
+AS $$
+    SELECT COUNT(*)::INTEGER FROM lessons WHERE course_id = p_course_id AND deleted_at IS NULL;
+$$;
+
+-- Function: transition_course
+-- Moves the course from p_from to p_to and returns 1, or returns 0 if it is no longer in p_from.
+-- Entering review resets the previous review; going live records the time and uses up publish_at;
+-- leaving published drops a pending unpublish_at.
+CREATE OR REPLACE FUNCTION transition_course(p_id UUID, p_from VARCHAR, p_to VARCHAR)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    updated_count INTEGER;
+BEGIN
+    UPDATE courses
+    SET status = p_to::course_status,
+        submitted_at = CASE WHEN p_to = 'in_review' THEN CURRENT_TIMESTAMP ELSE submitted_at END,
+        approved_at = NULL,
+        reviewed_by = CASE WHEN p_to = 'in_review' THEN NULL ELSE reviewed_by END,
+        reviewed_at = CASE WHEN p_to = 'in_review' THEN NULL ELSE reviewed_at END,
+        rejection_reason = CASE WHEN p_to = 'in_review' THEN NULL ELSE rejection_reason END,
+        publish_at = CASE WHEN p_to = 'published' THEN NULL ELSE publish_at END,
+        unpublish_at = CASE WHEN p_from = 'published' THEN NULL ELSE unpublish_at END,
+        published_at = CASE WHEN p_to = 'published' THEN CURRENT_TIMESTAMP ELSE published_at END,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = p_id AND status::text = p_from AND deleted_at IS NULL;
+
+    GET DIAGNOSTICS updated_count = ROW_COUNT;
+    RETURN updated_count;
+END;
+$$;
+
+-- Function: review_course
+-- Records an admin decision on a course in review and returns its new status, or NULL if it is not
+-- awaiting review. Approved courses go live at once unless publish_at is still ahead; rejected ones
+-- return to draft with the reason.
+CREATE OR REPLACE FUNCTION review_course(p_id UUID, p_reviewer_id UUID, p_approve BOOLEAN, p_reason TEXT)
+RETURNS VARCHAR
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    new_status VARCHAR;
+BEGIN
+    UPDATE courses
+    SET status = CASE
+            WHEN NOT p_approve THEN 'draft'
+            WHEN publish_at IS NULL OR publish_at <= CURRENT_TIMESTAMP THEN 'published'
+            ELSE 'in_review'
+        END::course_status,
+        approved_at = CASE WHEN p_approve AND publish_at > CURRENT_TIMESTAMP THEN CURRENT_TIMESTAMP END,
+        reviewed_by = p_reviewer_id,
+        reviewed_at = CURRENT_TIMESTAMP,
+        rejection_reason = CASE WHEN p_approve THEN NULL ELSE p_reason END,
+        published_at = CASE
+            WHEN p_approve AND (publish_at IS NULL OR publish_at <= CURRENT_TIMESTAMP) THEN CURRENT_TIMESTAMP
+            ELSE published_at
+        END,
+        publish_at = CASE
+            WHEN p_approve AND (publish_at IS NULL OR publish_at <= CURRENT_TIMESTAMP) THEN NULL
+            ELSE publish_at
+        END,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = p_id AND status = 'in_review' AND approved_at IS NULL AND deleted_at IS NULL
+    RETURNING status::VARCHAR INTO new_status;
+
+    RETURN new_status;
+END;
+$$;
+
+-- Function: schedule_course
+-- Sets or clears the release and withdrawal times; returns 0 if the course does not exist
+CREATE OR REPLACE FUNCTION schedule_course(p_id UUID, p_publish_at TIMESTAMP, p_unpublish_at TIMESTAMP)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    updated_count INTEGER;
+BEGIN
+    UPDATE courses
+    SET publish_at = p_publish_at,
+        unpublish_at = p_unpublish_at,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = p_id AND deleted_at IS NULL;
+
+    GET DIAGNOSTICS updated_count = ROW_COUNT;
+    RETURN updated_count;
+END;
+$$;
+
+-- Function: get_due_course_releases
+-- Approved courses whose publish_at has come, and published courses whose unpublish_at has come
+CREATE OR REPLACE FUNCTION get_due_course_releases(p_limit INTEGER)
+RETURNS TABLE (
+    id UUID,
+    status VARCHAR
+)
+LANGUAGE sql
+AS $$
+    SELECT id, status::VARCHAR FROM (
+        SELECT id, status, publish_at AS due_at FROM courses
+        WHERE status = 'in_review' AND approved_at IS NOT NULL AND publish_at <= CURRENT_TIMESTAMP
+          AND deleted_at IS NULL
+        UNION ALL
+        SELECT id, status, unpublish_at FROM courses
+        WHERE status = 'published' AND unpublish_at <= CURRENT_TIMESTAMP AND deleted_at IS NULL
+    ) due
+    ORDER BY due_at
+    LIMIT p_limit;
+$$;
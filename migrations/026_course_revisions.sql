@@ -0,0 +1,246 @@
+-- Course revisions: a published course is edited through a draft revision that is previewed and then
+-- published in one transaction. Each publish is recorded as a numbered version holding a snapshot of the
+-- course and its lessons, so an earlier version can be put back live.
+
+CREATE TABLE IF NOT EXISTS course_revisions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    version INTEGER,                              -- NULL while it is the course's draft
+    title TEXT NOT NULL,
+    description TEXT,
+    price FLOAT8,
+    cover_image_url TEXT[],
+    lessons JSONB NOT NULL DEFAULT '[]',          -- [{id, title, video_url, order}]; id is absent for lessons not live yet
+    restored_from INTEGER,                        -- the version a rollback copied
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    published_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    published_at TIMESTAMP,
+    CONSTRAINT uq_course_revisions_version UNIQUE (course_id, version)
+);
+
+-- At most one draft per course
+CREATE UNIQUE INDEX IF NOT EXISTS uq_course_revisions_draft ON course_revisions (course_id) WHERE version IS NULL;
+
+-- Function: course_lessons_snapshot
+-- The course's live lessons in the revision JSON format
+CREATE OR REPLACE FUNCTION course_lessons_snapshot(p_course_id UUID)
+RETURNS JSONB
+LANGUAGE sql
+AS $$
+    SELECT COALESCE(jsonb_agg(jsonb_build_object(
+            'id', id,
+            'title', title,
+            'video_url', to_jsonb(COALESCE(video_url, '{}'::TEXT[])),
+            'order', lesson_order
+        ) ORDER BY lesson_order, created_at), '[]'::JSONB)
+    FROM lessons
+    WHERE course_id = p_course_id AND deleted_at IS NULL;
+$$;
+
+-- Function: apply_course_snapshot
+-- Makes the live course match a snapshot: lessons missing from it are soft-deleted, lessons in it are
+-- updated (and restored if they had been deleted), and lessons without an id, or whose row is gone, are created.
+CREATE OR REPLACE FUNCTION apply_course_snapshot(
+    p_course_id UUID,
+    p_title TEXT,
+    p_description TEXT,
+    p_price FLOAT8,
+    p_cover_image_url TEXT[],
+    p_lessons JSONB
+)
+RETURNS VOID
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    UPDATE courses
+    SET title = p_title,
+        description = p_description,
+        price = p_price,
+        cover_image_url = p_cover_image_url,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = p_course_id;
+
+    UPDATE lessons
+    SET deleted_at = CURRENT_TIMESTAMP
+    WHERE course_id = p_course_id AND deleted_at IS NULL
+      AND id NOT IN (
+          SELECT (l->>'id')::UUID FROM jsonb_array_elements(p_lessons) l WHERE l->>'id' IS NOT NULL
+      );
+
+    UPDATE lessons
+    SET title = l.title,
+        video_url = l.video_url,
+        lesson_order = l."order",
+        deleted_at = NULL,
+        updated_at = CURRENT_TIMESTAMP
+    FROM jsonb_to_recordset(p_lessons) AS l(id UUID, title TEXT, video_url TEXT[], "order" INTEGER)
+    WHERE lessons.id = l.id AND lessons.course_id = p_course_id;
+
+    INSERT INTO lessons (id, course_id, title, video_url, lesson_order, created_at, updated_at)
+    SELECT COALESCE(l.id, uuid_generate_v4()), p_course_id, l.title, l.video_url, l."order", NOW(), NOW()
+    FROM jsonb_to_recordset(p_lessons) AS l(id UUID, title TEXT, video_url TEXT[], "order" INTEGER)
+    WHERE l.id IS NULL OR NOT EXISTS (SELECT 1 FROM lessons WHERE lessons.id = l.id);
+END;
+$$;
+
+-- Function: create_course_draft
+-- Returns the course's draft, creating it from the live course if there is none. The first draft of a
+-- course also records its live state as version 1, so there is always a version to roll back to.
+CREATE OR REPLACE FUNCTION create_course_draft(p_course_id UUID, p_user_id UUID)
+RETURNS UUID
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    draft_id UUID;
+BEGIN
+    INSERT INTO course_revisions (course_id, version, title, description, price, cover_image_url, lessons,
+                                  created_by, published_at)
+    SELECT id, 1, title, description, price, cover_image_url, course_lessons_snapshot(id),
+           influencer_id, COALESCE(published_at, updated_at)
+    FROM courses
+    WHERE id = p_course_id AND deleted_at IS NULL
+      AND NOT EXISTS (SELECT 1 FROM course_revisions WHERE course_id = p_course_id AND version IS NOT NULL)
+    ON CONFLICT (course_id, version) DO NOTHING;
+
+    INSERT INTO course_revisions (course_id, title, description, price, cover_image_url, lessons, created_by)
+    SELECT id, title, description, price, cover_image_url, course_lessons_snapshot(id), p_user_id
+    FROM courses
+    WHERE id = p_course_id AND deleted_at IS NULL
+    ON CONFLICT (course_id) WHERE version IS NULL DO NOTHING;
+
+    SELECT id INTO draft_id FROM course_revisions WHERE course_id = p_course_id AND version IS NULL;
+    RETURN draft_id;
+END;
+$$;
+
+-- Function: save_course_draft
+-- Replaces the draft's content; returns 0 if the course has no draft. Lesson ids that do not belong to
+-- the course are dropped, so those lessons are created as new ones on publish.
+CREATE OR REPLACE FUNCTION save_course_draft(
+    p_course_id UUID,
+    p_title TEXT,
+    p_description TEXT,
+    p_price FLOAT8,
+    p_cover_image_url TEXT[],
+    p_lessons JSONB
+)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    updated_count INTEGER;
+BEGIN
+    UPDATE course_revisions
+    SET title = p_title,
+        description = p_description,
+        price = p_price,
+        cover_image_url = p_cover_image_url,
+        lessons = (
+            SELECT COALESCE(jsonb_agg(
+                    CASE
+                        WHEN l->>'id' IS NULL THEN l - 'id'
+                        WHEN EXISTS (
+                            SELECT 1 FROM lessons WHERE lessons.id = (l->>'id')::UUID AND lessons.course_id = p_course_id
+                        ) THEN l
+                        ELSE l - 'id'
+                    END ORDER BY position), '[]'::JSONB)
+            FROM jsonb_array_elements(p_lessons) WITH ORDINALITY AS t(l, position)
+        ),
+        updated_at = CURRENT_TIMESTAMP
+    WHERE course_id = p_course_id AND version IS NULL;
+
+    GET DIAGNOSTICS updated_count = ROW_COUNT;
+    RETURN updated_count;
+END;
+$$;
+
+-- Function: delete_course_draft
+CREATE OR REPLACE FUNCTION delete_course_draft(p_course_id UUID)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    deleted_count INTEGER;
+BEGIN
+    DELETE FROM course_revisions WHERE course_id = p_course_id AND version IS NULL;
+
+    GET DIAGNOSTICS deleted_count = ROW_COUNT;
+    RETURN deleted_count;
+END;
+$$;
+
+-- Function: publish_course_draft
+-- Applies the draft to the published course and records it as the next version, which it returns;
+-- NULL if the course is not published or has no draft. The stored snapshot carries the ids of the
+-- lessons the publish created, so a later rollback restores those same lessons.
+CREATE OR REPLACE FUNCTION publish_course_draft(p_course_id UUID, p_user_id UUID)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    draft course_revisions%ROWTYPE;
+    new_version INTEGER;
+BEGIN
+    PERFORM 1 FROM courses WHERE id = p_course_id AND status = 'published' AND deleted_at IS NULL FOR UPDATE;
+    IF NOT FOUND THEN
+        RETURN NULL;
+    END IF;
+
+    SELECT * INTO draft FROM course_revisions WHERE course_id = p_course_id AND version IS NULL FOR UPDATE;
+    IF NOT FOUND THEN
+        RETURN NULL;
+    END IF;
+
+    PERFORM apply_course_snapshot(p_course_id, draft.title, draft.description, draft.price,
+                                  draft.cover_image_url, draft.lessons);
+
+    SELECT COALESCE(MAX(version), 0) + 1 INTO new_version FROM course_revisions WHERE course_id = p_course_id;
+
+    UPDATE course_revisions
+    SET version = new_version,
+        lessons = course_lessons_snapshot(p_course_id),
+        updated_at = CURRENT_TIMESTAMP,
+        published_by = p_user_id,
+        published_at = CURRENT_TIMESTAMP
+    WHERE id = draft.id;
+
+    RETURN new_version;
+END;
+$$;
+
+-- Function: rollback_course
+-- Puts an earlier version of a published course back live and records it as the next version, which it
+-- returns; NULL if the course is not published or the version does not exist. The draft is left alone.
+CREATE OR REPLACE FUNCTION rollback_course(p_course_id UUID, p_version INTEGER, p_user_id UUID)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    target course_revisions%ROWTYPE;
+    new_version INTEGER;
+BEGIN
+    PERFORM 1 FROM courses WHERE id = p_course_id AND status = 'published' AND deleted_at IS NULL FOR UPDATE;
+    IF NOT FOUND THEN
+        RETURN NULL;
+    END IF;
+
+    SELECT * INTO target FROM course_revisions WHERE course_id = p_course_id AND version = p_version;
+    IF NOT FOUND THEN
+        RETURN NULL;
+    END IF;
+
+    PERFORM apply_course_snapshot(p_course_id, target.title, target.description, target.price,
+                                  target.cover_image_url, target.lessons);
+
+    SELECT MAX(version) + 1 INTO new_version FROM course_revisions WHERE course_id = p_course_id;
+
+    INSERT INTO course_revisions (course_id, version, title, description, price, cover_image_url, lessons,
+                                  restored_from, created_by, published_by, published_at)
+    VALUES (p_course_id, new_version, target.title, target.description, target.price, target.cover_image_url,
+            course_lessons_snapshot(p_course_id), p_version, p_user_id, p_user_id, CURRENT_TIMESTAMP);
+
+    RETURN new_version;
+END;
+$$;
@@ -0,0 +1,644 @@
+-- Money: amounts are stored as integer minor units (cents) with an ISO 4217 currency code instead of
+-- floats. A course has a base price plus optional prices in other currencies, and every change to
+-- either is recorded in course_price_history so the price a past purchase was made at can be found.
+
+CREATE TABLE IF NOT EXISTS currencies (
+    code CHAR(3) PRIMARY KEY,
+    minor_units SMALLINT NOT NULL CHECK (minor_units BETWEEN 0 AND 4)
+);
+
+-- Keep in sync with model.Currencies
+INSERT INTO currencies (code, minor_units) VALUES
+    ('USD', 2), ('EUR', 2), ('GBP', 2), ('KES', 2), ('ETB', 2), ('SOS', 2), ('DJF', 0)
+ON CONFLICT (code) DO NOTHING;
+
+-- Function: money_major
+-- An amount in minor units as a decimal in major units, e.g. 1999 USD -> 19.99
+CREATE OR REPLACE FUNCTION money_major(p_amount BIGINT, p_currency CHAR(3))
+RETURNS NUMERIC
+LANGUAGE sql
+STABLE
+AS $$
+    SELECT p_amount::NUMERIC / (10 ^ minor_units)::NUMERIC FROM currencies WHERE code = p_currency;
+$$;
+
+-- Courses: the float price becomes the base price in minor units; existing prices were dollars
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS price_amount BIGINT NOT NULL DEFAULT 0 CHECK (price_amount >= 0);
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS price_currency CHAR(3) NOT NULL DEFAULT 'USD' REFERENCES currencies(code);
+
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'courses' AND column_name = 'price') THEN
+        UPDATE courses SET price_amount = ROUND(COALESCE(price, 0) * 100)::BIGINT;
+        ALTER TABLE courses DROP COLUMN price;
+    END IF;
+END
+$$;
+
+CREATE INDEX IF NOT EXISTS idx_courses_price ON courses (price_currency, price_amount) WHERE deleted_at IS NULL;
+
+-- Prices of a course in currencies other than its base currency
+CREATE TABLE IF NOT EXISTS course_prices (
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    currency CHAR(3) NOT NULL REFERENCES currencies(code),
+    amount BIGINT NOT NULL CHECK (amount >= 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (course_id, currency)
+);
+
+-- Every price a course has had; amount is NULL from when the course stopped being sold in the currency
+CREATE TABLE IF NOT EXISTS course_price_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    currency CHAR(3) NOT NULL REFERENCES currencies(code),
+    amount BIGINT,
+    changed_at TIMESTAMP NOT NULL DEFAULT clock_timestamp()
+);
+
+CREATE INDEX IF NOT EXISTS idx_course_price_history_course ON course_price_history (course_id, currency, changed_at DESC);
+
+-- Existing prices start the history at the time the course was created
+INSERT INTO course_price_history (course_id, currency, amount, changed_at)
+SELECT id, price_currency, price_amount, COALESCE(created_at, CURRENT_TIMESTAMP)
+FROM courses
+WHERE NOT EXISTS (SELECT 1 FROM course_price_history h WHERE h.course_id = courses.id);
+
+-- Trigger: record changes of the base price. When the base currency changes, the old currency is
+-- recorded as no longer sold unless the course keeps a price in it.
+CREATE OR REPLACE FUNCTION courses_price_history_trigger()
+RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' THEN
+        IF NEW.price_amount = OLD.price_amount AND NEW.price_currency = OLD.price_currency THEN
+            RETURN NULL;
+        END IF;
+        IF NEW.price_currency <> OLD.price_currency AND NOT EXISTS (
+            SELECT 1 FROM course_prices WHERE course_id = NEW.id AND currency = OLD.price_currency
+        ) THEN
+            INSERT INTO course_price_history (course_id, currency, amount) VALUES (NEW.id, OLD.price_currency, NULL);
+        END IF;
+    END IF;
+
+    INSERT INTO course_price_history (course_id, currency, amount) VALUES (NEW.id, NEW.price_currency, NEW.price_amount);
+    RETURN NULL;
+END;
+$$;
+
+DROP TRIGGER IF EXISTS trg_courses_price_history_insert ON courses;
+CREATE TRIGGER trg_courses_price_history_insert
+    AFTER INSERT ON courses
+    FOR EACH ROW EXECUTE FUNCTION courses_price_history_trigger();
+
+DROP TRIGGER IF EXISTS trg_courses_price_history_update ON courses;
+CREATE TRIGGER trg_courses_price_history_update
+    AFTER UPDATE OF price_amount, price_currency ON courses
+    FOR EACH ROW EXECUTE FUNCTION courses_price_history_trigger();
+
+-- Trigger: record changes of the other-currency prices. A row in the base currency is about to be
+-- replaced by the base price, which the courses trigger records, so it is skipped.
+CREATE OR REPLACE FUNCTION course_prices_history_trigger()
+RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO course_price_history (course_id, currency, amount)
+        SELECT OLD.course_id, OLD.currency, NULL
+        FROM courses WHERE id = OLD.course_id AND price_currency <> OLD.currency;
+    ELSIF TG_OP = 'INSERT' OR NEW.amount <> OLD.amount THEN
+        INSERT INTO course_price_history (course_id, currency, amount)
+        SELECT NEW.course_id, NEW.currency, NEW.amount
+        FROM courses WHERE id = NEW.course_id AND price_currency <> NEW.currency;
+    END IF;
+    RETURN NULL;
+END;
+$$;
+
+DROP TRIGGER IF EXISTS trg_course_prices_history ON course_prices;
+CREATE TRIGGER trg_course_prices_history
+    AFTER INSERT OR UPDATE OR DELETE ON course_prices
+    FOR EACH ROW EXECUTE FUNCTION course_prices_history_trigger();
+
+-- Procedures: create_course and update_course take the base price as amount and currency
+DROP PROCEDURE IF EXISTS create_course(UUID, UUID, TEXT, TEXT, FLOAT8, TEXT[], course_status);
+CREATE OR REPLACE PROCEDURE create_course(
+    IN p_id UUID,
+    IN p_influencer_id UUID,
+    IN p_title TEXT,
+    IN p_description TEXT,
+    IN p_price_amount BIGINT,
+    IN p_price_currency CHAR(3),
+    IN p_cover_image_url TEXT[],
+    IN p_status course_status
+)
+LANGUAGE plpgsql AS $$
+BEGIN
+    INSERT INTO courses (
+        id, influencer_id, title, description, price_amount, price_currency, cover_image_url, status
+    ) VALUES (
+        p_id, p_influencer_id, p_title, p_description, p_price_amount, p_price_currency, p_cover_image_url, p_status
+    );
+END;
+$$;
+
+-- A price the course had in its new base currency is replaced by the base price
+DROP PROCEDURE IF EXISTS update_course(UUID, TEXT, TEXT, FLOAT8, TEXT[], VARCHAR);
+CREATE OR REPLACE PROCEDURE update_course(
+    IN p_id UUID,
+    IN p_title TEXT,
+    IN p_description TEXT,
+    IN p_price_amount BIGINT,
+    IN p_price_currency CHAR(3),
+    IN p_cover_image_url TEXT[],
+    IN p_status VARCHAR
+)
+LANGUAGE plpgsql AS $$
+BEGIN
+    UPDATE courses
+    SET
+        title = p_title,
+        description = p_description,
+        price_amount = p_price_amount,
+        price_currency = p_price_currency,
+        cover_image_url = p_cover_image_url,
+        status = p_status::course_status,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = p_id AND deleted_at IS NULL;
+
+    DELETE FROM course_prices WHERE course_id = p_id AND currency = p_price_currency;
+END;
+$$;
+
+-- Function: set_course_price
+-- Sets the course's price in a currency: the base price if it is the base currency, otherwise an
+-- entry of its price list. Returns 0 if the course does not exist.
+CREATE OR REPLACE FUNCTION set_course_price(p_course_id UUID, p_currency CHAR(3), p_amount BIGINT)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    updated_count INTEGER;
+BEGIN
+    UPDATE courses
+    SET price_amount = p_amount,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = p_course_id AND price_currency = p_currency AND deleted_at IS NULL;
+
+    GET DIAGNOSTICS updated_count = ROW_COUNT;
+    IF updated_count > 0 THEN
+        RETURN updated_count;
+    END IF;
+
+    INSERT INTO course_prices (course_id, currency, amount)
+    SELECT id, p_currency, p_amount FROM courses
+    WHERE id = p_course_id AND price_currency <> p_currency AND deleted_at IS NULL
+    ON CONFLICT (course_id, currency) DO UPDATE
+    SET amount = EXCLUDED.amount,
+        updated_at = CURRENT_TIMESTAMP;
+
+    GET DIAGNOSTICS updated_count = ROW_COUNT;
+    RETURN updated_count;
+END;
+$$;
+
+-- Function: delete_course_price
+-- Stops selling the course in a currency other than its base one; returns 0 if it had no price in it
+CREATE OR REPLACE FUNCTION delete_course_price(p_course_id UUID, p_currency CHAR(3))
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    deleted_count INTEGER;
+BEGIN
+    DELETE FROM course_prices WHERE course_id = p_course_id AND currency = p_currency;
+
+    GET DIAGNOSTICS deleted_count = ROW_COUNT;
+    RETURN deleted_count;
+END;
+$$;
+
+-- Function: course_price_in
+-- The course's price in a currency, or NULL if it is not sold in it
+CREATE OR REPLACE FUNCTION course_price_in(p_course_id UUID, p_currency CHAR(3))
+RETURNS BIGINT
+LANGUAGE sql
+STABLE
+AS $$
+    SELECT price_amount FROM courses WHERE id = p_course_id AND price_currency = p_currency
+    UNION ALL
+    SELECT amount FROM course_prices WHERE course_id = p_course_id AND currency = p_currency
+    LIMIT 1;
+$$;
+
+-- Function: course_price_bucket
+-- Redefined from 023 for money; buckets are in the major units of the course's base currency
+DROP FUNCTION IF EXISTS course_price_bucket(FLOAT);
+CREATE OR REPLACE FUNCTION course_price_bucket(p_amount BIGINT, p_currency CHAR(3))
+RETURNS VARCHAR
+LANGUAGE sql
+STABLE
+AS $$
+    SELECT CASE
+        WHEN p_amount = 0 THEN 'free'
+        WHEN money_major(p_amount, p_currency) < 20 THEN 'under_20'
+        WHEN money_major(p_amount, p_currency) < 50 THEN '20_to_50'
+        WHEN money_major(p_amount, p_currency) < 100 THEN '50_to_100'
+        ELSE 'over_100'
+    END;
+$$;
+
+-- Function: search_courses
+-- Redefined from 024 for the money price columns
+CREATE OR REPLACE FUNCTION search_courses(
+    p_query TEXT,
+    p_viewer_id UUID,
+    p_all BOOLEAN,
+    p_price_bucket VARCHAR,
+    p_min_rating NUMERIC,
+    p_limit INTEGER,
+    p_offset INTEGER
+)
+RETURNS TABLE (
+    course_id UUID,
+    relevance REAL,
+    fuzzy BOOLEAN,
+    title_highlight TEXT,
+    description_highlight TEXT,
+    average_rating NUMERIC,
+    rating_count BIGINT
+)
+LANGUAGE sql
+STABLE
+AS $$
+    WITH page AS (
+        SELECT m.course_id, m.relevance, m.fuzzy,
+               COALESCE(r.average_rating, 0) AS average_rating,
+               COALESCE(r.rating_count, 0) AS rating_count
+        FROM match_courses(p_query, p_viewer_id, p_all) m
+        JOIN courses c ON c.id = m.course_id
+        LEFT JOIN LATERAL (
+            SELECT ROUND(AVG(ratings.score)::NUMERIC, 2) AS average_rating, COUNT(*) AS rating_count
+            FROM ratings WHERE ratings.course_id = c.id AND ratings.deleted_at IS NULL
+        ) r ON TRUE
+        WHERE (p_price_bucket IS NULL OR course_price_bucket(c.price_amount, c.price_currency) = p_price_bucket)
+          AND (p_min_rating IS NULL OR COALESCE(r.average_rating, 0) >= p_min_rating)
+        ORDER BY m.relevance DESC, c.id
+        LIMIT p_limit OFFSET p_offset
+    )
+    SELECT
+        p.course_id, p.relevance, p.fuzzy,
+        CASE WHEN p.fuzzy THEN c.title
+             ELSE ts_headline('english', c.title, websearch_to_tsquery('english', p_query),
+                  'HighlightAll=true, StartSel=' || chr(57344) || ', StopSel=' || chr(57345))
+        END,
+        CASE WHEN p.fuzzy THEN left(COALESCE(c.description, ''), 200)
+             ELSE ts_headline('english', COALESCE(c.description, ''), websearch_to_tsquery('english', p_query),
+                  'MaxFragments=2, MaxWords=30, MinWords=10, StartSel=' || chr(57344) || ', StopSel=' || chr(57345))
+        END,
+        p.average_rating, p.rating_count
+    FROM page p
+    JOIN courses c ON c.id = p.course_id
+    ORDER BY p.relevance DESC, p.course_id;
+$$;
+
+-- Function: search_course_facets
+-- Redefined from 023 for the money price columns
+CREATE OR REPLACE FUNCTION search_course_facets(p_query TEXT, p_viewer_id UUID, p_all BOOLEAN)
+RETURNS TABLE (
+    facet VARCHAR,
+    bucket VARCHAR,
+    course_count BIGINT
+)
+LANGUAGE sql
+STABLE
+AS $$
+    WITH matched AS (
+        SELECT 1 AS hit, course_price_bucket(c.price_amount, c.price_currency) AS price_bucket,
+               COALESCE(r.average_rating, 0) AS average_rating
+        FROM match_courses(p_query, p_viewer_id, p_all) m
+        JOIN courses c ON c.id = m.course_id
+        LEFT JOIN LATERAL (
+            SELECT AVG(ratings.score) AS average_rating
+            FROM ratings WHERE ratings.course_id = c.id AND ratings.deleted_at IS NULL
+        ) r ON TRUE
+    ),
+    price_buckets (bucket, position) AS (
+        VALUES ('free', 1), ('under_20', 2), ('20_to_50', 3), ('50_to_100', 4), ('over_100', 5)
+    ),
+    rating_buckets (bucket, min_rating, position) AS (
+        VALUES ('4_up', 4, 1), ('3_up', 3, 2), ('2_up', 2, 3), ('1_up', 1, 4)
+    )
+    SELECT facet, bucket, course_count FROM (
+        SELECT 'price'::VARCHAR AS facet, b.bucket::VARCHAR, COUNT(m.hit) AS course_count, b.position
+        FROM price_buckets b
+        LEFT JOIN matched m ON m.price_bucket = b.bucket
+        GROUP BY b.bucket, b.position
+        UNION ALL
+        SELECT 'rating'::VARCHAR, b.bucket::VARCHAR, COUNT(m.hit), b.position
+        FROM rating_buckets b
+        LEFT JOIN matched m ON m.average_rating >= b.min_rating
+        GROUP BY b.bucket, b.position
+    ) facets
+    ORDER BY facet, position;
+$$;
+
+-- Unused since the API reads the courses table with its own column list, and still typed for the float price
+DROP FUNCTION IF EXISTS get_all_courses();
+DROP FUNCTION IF EXISTS get_course_by_id(UUID);
+
+-- Course revisions no longer carry the price: it is changed through the price list, with its own
+-- history, and rolling back content leaves it alone
+ALTER TABLE course_revisions DROP COLUMN IF EXISTS price;
+
+DROP FUNCTION IF EXISTS apply_course_snapshot(UUID, TEXT, TEXT, FLOAT8, TEXT[], JSONB);
+CREATE OR REPLACE FUNCTION apply_course_snapshot(
+    p_course_id UUID,
+    p_title TEXT,
+    p_description TEXT,
+    p_cover_image_url TEXT[],
+    p_lessons JSONB
+)
+RETURNS VOID
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    UPDATE courses
+    SET title = p_title,
+        description = p_description,
+        cover_image_url = p_cover_image_url,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = p_course_id;
+
+    UPDATE lessons
+    SET deleted_at = CURRENT_TIMESTAMP
+    WHERE course_id = p_course_id AND deleted_at IS NULL
+      AND id NOT IN (
+          SELECT (l->>'id')::UUID FROM jsonb_array_elements(p_lessons) l WHERE l->>'id' IS NOT NULL
+      );
+
+    UPDATE lessons
+    SET title = l.title,
+        video_url = l.video_url,
+        lesson_order = l."order",
+        deleted_at = NULL,
+        updated_at = CURRENT_TIMESTAMP
+    FROM jsonb_to_recordset(p_lessons) AS l(id UUID, title TEXT, video_url TEXT[], "order" INTEGER)
+    WHERE lessons.id = l.id AND lessons.course_id = p_course_id;
+
+    INSERT INTO lessons (id, course_id, title, video_url, lesson_order, created_at, updated_at)
+    SELECT COALESCE(l.id, uuid_generate_v4()), p_course_id, l.title, l.video_url, l."order", NOW(), NOW()
+    FROM jsonb_to_recordset(p_lessons) AS l(id UUID, title TEXT, video_url TEXT[], "order" INTEGER)
+    WHERE l.id IS NULL OR NOT EXISTS (SELECT 1 FROM lessons WHERE lessons.id = l.id);
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION create_course_draft(p_course_id UUID, p_user_id UUID)
+RETURNS UUID
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    draft_id UUID;
+BEGIN
+    INSERT INTO course_revisions (course_id, version, title, description, cover_image_url, lessons,
+                                  created_by, published_at)
+    SELECT id, 1, title, description, cover_image_url, course_lessons_snapshot(id),
+           influencer_id, COALESCE(published_at, updated_at)
+    FROM courses
+    WHERE id = p_course_id AND deleted_at IS NULL
+      AND NOT EXISTS (SELECT 1 FROM course_revisions WHERE course_id = p_course_id AND version IS NOT NULL)
+    ON CONFLICT (course_id, version) DO NOTHING;
+
+    INSERT INTO course_revisions (course_id, title, description, cover_image_url, lessons, created_by)
+    SELECT id, title, description, cover_image_url, course_lessons_snapshot(id), p_user_id
+    FROM courses
+    WHERE id = p_course_id AND deleted_at IS NULL
+    ON CONFLICT (course_id) WHERE version IS NULL DO NOTHING;
+
+    SELECT id INTO draft_id FROM course_revisions WHERE course_id = p_course_id AND version IS NULL;
+    RETURN draft_id;
+END;
+$$;
+
+DROP FUNCTION IF EXISTS save_course_draft(UUID, TEXT, TEXT, FLOAT8, TEXT[], JSONB);
+CREATE OR REPLACE FUNCTION save_course_draft(
+    p_course_id UUID,
+    p_title TEXT,
+    p_description TEXT,
+    p_cover_image_url TEXT[],
+    p_lessons JSONB
+)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    updated_count INTEGER;
+BEGIN
+    UPDATE course_revisions
+    SET title = p_title,
+        description = p_description,
+        cover_image_url = p_cover_image_url,
+        lessons = (
+            SELECT COALESCE(jsonb_agg(
+                    CASE
+                        WHEN l->>'id' IS NULL THEN l - 'id'
+                        WHEN EXISTS (
+                            SELECT 1 FROM lessons WHERE lessons.id = (l->>'id')::UUID AND lessons.course_id = p_course_id
+                        ) THEN l
+                        ELSE l - 'id'
+                    END ORDER BY position), '[]'::JSONB)
+            FROM jsonb_array_elements(p_lessons) WITH ORDINALITY AS t(l, position)
+        ),
+        updated_at = CURRENT_TIMESTAMP
+    WHERE course_id = p_course_id AND version IS NULL;
+
+    GET DIAGNOSTICS updated_count = ROW_COUNT;
+    RETURN updated_count;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION publish_course_draft(p_course_id UUID, p_user_id UUID)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    draft course_revisions%ROWTYPE;
+    new_version INTEGER;
+BEGIN
+    PERFORM 1 FROM courses WHERE id = p_course_id AND status = 'published' AND deleted_at IS NULL FOR UPDATE;
+    IF NOT FOUND THEN
+        RETURN NULL;
+    END IF;
+
+    SELECT * INTO draft FROM course_revisions WHERE course_id = p_course_id AND version IS NULL FOR UPDATE;
+    IF NOT FOUND THEN
+        RETURN NULL;
+    END IF;
+
+    PERFORM apply_course_snapshot(p_course_id, draft.title, draft.description, draft.cover_image_url, draft.lessons);
+
+    SELECT COALESCE(MAX(version), 0) + 1 INTO new_version FROM course_revisions WHERE course_id = p_course_id;
+
+    UPDATE course_revisions
+    SET version = new_version,
+        lessons = course_lessons_snapshot(p_course_id),
+        updated_at = CURRENT_TIMESTAMP,
+        published_by = p_user_id,
+        published_at = CURRENT_TIMESTAMP
+    WHERE id = draft.id;
+
+    RETURN new_version;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION rollback_course(p_course_id UUID, p_version INTEGER, p_user_id UUID)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    target course_revisions%ROWTYPE;
+    new_version INTEGER;
+BEGIN
+    PERFORM 1 FROM courses WHERE id = p_course_id AND status = 'published' AND deleted_at IS NULL FOR UPDATE;
+    IF NOT FOUND THEN
+        RETURN NULL;
+    END IF;
+
+    SELECT * INTO target FROM course_revisions WHERE course_id = p_course_id AND version = p_version;
+    IF NOT FOUND THEN
+        RETURN NULL;
+    END IF;
+
+    PERFORM apply_course_snapshot(p_course_id, target.title, target.description, target.cover_image_url, target.lessons);
+
+    SELECT MAX(version) + 1 INTO new_version FROM course_revisions WHERE course_id = p_course_id;
+
+    INSERT INTO course_revisions (course_id, version, title, description, cover_image_url, lessons,
+                                  restored_from, created_by, published_by, published_at)
+    VALUES (p_course_id, new_version, target.title, target.description, target.cover_image_url,
+            course_lessons_snapshot(p_course_id), p_version, p_user_id, p_user_id, CURRENT_TIMESTAMP);
+
+    RETURN new_version;
+END;
+$$;
+
+-- Payments: the float amount becomes minor units; existing amounts were dollars
+DO $$
+BEGIN
+    IF (SELECT data_type FROM information_schema.columns
+        WHERE table_name = 'payments' AND column_name = 'amount') = 'double precision' THEN
+        ALTER TABLE payments ALTER COLUMN amount TYPE BIGINT USING ROUND(amount * 100)::BIGINT;
+    END IF;
+END
+$$;
+
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD' REFERENCES currencies(code);
+
+DROP PROCEDURE IF EXISTS create_payment(UUID, VARCHAR, UUID, UUID, DOUBLE PRECISION, VARCHAR, TIMESTAMPTZ);
+CREATE OR REPLACE PROCEDURE create_payment(
+    IN p_id UUID,
+    IN p_external_ref VARCHAR,
+    IN p_user_id UUID,
+    IN p_subscription_id UUID,
+    IN p_amount BIGINT,
+    IN p_currency CHAR(3),
+    IN p_status VARCHAR,
+    IN p_processed_at TIMESTAMPTZ
+)
+LANGUAGE plpgsql AS $$
+BEGIN
+    INSERT INTO payments (id, external_ref, user_id, subscription_id, amount, currency, status, processed_at, created_at, updated_at)
+    VALUES (p_id, p_external_ref, p_user_id, p_subscription_id, p_amount, p_currency, p_status, p_processed_at, NOW(), NOW());
+END;
+$$;
+
+DROP PROCEDURE IF EXISTS update_payment(UUID, VARCHAR, UUID, UUID, DOUBLE PRECISION, VARCHAR, TIMESTAMPTZ);
+CREATE OR REPLACE PROCEDURE update_payment(
+    IN p_id UUID,
+    IN p_external_ref VARCHAR,
+    IN p_user_id UUID,
+    IN p_subscription_id UUID,
+    IN p_amount BIGINT,
+    IN p_currency CHAR(3),
+    IN p_status VARCHAR,
+    IN p_processed_at TIMESTAMPTZ
+)
+LANGUAGE plpgsql AS $$
+BEGIN
+    UPDATE payments
+    SET external_ref = p_external_ref,
+        user_id = p_user_id,
+        subscription_id = p_subscription_id,
+        amount = p_amount,
+        currency = p_currency,
+        status = p_status,
+        processed_at = p_processed_at,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = p_id AND deleted_at IS NULL;
+END;
+$$;
+
+-- Replaced by reading the payments table directly with the API's payment column list
+DROP FUNCTION IF EXISTS get_payment_by_id(UUID);
+DROP FUNCTION IF EXISTS get_user_payments(UUID);
+DROP FUNCTION IF EXISTS get_all_payments();
+DROP FUNCTION IF EXISTS get_payment_by_external_ref(VARCHAR);
+
+-- Withdrawals: the decimal amount becomes minor units
+DO $$
+BEGIN
+    IF (SELECT data_type FROM information_schema.columns
+        WHERE table_name = 'withdrawals' AND column_name = 'amount') = 'numeric' THEN
+        ALTER TABLE withdrawals ALTER COLUMN amount TYPE BIGINT USING ROUND(amount * 100)::BIGINT;
+    END IF;
+END
+$$;
+
+ALTER TABLE withdrawals ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD' REFERENCES currencies(code);
+
+DROP PROCEDURE IF EXISTS create_withdrawal(UUID, UUID, NUMERIC, withdrawal_status, TIMESTAMPTZ, TIMESTAMPTZ);
+CREATE OR REPLACE PROCEDURE create_withdrawal(
+    IN p_id UUID,
+    IN p_influencer_id UUID,
+    IN p_amount BIGINT,
+    IN p_currency CHAR(3),
+    IN p_status withdrawal_status,
+    IN p_requested_at TIMESTAMPTZ,
+    IN p_processed_at TIMESTAMPTZ
+)
+LANGUAGE plpgsql AS $$
+BEGIN
+    INSERT INTO withdrawals (
+        id, influencer_id, amount, currency, status, requested_at, processed_at, created_at, updated_at
+    ) VALUES (
+        p_id, p_influencer_id, p_amount, p_currency, p_status, p_requested_at, p_processed_at, NOW(), NOW()
+    );
+END;
+$$;
+
+DROP PROCEDURE IF EXISTS update_withdrawal(UUID, NUMERIC, withdrawal_status, TIMESTAMPTZ);
+CREATE OR REPLACE PROCEDURE update_withdrawal(
+    IN p_id UUID,
+    IN p_amount BIGINT,
+    IN p_currency CHAR(3),
+    IN p_status withdrawal_status,
+    IN p_processed_at TIMESTAMPTZ
+)
+LANGUAGE plpgsql AS $$
+BEGIN
+    UPDATE withdrawals
+    SET
+        amount = p_amount,
+        currency = p_currency,
+        status = p_status,
+        processed_at = p_processed_at,
+        updated_at = NOW()
+    WHERE id = p_id AND deleted_at IS NULL;
+END;
+$$;
+
+-- Replaced by reading the withdrawals table directly with the API's withdrawal column list
+DROP FUNCTION IF EXISTS get_withdrawal_by_id(UUID);
+DROP FUNCTION IF EXISTS get_user_withdrawals(UUID);
+DROP FUNCTION IF EXISTS get_all_withdrawals();
@@ -0,0 +1,293 @@
+-- Promotions: coupon codes, time-boxed course sales, and the price quotes payments are made against.
+-- A quote fixes the price a user will be charged and the coupon applied; paying it links the payment
+-- to the quote, which is what counts as a coupon redemption.
+
+CREATE TABLE IF NOT EXISTS coupons (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    code VARCHAR(40) NOT NULL UNIQUE,                            -- upper-cased by the API
+    discount_type VARCHAR(20) NOT NULL,                          -- 'percentage' or 'fixed'
+    percent_off INTEGER,                                         -- percentage coupons
+    amount_off BIGINT,                                           -- fixed coupons, minor units of amount_currency
+    amount_currency CHAR(3) REFERENCES currencies(code),
+    course_id UUID REFERENCES courses(id) ON DELETE CASCADE,     -- NULL for site-wide coupons
+    max_redemptions INTEGER,                                     -- NULL for unlimited
+    max_per_user INTEGER,                                        -- NULL for unlimited
+    starts_at TIMESTAMP,                                         -- NULL for immediately
+    expires_at TIMESTAMP,                                        -- NULL for never
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_coupons_discount CHECK (
+        (discount_type = 'percentage' AND percent_off BETWEEN 1 AND 100 AND amount_off IS NULL AND amount_currency IS NULL)
+        OR (discount_type = 'fixed' AND percent_off IS NULL AND amount_off > 0 AND amount_currency IS NOT NULL)
+    ),
+    CONSTRAINT chk_coupons_limits CHECK (max_redemptions > 0 AND max_per_user > 0),
+    CONSTRAINT chk_coupons_window CHECK (expires_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_coupons_course ON coupons (course_id);
+
+-- A sale replaces a course's price in one currency for a while; sales in a currency never overlap
+CREATE TABLE IF NOT EXISTS course_sales (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    currency CHAR(3) NOT NULL REFERENCES currencies(code),
+    amount BIGINT NOT NULL CHECK (amount >= 0),
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_course_sales_window CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_course_sales_course ON course_sales (course_id, currency, starts_at);
+
+CREATE TABLE IF NOT EXISTS price_quotes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    currency CHAR(3) NOT NULL REFERENCES currencies(code),
+    list_amount BIGINT NOT NULL,                                 -- the course's price in the currency
+    sale_id UUID REFERENCES course_sales(id) ON DELETE SET NULL,
+    sale_amount BIGINT,                                          -- the sale price, NULL without a sale
+    coupon_id UUID REFERENCES coupons(id) ON DELETE RESTRICT,
+    coupon_code VARCHAR(40),
+    discount_amount BIGINT NOT NULL DEFAULT 0,                   -- taken off by the coupon
+    total_amount BIGINT NOT NULL CHECK (total_amount >= 0),
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_quotes_coupon ON price_quotes (coupon_id, user_id) WHERE coupon_id IS NOT NULL;
+
+-- A quote is paid at most once
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS quote_id UUID REFERENCES price_quotes(id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payments_quote ON payments (quote_id) WHERE quote_id IS NOT NULL AND deleted_at IS NULL;
+
+-- Running promotions is open to admins, and to influencers for their own courses
+INSERT INTO permissions (name) VALUES ('promotions:manage')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT roles.id, permissions.id
+FROM roles JOIN permissions ON permissions.name = 'promotions:manage'
+WHERE roles.name IN ('admin', 'influencer')
+ON CONFLICT DO NOTHING;
+
+-- Function: coupon_redemptions counts paid quotes that used the coupon, by one user or (NULL) by
+-- everyone; failed and deleted payments give the redemption back
+CREATE OR REPLACE FUNCTION coupon_redemptions(p_coupon_id UUID, p_user_id UUID)
+RETURNS INTEGER
+LANGUAGE SQL
+STABLE
+AS $$
+    SELECT COUNT(*)::INTEGER
+    FROM price_quotes
+    JOIN payments ON payments.quote_id = price_quotes.id
+    WHERE price_quotes.coupon_id = p_coupon_id
+      AND (p_user_id IS NULL OR price_quotes.user_id = p_user_id)
+      AND payments.deleted_at IS NULL
+      AND payments.status <> 'failed';
+$$;
+
+-- Function: create_coupon
+CREATE OR REPLACE FUNCTION create_coupon(
+    p_code VARCHAR, p_discount_type VARCHAR, p_percent_off INTEGER, p_amount_off BIGINT, p_amount_currency CHAR(3),
+    p_course_id UUID, p_max_redemptions INTEGER, p_max_per_user INTEGER,
+    p_starts_at TIMESTAMP, p_expires_at TIMESTAMP, p_active BOOLEAN, p_created_by UUID
+)
+RETURNS SETOF coupons
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    RETURN QUERY
+    INSERT INTO coupons (code, discount_type, percent_off, amount_off, amount_currency, course_id,
+                         max_redemptions, max_per_user, starts_at, expires_at, active, created_by)
+    VALUES (p_code, p_discount_type, p_percent_off, p_amount_off, p_amount_currency, p_course_id,
+            p_max_redemptions, p_max_per_user, p_starts_at, p_expires_at, p_active, p_created_by)
+    RETURNING *;
+END;
+$$;
+
+-- Function: update_coupon; quotes already made keep the discount they were given
+CREATE OR REPLACE FUNCTION update_coupon(
+    p_id UUID, p_code VARCHAR, p_discount_type VARCHAR, p_percent_off INTEGER, p_amount_off BIGINT, p_amount_currency CHAR(3),
+    p_course_id UUID, p_max_redemptions INTEGER, p_max_per_user INTEGER,
+    p_starts_at TIMESTAMP, p_expires_at TIMESTAMP, p_active BOOLEAN
+)
+RETURNS SETOF coupons
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    RETURN QUERY
+    UPDATE coupons
+    SET code = p_code,
+        discount_type = p_discount_type,
+        percent_off = p_percent_off,
+        amount_off = p_amount_off,
+        amount_currency = p_amount_currency,
+        course_id = p_course_id,
+        max_redemptions = p_max_redemptions,
+        max_per_user = p_max_per_user,
+        starts_at = p_starts_at,
+        expires_at = p_expires_at,
+        active = p_active,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = p_id
+    RETURNING *;
+END;
+$$;
+
+-- Function: delete_coupon. Unpaid quotes go with it; a coupon that has been redeemed is kept
+-- (the foreign key fails) and should be deactivated instead.
+CREATE OR REPLACE FUNCTION delete_coupon(p_id UUID)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    deleted INTEGER;
+BEGIN
+    DELETE FROM price_quotes
+    WHERE coupon_id = p_id
+      AND NOT EXISTS (SELECT 1 FROM payments WHERE payments.quote_id = price_quotes.id);
+
+    DELETE FROM coupons WHERE id = p_id;
+    GET DIAGNOSTICS deleted = ROW_COUNT;
+    RETURN deleted;
+END;
+$$;
+
+-- Function: create_course_sale. Returns no row when the sale overlaps another in the same currency;
+-- the course row lock keeps two overlapping sales from being added at once.
+CREATE OR REPLACE FUNCTION create_course_sale(
+    p_course_id UUID, p_currency CHAR(3), p_amount BIGINT, p_starts_at TIMESTAMP, p_ends_at TIMESTAMP, p_created_by UUID
+)
+RETURNS SETOF course_sales
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    PERFORM 1 FROM courses WHERE id = p_course_id AND deleted_at IS NULL FOR UPDATE;
+    IF NOT FOUND THEN
+        RETURN;
+    END IF;
+
+    IF EXISTS (
+        SELECT 1 FROM course_sales
+        WHERE course_id = p_course_id AND currency = p_currency
+          AND starts_at < p_ends_at AND ends_at > p_starts_at
+    ) THEN
+        RETURN;
+    END IF;
+
+    RETURN QUERY
+    INSERT INTO course_sales (course_id, currency, amount, starts_at, ends_at, created_by)
+    VALUES (p_course_id, p_currency, p_amount, p_starts_at, p_ends_at, p_created_by)
+    RETURNING *;
+END;
+$$;
+
+-- Function: delete_course_sale
+CREATE OR REPLACE FUNCTION delete_course_sale(p_course_id UUID, p_id UUID)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    deleted INTEGER;
+BEGIN
+    DELETE FROM course_sales WHERE id = p_id AND course_id = p_course_id;
+    GET DIAGNOSTICS deleted = ROW_COUNT;
+    RETURN deleted;
+END;
+$$;
+
+-- Function: active_course_sale returns the sale running now in the currency, if any
+CREATE OR REPLACE FUNCTION active_course_sale(p_course_id UUID, p_currency CHAR(3))
+RETURNS SETOF course_sales
+LANGUAGE SQL
+STABLE
+AS $$
+    SELECT * FROM course_sales
+    WHERE course_id = p_course_id AND currency = p_currency
+      AND starts_at <= CURRENT_TIMESTAMP AND ends_at > CURRENT_TIMESTAMP
+    LIMIT 1;
+$$;
+
+-- Function: create_price_quote
+CREATE OR REPLACE FUNCTION create_price_quote(
+    p_user_id UUID, p_course_id UUID, p_currency CHAR(3), p_list_amount BIGINT,
+    p_sale_id UUID, p_sale_amount BIGINT, p_coupon_id UUID, p_coupon_code VARCHAR,
+    p_discount_amount BIGINT, p_total_amount BIGINT, p_expires_at TIMESTAMP
+)
+RETURNS SETOF price_quotes
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    RETURN QUERY
+    INSERT INTO price_quotes (user_id, course_id, currency, list_amount, sale_id, sale_amount,
+                              coupon_id, coupon_code, discount_amount, total_amount, expires_at)
+    VALUES (p_user_id, p_course_id, p_currency, p_list_amount, p_sale_id, p_sale_amount,
+            p_coupon_id, p_coupon_code, p_discount_amount, p_total_amount, p_expires_at)
+    RETURNING *;
+END;
+$$;
+
+-- Function: redeem_price_quote checks, while the payment for it is being created, that the quote is
+-- the user's, unexpired, unpaid and for this amount, and that its coupon still has redemptions left.
+-- The coupon row lock keeps concurrent payments from going past the limits.
+CREATE OR REPLACE FUNCTION redeem_price_quote(p_quote_id UUID, p_user_id UUID, p_amount BIGINT, p_currency CHAR(3))
+RETURNS BOOLEAN
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    quote price_quotes%ROWTYPE;
+    coupon coupons%ROWTYPE;
+BEGIN
+    SELECT * INTO quote FROM price_quotes
+    WHERE id = p_quote_id AND user_id = p_user_id AND expires_at > CURRENT_TIMESTAMP
+      AND total_amount = p_amount AND currency = p_currency
+    FOR UPDATE;
+    IF NOT FOUND THEN
+        RETURN FALSE;
+    END IF;
+
+    IF EXISTS (SELECT 1 FROM payments WHERE quote_id = p_quote_id AND deleted_at IS NULL) THEN
+        RETURN FALSE;
+    END IF;
+
+    IF quote.coupon_id IS NOT NULL THEN
+        SELECT * INTO coupon FROM coupons WHERE id = quote.coupon_id FOR UPDATE;
+        IF NOT coupon.active
+           OR coupon_redemptions(coupon.id, NULL) >= coupon.max_redemptions
+           OR coupon_redemptions(coupon.id, p_user_id) >= coupon.max_per_user THEN
+            RETURN FALSE;
+        END IF;
+    END IF;
+
+    RETURN TRUE;
+END;
+$$;
+
+-- Payments may be made against a price quote, which is redeemed in the same statement
+DROP PROCEDURE IF EXISTS create_payment(UUID, VARCHAR, UUID, UUID, BIGINT, CHAR(3), VARCHAR, TIMESTAMPTZ);
+CREATE OR REPLACE PROCEDURE create_payment(
+    IN p_id UUID,
+    IN p_external_ref VARCHAR,
+    IN p_user_id UUID,
+    IN p_subscription_id UUID,
+    IN p_amount BIGINT,
+    IN p_currency CHAR(3),
+    IN p_status VARCHAR,
+    IN p_processed_at TIMESTAMPTZ,
+    IN p_quote_id UUID
+)
+LANGUAGE plpgsql AS $$
+BEGIN
+    IF p_quote_id IS NOT NULL AND NOT redeem_price_quote(p_quote_id, p_user_id, p_amount, p_currency) THEN
+        RAISE EXCEPTION 'price quote cannot be redeemed';
+    END IF;
+
+    INSERT INTO payments (id, external_ref, user_id, subscription_id, amount, currency, status, processed_at, quote_id, created_at, updated_at)
+    VALUES (p_id, p_external_ref, p_user_id, p_subscription_id, p_amount, p_currency, p_status, p_processed_at, p_quote_id, NOW(), NOW());
+END;
+$$;
@@ -0,0 +1,356 @@
+-- Bundles and all-access passes: an influencer sells several of their courses together, or every
+-- course they publish, at one price. Both are bought through a price quote like a single course;
+-- when the payment completes, a purchase is recorded and ordinary subscriptions are created for the
+-- courses it covers, so access checks keep working off subscriptions alone.
+
+CREATE TABLE IF NOT EXISTS bundles (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    influencer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    price_amount BIGINT NOT NULL CHECK (price_amount > 0),
+    price_currency CHAR(3) NOT NULL REFERENCES currencies(code),
+    access_days INTEGER NOT NULL CHECK (access_days > 0),        -- how long buyers keep the courses
+    active BOOLEAN NOT NULL DEFAULT TRUE,                        -- inactive bundles cannot be bought
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_bundles_influencer ON bundles (influencer_id);
+
+CREATE TABLE IF NOT EXISTS bundle_courses (
+    bundle_id UUID NOT NULL REFERENCES bundles(id) ON DELETE CASCADE,
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (bundle_id, course_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_bundle_courses_course ON bundle_courses (course_id);
+
+-- An influencer has at most one pass, covering all their published courses, including later ones
+CREATE TABLE IF NOT EXISTS influencer_passes (
+    influencer_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    price_amount BIGINT NOT NULL CHECK (price_amount > 0),
+    price_currency CHAR(3) NOT NULL REFERENCES currencies(code),
+    access_days INTEGER NOT NULL CHECK (access_days > 0),
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Quotes are for exactly one of a course, a bundle or a pass
+ALTER TABLE price_quotes ALTER COLUMN course_id DROP NOT NULL;
+ALTER TABLE price_quotes ADD COLUMN IF NOT EXISTS bundle_id UUID REFERENCES bundles(id) ON DELETE CASCADE;
+ALTER TABLE price_quotes ADD COLUMN IF NOT EXISTS pass_influencer_id UUID REFERENCES influencer_passes(influencer_id) ON DELETE CASCADE;
+ALTER TABLE price_quotes DROP CONSTRAINT IF EXISTS chk_price_quotes_item;
+ALTER TABLE price_quotes ADD CONSTRAINT chk_price_quotes_item CHECK (num_nonnulls(course_id, bundle_id, pass_influencer_id) = 1);
+
+-- A bundle or pass purchase, made by one completed payment
+CREATE TABLE IF NOT EXISTS purchases (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    payment_id UUID NOT NULL UNIQUE REFERENCES payments(id),
+    bundle_id UUID REFERENCES bundles(id) ON DELETE RESTRICT,
+    pass_influencer_id UUID REFERENCES influencer_passes(influencer_id) ON DELETE RESTRICT,
+    started_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_purchases_item CHECK (num_nonnulls(bundle_id, pass_influencer_id) = 1)
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchases_user ON purchases (user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_purchases_pass ON purchases (pass_influencer_id, expires_at) WHERE pass_influencer_id IS NOT NULL;
+
+-- Subscriptions created by a purchase point back to it
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS purchase_id UUID REFERENCES purchases(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_subscriptions_purchase ON subscriptions (purchase_id) WHERE purchase_id IS NOT NULL;
+
+-- Bundle and pass payments pay a quote rather than a subscription made beforehand
+ALTER TABLE payments ALTER COLUMN subscription_id DROP NOT NULL;
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS chk_payments_item;
+ALTER TABLE payments ADD CONSTRAINT chk_payments_item CHECK (subscription_id IS NOT NULL OR quote_id IS NOT NULL);
+
+-- Function: bundle_course_ids lists a bundle's courses in order
+CREATE OR REPLACE FUNCTION bundle_course_ids(p_bundle_id UUID)
+RETURNS UUID[]
+LANGUAGE SQL
+STABLE
+AS $$
+    SELECT COALESCE(array_agg(course_id ORDER BY position), '{}')
+    FROM bundle_courses WHERE bundle_id = p_bundle_id;
+$$;
+
+-- Function: set_bundle_courses replaces a bundle's courses, keeping the given order
+CREATE OR REPLACE FUNCTION set_bundle_courses(p_bundle_id UUID, p_course_ids UUID[])
+RETURNS VOID
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    DELETE FROM bundle_courses WHERE bundle_id = p_bundle_id;
+    INSERT INTO bundle_courses (bundle_id, course_id, position)
+    SELECT p_bundle_id, ids.course_id, ids.position
+    FROM unnest(p_course_ids) WITH ORDINALITY AS ids(course_id, position);
+END;
+$$;
+
+-- Function: create_bundle
+CREATE OR REPLACE FUNCTION create_bundle(
+    p_influencer_id UUID, p_title VARCHAR, p_description TEXT, p_price_amount BIGINT,
+    p_price_currency CHAR(3), p_access_days INTEGER, p_active BOOLEAN, p_course_ids UUID[]
+)
+RETURNS SETOF bundles
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    bundle bundles%ROWTYPE;
+BEGIN
+    INSERT INTO bundles (influencer_id, title, description, price_amount, price_currency, access_days, active)
+    VALUES (p_influencer_id, p_title, p_description, p_price_amount, p_price_currency, p_access_days, p_active)
+    RETURNING * INTO bundle;
+
+    PERFORM set_bundle_courses(bundle.id, p_course_ids);
+    RETURN NEXT bundle;
+END;
+$$;
+
+-- Function: update_bundle returns no row if the bundle does not exist. Buyers keep the courses
+-- they were granted when courses are taken out of a bundle.
+CREATE OR REPLACE FUNCTION update_bundle(
+    p_id UUID, p_title VARCHAR, p_description TEXT, p_price_amount BIGINT,
+    p_price_currency CHAR(3), p_access_days INTEGER, p_active BOOLEAN, p_course_ids UUID[]
+)
+RETURNS SETOF bundles
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    bundle bundles%ROWTYPE;
+BEGIN
+    UPDATE bundles
+    SET title = p_title,
+        description = p_description,
+        price_amount = p_price_amount,
+        price_currency = p_price_currency,
+        access_days = p_access_days,
+        active = p_active,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = p_id
+    RETURNING * INTO bundle;
+    IF NOT FOUND THEN
+        RETURN;
+    END IF;
+
+    PERFORM set_bundle_courses(bundle.id, p_course_ids);
+    RETURN NEXT bundle;
+END;
+$$;
+
+-- Function: delete_bundle drops the bundle's unpaid quotes with it; a bundle someone has paid for
+-- is kept (the payments reference their quotes), so this raises a foreign key violation
+CREATE OR REPLACE FUNCTION delete_bundle(p_id UUID)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    deleted INTEGER;
+BEGIN
+    DELETE FROM price_quotes
+    WHERE bundle_id = p_id
+      AND NOT EXISTS (SELECT 1 FROM payments WHERE payments.quote_id = price_quotes.id);
+
+    DELETE FROM bundles WHERE id = p_id;
+    GET DIAGNOSTICS deleted = ROW_COUNT;
+    RETURN deleted;
+END;
+$$;
+
+-- Function: set_influencer_pass creates or replaces the influencer's pass; passes already bought
+-- keep their access period
+CREATE OR REPLACE FUNCTION set_influencer_pass(
+    p_influencer_id UUID, p_price_amount BIGINT, p_price_currency CHAR(3), p_access_days INTEGER, p_active BOOLEAN
+)
+RETURNS SETOF influencer_passes
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    RETURN QUERY
+    INSERT INTO influencer_passes (influencer_id, price_amount, price_currency, access_days, active)
+    VALUES (p_influencer_id, p_price_amount, p_price_currency, p_access_days, p_active)
+    ON CONFLICT (influencer_id) DO UPDATE
+    SET price_amount = EXCLUDED.price_amount,
+        price_currency = EXCLUDED.price_currency,
+        access_days = EXCLUDED.access_days,
+        active = EXCLUDED.active,
+        updated_at = CURRENT_TIMESTAMP
+    RETURNING *;
+END;
+$$;
+
+-- Function: grant_purchase_courses subscribes the purchase's buyer to the given courses until the
+-- purchase expires, skipping unpublished courses and ones the buyer already has access to for as
+-- long. Returns the number of subscriptions created.
+CREATE OR REPLACE FUNCTION grant_purchase_courses(p_purchase_id UUID, p_course_ids UUID[])
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    purchase purchases%ROWTYPE;
+    granted INTEGER;
+BEGIN
+    SELECT * INTO purchase FROM purchases WHERE id = p_purchase_id;
+
+    INSERT INTO subscriptions (user_id, course_id, started_at, expires_at, status, purchase_id, created_at, updated_at)
+    SELECT purchase.user_id, courses.id, purchase.started_at, purchase.expires_at, 'active', purchase.id, NOW(), NOW()
+    FROM courses
+    WHERE courses.id = ANY (p_course_ids)
+      AND courses.status = 'published' AND courses.deleted_at IS NULL
+      AND NOT EXISTS (
+          SELECT 1 FROM subscriptions
+          WHERE subscriptions.user_id = purchase.user_id AND subscriptions.course_id = courses.id
+            AND subscriptions.status = 'active' AND subscriptions.deleted_at IS NULL
+            AND subscriptions.expires_at >= purchase.expires_at
+      );
+    GET DIAGNOSTICS granted = ROW_COUNT;
+    RETURN granted;
+END;
+$$;
+
+-- Function: grant_purchase records the purchase a completed bundle or pass payment makes and
+-- subscribes the buyer to its courses. Returns the purchase, or NULL if the payment is not a
+-- completed bundle or pass payment. Calling it again for the same payment changes nothing.
+CREATE OR REPLACE FUNCTION grant_purchase(p_payment_id UUID)
+RETURNS UUID
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    payment payments%ROWTYPE;
+    quote price_quotes%ROWTYPE;
+    v_purchase_id UUID;
+    v_access_days INTEGER;
+    v_course_ids UUID[];
+BEGIN
+    SELECT * INTO payment FROM payments WHERE id = p_payment_id AND deleted_at IS NULL;
+    IF NOT FOUND OR payment.status <> 'completed' OR payment.quote_id IS NULL THEN
+        RETURN NULL;
+    END IF;
+
+    SELECT * INTO quote FROM price_quotes WHERE id = payment.quote_id;
+    IF quote.course_id IS NOT NULL THEN
+        RETURN NULL;
+    END IF;
+
+    SELECT id INTO v_purchase_id FROM purchases WHERE payment_id = p_payment_id;
+    IF FOUND THEN
+        RETURN v_purchase_id;
+    END IF;
+
+    IF quote.bundle_id IS NOT NULL THEN
+        SELECT access_days INTO v_access_days FROM bundles WHERE id = quote.bundle_id;
+        v_course_ids := bundle_course_ids(quote.bundle_id);
+    ELSE
+        SELECT access_days INTO v_access_days FROM influencer_passes WHERE influencer_id = quote.pass_influencer_id;
+        SELECT COALESCE(array_agg(id), '{}') INTO v_course_ids
+        FROM courses WHERE influencer_id = quote.pass_influencer_id;
+    END IF;
+
+    INSERT INTO purchases (user_id, payment_id, bundle_id, pass_influencer_id, started_at, expires_at)
+    VALUES (payment.user_id, payment.id, quote.bundle_id, quote.pass_influencer_id,
+            NOW(), NOW() + make_interval(days => v_access_days))
+    RETURNING id INTO v_purchase_id;
+
+    PERFORM grant_purchase_courses(v_purchase_id, v_course_ids);
+    RETURN v_purchase_id;
+END;
+$$;
+
+-- Function: grant_pass_course subscribes everyone holding an unexpired pass from the course's
+-- influencer to the newly published course. Returns the number of subscriptions created.
+CREATE OR REPLACE FUNCTION grant_pass_course(p_course_id UUID)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    v_purchase_id UUID;
+    granted INTEGER := 0;
+BEGIN
+    FOR v_purchase_id IN
+        SELECT purchases.id FROM purchases
+        JOIN courses ON courses.influencer_id = purchases.pass_influencer_id
+        WHERE courses.id = p_course_id AND purchases.expires_at > NOW()
+    LOOP
+        granted := granted + grant_purchase_courses(v_purchase_id, ARRAY[p_course_id]);
+    END LOOP;
+    RETURN granted;
+END;
+$$;
+
+-- Quotes may be for a bundle or a pass
+DROP FUNCTION IF EXISTS create_price_quote(UUID, UUID, CHAR(3), BIGINT, UUID, BIGINT, UUID, VARCHAR, BIGINT, BIGINT, TIMESTAMP);
+CREATE OR REPLACE FUNCTION create_price_quote(
+    p_user_id UUID, p_course_id UUID, p_bundle_id UUID, p_pass_influencer_id UUID, p_currency CHAR(3),
+    p_list_amount BIGINT, p_sale_id UUID, p_sale_amount BIGINT, p_coupon_id UUID, p_coupon_code VARCHAR,
+    p_discount_amount BIGINT, p_total_amount BIGINT, p_expires_at TIMESTAMP
+)
+RETURNS SETOF price_quotes
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    RETURN QUERY
+    INSERT INTO price_quotes (user_id, course_id, bundle_id, pass_influencer_id, currency, list_amount, sale_id,
+                              sale_amount, coupon_id, coupon_code, discount_amount, total_amount, expires_at)
+    VALUES (p_user_id, p_course_id, p_bundle_id, p_pass_influencer_id, p_currency, p_list_amount, p_sale_id,
+            p_sale_amount, p_coupon_id, p_coupon_code, p_discount_amount, p_total_amount, p_expires_at)
+    RETURNING *;
+END;
+$$;
+
+-- Completing a bundle or pass payment, when it is made or later, makes the purchase in the same statement
+CREATE OR REPLACE PROCEDURE create_payment(
+    IN p_id UUID,
+    IN p_external_ref VARCHAR,
+    IN p_user_id UUID,
+    IN p_subscription_id UUID,
+    IN p_amount BIGINT,
+    IN p_currency CHAR(3),
+    IN p_status VARCHAR,
+    IN p_processed_at TIMESTAMPTZ,
+    IN p_quote_id UUID
+)
+LANGUAGE plpgsql AS $$
+BEGIN
+    IF p_quote_id IS NOT NULL AND NOT redeem_price_quote(p_quote_id, p_user_id, p_amount, p_currency) THEN
+        RAISE EXCEPTION 'price quote cannot be redeemed';
+    END IF;
+
+    INSERT INTO payments (id, external_ref, user_id, subscription_id, amount, currency, status, processed_at, quote_id, created_at, updated_at)
+    VALUES (p_id, p_external_ref, p_user_id, p_subscription_id, p_amount, p_currency, p_status, p_processed_at, p_quote_id, NOW(), NOW());
+
+    PERFORM grant_purchase(p_id);
+END;
+$$;
+
+CREATE OR REPLACE PROCEDURE update_payment(
+    IN p_id UUID,
+    IN p_external_ref VARCHAR,
+    IN p_user_id UUID,
+    IN p_subscription_id UUID,
+    IN p_amount BIGINT,
+    IN p_currency CHAR(3),
+    IN p_status VARCHAR,
+    IN p_processed_at TIMESTAMPTZ
+)
+LANGUAGE plpgsql AS $$
+BEGIN
+    UPDATE payments
+    SET external_ref = p_external_ref,
+        user_id = p_user_id,
+        subscription_id = p_subscription_id,
+        amount = p_amount,
+        currency = p_currency,
+        status = p_status,
+        processed_at = p_processed_at,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = p_id AND deleted_at IS NULL;
+
+    PERFORM grant_purchase(p_id);
+END;
+$$;
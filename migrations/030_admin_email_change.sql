@@ -0,0 +1,39 @@
+-- An admin changing a user's email through update_user must not carry the old address's
+-- verification over: the new address is unverified until its owner follows a verification link.
+
+-- Procedure: update_user
+CREATE OR REPLACE PROCEDURE update_user(
+    IN p_id UUID,
+    IN p_email VARCHAR,
+    IN p_password TEXT,
+    IN p_first_name VARCHAR,
+    IN p_last_name VARCHAR,
+    IN p_role user_role,
+    IN p_wallet_id UUID,
+    INOUT p_updated_at TIMESTAMP
+)
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    row_count INT;
+BEGIN
+    UPDATE users SET
+        email_verified_at = CASE WHEN email IS DISTINCT FROM p_email THEN NULL ELSE email_verified_at END,
+        email = p_email,
+        password = p_password,
+        first_name = p_first_name,
+        last_name = p_last_name,
+        role = p_role,
+        wallet_id = p_wallet_id,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = p_id;
+
+    GET DIAGNOSTICS row_count = ROW_COUNT;
+
+    IF row_count > 0 THEN
+        SELECT updated_at INTO p_updated_at FROM users WHERE id = p_id;
+    ELSE
+        p_updated_at := NULL;
+    END IF;
+END;
+$$;
@@ -0,0 +1,25 @@
+-- create_payment raises a dedicated SQLSTATE when the quote a payment pays cannot be redeemed, so the
+-- application can tell it apart from other errors without matching the message.
+-- Keep KB001 in sync with the gateway's quoteNotRedeemableCode.
+
+CREATE OR REPLACE PROCEDURE create_payment(
+    IN p_id UUID,
+    IN p_external_ref VARCHAR,
+    IN p_user_id UUID,
+    IN p_subscription_id UUID,
+    IN p_amount BIGINT,
+    IN p_currency CHAR(3),
+    IN p_status VARCHAR,
+    IN p_processed_at TIMESTAMPTZ,
+    IN p_quote_id UUID
+)
+LANGUAGE plpgsql AS $$
+BEGIN
+    IF p_quote_id IS NOT NULL AND NOT redeem_price_quote(p_quote_id, p_user_id, p_amount, p_currency) THEN
+        RAISE EXCEPTION 'price quote cannot be redeemed' USING ERRCODE = 'KB001';
+    END IF;
+
+    INSERT INTO payments (id, external_ref, user_id, subscription_id, amount, currency, status, processed_at, quote_id, created_at, updated_at)
+    VALUES (p_id, p_external_ref, p_user_id, p_subscription_id, p_amount, p_currency, p_status, p_processed_at, p_quote_id, NOW(), NOW());
+END;
+$$;
@@ -0,0 +1,192 @@
+-- A purchase follows its payment: it is granted while the payment is completed and revoked when the
+-- payment fails, goes back to pending or is deleted. Revoking expires the subscriptions the purchase
+-- made; completing the payment again restores them. Only the signed payment webhook and admins can
+-- complete a payment, so only they can grant a purchase.
+
+ALTER TABLE purchases ADD COLUMN IF NOT EXISTS revoked_at TIMESTAMPTZ;
+
+-- Function: revoke_purchase revokes the purchase a payment made, if any, and expires the
+-- subscriptions it made. Returns the purchase, or NULL if there was nothing to revoke.
+CREATE OR REPLACE FUNCTION revoke_purchase(p_payment_id UUID)
+RETURNS UUID
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    v_purchase_id UUID;
+BEGIN
+    UPDATE purchases SET revoked_at = NOW()
+    WHERE payment_id = p_payment_id AND revoked_at IS NULL
+    RETURNING id INTO v_purchase_id;
+    IF v_purchase_id IS NULL THEN
+        RETURN NULL;
+    END IF;
+
+    UPDATE subscriptions
+    SET status = 'expired',
+        expires_at = LEAST(expires_at, NOW()),
+        updated_at = NOW()
+    WHERE purchase_id = v_purchase_id AND status = 'active' AND deleted_at IS NULL;
+    RETURN v_purchase_id;
+END;
+$$;
+
+-- Function: grant_purchase records the purchase a completed bundle or pass payment makes and
+-- subscribes the buyer to its courses; a revoked purchase is restored with its subscriptions.
+-- Returns the purchase, or NULL if the payment is not a completed bundle or pass payment.
+-- Calling it again for the same payment changes nothing.
+CREATE OR REPLACE FUNCTION grant_purchase(p_payment_id UUID)
+RETURNS UUID
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    payment payments%ROWTYPE;
+    quote price_quotes%ROWTYPE;
+    purchase purchases%ROWTYPE;
+    v_purchase_id UUID;
+    v_access_days INTEGER;
+    v_course_ids UUID[];
+BEGIN
+    SELECT * INTO payment FROM payments WHERE id = p_payment_id AND deleted_at IS NULL;
+    IF NOT FOUND OR payment.status <> 'completed' OR payment.quote_id IS NULL THEN
+        RETURN NULL;
+    END IF;
+
+    SELECT * INTO quote FROM price_quotes WHERE id = payment.quote_id;
+    IF quote.course_id IS NOT NULL THEN
+        RETURN NULL;
+    END IF;
+
+    IF quote.bundle_id IS NOT NULL THEN
+        SELECT access_days INTO v_access_days FROM bundles WHERE id = quote.bundle_id;
+        v_course_ids := bundle_course_ids(quote.bundle_id);
+    ELSE
+        SELECT access_days INTO v_access_days FROM influencer_passes WHERE influencer_id = quote.pass_influencer_id;
+        SELECT COALESCE(array_agg(id), '{}') INTO v_course_ids
+        FROM courses WHERE influencer_id = quote.pass_influencer_id;
+    END IF;
+
+    SELECT * INTO purchase FROM purchases WHERE payment_id = p_payment_id;
+    IF FOUND THEN
+        IF purchase.revoked_at IS NULL THEN
+            RETURN purchase.id;
+        END IF;
+
+        -- Completed again: the purchase keeps its original period
+        UPDATE purchases SET revoked_at = NULL WHERE id = purchase.id;
+        UPDATE subscriptions
+        SET status = 'active',
+            expires_at = purchase.expires_at,
+            updated_at = NOW()
+        WHERE purchase_id = purchase.id AND status = 'expired' AND deleted_at IS NULL
+          AND purchase.expires_at > NOW();
+        PERFORM grant_purchase_courses(purchase.id, v_course_ids);
+        RETURN purchase.id;
+    END IF;
+
+    INSERT INTO purchases (user_id, payment_id, bundle_id, pass_influencer_id, started_at, expires_at)
+    VALUES (payment.user_id, payment.id, quote.bundle_id, quote.pass_influencer_id,
+            NOW(), NOW() + make_interval(days => v_access_days))
+    RETURNING id INTO v_purchase_id;
+
+    PERFORM grant_purchase_courses(v_purchase_id, v_course_ids);
+    RETURN v_purchase_id;
+END;
+$$;
+
+-- Function: settle_purchase grants the purchase of a completed payment and revokes it otherwise
+CREATE OR REPLACE FUNCTION settle_purchase(p_payment_id UUID)
+RETURNS UUID
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM payments WHERE id = p_payment_id AND deleted_at IS NULL AND status = 'completed') THEN
+        RETURN grant_purchase(p_payment_id);
+    END IF;
+    RETURN revoke_purchase(p_payment_id);
+END;
+$$;
+
+-- Function: grant_pass_course subscribes everyone holding an unexpired, unrevoked pass from the
+-- course's influencer to the newly published course. Returns the number of subscriptions created.
+CREATE OR REPLACE FUNCTION grant_pass_course(p_course_id UUID)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    v_purchase_id UUID;
+    granted INTEGER := 0;
+BEGIN
+    FOR v_purchase_id IN
+        SELECT purchases.id FROM purchases
+        JOIN courses ON courses.influencer_id = purchases.pass_influencer_id
+        WHERE courses.id = p_course_id AND purchases.expires_at > NOW() AND purchases.revoked_at IS NULL
+    LOOP
+        granted := granted + grant_purchase_courses(v_purchase_id, ARRAY[p_course_id]);
+    END LOOP;
+    RETURN granted;
+END;
+$$;
+
+CREATE OR REPLACE PROCEDURE create_payment(
+    IN p_id UUID,
+    IN p_external_ref VARCHAR,
+    IN p_user_id UUID,
+    IN p_subscription_id UUID,
+    IN p_amount BIGINT,
+    IN p_currency CHAR(3),
+    IN p_status VARCHAR,
+    IN p_processed_at TIMESTAMPTZ,
+    IN p_quote_id UUID
+)
+LANGUAGE plpgsql AS $$
+BEGIN
+    IF p_quote_id IS NOT NULL AND NOT redeem_price_quote(p_quote_id, p_user_id, p_amount, p_currency) THEN
+        RAISE EXCEPTION 'price quote cannot be redeemed' USING ERRCODE = 'KB001';
+    END IF;
+
+    INSERT INTO payments (id, external_ref, user_id, subscription_id, amount, currency, status, processed_at, quote_id, created_at, updated_at)
+    VALUES (p_id, p_external_ref, p_user_id, p_subscription_id, p_amount, p_currency, p_status, p_processed_at, p_quote_id, NOW(), NOW());
+
+    PERFORM settle_purchase(p_id);
+END;
+$$;
+
+CREATE OR REPLACE PROCEDURE update_payment(
+    IN p_id UUID,
+    IN p_external_ref VARCHAR,
+    IN p_user_id UUID,
+    IN p_subscription_id UUID,
+    IN p_amount BIGINT,
+    IN p_currency CHAR(3),
+    IN p_status VARCHAR,
+    IN p_processed_at TIMESTAMPTZ
+)
+LANGUAGE plpgsql AS $$
+BEGIN
+    UPDATE payments
+    SET external_ref = p_external_ref,
+        user_id = p_user_id,
+        subscription_id = p_subscription_id,
+        amount = p_amount,
+        currency = p_currency,
+        status = p_status,
+        processed_at = p_processed_at,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = p_id AND deleted_at IS NULL;
+
+    PERFORM settle_purchase(p_id);
+END;
+$$;
+
+CREATE OR REPLACE PROCEDURE delete_payment(
+    IN p_id UUID
+)
+LANGUAGE plpgsql AS $$
+BEGIN
+    UPDATE payments
+    SET deleted_at = CURRENT_TIMESTAMP
+    WHERE id = p_id;
+
+    PERFORM revoke_purchase(p_id);
+END;
+$$;
@@ -0,0 +1,9 @@
+-- Subscriptions are made by completed payments or by admins: learners and influencers no longer
+-- create them.
+
+DELETE FROM role_permissions
+USING roles, permissions
+WHERE role_permissions.role_id = roles.id
+  AND role_permissions.permission_id = permissions.id
+  AND roles.name IN ('user', 'influencer')
+  AND permissions.name = 'subscriptions:create';
@@ -0,0 +1,72 @@
+-- A payment of a course quote without a subscription becomes a course purchase, which subscribes
+-- the buyer when the payment completes and is revoked like bundle and pass purchases.
+
+ALTER TABLE purchases ADD COLUMN IF NOT EXISTS course_id UUID REFERENCES courses(id) ON DELETE RESTRICT;
+ALTER TABLE purchases DROP CONSTRAINT IF EXISTS chk_purchases_item;
+ALTER TABLE purchases ADD CONSTRAINT chk_purchases_item CHECK (num_nonnulls(bundle_id, pass_influencer_id, course_id) = 1);
+
+-- Function: grant_purchase records the purchase a completed quote payment makes and subscribes the
+-- buyer to its courses; a revoked purchase is restored with its subscriptions. A course quote paid
+-- for an existing subscription makes no purchase. Returns the purchase, or NULL if the payment
+-- makes none. Calling it again for the same payment changes nothing.
+CREATE OR REPLACE FUNCTION grant_purchase(p_payment_id UUID)
+RETURNS UUID
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    payment payments%ROWTYPE;
+    quote price_quotes%ROWTYPE;
+    purchase purchases%ROWTYPE;
+    v_purchase_id UUID;
+    v_access_days INTEGER;
+    v_course_ids UUID[];
+BEGIN
+    SELECT * INTO payment FROM payments WHERE id = p_payment_id AND deleted_at IS NULL;
+    IF NOT FOUND OR payment.status <> 'completed' OR payment.quote_id IS NULL THEN
+        RETURN NULL;
+    END IF;
+
+    SELECT * INTO quote FROM price_quotes WHERE id = payment.quote_id;
+    IF quote.course_id IS NOT NULL THEN
+        IF payment.subscription_id IS NOT NULL THEN
+            RETURN NULL;
+        END IF;
+        -- Keep in sync with model.CourseAccessDays
+        v_access_days := 365;
+        v_course_ids := ARRAY[quote.course_id];
+    ELSIF quote.bundle_id IS NOT NULL THEN
+        SELECT access_days INTO v_access_days FROM bundles WHERE id = quote.bundle_id;
+        v_course_ids := bundle_course_ids(quote.bundle_id);
+    ELSE
+        SELECT access_days INTO v_access_days FROM influencer_passes WHERE influencer_id = quote.pass_influencer_id;
+        SELECT COALESCE(array_agg(id), '{}') INTO v_course_ids
+        FROM courses WHERE influencer_id = quote.pass_influencer_id;
+    END IF;
+
+    SELECT * INTO purchase FROM purchases WHERE payment_id = p_payment_id;
+    IF FOUND THEN
+        IF purchase.revoked_at IS NULL THEN
+            RETURN purchase.id;
+        END IF;
+
+        -- Completed again: the purchase keeps its original period
+        UPDATE purchases SET revoked_at = NULL WHERE id = purchase.id;
+        UPDATE subscriptions
+        SET status = 'active',
+            expires_at = purchase.expires_at,
+            updated_at = NOW()
+        WHERE purchase_id = purchase.id AND status = 'expired' AND deleted_at IS NULL
+          AND purchase.expires_at > NOW();
+        PERFORM grant_purchase_courses(purchase.id, v_course_ids);
+        RETURN purchase.id;
+    END IF;
+
+    INSERT INTO purchases (user_id, payment_id, bundle_id, pass_influencer_id, course_id, started_at, expires_at)
+    VALUES (payment.user_id, payment.id, quote.bundle_id, quote.pass_influencer_id, quote.course_id,
+            NOW(), NOW() + make_interval(days => v_access_days))
+    RETURNING id INTO v_purchase_id;
+
+    PERFORM grant_purchase_courses(v_purchase_id, v_course_ids);
+    RETURN v_purchase_id;
+END;
+$$;
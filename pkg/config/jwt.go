@@ -1,10 +1,13 @@
 package utils
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
 	"errors"
 	"os"
 	"time"
 
+	"github.com/gofrs/uuid"
 	"github.com/golang-jwt/jwt/v5"
 )
 
@@ -28,6 +31,7 @@ func GenereteToken(userID string, expiry int64) (string, error) {
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			Issuer:    "kaabe-backend",
 			Subject:   userID,
+			ID:        newTokenID(),
 		},
 	}
 
@@ -49,6 +53,7 @@ func GenerateRefreshToken(userID string, expiry int64) (string, error) {
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			Issuer:    "kaabe-backend",
 			Subject:   userID,
+			ID:        newTokenID(),
 		},
 	}
 
@@ -56,6 +61,15 @@ func GenerateRefreshToken(userID string, expiry int64) (string, error) {
 	return token.SignedString([]byte(secret))
 }
 
+// newTokenID returns a unique JWT ID so tokens issued in the same second never collide
+func newTokenID() string {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return ""
+	}
+	return id.String()
+}
+
 // ValidateToken parses and verifies the JWT token and returns the claims if valid
 func ValidateToken(tokenString string, isRefresh bool) (*CustomClaims, error) {
 	secret := os.Getenv("JWT_SECRET")
@@ -69,7 +83,7 @@ func ValidateToken(tokenString string, isRefresh bool) (*CustomClaims, error) {
 
 	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, func(token *jwt.Token) (interface{}, error) {
 		return []byte(secret), nil
-	})
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithIssuer("kaabe-backend"))
 	if err != nil {
 		return nil, err
 	}
@@ -80,3 +94,89 @@ func ValidateToken(tokenString string, isRefresh bool) (*CustomClaims, error) {
 
 	return nil, errors.New("invalid or expired token")
 }
+
+// Purposes for single-use tokens sent to users out of band
+const (
+	PurposeEmailVerification = "email_verification"
+	PurposeEmailChange       = "email_change"
+	PurposeMFALogin          = "mfa_login"      // password checked, waiting for a TOTP or recovery code
+	PurposeMFAEnrollment     = "mfa_enrollment" // password checked, 2FA must be set up before tokens are issued
+	PurposeOIDCLogin         = "oidc_login"     // state of a social login round trip
+	PurposeOIDCLink          = "oidc_link"      // state of linking a social account to a logged in user
+)
+
+// PurposeClaims ties a token to one user, one email address and one purpose
+type PurposeClaims struct {
+	UserID   string `json:"user_id"`
+	Email    string `json:"email"`
+	NewEmail string `json:"new_email,omitempty"` // target address of an email change
+	Provider string `json:"provider,omitempty"`  // OIDC provider of a login or link state
+	Nonce    string `json:"nonce,omitempty"`     // expected nonce in the provider's ID token
+	Purpose  string `json:"purpose"`
+	jwt.RegisteredClaims
+}
+
+// GeneratePurposeToken signs a token that is only valid for the given purpose.
+// The signing key is derived per purpose, so it can never be used as an access token.
+func GeneratePurposeToken(userID, email, purpose string, expiry int64) (string, error) {
+	return signPurposeToken(PurposeClaims{UserID: userID, Email: email, Purpose: purpose}, expiry)
+}
+
+// GenerateEmailChangeToken signs a token confirming a move from the current email to newEmail.
+// It stops working as soon as the current email changes.
+func GenerateEmailChangeToken(userID, currentEmail, newEmail string, expiry int64) (string, error) {
+	return signPurposeToken(PurposeClaims{UserID: userID, Email: currentEmail, NewEmail: newEmail, Purpose: PurposeEmailChange}, expiry)
+}
+
+// GenerateOIDCStateToken signs the state parameter of an OIDC round trip. userID is empty
+// for logins and set to the user the identity will be linked to otherwise.
+func GenerateOIDCStateToken(purpose, userID, provider, nonce string, expiry int64) (string, error) {
+	return signPurposeToken(PurposeClaims{UserID: userID, Provider: provider, Nonce: nonce, Purpose: purpose}, expiry)
+}
+
+func signPurposeToken(claims PurposeClaims, expiry int64) (string, error) {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		return "", errors.New("JWT_SECRET not set in env")
+	}
+
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(time.Unix(expiry, 0)),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		Issuer:    "kaabe-backend",
+		Subject:   claims.UserID,
+		Audience:  jwt.ClaimStrings{claims.Purpose},
+		ID:        newTokenID(),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(purposeKey(secret, claims.Purpose))
+}
+
+// ValidatePurposeToken verifies a token issued by GeneratePurposeToken for the same purpose
+func ValidatePurposeToken(tokenString, purpose string) (*PurposeClaims, error) {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		return nil, errors.New("JWT secret not set")
+	}
+
+	token, err := jwt.ParseWithClaims(tokenString, &PurposeClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return purposeKey(secret, purpose), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithIssuer("kaabe-backend"), jwt.WithAudience(purpose))
+	if err != nil {
+		return nil, err
+	}
+
+	if claims, ok := token.Claims.(*PurposeClaims); ok && token.Valid && claims.Purpose == purpose {
+		return claims, nil
+	}
+
+	return nil, errors.New("invalid or expired token")
+}
+
+// purposeKey derives a separate HMAC key for each purpose from the JWT secret
+func purposeKey(secret, purpose string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ErrInvalidPhoneNumber is returned for numbers that cannot be turned into E.164 form
+var ErrInvalidPhoneNumber = errors.New("invalid phone number")
+
+// NormalizePhoneNumber converts a number typed by a user into E.164 form (+252612345678).
+// Numbers without an international prefix are assumed to be in defaultCountryCode.
+func NormalizePhoneNumber(raw, defaultCountryCode string) (string, error) {
+	cleaned := strings.NewReplacer(" ", "", "-", "", "(", "", ")", "", ".", "").Replace(strings.TrimSpace(raw))
+
+	var digits string
+	switch {
+	case strings.HasPrefix(cleaned, "+"):
+		digits = cleaned[1:]
+	case strings.HasPrefix(cleaned, "00"):
+		digits = cleaned[2:]
+	case strings.HasPrefix(cleaned, "0"):
+		digits = defaultCountryCode + cleaned[1:]
+	case defaultCountryCode != "" && !strings.HasPrefix(cleaned, defaultCountryCode):
+		digits = defaultCountryCode + cleaned
+	default:
+		digits = cleaned
+	}
+
+	if len(digits) < 8 || len(digits) > 15 || digits[0] == '0' {
+		return "", ErrInvalidPhoneNumber
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return "", ErrInvalidPhoneNumber
+		}
+	}
+	return "+" + digits, nil
+}
+
+// GenerateOTP returns a random numeric one-time code with the given number of digits
+func GenerateOTP(digits int) (string, error) {
+	max := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(digits)), nil)
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", digits, n), nil
+}
+
+// HashOTP returns the SHA-256 hex digest of a one-time code, salted with the phone number.
+// Codes live for minutes and allow only a few guesses, so a fast hash is sufficient.
+func HashOTP(phoneNumber, code string) string {
+	sum := sha256.Sum256([]byte(phoneNumber + ":" + strings.TrimSpace(code)))
+	return hex.EncodeToString(sum[:])
+}
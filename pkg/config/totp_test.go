@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA1 key of the RFC 6238 test vectors, "12345678901234567890", in base32
+var rfc6238Secret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestValidateTOTPRFC6238Vectors(t *testing.T) {
+	// RFC 6238 appendix B, SHA1; the RFC lists eight digits and the app uses the last six
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		step, ok := ValidateTOTP(rfc6238Secret, tt.code, time.Unix(tt.unix, 0))
+		if !ok || step != tt.unix/totpPeriod {
+			t.Errorf("ValidateTOTP(%q) at %d = %d, %v; want step %d", tt.code, tt.unix, step, ok, tt.unix/totpPeriod)
+		}
+	}
+}
+
+func TestValidateTOTPSkew(t *testing.T) {
+	// "081804" is the code of step 37037036, which runs from 1111111080 to 1111111109
+	const code = "081804"
+	const step = 37037036
+
+	tests := []struct {
+		name string
+		unix int64
+		ok   bool
+	}{
+		{"one step early", (step - 1) * totpPeriod, true},
+		{"two steps early", (step-1)*totpPeriod - 1, false},
+		{"during its step", step*totpPeriod + 10, true},
+		{"one step late", (step+2)*totpPeriod - 1, true},
+		{"two steps late", (step + 2) * totpPeriod, false},
+	}
+
+	for _, tt := range tests {
+		matched, ok := ValidateTOTP(rfc6238Secret, code, time.Unix(tt.unix, 0))
+		if ok != tt.ok {
+			t.Errorf("%s: ValidateTOTP at %d = %v, want %v", tt.name, tt.unix, ok, tt.ok)
+		}
+		// The step reported is the code's own, whichever step it was accepted in
+		if ok && matched != step {
+			t.Errorf("%s: matched step %d, want %d", tt.name, matched, step)
+		}
+	}
+}
+
+func TestValidateTOTPInput(t *testing.T) {
+	at := time.Unix(59, 0)
+
+	tests := []struct {
+		name   string
+		secret string
+		code   string
+		ok     bool
+	}{
+		{"surrounding spaces", rfc6238Secret, " 287082 ", true},
+		{"lowercase padded secret", "gezdgnbvgy3tqojqgezdgnbvgy3tqojq===", "287082", true},
+		{"wrong code", rfc6238Secret, "287083", false},
+		{"too short", rfc6238Secret, "28708", false},
+		{"eight digits", rfc6238Secret, "94287082", false},
+		{"invalid secret", "not base32!", "287082", false},
+	}
+
+	for _, tt := range tests {
+		if _, ok := ValidateTOTP(tt.secret, tt.code, at); ok != tt.ok {
+			t.Errorf("%s: ValidateTOTP = %v, want %v", tt.name, ok, tt.ok)
+		}
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seen := map[string]bool{}
+	for _, code := range codes {
+		if len(code) != 11 || code[5] != '-' {
+			t.Errorf("recovery code %q, want xxxxx-xxxxx", code)
+		}
+		if seen[code] {
+			t.Errorf("recovery code %q issued twice", code)
+		}
+		seen[code] = true
+	}
+
+	// A code is recognised however it is typed
+	hash := HashRecoveryCode("abcde-fghij")
+	for _, typed := range []string{"ABCDE-FGHIJ", "abcdefghij", "abcde fghij"} {
+		if HashRecoveryCode(typed) != hash {
+			t.Errorf("HashRecoveryCode(%q) differs from HashRecoveryCode(%q)", typed, "abcde-fghij")
+		}
+	}
+	if HashRecoveryCode("abcde-fghik") == hash {
+		t.Errorf("different codes hash the same")
+	}
+}